@@ -0,0 +1,209 @@
+package accounts
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/blanquicet/conti/backend/internal/audit"
+	"github.com/blanquicet/conti/backend/internal/money"
+)
+
+// checkpointRepository keeps one account, its movements and checkpoints in
+// memory. Balances include the adjustments of the checkpoints, like the
+// database does.
+type checkpointRepository struct {
+	Repository
+	account     *Account
+	activity    []*Activity
+	checkpoints []*Checkpoint
+}
+
+func (r *checkpointRepository) GetByID(ctx context.Context, id string) (*Account, error) {
+	if id != r.account.ID {
+		return nil, ErrAccountNotFound
+	}
+	return r.account, nil
+}
+
+func (r *checkpointRepository) GetBalanceAt(ctx context.Context, id string, asOf time.Time) (money.Amount, error) {
+	var balance money.Amount
+	for _, activity := range r.all() {
+		if !activity.Date.After(asOf) {
+			balance += activity.Amount
+		}
+	}
+	return balance, nil
+}
+
+func (r *checkpointRepository) ListActivity(ctx context.Context, id string, after *time.Time, upTo time.Time) ([]*Activity, error) {
+	var list []*Activity
+	for _, activity := range r.all() {
+		if (after == nil || activity.Date.After(*after)) && !activity.Date.After(upTo) {
+			list = append(list, activity)
+		}
+	}
+	return list, nil
+}
+
+func (r *checkpointRepository) GetCheckpoint(ctx context.Context, id string) (*Checkpoint, error) {
+	for _, checkpoint := range r.checkpoints {
+		if checkpoint.ID == id {
+			stored := *checkpoint
+			return &stored, nil
+		}
+	}
+	return nil, ErrCheckpointNotFound
+}
+
+func (r *checkpointRepository) ListCheckpoints(ctx context.Context, accountID string) ([]*Checkpoint, error) {
+	list := make([]*Checkpoint, len(r.checkpoints))
+	for i, checkpoint := range r.checkpoints {
+		stored := *checkpoint
+		list[i] = &stored
+	}
+	return list, nil
+}
+
+func (r *checkpointRepository) SetCheckpointAdjustment(ctx context.Context, id string, adjustment money.Amount) error {
+	for _, checkpoint := range r.checkpoints {
+		if checkpoint.ID == id {
+			checkpoint.Adjustment = adjustment
+			return nil
+		}
+	}
+	return ErrCheckpointNotFound
+}
+
+// all returns the movements and the adjustments of the checkpoints
+func (r *checkpointRepository) all() []*Activity {
+	all := append([]*Activity{}, r.activity...)
+	for _, checkpoint := range r.checkpoints {
+		if checkpoint.Adjustment != 0 {
+			all = append(all, &Activity{Kind: ActivityAdjustment, ID: checkpoint.ID, Date: checkpoint.Date, Amount: checkpoint.Adjustment})
+		}
+	}
+	return all
+}
+
+// discardAudit ignores audit entries
+type discardAudit struct {
+	audit.Service
+}
+
+func (discardAudit) LogAsync(ctx context.Context, input *audit.LogInput) {}
+
+func newCheckpointService() (*Service, *checkpointRepository) {
+	repo := &checkpointRepository{
+		account: &Account{ID: "a1", HouseholdID: "h1"},
+		activity: []*Activity{
+			{Kind: ActivityIncome, ID: "i1", Date: date(2025, 1, 5), Amount: money.New(1000)},
+			{Kind: ActivityMovement, ID: "m1", Date: date(2025, 1, 20), Amount: money.New(-300)},
+			{Kind: ActivityIncome, ID: "i2", Date: date(2025, 2, 10), Amount: money.New(500)},
+		},
+		checkpoints: []*Checkpoint{
+			// The bank says 800 where movements add up to 700
+			{ID: "c1", AccountID: "a1", Date: date(2025, 1, 31), ReportedBalance: money.New(800)},
+			{ID: "c2", AccountID: "a1", Date: date(2025, 2, 28), ReportedBalance: money.New(1300)},
+		},
+	}
+	return NewService(repo, discardAudit{}), repo
+}
+
+func TestPreviousCheckpoint(t *testing.T) {
+	checkpoints := []*Checkpoint{
+		{ID: "feb", Date: date(2025, 2, 28)},
+		{ID: "jan", Date: date(2025, 1, 31)},
+		{ID: "mar", Date: date(2025, 3, 31)},
+	}
+
+	tests := []struct {
+		name string
+		date time.Time
+		want string
+	}{
+		{"none before", date(2025, 1, 31), ""},
+		{"latest before", date(2025, 3, 31), "feb"},
+		{"between checkpoints", date(2025, 2, 15), "jan"},
+		{"after all", date(2025, 4, 30), "mar"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			previous := previousCheckpoint(checkpoints, tt.date)
+			got := ""
+			if previous != nil {
+				got = previous.ID
+			}
+			if got != tt.want {
+				t.Errorf("previousCheckpoint() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAdjustCheckpoint(t *testing.T) {
+	s, _ := newCheckpointService()
+	ctx := context.Background()
+
+	before, err := s.GetReconciliation(ctx, "c1", "a1", "h1")
+	if err != nil {
+		t.Fatalf("GetReconciliation() error = %v", err)
+	}
+	if before.Checkpoint.Discrepancy != money.New(100) {
+		t.Fatalf("discrepancy = %v, want 100", before.Checkpoint.Discrepancy)
+	}
+
+	adjusted, err := s.AdjustCheckpoint(ctx, "h1", "u1", "a1", "c1")
+	if err != nil {
+		t.Fatalf("AdjustCheckpoint() error = %v", err)
+	}
+	if adjusted.Checkpoint.Adjustment != money.New(100) {
+		t.Errorf("adjustment = %v, want 100", adjusted.Checkpoint.Adjustment)
+	}
+	if adjusted.Checkpoint.DerivedBalance != money.New(800) || adjusted.Checkpoint.Discrepancy != 0 {
+		t.Errorf("derived = %v, discrepancy = %v, want 800 and 0",
+			adjusted.Checkpoint.DerivedBalance, adjusted.Checkpoint.Discrepancy)
+	}
+
+	// Once the gap is closed there is nothing left to adjust
+	if _, err := s.AdjustCheckpoint(ctx, "h1", "u1", "a1", "c1"); err != ErrNothingToAdjust {
+		t.Errorf("second AdjustCheckpoint() error = %v, want ErrNothingToAdjust", err)
+	}
+}
+
+func TestLaterCheckpointIncludesEarlierAdjustment(t *testing.T) {
+	s, _ := newCheckpointService()
+	ctx := context.Background()
+
+	// Before adjusting, the February checkpoint inherits January's gap
+	later, err := s.GetReconciliation(ctx, "c2", "a1", "h1")
+	if err != nil {
+		t.Fatalf("GetReconciliation() error = %v", err)
+	}
+	if later.Checkpoint.Discrepancy != money.New(100) {
+		t.Fatalf("discrepancy = %v, want 100", later.Checkpoint.Discrepancy)
+	}
+
+	if _, err := s.AdjustCheckpoint(ctx, "h1", "u1", "a1", "c1"); err != nil {
+		t.Fatalf("AdjustCheckpoint() error = %v", err)
+	}
+
+	later, err = s.GetReconciliation(ctx, "c2", "a1", "h1")
+	if err != nil {
+		t.Fatalf("GetReconciliation() error = %v", err)
+	}
+	if later.Checkpoint.DerivedBalance != money.New(1300) || later.Checkpoint.Discrepancy != 0 {
+		t.Errorf("derived = %v, discrepancy = %v, want 1300 and 0",
+			later.Checkpoint.DerivedBalance, later.Checkpoint.Discrepancy)
+	}
+
+	// The activity starts after the previous checkpoint, so its adjustment
+	// isn't counted again
+	if later.Previous == nil || later.Previous.ID != "c1" || later.Previous.Discrepancy != 0 {
+		t.Errorf("previous = %+v, want c1 without discrepancy", later.Previous)
+	}
+	if len(later.Activity) != 1 || later.Activity[0].ID != "i2" || later.ActivityTotal != money.New(500) {
+		t.Errorf("activity = %d entries totaling %v, want only i2 (500)", len(later.Activity), later.ActivityTotal)
+	}
+}
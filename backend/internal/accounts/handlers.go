@@ -6,9 +6,11 @@ import (
 	"errors"
 	"log/slog"
 	"net/http"
+	"time"
 
 	"github.com/blanquicet/conti/backend/internal/auth"
 	"github.com/blanquicet/conti/backend/internal/households"
+	"github.com/blanquicet/conti/backend/internal/money"
 )
 
 // Handler handles HTTP requests for account management
@@ -45,7 +47,7 @@ type CreateAccountRequest struct {
 	Type           AccountType  `json:"type"`
 	Institution    *string      `json:"institution,omitempty"`
 	Last4          *string      `json:"last4,omitempty"`
-	InitialBalance *float64     `json:"initial_balance,omitempty"`
+	InitialBalance *money.Amount     `json:"initial_balance,omitempty"`
 	Notes          *string      `json:"notes,omitempty"`
 }
 
@@ -53,10 +55,16 @@ type UpdateAccountRequest struct {
 	Name           *string  `json:"name,omitempty"`
 	Institution    *string  `json:"institution,omitempty"`
 	Last4          *string  `json:"last4,omitempty"`
-	InitialBalance *float64 `json:"initial_balance,omitempty"`
+	InitialBalance *money.Amount `json:"initial_balance,omitempty"`
 	Notes          *string  `json:"notes,omitempty"`
 }
 
+type CreateCheckpointRequest struct {
+	Date    *string       `json:"date,omitempty"` // YYYY-MM-DD format, defaults to today
+	Balance *money.Amount `json:"balance"`
+	Notes   *string       `json:"notes,omitempty"`
+}
+
 type ErrorResponse struct {
 	Error string `json:"error"`
 }
@@ -328,3 +336,252 @@ func (h *Handler) DeleteAccount(w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// respondCheckpointError maps checkpoint errors to status codes
+func (h *Handler) respondCheckpointError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrAccountNotFound), errors.Is(err, ErrCheckpointNotFound):
+		h.respondError(w, err, http.StatusNotFound)
+	case errors.Is(err, ErrNotAuthorized):
+		h.respondError(w, err, http.StatusForbidden)
+	case errors.Is(err, ErrCheckpointExists), errors.Is(err, ErrNothingToAdjust):
+		h.respondError(w, err, http.StatusConflict)
+	case errors.Is(err, ErrCheckpointBalanceRequired), errors.Is(err, ErrCheckpointInFuture):
+		h.respondError(w, err, http.StatusBadRequest)
+	default:
+		h.respondError(w, err, http.StatusInternalServerError)
+	}
+}
+
+// CreateCheckpoint handles POST /api/accounts/:id/checkpoints
+// Records the real balance of the account and returns its reconciliation
+func (h *Handler) CreateCheckpoint(w http.ResponseWriter, r *http.Request) {
+	user, err := h.getUserFromRequest(r)
+	if err != nil {
+		h.respondError(w, errors.New("unauthorized"), http.StatusUnauthorized)
+		return
+	}
+
+	household, err := h.getUserHousehold(r.Context(), user.ID)
+	if err != nil {
+		h.respondError(w, errors.New("user has no household"), http.StatusNotFound)
+		return
+	}
+
+	var req CreateCheckpointRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, errors.New("invalid request body"), http.StatusBadRequest)
+		return
+	}
+
+	input := CheckpointInput{
+		Balance: req.Balance,
+		Notes:   req.Notes,
+	}
+	if req.Date != nil {
+		date, err := time.Parse("2006-01-02", *req.Date)
+		if err != nil {
+			h.respondError(w, errors.New("invalid date format, expected YYYY-MM-DD"), http.StatusBadRequest)
+			return
+		}
+		input.Date = &date
+	}
+
+	reconciliation, err := h.service.CreateCheckpoint(r.Context(), household.ID, user.ID, r.PathValue("id"), input)
+	if err != nil {
+		h.respondCheckpointError(w, err)
+		return
+	}
+
+	h.respondJSON(w, reconciliation, http.StatusCreated)
+}
+
+// ListCheckpoints handles GET /api/accounts/:id/checkpoints
+func (h *Handler) ListCheckpoints(w http.ResponseWriter, r *http.Request) {
+	user, err := h.getUserFromRequest(r)
+	if err != nil {
+		h.respondError(w, errors.New("unauthorized"), http.StatusUnauthorized)
+		return
+	}
+
+	household, err := h.getUserHousehold(r.Context(), user.ID)
+	if err != nil {
+		h.respondError(w, errors.New("user has no household"), http.StatusNotFound)
+		return
+	}
+
+	checkpoints, err := h.service.ListCheckpoints(r.Context(), r.PathValue("id"), household.ID)
+	if err != nil {
+		h.respondCheckpointError(w, err)
+		return
+	}
+
+	h.respondJSON(w, checkpoints, http.StatusOK)
+}
+
+// GetCheckpoint handles GET /api/accounts/:id/checkpoints/:checkpointId
+// Returns the checkpoint discrepancy and the activity since the previous one
+func (h *Handler) GetCheckpoint(w http.ResponseWriter, r *http.Request) {
+	user, err := h.getUserFromRequest(r)
+	if err != nil {
+		h.respondError(w, errors.New("unauthorized"), http.StatusUnauthorized)
+		return
+	}
+
+	household, err := h.getUserHousehold(r.Context(), user.ID)
+	if err != nil {
+		h.respondError(w, errors.New("user has no household"), http.StatusNotFound)
+		return
+	}
+
+	reconciliation, err := h.service.GetReconciliation(r.Context(), r.PathValue("checkpointId"), r.PathValue("id"), household.ID)
+	if err != nil {
+		h.respondCheckpointError(w, err)
+		return
+	}
+
+	h.respondJSON(w, reconciliation, http.StatusOK)
+}
+
+// AdjustCheckpoint handles POST /api/accounts/:id/checkpoints/:checkpointId/adjust
+// Adds an adjustment that brings the account balance to the checkpoint one
+func (h *Handler) AdjustCheckpoint(w http.ResponseWriter, r *http.Request) {
+	user, err := h.getUserFromRequest(r)
+	if err != nil {
+		h.respondError(w, errors.New("unauthorized"), http.StatusUnauthorized)
+		return
+	}
+
+	household, err := h.getUserHousehold(r.Context(), user.ID)
+	if err != nil {
+		h.respondError(w, errors.New("user has no household"), http.StatusNotFound)
+		return
+	}
+
+	reconciliation, err := h.service.AdjustCheckpoint(r.Context(), household.ID, user.ID, r.PathValue("id"), r.PathValue("checkpointId"))
+	if err != nil {
+		h.respondCheckpointError(w, err)
+		return
+	}
+
+	h.respondJSON(w, reconciliation, http.StatusOK)
+}
+
+// DeleteCheckpoint handles DELETE /api/accounts/:id/checkpoints/:checkpointId
+func (h *Handler) DeleteCheckpoint(w http.ResponseWriter, r *http.Request) {
+	user, err := h.getUserFromRequest(r)
+	if err != nil {
+		h.respondError(w, errors.New("unauthorized"), http.StatusUnauthorized)
+		return
+	}
+
+	household, err := h.getUserHousehold(r.Context(), user.ID)
+	if err != nil {
+		h.respondError(w, errors.New("user has no household"), http.StatusNotFound)
+		return
+	}
+
+	if err := h.service.DeleteCheckpoint(r.Context(), household.ID, user.ID, r.PathValue("id"), r.PathValue("checkpointId")); err != nil {
+		h.respondCheckpointError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parseHistoryInput reads the range of a balance history from the query string
+func parseHistoryInput(r *http.Request) (HistoryInput, error) {
+	input := HistoryInput{Interval: Interval(r.URL.Query().Get("interval"))}
+	if fromStr := r.URL.Query().Get("from"); fromStr != "" {
+		from, err := time.Parse("2006-01-02", fromStr)
+		if err != nil {
+			return input, errors.New("invalid from format, expected YYYY-MM-DD")
+		}
+		input.From = &from
+	}
+	if toStr := r.URL.Query().Get("to"); toStr != "" {
+		to, err := time.Parse("2006-01-02", toStr)
+		if err != nil {
+			return input, errors.New("invalid to format, expected YYYY-MM-DD")
+		}
+		input.To = &to
+	}
+	return input, nil
+}
+
+// respondHistoryError maps balance history errors to status codes
+func (h *Handler) respondHistoryError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrAccountNotFound):
+		h.respondError(w, err, http.StatusNotFound)
+	case errors.Is(err, ErrNotAuthorized):
+		h.respondError(w, err, http.StatusForbidden)
+	case errors.Is(err, ErrInvalidInterval), errors.Is(err, ErrInvalidHistoryRange), errors.Is(err, ErrHistoryTooLong):
+		h.respondError(w, err, http.StatusBadRequest)
+	default:
+		h.respondError(w, err, http.StatusInternalServerError)
+	}
+}
+
+// GetBalanceHistory handles GET /api/accounts/:id/balance-history
+// Query parameters (all optional):
+//   - from: YYYY-MM-DD, defaults to a year before to
+//   - to: YYYY-MM-DD, defaults to today
+//   - interval: day, week or month (default)
+func (h *Handler) GetBalanceHistory(w http.ResponseWriter, r *http.Request) {
+	user, err := h.getUserFromRequest(r)
+	if err != nil {
+		h.respondError(w, errors.New("unauthorized"), http.StatusUnauthorized)
+		return
+	}
+
+	household, err := h.getUserHousehold(r.Context(), user.ID)
+	if err != nil {
+		h.respondError(w, errors.New("user has no household"), http.StatusNotFound)
+		return
+	}
+
+	input, err := parseHistoryInput(r)
+	if err != nil {
+		h.respondError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	history, err := h.service.GetBalanceHistory(r.Context(), r.PathValue("id"), household.ID, input)
+	if err != nil {
+		h.respondHistoryError(w, err)
+		return
+	}
+
+	h.respondJSON(w, history, http.StatusOK)
+}
+
+// GetHouseholdBalanceHistory handles GET /api/accounts/balance-history
+// Same parameters as GetBalanceHistory, over every account in the household
+func (h *Handler) GetHouseholdBalanceHistory(w http.ResponseWriter, r *http.Request) {
+	user, err := h.getUserFromRequest(r)
+	if err != nil {
+		h.respondError(w, errors.New("unauthorized"), http.StatusUnauthorized)
+		return
+	}
+
+	household, err := h.getUserHousehold(r.Context(), user.ID)
+	if err != nil {
+		h.respondError(w, errors.New("user has no household"), http.StatusNotFound)
+		return
+	}
+
+	input, err := parseHistoryInput(r)
+	if err != nil {
+		h.respondError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	history, err := h.service.GetHouseholdBalanceHistory(r.Context(), household.ID, input)
+	if err != nil {
+		h.respondHistoryError(w, err)
+		return
+	}
+
+	h.respondJSON(w, history, http.StatusOK)
+}
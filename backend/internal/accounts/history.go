@@ -0,0 +1,153 @@
+package accounts
+
+import (
+	"errors"
+	"time"
+
+	"github.com/blanquicet/conti/backend/internal/money"
+)
+
+// maxHistoryPoints bounds the size of a balance history
+const maxHistoryPoints = 1000
+
+// Errors for balance histories
+var (
+	ErrInvalidInterval     = errors.New("invalid interval, expected day, week or month")
+	ErrInvalidHistoryRange = errors.New("from must not be after to")
+	ErrHistoryTooLong      = errors.New("too many points in the balance history, use a shorter range or a longer interval")
+)
+
+// Interval is the length of each point of a balance history
+type Interval string
+
+const (
+	IntervalDay   Interval = "day"
+	IntervalWeek  Interval = "week" // Monday to Sunday
+	IntervalMonth Interval = "month"
+)
+
+// Validate checks if the interval is valid
+func (i Interval) Validate() error {
+	switch i {
+	case IntervalDay, IntervalWeek, IntervalMonth:
+		return nil
+	default:
+		return ErrInvalidInterval
+	}
+}
+
+// periodEnd returns the last day of the period that starts on a date
+func (i Interval) periodEnd(start time.Time) time.Time {
+	switch i {
+	case IntervalWeek:
+		return start.AddDate(0, 0, (7-int(start.Weekday()))%7)
+	case IntervalMonth:
+		return time.Date(start.Year(), start.Month()+1, 0, 0, 0, 0, 0, start.Location())
+	default:
+		return start
+	}
+}
+
+// BalancePoint is the balance at the end of a period and how much it changed in it
+type BalancePoint struct {
+	Start   time.Time    `json:"start"`
+	End     time.Time    `json:"end"`
+	Balance money.Amount `json:"balance"`
+	Change  money.Amount `json:"change"`
+}
+
+// BalanceHistory is how the balance of an account evolved over a range
+type BalanceHistory struct {
+	AccountID   string          `json:"account_id"`
+	AccountName string          `json:"account_name"`
+	Interval    Interval        `json:"interval"`
+	From        time.Time       `json:"from"`
+	To          time.Time       `json:"to"`
+	Points      []*BalancePoint `json:"points"`
+}
+
+// HouseholdBalanceHistory is the evolution of the balance of every account in
+// the household, added up in Points
+type HouseholdBalanceHistory struct {
+	Interval Interval          `json:"interval"`
+	From     time.Time         `json:"from"`
+	To       time.Time         `json:"to"`
+	Points   []*BalancePoint   `json:"points"`
+	Accounts []*BalanceHistory `json:"accounts"`
+}
+
+// HistoryInput contains the range of a balance history
+type HistoryInput struct {
+	From     *time.Time // Optional, defaults to a year before To
+	To       *time.Time // Optional, defaults to today
+	Interval Interval   // Optional, defaults to month
+}
+
+// periods validates the input and returns the empty points of the history
+func (i *HistoryInput) periods(today time.Time) ([]*BalancePoint, error) {
+	if i.Interval == "" {
+		i.Interval = IntervalMonth
+	}
+	if err := i.Interval.Validate(); err != nil {
+		return nil, err
+	}
+
+	to := dateOf(today)
+	if i.To != nil {
+		to = dateOf(*i.To)
+	}
+	from := to.AddDate(-1, 0, 1)
+	if i.From != nil {
+		from = dateOf(*i.From)
+	}
+	if from.After(to) {
+		return nil, ErrInvalidHistoryRange
+	}
+	i.From, i.To = &from, &to
+
+	points := make([]*BalancePoint, 0)
+	for start := from; !start.After(to); {
+		if len(points) == maxHistoryPoints {
+			return nil, ErrHistoryTooLong
+		}
+		end := i.Interval.periodEnd(start)
+		if end.After(to) {
+			end = to
+		}
+		points = append(points, &BalancePoint{Start: start, End: end})
+		start = end.AddDate(0, 0, 1)
+	}
+	return points, nil
+}
+
+// replay fills the points from the balance before the first one and the
+// activity within them, oldest first
+func replay(points []*BalancePoint, opening money.Amount, activity []*Activity) {
+	balance := opening
+	next := 0
+	for _, point := range points {
+		for next < len(activity) && !activity[next].Date.After(point.End) {
+			point.Change += activity[next].Amount
+			next++
+		}
+		balance += point.Change
+		point.Balance = balance
+	}
+}
+
+// blankPoints returns points over the same periods, with no balance
+func blankPoints(points []*BalancePoint) []*BalancePoint {
+	blank := make([]*BalancePoint, len(points))
+	for i, point := range points {
+		blank[i] = &BalancePoint{Start: point.Start, End: point.End}
+	}
+	return blank
+}
+
+// addPoints adds the points of an account to the household ones (same periods)
+func addPoints(total, points []*BalancePoint) {
+	for i, point := range points {
+		total[i].Balance += point.Balance
+		total[i].Change += point.Change
+	}
+}
@@ -0,0 +1,122 @@
+package accounts
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blanquicet/conti/backend/internal/money"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestHistoryPeriods(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to time.Time
+		interval Interval
+		want     [][2]time.Time
+	}{
+		{"days", date(2026, time.March, 30), date(2026, time.April, 1), IntervalDay, [][2]time.Time{
+			{date(2026, time.March, 30), date(2026, time.March, 30)},
+			{date(2026, time.March, 31), date(2026, time.March, 31)},
+			{date(2026, time.April, 1), date(2026, time.April, 1)},
+		}},
+		{"weeks end on sunday", date(2026, time.March, 4), date(2026, time.March, 16), IntervalWeek, [][2]time.Time{
+			{date(2026, time.March, 4), date(2026, time.March, 8)},
+			{date(2026, time.March, 9), date(2026, time.March, 15)},
+			{date(2026, time.March, 16), date(2026, time.March, 16)},
+		}},
+		{"months clamped to the range", date(2026, time.January, 15), date(2026, time.March, 10), IntervalMonth, [][2]time.Time{
+			{date(2026, time.January, 15), date(2026, time.January, 31)},
+			{date(2026, time.February, 1), date(2026, time.February, 28)},
+			{date(2026, time.March, 1), date(2026, time.March, 10)},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := HistoryInput{From: &tt.from, To: &tt.to, Interval: tt.interval}
+			points, err := input.periods(tt.to)
+			if err != nil {
+				t.Fatalf("periods() error = %v", err)
+			}
+			if len(points) != len(tt.want) {
+				t.Fatalf("periods() returned %d points, want %d", len(points), len(tt.want))
+			}
+			for i, point := range points {
+				if !point.Start.Equal(tt.want[i][0]) || !point.End.Equal(tt.want[i][1]) {
+					t.Errorf("point %d = %s..%s, want %s..%s", i,
+						point.Start.Format("2006-01-02"), point.End.Format("2006-01-02"),
+						tt.want[i][0].Format("2006-01-02"), tt.want[i][1].Format("2006-01-02"))
+				}
+			}
+		})
+	}
+}
+
+func TestHistoryPeriods_Defaults(t *testing.T) {
+	input := HistoryInput{}
+	points, err := input.periods(time.Date(2026, time.April, 10, 15, 30, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("periods() error = %v", err)
+	}
+	if input.Interval != IntervalMonth || !input.From.Equal(date(2025, time.April, 11)) || !input.To.Equal(date(2026, time.April, 10)) {
+		t.Errorf("defaults = %s %s..%s", input.Interval, input.From.Format("2006-01-02"), input.To.Format("2006-01-02"))
+	}
+	if len(points) != 13 {
+		t.Errorf("periods() returned %d points, want 13", len(points))
+	}
+}
+
+func TestHistoryPeriods_Invalid(t *testing.T) {
+	from, to := date(2026, time.April, 2), date(2026, time.April, 1)
+	longFrom := date(2020, time.January, 1)
+	tests := []struct {
+		name  string
+		input HistoryInput
+		want  error
+	}{
+		{"unknown interval", HistoryInput{Interval: "year"}, ErrInvalidInterval},
+		{"from after to", HistoryInput{From: &from, To: &to}, ErrInvalidHistoryRange},
+		{"too many days", HistoryInput{From: &longFrom, To: &to, Interval: IntervalDay}, ErrHistoryTooLong},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.input.periods(to); err != tt.want {
+				t.Errorf("periods() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestReplay(t *testing.T) {
+	from, to := date(2026, time.January, 1), date(2026, time.March, 31)
+	input := HistoryInput{From: &from, To: &to, Interval: IntervalMonth}
+	points, err := input.periods(to)
+	if err != nil {
+		t.Fatalf("periods() error = %v", err)
+	}
+
+	replay(points, money.New(1000), []*Activity{
+		{Kind: ActivityIncome, Date: date(2026, time.January, 5), Amount: money.New(500)},
+		{Kind: ActivityMovement, Date: date(2026, time.January, 31), Amount: money.New(-200)},
+		{Kind: ActivityCardPayment, Date: date(2026, time.March, 1), Amount: money.New(-300)},
+	})
+
+	want := []struct{ balance, change int64 }{{1300, 300}, {1300, 0}, {1000, -300}}
+	for i, point := range points {
+		if point.Balance != money.New(want[i].balance) || point.Change != money.New(want[i].change) {
+			t.Errorf("point %d = balance %v change %v, want %d %d", i, point.Balance, point.Change, want[i].balance, want[i].change)
+		}
+	}
+
+	total := blankPoints(points)
+	addPoints(total, points)
+	addPoints(total, points)
+	if total[2].Balance != money.New(2000) || total[0].Change != money.New(600) {
+		t.Errorf("addPoints() = %v %v, want 2000 600", total[2].Balance, total[0].Change)
+	}
+}
@@ -3,10 +3,13 @@ package accounts
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/blanquicet/conti/backend/internal/money"
 )
 
 // repository implements Repository using PostgreSQL
@@ -278,20 +281,26 @@ func (r *repository) FindByName(ctx context.Context, householdID, name string) (
 }
 
 // GetBalance calculates the current balance of an account
-// Current balance = initial_balance + SUM(income) + SUM(DEBT_PAYMENTs received) - SUM(movements via debit cards) - SUM(credit card payments)
-func (r *repository) GetBalance(ctx context.Context, id string) (float64, error) {
-	var balance float64
+// Current balance = initial_balance + SUM(income) + SUM(DEBT_PAYMENTs and TRANSFERs received) - SUM(movements via debit cards)
+// - SUM(TRANSFERs sent) - SUM(credit card payments) + SUM(checkpoint adjustments)
+// Movements count in the household currency (base_amount), refunds through a payment method give money back
+func (r *repository) GetBalance(ctx context.Context, id string) (money.Amount, error) {
+	var balance money.Amount
 	err := r.pool.QueryRow(ctx, `
 		SELECT 
 			a.initial_balance 
-			+ COALESCE((SELECT SUM(i.amount) FROM income i WHERE i.account_id = a.id), 0)
-			+ COALESCE((SELECT SUM(m.amount) FROM movements m 
-			            WHERE m.receiver_account_id = a.id), 0)
-			- COALESCE((SELECT SUM(m.amount) FROM movements m 
+			+ COALESCE((SELECT SUM(i.amount) FROM income i WHERE i.account_id = a.id AND i.deleted_at IS NULL), 0)
+			+ COALESCE((SELECT SUM(m.base_amount) FROM movements m 
+			            WHERE m.receiver_account_id = a.id AND m.deleted_at IS NULL), 0)
+			- COALESCE((SELECT SUM(m.signed_base_amount) FROM movements m 
 			            JOIN payment_methods pm ON m.payment_method_id = pm.id 
-			            WHERE COALESCE(pm.linked_account_id, pm.account_id) = a.id), 0)
-			- COALESCE((SELECT SUM(ccp.amount) FROM credit_card_payments ccp 
-			            WHERE ccp.source_account_id = a.id), 0)
+			            WHERE COALESCE(pm.linked_account_id, pm.account_id) = a.id AND m.deleted_at IS NULL), 0)
+			- COALESCE((SELECT SUM(m.base_amount) FROM movements m 
+			            WHERE m.source_account_id = a.id AND m.deleted_at IS NULL), 0)
+			- COALESCE((SELECT SUM(s.amount) FROM credit_card_payment_sources s 
+			            WHERE s.account_id = a.id), 0)
+			+ COALESCE((SELECT SUM(c.adjustment) FROM account_checkpoints c 
+			            WHERE c.account_id = a.id), 0)
 			as current_balance
 		FROM accounts a
 		WHERE a.id = $1
@@ -306,3 +315,203 @@ func (r *repository) GetBalance(ctx context.Context, id string) (float64, error)
 
 	return balance, nil
 }
+
+// accountActivity lists every entry that changes the balance of account $1,
+// signed so that it adds to the balance. Same terms as GetBalance.
+const accountActivity = `
+	SELECT 'INCOME'::text AS kind, i.id, i.income_date AS date, COALESCE(i.description, '') AS description, i.amount
+	FROM income i
+	WHERE i.account_id = $1 AND i.deleted_at IS NULL
+	UNION ALL
+	SELECT 'MOVEMENT', m.id, m.movement_date, COALESCE(m.description, ''), m.base_amount
+	FROM movements m
+	WHERE m.receiver_account_id = $1 AND m.deleted_at IS NULL
+	UNION ALL
+	SELECT 'MOVEMENT', m.id, m.movement_date, COALESCE(m.description, ''), -m.signed_base_amount
+	FROM movements m
+	JOIN payment_methods pm ON m.payment_method_id = pm.id
+	WHERE COALESCE(pm.linked_account_id, pm.account_id) = $1 AND m.deleted_at IS NULL
+	UNION ALL
+	SELECT 'MOVEMENT', m.id, m.movement_date, COALESCE(m.description, ''), -m.base_amount
+	FROM movements m
+	WHERE m.source_account_id = $1 AND m.deleted_at IS NULL
+	UNION ALL
+	SELECT 'CARD_PAYMENT', p.id, p.payment_date, pm.name, -s.amount
+	FROM credit_card_payment_sources s
+	JOIN credit_card_payments p ON s.payment_id = p.id
+	JOIN payment_methods pm ON p.credit_card_id = pm.id
+	WHERE s.account_id = $1
+	UNION ALL
+	SELECT 'ADJUSTMENT', c.id, c.checkpoint_date, COALESCE(c.notes, ''), c.adjustment
+	FROM account_checkpoints c
+	WHERE c.account_id = $1 AND c.adjustment <> 0
+`
+
+// GetBalanceAt calculates the balance of an account at the end of a date
+func (r *repository) GetBalanceAt(ctx context.Context, id string, asOf time.Time) (money.Amount, error) {
+	var balance money.Amount
+	err := r.pool.QueryRow(ctx, `
+		SELECT a.initial_balance
+			+ COALESCE((SELECT SUM(act.amount) FROM (`+accountActivity+`) act WHERE act.date <= $2), 0)
+		FROM accounts a
+		WHERE a.id = $1
+	`, id, asOf).Scan(&balance)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, ErrAccountNotFound
+		}
+		return 0, err
+	}
+
+	return balance, nil
+}
+
+// ListActivity lists what changed the balance of an account after a date (from
+// the start when nil) and up to another, oldest first
+func (r *repository) ListActivity(ctx context.Context, id string, after *time.Time, upTo time.Time) ([]*Activity, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT act.kind, act.id, act.date, act.description, act.amount
+		FROM (`+accountActivity+`) act
+		WHERE ($2::date IS NULL OR act.date > $2) AND act.date <= $3
+		ORDER BY act.date, act.kind, act.id
+	`, id, after, upTo)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	activity := make([]*Activity, 0)
+	for rows.Next() {
+		var a Activity
+		if err := rows.Scan(&a.Kind, &a.ID, &a.Date, &a.Description, &a.Amount); err != nil {
+			return nil, err
+		}
+		activity = append(activity, &a)
+	}
+
+	return activity, rows.Err()
+}
+
+const checkpointColumns = `
+	id, household_id, account_id, checkpoint_date, reported_balance, adjustment,
+	adjusted_at, notes, created_by, created_at
+`
+
+func scanCheckpoint(row pgx.Row) (*Checkpoint, error) {
+	var c Checkpoint
+	err := row.Scan(
+		&c.ID,
+		&c.HouseholdID,
+		&c.AccountID,
+		&c.Date,
+		&c.ReportedBalance,
+		&c.Adjustment,
+		&c.AdjustedAt,
+		&c.Notes,
+		&c.CreatedBy,
+		&c.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// CreateCheckpoint creates a balance checkpoint
+func (r *repository) CreateCheckpoint(ctx context.Context, checkpoint *Checkpoint) (*Checkpoint, error) {
+	created, err := scanCheckpoint(r.pool.QueryRow(ctx, `
+		INSERT INTO account_checkpoints (household_id, account_id, checkpoint_date, reported_balance, notes, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING `+checkpointColumns,
+		checkpoint.HouseholdID,
+		checkpoint.AccountID,
+		checkpoint.Date,
+		checkpoint.ReportedBalance,
+		checkpoint.Notes,
+		checkpoint.CreatedBy,
+	))
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, ErrCheckpointExists
+		}
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// GetCheckpoint retrieves a checkpoint by ID
+func (r *repository) GetCheckpoint(ctx context.Context, id string) (*Checkpoint, error) {
+	checkpoint, err := scanCheckpoint(r.pool.QueryRow(ctx, `
+		SELECT `+checkpointColumns+`
+		FROM account_checkpoints
+		WHERE id = $1
+	`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCheckpointNotFound
+		}
+		return nil, err
+	}
+
+	return checkpoint, nil
+}
+
+// ListCheckpoints lists the checkpoints of an account, latest first
+func (r *repository) ListCheckpoints(ctx context.Context, accountID string) ([]*Checkpoint, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+checkpointColumns+`
+		FROM account_checkpoints
+		WHERE account_id = $1
+		ORDER BY checkpoint_date DESC
+	`, accountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	checkpoints := make([]*Checkpoint, 0)
+	for rows.Next() {
+		checkpoint, err := scanCheckpoint(rows)
+		if err != nil {
+			return nil, err
+		}
+		checkpoints = append(checkpoints, checkpoint)
+	}
+
+	return checkpoints, rows.Err()
+}
+
+// SetCheckpointAdjustment sets what a checkpoint adds to the account balance
+func (r *repository) SetCheckpointAdjustment(ctx context.Context, id string, adjustment money.Amount) error {
+	result, err := r.pool.Exec(ctx, `
+		UPDATE account_checkpoints
+		SET adjustment = $2, adjusted_at = NOW(), updated_at = NOW()
+		WHERE id = $1
+	`, id, adjustment)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrCheckpointNotFound
+	}
+
+	return nil
+}
+
+// DeleteCheckpoint deletes a checkpoint and its adjustment
+func (r *repository) DeleteCheckpoint(ctx context.Context, id string) error {
+	result, err := r.pool.Exec(ctx, `DELETE FROM account_checkpoints WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrCheckpointNotFound
+	}
+
+	return nil
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"strings"
+	"time"
 
 	"github.com/blanquicet/conti/backend/internal/audit"
+	"github.com/blanquicet/conti/backend/internal/money"
 )
 
 // Service handles account business logic
@@ -30,7 +32,7 @@ type CreateInput struct {
 	Type           AccountType
 	Institution    *string
 	Last4          *string
-	InitialBalance *float64 // Optional, defaults to 0
+	InitialBalance *money.Amount // Optional, defaults to 0
 	Notes          *string
 }
 
@@ -83,7 +85,7 @@ func (s *Service) Create(ctx context.Context, input CreateInput) (*Account, erro
 	}
 
 	// Set default initial balance if not provided
-	initialBalance := 0.0
+	var initialBalance money.Amount
 	if input.InitialBalance != nil {
 		initialBalance = *input.InitialBalance
 	}
@@ -129,7 +131,7 @@ type UpdateInput struct {
 	Name           *string
 	Institution    *string
 	Last4          *string
-	InitialBalance *float64
+	InitialBalance *money.Amount
 	Notes          *string
 }
 
@@ -301,3 +303,343 @@ func (s *Service) Delete(ctx context.Context, id, householdID string) error {
 
 	return nil
 }
+
+// CheckpointInput contains the data needed to record a balance checkpoint
+type CheckpointInput struct {
+	Date    *time.Time    // Optional, defaults to today
+	Balance *money.Amount // The real balance at the end of Date
+	Notes   *string
+}
+
+// CreateCheckpoint records the real balance of an account on a date and
+// reconciles it against the derived balance
+func (s *Service) CreateCheckpoint(ctx context.Context, householdID, userID, accountID string, input CheckpointInput) (*Reconciliation, error) {
+	if input.Balance == nil {
+		return nil, ErrCheckpointBalanceRequired
+	}
+
+	if _, err := s.GetByID(ctx, accountID, householdID); err != nil {
+		return nil, err
+	}
+
+	today := dateOf(time.Now())
+	date := today
+	if input.Date != nil {
+		date = dateOf(*input.Date)
+	}
+	if date.After(today) {
+		return nil, ErrCheckpointInFuture
+	}
+
+	if input.Notes != nil {
+		*input.Notes = strings.TrimSpace(*input.Notes)
+		if *input.Notes == "" {
+			input.Notes = nil
+		}
+	}
+
+	created, err := s.repo.CreateCheckpoint(ctx, &Checkpoint{
+		HouseholdID:     householdID,
+		AccountID:       accountID,
+		Date:            date,
+		ReportedBalance: *input.Balance,
+		Notes:           input.Notes,
+		CreatedBy:       &userID,
+	})
+	if err != nil {
+		s.auditService.LogAsync(ctx, &audit.LogInput{
+			UserID:       audit.StringPtr(userID),
+			Action:       audit.ActionAccountCheckpointCreated,
+			ResourceType: "account_checkpoint",
+			HouseholdID:  audit.StringPtr(householdID),
+			Success:      false,
+			ErrorMessage: audit.StringPtr(err.Error()),
+		})
+		return nil, err
+	}
+
+	reconciliation, err := s.reconcile(ctx, created)
+	if err != nil {
+		return nil, err
+	}
+
+	s.auditService.LogAsync(ctx, &audit.LogInput{
+		UserID:       audit.StringPtr(userID),
+		Action:       audit.ActionAccountCheckpointCreated,
+		ResourceType: "account_checkpoint",
+		ResourceID:   audit.StringPtr(created.ID),
+		HouseholdID:  audit.StringPtr(householdID),
+		Success:      true,
+		NewValues:    audit.StructToMap(created),
+	})
+
+	return reconciliation, nil
+}
+
+// ListCheckpoints lists the checkpoints of an account with their discrepancy, latest first
+func (s *Service) ListCheckpoints(ctx context.Context, accountID, householdID string) ([]*Checkpoint, error) {
+	if _, err := s.GetByID(ctx, accountID, householdID); err != nil {
+		return nil, err
+	}
+
+	checkpoints, err := s.repo.ListCheckpoints(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, checkpoint := range checkpoints {
+		if err := s.deriveBalance(ctx, checkpoint); err != nil {
+			return nil, err
+		}
+	}
+
+	return checkpoints, nil
+}
+
+// GetReconciliation returns a checkpoint with the activity since the previous one
+func (s *Service) GetReconciliation(ctx context.Context, checkpointID, accountID, householdID string) (*Reconciliation, error) {
+	checkpoint, err := s.getCheckpoint(ctx, checkpointID, accountID, householdID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.reconcile(ctx, checkpoint)
+}
+
+// AdjustCheckpoint closes the gap between the real and the derived balance at
+// a checkpoint, adding the discrepancy to its adjustment
+func (s *Service) AdjustCheckpoint(ctx context.Context, householdID, userID, accountID, checkpointID string) (*Reconciliation, error) {
+	checkpoint, err := s.getCheckpoint(ctx, checkpointID, accountID, householdID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.deriveBalance(ctx, checkpoint); err != nil {
+		return nil, err
+	}
+	if checkpoint.Discrepancy == 0 {
+		return nil, ErrNothingToAdjust
+	}
+
+	oldValues := audit.StructToMap(checkpoint)
+	adjustment := checkpoint.Adjustment + checkpoint.Discrepancy
+
+	if err := s.repo.SetCheckpointAdjustment(ctx, checkpointID, adjustment); err != nil {
+		s.auditService.LogAsync(ctx, &audit.LogInput{
+			UserID:       audit.StringPtr(userID),
+			Action:       audit.ActionAccountBalanceAdjusted,
+			ResourceType: "account_checkpoint",
+			ResourceID:   audit.StringPtr(checkpointID),
+			HouseholdID:  audit.StringPtr(householdID),
+			Success:      false,
+			ErrorMessage: audit.StringPtr(err.Error()),
+		})
+		return nil, err
+	}
+
+	adjusted, err := s.repo.GetCheckpoint(ctx, checkpointID)
+	if err != nil {
+		return nil, err
+	}
+
+	reconciliation, err := s.reconcile(ctx, adjusted)
+	if err != nil {
+		return nil, err
+	}
+
+	s.auditService.LogAsync(ctx, &audit.LogInput{
+		UserID:       audit.StringPtr(userID),
+		Action:       audit.ActionAccountBalanceAdjusted,
+		ResourceType: "account_checkpoint",
+		ResourceID:   audit.StringPtr(checkpointID),
+		HouseholdID:  audit.StringPtr(householdID),
+		Success:      true,
+		OldValues:    oldValues,
+		NewValues:    audit.StructToMap(adjusted),
+	})
+
+	return reconciliation, nil
+}
+
+// DeleteCheckpoint deletes a checkpoint, undoing its adjustment
+func (s *Service) DeleteCheckpoint(ctx context.Context, householdID, userID, accountID, checkpointID string) error {
+	checkpoint, err := s.getCheckpoint(ctx, checkpointID, accountID, householdID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteCheckpoint(ctx, checkpointID); err != nil {
+		s.auditService.LogAsync(ctx, &audit.LogInput{
+			UserID:       audit.StringPtr(userID),
+			Action:       audit.ActionAccountCheckpointDeleted,
+			ResourceType: "account_checkpoint",
+			ResourceID:   audit.StringPtr(checkpointID),
+			HouseholdID:  audit.StringPtr(householdID),
+			Success:      false,
+			ErrorMessage: audit.StringPtr(err.Error()),
+		})
+		return err
+	}
+
+	s.auditService.LogAsync(ctx, &audit.LogInput{
+		UserID:       audit.StringPtr(userID),
+		Action:       audit.ActionAccountCheckpointDeleted,
+		ResourceType: "account_checkpoint",
+		ResourceID:   audit.StringPtr(checkpointID),
+		HouseholdID:  audit.StringPtr(householdID),
+		Success:      true,
+		OldValues:    audit.StructToMap(checkpoint),
+	})
+
+	return nil
+}
+
+// getCheckpoint retrieves a checkpoint of an account in the household
+func (s *Service) getCheckpoint(ctx context.Context, checkpointID, accountID, householdID string) (*Checkpoint, error) {
+	if _, err := s.GetByID(ctx, accountID, householdID); err != nil {
+		return nil, err
+	}
+
+	checkpoint, err := s.repo.GetCheckpoint(ctx, checkpointID)
+	if err != nil {
+		return nil, err
+	}
+	if checkpoint.AccountID != accountID {
+		return nil, ErrCheckpointNotFound
+	}
+
+	return checkpoint, nil
+}
+
+// deriveBalance fills the balance derived from movements at a checkpoint and
+// how far the real one is from it
+func (s *Service) deriveBalance(ctx context.Context, checkpoint *Checkpoint) error {
+	derived, err := s.repo.GetBalanceAt(ctx, checkpoint.AccountID, checkpoint.Date)
+	if err != nil {
+		return err
+	}
+	checkpoint.DerivedBalance = derived
+	checkpoint.Discrepancy = checkpoint.ReportedBalance - derived
+	return nil
+}
+
+// reconcile returns a checkpoint with the activity since the previous one
+func (s *Service) reconcile(ctx context.Context, checkpoint *Checkpoint) (*Reconciliation, error) {
+	if err := s.deriveBalance(ctx, checkpoint); err != nil {
+		return nil, err
+	}
+
+	checkpoints, err := s.repo.ListCheckpoints(ctx, checkpoint.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	reconciliation := &Reconciliation{Checkpoint: checkpoint}
+	var after *time.Time
+	if previous := previousCheckpoint(checkpoints, checkpoint.Date); previous != nil {
+		if err := s.deriveBalance(ctx, previous); err != nil {
+			return nil, err
+		}
+		reconciliation.Previous = previous
+		after = &previous.Date
+	}
+
+	reconciliation.Activity, err = s.repo.ListActivity(ctx, checkpoint.AccountID, after, checkpoint.Date)
+	if err != nil {
+		return nil, err
+	}
+	for _, activity := range reconciliation.Activity {
+		reconciliation.ActivityTotal += activity.Amount
+	}
+
+	return reconciliation, nil
+}
+
+// previousCheckpoint returns the latest checkpoint before a date, or nil
+func previousCheckpoint(checkpoints []*Checkpoint, date time.Time) *Checkpoint {
+	var previous *Checkpoint
+	for _, checkpoint := range checkpoints {
+		if checkpoint.Date.Before(date) && (previous == nil || checkpoint.Date.After(previous.Date)) {
+			previous = checkpoint
+		}
+	}
+	return previous
+}
+
+// dateOf returns the date of t at midnight UTC
+func dateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// GetBalanceHistory returns the balance of an account at the end of each
+// period of a range, replaying its activity from the balance before it
+func (s *Service) GetBalanceHistory(ctx context.Context, accountID, householdID string, input HistoryInput) (*BalanceHistory, error) {
+	account, err := s.GetByID(ctx, accountID, householdID)
+	if err != nil {
+		return nil, err
+	}
+
+	points, err := input.periods(time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return s.balanceHistory(ctx, account, input, points)
+}
+
+// GetHouseholdBalanceHistory returns the balance history of every account in
+// the household and their total
+func (s *Service) GetHouseholdBalanceHistory(ctx context.Context, householdID string, input HistoryInput) (*HouseholdBalanceHistory, error) {
+	points, err := input.periods(time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	accounts, err := s.repo.ListByHousehold(ctx, householdID)
+	if err != nil {
+		return nil, err
+	}
+
+	history := &HouseholdBalanceHistory{
+		Interval: input.Interval,
+		From:     *input.From,
+		To:       *input.To,
+		Points:   points,
+		Accounts: make([]*BalanceHistory, 0, len(accounts)),
+	}
+	for _, account := range accounts {
+		accountHistory, err := s.balanceHistory(ctx, account, input, blankPoints(points))
+		if err != nil {
+			return nil, err
+		}
+		addPoints(history.Points, accountHistory.Points)
+		history.Accounts = append(history.Accounts, accountHistory)
+	}
+
+	return history, nil
+}
+
+// balanceHistory fills the points of an account (input already validated)
+func (s *Service) balanceHistory(ctx context.Context, account *Account, input HistoryInput, points []*BalancePoint) (*BalanceHistory, error) {
+	before := input.From.AddDate(0, 0, -1)
+	opening, err := s.repo.GetBalanceAt(ctx, account.ID, before)
+	if err != nil {
+		return nil, err
+	}
+
+	activity, err := s.repo.ListActivity(ctx, account.ID, &before, *input.To)
+	if err != nil {
+		return nil, err
+	}
+
+	replay(points, opening, activity)
+
+	return &BalanceHistory{
+		AccountID:   account.ID,
+		AccountName: account.Name,
+		Interval:    input.Interval,
+		From:        *input.From,
+		To:          *input.To,
+		Points:      points,
+	}, nil
+}
@@ -4,16 +4,23 @@ import (
 	"context"
 	"errors"
 	"time"
+
+	"github.com/blanquicet/conti/backend/internal/money"
 )
 
 // Errors for account operations
 var (
-	ErrAccountNotFound    = errors.New("account not found")
-	ErrAccountNameExists  = errors.New("account name already exists in household")
-	ErrNotAuthorized      = errors.New("not authorized")
-	ErrInvalidAccountType = errors.New("invalid account type")
-	ErrAccountHasIncome   = errors.New("cannot delete account with income entries")
+	ErrAccountNotFound                = errors.New("account not found")
+	ErrAccountNameExists              = errors.New("account name already exists in household")
+	ErrNotAuthorized                  = errors.New("not authorized")
+	ErrInvalidAccountType             = errors.New("invalid account type")
+	ErrAccountHasIncome               = errors.New("cannot delete account with income entries")
 	ErrAccountHasLinkedPaymentMethods = errors.New("cannot delete account with linked payment methods")
+	ErrCheckpointNotFound             = errors.New("checkpoint not found")
+	ErrCheckpointExists               = errors.New("the account already has a checkpoint on that date")
+	ErrCheckpointBalanceRequired      = errors.New("balance is required")
+	ErrCheckpointInFuture             = errors.New("checkpoint date cannot be in the future")
+	ErrNothingToAdjust                = errors.New("the checkpoint has no discrepancy to adjust")
 )
 
 // AccountType represents the type of account
@@ -40,6 +47,11 @@ func (t AccountType) CanReceiveIncome() bool {
 	return t == TypeSavings || t == TypeCash
 }
 
+// HoldsFunds returns true if money can be kept in (and paid from) this account type
+func (t AccountType) HoldsFunds() bool {
+	return t == TypeSavings || t == TypeCash || t == TypeChecking
+}
+
 // Account represents a bank account or cash reserve
 type Account struct {
 	ID             string       `json:"id"`
@@ -50,15 +62,15 @@ type Account struct {
 	Type           AccountType  `json:"type"`
 	Institution    *string      `json:"institution,omitempty"`
 	Last4          *string      `json:"last4,omitempty"`
-	InitialBalance float64      `json:"initial_balance"`
+	InitialBalance money.Amount `json:"initial_balance"`
 	Notes          *string      `json:"notes,omitempty"`
 	CreatedAt      time.Time    `json:"created_at"`
 	UpdatedAt      time.Time    `json:"updated_at"`
-	
+
 	// Calculated fields (not in DB)
-	CurrentBalance *float64     `json:"current_balance,omitempty"`
-	IncomeTotal    *float64     `json:"income_total,omitempty"`
-	ExpenseTotal   *float64     `json:"expense_total,omitempty"`
+	CurrentBalance *money.Amount `json:"current_balance,omitempty"`
+	IncomeTotal    *money.Amount `json:"income_total,omitempty"`
+	ExpenseTotal   *money.Amount `json:"expense_total,omitempty"`
 }
 
 // Validate validates account fields
@@ -81,6 +93,52 @@ func (a *Account) Validate() error {
 	return nil
 }
 
+// Checkpoint is the real balance of an account on a date (what the bank says),
+// to compare with the balance derived from its movements
+type Checkpoint struct {
+	ID              string       `json:"id"`
+	HouseholdID     string       `json:"household_id"`
+	AccountID       string       `json:"account_id"`
+	Date            time.Time    `json:"date"`
+	ReportedBalance money.Amount `json:"reported_balance"`
+	Adjustment      money.Amount `json:"adjustment"` // Added to the account balance from Date on
+	AdjustedAt      *time.Time   `json:"adjusted_at,omitempty"`
+	Notes           *string      `json:"notes,omitempty"`
+	CreatedBy       *string      `json:"created_by,omitempty"`
+	CreatedAt       time.Time    `json:"created_at"`
+
+	// Calculated fields (not in DB)
+	DerivedBalance money.Amount `json:"derived_balance"` // As of Date, adjustments included
+	Discrepancy    money.Amount `json:"discrepancy"`     // Reported - derived
+}
+
+// ActivityKind is what changed an account balance
+type ActivityKind string
+
+const (
+	ActivityIncome      ActivityKind = "INCOME"
+	ActivityMovement    ActivityKind = "MOVEMENT" // Spent with a linked card or cash, or received (debt payments, transfers)
+	ActivityCardPayment ActivityKind = "CARD_PAYMENT"
+	ActivityAdjustment  ActivityKind = "ADJUSTMENT"
+)
+
+// Activity is an entry that changed an account balance
+type Activity struct {
+	Kind        ActivityKind `json:"kind"`
+	ID          string       `json:"id"` // Of the income, movement, card payment or checkpoint
+	Date        time.Time    `json:"date"`
+	Description string       `json:"description"`
+	Amount      money.Amount `json:"amount"` // Positive adds to the balance
+}
+
+// Reconciliation is a checkpoint with the activity since the previous one
+type Reconciliation struct {
+	Checkpoint    *Checkpoint  `json:"checkpoint"`
+	Previous      *Checkpoint  `json:"previous,omitempty"`
+	Activity      []*Activity  `json:"activity"` // After the previous checkpoint, up to this one
+	ActivityTotal money.Amount `json:"activity_total"`
+}
+
 // Repository defines the interface for account persistence
 type Repository interface {
 	Create(ctx context.Context, account *Account) (*Account, error)
@@ -89,5 +147,14 @@ type Repository interface {
 	Delete(ctx context.Context, id string) error
 	ListByHousehold(ctx context.Context, householdID string) ([]*Account, error)
 	FindByName(ctx context.Context, householdID, name string) (*Account, error)
-	GetBalance(ctx context.Context, id string) (float64, error)
+	GetBalance(ctx context.Context, id string) (money.Amount, error)
+
+	// Checkpoints
+	GetBalanceAt(ctx context.Context, id string, asOf time.Time) (money.Amount, error)
+	ListActivity(ctx context.Context, id string, after *time.Time, upTo time.Time) ([]*Activity, error)
+	CreateCheckpoint(ctx context.Context, checkpoint *Checkpoint) (*Checkpoint, error)
+	GetCheckpoint(ctx context.Context, id string) (*Checkpoint, error)
+	ListCheckpoints(ctx context.Context, accountID string) ([]*Checkpoint, error)
+	SetCheckpointAdjustment(ctx context.Context, id string, adjustment money.Amount) error
+	DeleteCheckpoint(ctx context.Context, id string) error
 }
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"strings"
 	"time"
+
+	"github.com/blanquicet/conti/backend/internal/money"
 )
 
 // Bogota is the timezone for Colombia (UTC-5).
@@ -42,13 +44,11 @@ func CurrentMonth() string {
 
 // FormatCOP formats an amount as Colombian pesos with thousands separator.
 // Examples: 345000 → "$345.000", 1234567.50 → "$1.234.568"
-func FormatCOP(amount float64) string {
+func FormatCOP(amount money.Amount) string {
 	negative := amount < 0
-	if negative {
-		amount = -amount
-	}
+	minor := amount.Abs().Minor()
 
-	rounded := int64(amount + 0.5)
+	rounded := (minor + money.Scale/2) / money.Scale
 	s := fmt.Sprintf("%d", rounded)
 
 	// Insert thousands separators (dots)
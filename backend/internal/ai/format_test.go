@@ -3,6 +3,8 @@ package ai
 import (
 	"testing"
 	"time"
+
+	"github.com/blanquicet/conti/backend/internal/money"
 )
 
 func TestFormatCOP(t *testing.T) {
@@ -23,7 +25,7 @@ func TestFormatCOP(t *testing.T) {
 	}
 
 	for _, tt := range tests {
-		got := FormatCOP(tt.amount)
+		got := FormatCOP(money.FromFloat(tt.amount))
 		if got != tt.expected {
 			t.Errorf("FormatCOP(%v) = %q, want %q", tt.amount, got, tt.expected)
 		}
@@ -201,6 +201,11 @@ func (h *Handler) HandleCreateMovement(w http.ResponseWriter, r *http.Request) {
 		input.ReceiverAccountID = &draft.ReceiverAccountID
 	}
 
+	// Event (set by a household rule)
+	if draft.EventID != "" {
+		input.EventID = &draft.EventID
+	}
+
 	// Participants (for SPLIT)
 	for _, p := range draft.Participants {
 		pi := movements.ParticipantInput{Percentage: p.Percentage}
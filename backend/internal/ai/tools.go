@@ -14,6 +14,7 @@ import (
 	"github.com/blanquicet/conti/backend/internal/categorygroups"
 	"github.com/blanquicet/conti/backend/internal/households"
 	"github.com/blanquicet/conti/backend/internal/income"
+	"github.com/blanquicet/conti/backend/internal/money"
 	"github.com/blanquicet/conti/backend/internal/movements"
 	"github.com/blanquicet/conti/backend/internal/paymentmethods"
 )
@@ -29,6 +30,7 @@ type ToolExecutor struct {
 	paymentMethodRepo paymentmethods.Repository
 	householdRepo     households.HouseholdRepository
 	accountsRepo      accounts.Repository
+	ruleApplier       movements.RuleApplier // Optional, household categorization rules
 }
 
 // NewToolExecutor creates a new tool executor backed by existing services.
@@ -41,6 +43,7 @@ func NewToolExecutor(
 	paymentMethodRepo paymentmethods.Repository,
 	householdRepo households.HouseholdRepository,
 	accountsRepo accounts.Repository,
+	ruleApplier movements.RuleApplier,
 ) *ToolExecutor {
 	return &ToolExecutor{
 		movementsService:  movementsService,
@@ -51,6 +54,7 @@ func NewToolExecutor(
 		paymentMethodRepo: paymentMethodRepo,
 		householdRepo:     householdRepo,
 		accountsRepo:      accountsRepo,
+		ruleApplier:       ruleApplier,
 	}
 }
 
@@ -305,8 +309,8 @@ func (te *ToolExecutor) getMovementsSummary(ctx context.Context, userID string,
 	type catSummary struct {
 		Group string  `json:"group"`
 		Name  string  `json:"name"`
-		Total float64 `json:"total"`
-		Count int     `json:"count"`
+		Total money.Amount `json:"total"`
+		Count int          `json:"count"`
 	}
 	catMap := make(map[string]*catSummary)
 
@@ -331,12 +335,12 @@ func (te *ToolExecutor) getMovementsSummary(ctx context.Context, userID string,
 		if _, ok := catMap[key]; !ok {
 			catMap[key] = &catSummary{Group: groupName, Name: catName}
 		}
-		catMap[key].Total += m.Amount
+		catMap[key].Total += m.NetHouseholdAmount()
 		catMap[key].Count++
 	}
 
 	var categories []catSummary
-	var grandTotal float64
+	var grandTotal money.Amount
 	var grandCount int
 	for _, cs := range catMap {
 		categories = append(categories, *cs)
@@ -348,6 +352,9 @@ func (te *ToolExecutor) getMovementsSummary(ctx context.Context, userID string,
 	// Top evidence (largest movements matching filter)
 	var filtered []*movements.Movement
 	for _, m := range allMovements {
+		if m.IsRefund() {
+			continue
+		}
 		if categoryFilter != "" {
 			groupName := ""
 			catName := ""
@@ -430,10 +437,10 @@ func (te *ToolExecutor) getBudgetStatus(ctx context.Context, userID string, args
 	type budgetRow struct {
 		Group    string  `json:"group"`
 		Category string  `json:"category"`
-		Budget   float64 `json:"budget"`
-		Spent    float64 `json:"spent"`
-		Diff     float64 `json:"difference"`
-		Status   string  `json:"status"`
+		Budget   money.Amount `json:"budget"`
+		Spent    money.Amount `json:"spent"`
+		Diff     money.Amount `json:"difference"`
+		Status   string       `json:"status"`
 	}
 
 	var rows []budgetRow
@@ -492,7 +499,13 @@ func (te *ToolExecutor) getTopExpenses(ctx context.Context, userID string, args
 		return nil, err
 	}
 
-	all := append(resp.Movements, splitResp.Movements...)
+	// Refunds aren't expenses
+	var all []*movements.Movement
+	for _, m := range append(resp.Movements, splitResp.Movements...) {
+		if !m.IsRefund() {
+			all = append(all, m)
+		}
+	}
 	sort.Slice(all, func(i, j int) bool { return all[i].Amount > all[j].Amount })
 	if len(all) > limit {
 		all = all[:limit]
@@ -515,7 +528,7 @@ func (te *ToolExecutor) compareMonths(ctx context.Context, userID string, args m
 	month2 := getString(args, "month2")
 	categoryFilter := getString(args, "category")
 
-	queryMonth := func(month string) (float64, int, error) {
+	queryMonth := func(month string) (money.Amount, int, error) {
 		typeHousehold := movements.TypeHousehold
 		resp, err := te.movementsService.ListByHousehold(ctx, userID, &movements.ListMovementsFilters{
 			Type:  &typeHousehold,
@@ -534,7 +547,7 @@ func (te *ToolExecutor) compareMonths(ctx context.Context, userID string, args m
 		}
 
 		all := append(resp.Movements, splitResp.Movements...)
-		var total float64
+		var total money.Amount
 		var count int
 		for _, m := range all {
 			if categoryFilter != "" {
@@ -550,7 +563,7 @@ func (te *ToolExecutor) compareMonths(ctx context.Context, userID string, args m
 					continue
 				}
 			}
-			total += m.Amount
+			total += m.NetHouseholdAmount()
 			count++
 		}
 		return total, count, nil
@@ -568,7 +581,7 @@ func (te *ToolExecutor) compareMonths(ctx context.Context, userID string, args m
 	diff := total2 - total1
 	var pctChange float64
 	if total1 > 0 {
-		pctChange = (diff / total1) * 100
+		pctChange = diff.Ratio(total1) * 100
 	}
 
 	return map[string]any{
@@ -590,14 +603,14 @@ func (te *ToolExecutor) getDebtSummary(ctx context.Context, userID string, args
 	}
 
 	type balance struct {
-		Debtor   string  `json:"debtor"`
-		Creditor string  `json:"creditor"`
-		Amount   float64 `json:"net_amount"`
+		Debtor   string       `json:"debtor"`
+		Creditor string       `json:"creditor"`
+		Amount   money.Amount `json:"net_amount"`
 	}
 
 	var balances []balance
 	for _, b := range result.Balances {
-		if b.Amount > 1.0 { // Consistent with backend: < $1 COP = settled
+		if b.Amount > money.New(1) { // Consistent with backend: < $1 COP = settled
 			// Apply person filter
 			if personFilter != "" {
 				if !containsInsensitive(b.DebtorName, personFilter) && !containsInsensitive(b.CreditorName, personFilter) {
@@ -612,7 +625,7 @@ func (te *ToolExecutor) getDebtSummary(ctx context.Context, userID string, args
 		}
 	}
 
-	summary := map[string]float64{}
+	summary := map[string]money.Amount{}
 	if result.Summary != nil {
 		summary["they_owe_us"] = result.Summary.TheyOweUs
 		summary["we_owe"] = result.Summary.WeOwe
@@ -648,9 +661,9 @@ func (te *ToolExecutor) getSpendingByPaymentMethod(ctx context.Context, userID s
 	}
 
 	type pmSummary struct {
-		Name  string  `json:"payment_method"`
-		Total float64 `json:"total"`
-		Count int     `json:"count"`
+		Name  string       `json:"payment_method"`
+		Total money.Amount `json:"total"`
+		Count int          `json:"count"`
 	}
 
 	pmMap := make(map[string]*pmSummary)
@@ -662,12 +675,12 @@ func (te *ToolExecutor) getSpendingByPaymentMethod(ctx context.Context, userID s
 		if _, ok := pmMap[name]; !ok {
 			pmMap[name] = &pmSummary{Name: name}
 		}
-		pmMap[name].Total += m.Amount
+		pmMap[name].Total += m.NetHouseholdAmount()
 		pmMap[name].Count++
 	}
 
 	var methods []pmSummary
-	var total float64
+	var total money.Amount
 	for _, pm := range pmMap {
 		methods = append(methods, *pm)
 		total += pm.Total
@@ -703,9 +716,9 @@ func (te *ToolExecutor) getSpendingByMember(ctx context.Context, userID string,
 	}
 
 	type memberSummary struct {
-		Name  string  `json:"member"`
-		Total float64 `json:"total"`
-		Count int     `json:"count"`
+		Name  string       `json:"member"`
+		Total money.Amount `json:"total"`
+		Count int          `json:"count"`
 	}
 
 	memMap := make(map[string]*memberSummary)
@@ -717,12 +730,12 @@ func (te *ToolExecutor) getSpendingByMember(ctx context.Context, userID string,
 		if _, ok := memMap[name]; !ok {
 			memMap[name] = &memberSummary{Name: name}
 		}
-		memMap[name].Total += m.Amount
+		memMap[name].Total += m.NetHouseholdAmount()
 		memMap[name].Count++
 	}
 
 	var members []memberSummary
-	var total float64
+	var total money.Amount
 	for _, ms := range memMap {
 		members = append(members, *ms)
 		total += ms.Total
@@ -743,7 +756,7 @@ type MovementDraft struct {
 	Action            string  `json:"action"` // always "confirm_movement"
 	Type              string  `json:"type"`
 	Description       string  `json:"description"`
-	Amount            float64 `json:"amount"`
+	Amount            money.Amount `json:"amount"`
 	CategoryID        string  `json:"category_id"`
 	CategoryName      string  `json:"category_name"`
 	CategoryGroup     string  `json:"category_group,omitempty"`
@@ -759,6 +772,8 @@ type MovementDraft struct {
 	CounterpartyName      string `json:"counterparty_name,omitempty"`
 	ReceiverAccountID     string `json:"receiver_account_id,omitempty"`
 	ReceiverAccountName   string `json:"receiver_account_name,omitempty"`
+	// Set by a household rule
+	EventID string `json:"event_id,omitempty"`
 	// For SPLIT
 	Participants []ParticipantDraft `json:"participants,omitempty"`
 }
@@ -773,7 +788,7 @@ type ParticipantDraft struct {
 
 func (te *ToolExecutor) prepareMovement(ctx context.Context, householdID, userID string, args map[string]any) (any, error) {
 	description := getString(args, "description")
-	amount := getFloat(args, "amount")
+	amount := getAmount(args, "amount")
 	categoryName := getString(args, "category")
 	pmName := getString(args, "payment_method")
 	dateStr := getString(args, "date")
@@ -796,7 +811,21 @@ func (te *ToolExecutor) prepareMovement(ctx context.Context, householdID, userID
 		return nil, fmt.Errorf("failed to list categories: %w", err)
 	}
 
+	// Household rules may know the category when the user didn't say it
+	ruled, err := te.applyRules(ctx, householdID, userID, description, amount, pmName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply rules: %w", err)
+	}
+
 	var matchedCat *categories.Category
+	if categoryName == "" && ruled != nil && ruled.CategoryID != nil {
+		for _, c := range cats {
+			if c.ID == *ruled.CategoryID {
+				matchedCat = c
+				break
+			}
+		}
+	}
 	if categoryName != "" {
 		// Handle "Group > Name" or "Group - Name" format (from option chips or LLM text)
 		groupFilter := ""
@@ -1018,7 +1047,7 @@ func (te *ToolExecutor) prepareMovement(ctx context.Context, householdID, userID
 	// CategoryGroupID is available but name requires separate lookup
 	// For the draft card, the category name is sufficient
 
-	return &MovementDraft{
+	draft := &MovementDraft{
 		Action:            "confirm_movement",
 		Type:              "HOUSEHOLD",
 		Description:       description,
@@ -1031,14 +1060,94 @@ func (te *ToolExecutor) prepareMovement(ctx context.Context, householdID, userID
 		PayerUserID:       userID,
 		PayerName:         payerName,
 		MovementDate:      dateStr,
-	}, nil
+	}
+
+	if ruled != nil {
+		if ruled.EventID != nil {
+			draft.EventID = *ruled.EventID
+		}
+		// A rule can turn the expense into a split with its participants
+		if ruled.Type == movements.TypeSplit && len(ruled.Participants) > 0 {
+			participants, err := te.participantDrafts(ctx, householdID, members, ruled.Participants)
+			if err != nil {
+				return nil, err
+			}
+			draft.Type = string(movements.TypeSplit)
+			draft.Participants = participants
+		}
+	}
+
+	return draft, nil
+}
+
+// applyRules runs the household's rules over the expense being drafted. The
+// payment method is looked up quietly here; prepareMovement still asks for it
+// when it can't be resolved. Returns nil when there are no rules to apply.
+func (te *ToolExecutor) applyRules(ctx context.Context, householdID, userID, description string, amount money.Amount, pmName string) (*movements.CreateMovementInput, error) {
+	if te.ruleApplier == nil {
+		return nil, nil
+	}
+
+	input := &movements.CreateMovementInput{
+		Description: description,
+		Amount:      amount,
+		PayerUserID: &userID,
+	}
+	if pmName != "" {
+		pms, err := te.paymentMethodRepo.ListByHousehold(ctx, householdID)
+		if err != nil {
+			return nil, err
+		}
+		for _, pm := range pms {
+			if strings.EqualFold(pm.Name, pmName) || containsInsensitive(pm.Name, pmName) {
+				id := pm.ID
+				input.PaymentMethodID = &id
+				break
+			}
+		}
+	}
+
+	if err := te.ruleApplier.Apply(ctx, householdID, input); err != nil {
+		return nil, err
+	}
+	return input, nil
+}
+
+// participantDrafts names the participants a rule sets for the draft card
+func (te *ToolExecutor) participantDrafts(ctx context.Context, householdID string, members []*households.HouseholdMember, participants []movements.ParticipantInput) ([]ParticipantDraft, error) {
+	contacts, err := te.householdRepo.ListContacts(ctx, householdID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list contacts: %w", err)
+	}
+
+	drafts := make([]ParticipantDraft, 0, len(participants))
+	for _, p := range participants {
+		d := ParticipantDraft{Percentage: p.Percentage}
+		if p.ParticipantUserID != nil {
+			d.UserID = *p.ParticipantUserID
+			for _, m := range members {
+				if m.UserID == d.UserID {
+					d.Name = m.UserName
+				}
+			}
+		} else if p.ParticipantContactID != nil {
+			d.ContactID = *p.ParticipantContactID
+			for _, c := range contacts {
+				if c.ID == d.ContactID {
+					d.Name = c.Name
+				}
+			}
+		}
+		drafts = append(drafts, d)
+	}
+	return drafts, nil
 }
 
 func (te *ToolExecutor) prepareLoan(ctx context.Context, householdID, userID string, args map[string]any) (any, error) {
 	loanType := getString(args, "type")
 	direction := getString(args, "direction")
 	personName := getString(args, "person")
-	amount := getFloat(args, "amount")
+	amount := getAmount(args, "amount")
 	description := getString(args, "description")
 	categoryName := getString(args, "category")
 	pmName := getString(args, "payment_method")
@@ -1375,6 +1484,7 @@ func movementToEvidence(m *movements.Movement) map[string]any {
 		"id":          m.ID,
 		"description": m.Description,
 		"amount":      m.Amount,
+		"currency":    m.Currency,
 		"date":        m.MovementDate.In(Bogota).Format("2006-01-02"),
 		"group":       group,
 		"category":    category,
@@ -1407,13 +1517,13 @@ func getInt(args map[string]any, key string, defaultVal int) int {
 	return defaultVal
 }
 
-func getFloat(args map[string]any, key string) float64 {
+func getAmount(args map[string]any, key string) money.Amount {
 	if v, ok := args[key]; ok {
 		switch n := v.(type) {
 		case float64:
-			return n
+			return money.FromFloat(n)
 		case int:
-			return float64(n)
+			return money.New(int64(n))
 		}
 	}
 	return 0
@@ -0,0 +1,181 @@
+package attachments
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/blanquicet/conti/backend/internal/auth"
+	"github.com/blanquicet/conti/backend/internal/movements"
+)
+
+// multipartOverhead leaves room for the multipart boundaries and headers on
+// top of the file itself
+const multipartOverhead = 1 << 20
+
+// Handler handles HTTP requests for movement attachments
+type Handler struct {
+	service    Service
+	authSvc    *auth.Service
+	cookieName string
+	logger     *slog.Logger
+}
+
+// NewHandler creates a new attachments handler
+func NewHandler(service Service, authService *auth.Service, cookieName string, logger *slog.Logger) *Handler {
+	return &Handler{
+		service:    service,
+		authSvc:    authService,
+		cookieName: cookieName,
+		logger:     logger,
+	}
+}
+
+// getUserFromSession extracts user from session cookie
+func (h *Handler) getUserFromSession(r *http.Request) (*auth.User, error) {
+	cookie, err := r.Cookie(h.cookieName)
+	if err != nil {
+		return nil, err
+	}
+	return h.authSvc.GetUserBySession(r.Context(), cookie.Value)
+}
+
+// HandleUpload handles POST /movements/{id}/attachments (multipart, field "file")
+func (h *Handler) HandleUpload(w http.ResponseWriter, r *http.Request) {
+	// Multipart forms can be posted cross-site without a preflight
+	if r.Header.Get("X-Requested-With") != "conti" {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	user, err := h.getUserFromSession(r)
+	if err != nil {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, MaxFileSize+multipartOverhead)
+	if err := r.ParseMultipartForm(MaxFileSize); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			http.Error(w, ErrFileTooLarge.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "Invalid multipart form", http.StatusBadRequest)
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "file field is required", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	attachment, err := h.service.Upload(r.Context(), user.ID, &UploadInput{
+		MovementID: r.PathValue("id"),
+		FileName:   header.Filename,
+		Size:       header.Size,
+		Content:    file,
+	})
+	if err != nil {
+		h.writeError(w, "failed to upload attachment", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(attachment)
+}
+
+// HandleList handles GET /movements/{id}/attachments
+func (h *Handler) HandleList(w http.ResponseWriter, r *http.Request) {
+	user, err := h.getUserFromSession(r)
+	if err != nil {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	attachments, err := h.service.List(r.Context(), user.ID, r.PathValue("id"))
+	if err != nil {
+		h.writeError(w, "failed to list attachments", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"attachments": attachments,
+	})
+}
+
+// HandleDownload handles GET /movements/{id}/attachments/{attachmentId}.
+// Images and PDFs open in the browser; ?download=true forces a download.
+func (h *Handler) HandleDownload(w http.ResponseWriter, r *http.Request) {
+	user, err := h.getUserFromSession(r)
+	if err != nil {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	attachment, content, err := h.service.Open(r.Context(), user.ID, r.PathValue("id"), r.PathValue("attachmentId"))
+	if err != nil {
+		h.writeError(w, "failed to open attachment", err)
+		return
+	}
+	defer content.Close()
+
+	disposition := "inline"
+	if r.URL.Query().Get("download") == "true" {
+		disposition = "attachment"
+	}
+	w.Header().Set("Content-Type", attachment.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(attachment.SizeBytes, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": attachment.FileName}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Content-Security-Policy", "sandbox")
+	w.Header().Set("Cache-Control", "private, max-age=3600")
+
+	if _, err := io.Copy(w, content); err != nil {
+		h.logger.Warn("failed to send attachment", "attachment_id", attachment.ID, "error", err)
+	}
+}
+
+// HandleDelete handles DELETE /movements/{id}/attachments/{attachmentId}
+func (h *Handler) HandleDelete(w http.ResponseWriter, r *http.Request) {
+	user, err := h.getUserFromSession(r)
+	if err != nil {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), user.ID, r.PathValue("id"), r.PathValue("attachmentId")); err != nil {
+		h.writeError(w, "failed to delete attachment", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeError maps service errors to HTTP status codes
+func (h *Handler) writeError(w http.ResponseWriter, msg string, err error) {
+	switch {
+	case errors.Is(err, ErrAttachmentNotFound), errors.Is(err, movements.ErrMovementNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, ErrNotAuthorized):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, ErrFileTooLarge):
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+	case errors.Is(err, ErrUnsupportedType):
+		http.Error(w, err.Error(), http.StatusUnsupportedMediaType)
+	case errors.Is(err, ErrEmptyFile), errors.Is(err, ErrTooManyAttachments):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		h.logger.Error(msg, "error", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,150 @@
+package attachments
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/blanquicet/conti/backend/internal/movements"
+)
+
+// repository implements Repository using PostgreSQL
+type repository struct {
+	pool *pgxpool.Pool
+}
+
+// NewRepository creates a new attachments repository
+func NewRepository(pool *pgxpool.Pool) Repository {
+	return &repository{pool: pool}
+}
+
+const attachmentColumns = `
+	id, movement_id, household_id, file_name, content_type, size_bytes,
+	storage_key, uploaded_by, created_at
+`
+
+// scanAttachment scans a row selected with attachmentColumns
+func scanAttachment(row pgx.Row) (*Attachment, error) {
+	var a Attachment
+	err := row.Scan(
+		&a.ID,
+		&a.MovementID,
+		&a.HouseholdID,
+		&a.FileName,
+		&a.ContentType,
+		&a.SizeBytes,
+		&a.StorageKey,
+		&a.UploadedBy,
+		&a.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// Create inserts an attachment whose ID was chosen by the service (it is
+// part of the storage key). The movement is locked while its attachments are
+// counted, so concurrent uploads can't go over MaxPerMovement together.
+func (r *repository) Create(ctx context.Context, a *Attachment) (*Attachment, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var locked string
+	err = tx.QueryRow(ctx, `
+		SELECT id FROM movements WHERE id = $1 AND deleted_at IS NULL FOR UPDATE
+	`, a.MovementID).Scan(&locked)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, movements.ErrMovementNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var count int
+	err = tx.QueryRow(ctx,
+		`SELECT COUNT(*) FROM movement_attachments WHERE movement_id = $1`, a.MovementID).Scan(&count)
+	if err != nil {
+		return nil, err
+	}
+	if count >= MaxPerMovement {
+		return nil, ErrTooManyAttachments
+	}
+
+	created, err := scanAttachment(tx.QueryRow(ctx, `
+		INSERT INTO movement_attachments (
+			id, movement_id, household_id, file_name, content_type, size_bytes,
+			storage_key, uploaded_by
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING `+attachmentColumns,
+		a.ID, a.MovementID, a.HouseholdID, a.FileName, a.ContentType, a.SizeBytes,
+		a.StorageKey, a.UploadedBy,
+	))
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// GetByID retrieves an attachment by ID
+func (r *repository) GetByID(ctx context.Context, id string) (*Attachment, error) {
+	a, err := scanAttachment(r.pool.QueryRow(ctx,
+		`SELECT `+attachmentColumns+` FROM movement_attachments WHERE id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrAttachmentNotFound
+	}
+	return a, err
+}
+
+// ListByMovement lists the attachments of a movement, oldest first
+func (r *repository) ListByMovement(ctx context.Context, movementID string) ([]*Attachment, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+attachmentColumns+`
+		FROM movement_attachments
+		WHERE movement_id = $1
+		ORDER BY created_at ASC
+	`, movementID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	attachments := make([]*Attachment, 0)
+	for rows.Next() {
+		a, err := scanAttachment(rows)
+		if err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, a)
+	}
+	return attachments, rows.Err()
+}
+
+// CountByMovement counts the attachments of a movement
+func (r *repository) CountByMovement(ctx context.Context, movementID string) (int, error) {
+	var count int
+	err := r.pool.QueryRow(ctx,
+		`SELECT COUNT(*) FROM movement_attachments WHERE movement_id = $1`, movementID).Scan(&count)
+	return count, err
+}
+
+// Delete deletes an attachment row
+func (r *repository) Delete(ctx context.Context, id string) error {
+	result, err := r.pool.Exec(ctx, `DELETE FROM movement_attachments WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrAttachmentNotFound
+	}
+	return nil
+}
@@ -0,0 +1,311 @@
+package attachments
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"path"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/blanquicet/conti/backend/internal/audit"
+	"github.com/blanquicet/conti/backend/internal/households"
+	"github.com/blanquicet/conti/backend/internal/movements"
+)
+
+// service implements Service interface
+type service struct {
+	repo           Repository
+	storage        Storage
+	movementsRepo  movements.Repository
+	householdsRepo households.HouseholdRepository
+	auditService   audit.Service
+	logger         *slog.Logger
+}
+
+// NewService creates a new attachments service
+func NewService(
+	repo Repository,
+	storage Storage,
+	movementsRepo movements.Repository,
+	householdsRepo households.HouseholdRepository,
+	auditService audit.Service,
+	logger *slog.Logger,
+) Service {
+	return &service{
+		repo:           repo,
+		storage:        storage,
+		movementsRepo:  movementsRepo,
+		householdsRepo: householdsRepo,
+		auditService:   auditService,
+		logger:         logger,
+	}
+}
+
+// Upload stores a file and attaches it to a movement of the user's household
+func (s *service) Upload(ctx context.Context, userID string, input *UploadInput) (*Attachment, error) {
+	if input.Size <= 0 {
+		return nil, ErrEmptyFile
+	}
+	if input.Size > MaxFileSize {
+		return nil, ErrFileTooLarge
+	}
+
+	householdID, err := s.authorizeMovement(ctx, userID, input.MovementID)
+	if err != nil {
+		return nil, err
+	}
+	// Saves storing a file that can't be attached; Create checks again with
+	// the movement locked
+	count, err := s.repo.CountByMovement(ctx, input.MovementID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= MaxPerMovement {
+		return nil, ErrTooManyAttachments
+	}
+
+	// The type comes from the content, never from the client
+	head := make([]byte, contentSniffingLength)
+	n, err := io.ReadFull(input.Content, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, err
+	}
+	head = head[:n]
+	if n == 0 {
+		return nil, ErrEmptyFile
+	}
+	contentType := detectContentType(head)
+	ext, ok := allowedTypes[contentType]
+	if !ok {
+		return nil, ErrUnsupportedType
+	}
+
+	id, err := newID()
+	if err != nil {
+		return nil, err
+	}
+	attachment := &Attachment{
+		ID:          id,
+		MovementID:  input.MovementID,
+		HouseholdID: householdID,
+		FileName:    cleanFileName(input.FileName, ext),
+		ContentType: contentType,
+		StorageKey:  path.Join(movementPrefix(householdID, input.MovementID), id+ext),
+		UploadedBy:  &userID,
+	}
+
+	// Count what is actually stored; the multipart header may be wrong
+	content := &countingReader{r: io.LimitReader(io.MultiReader(bytes.NewReader(head), input.Content), MaxFileSize+1)}
+	if err := s.storage.Put(ctx, attachment.StorageKey, content); err != nil {
+		return nil, fmt.Errorf("failed to store attachment: %w", err)
+	}
+	if content.n > MaxFileSize {
+		s.deleteFile(ctx, attachment.StorageKey)
+		return nil, ErrFileTooLarge
+	}
+	attachment.SizeBytes = content.n
+
+	created, err := s.repo.Create(ctx, attachment)
+	if err != nil {
+		s.deleteFile(ctx, attachment.StorageKey)
+		s.auditService.LogAsync(ctx, &audit.LogInput{
+			UserID:       audit.StringPtr(userID),
+			Action:       audit.ActionAttachmentUploaded,
+			ResourceType: "attachment",
+			HouseholdID:  audit.StringPtr(householdID),
+			Success:      false,
+			ErrorMessage: audit.StringPtr(err.Error()),
+		})
+		return nil, err
+	}
+
+	s.auditService.LogAsync(ctx, &audit.LogInput{
+		UserID:       audit.StringPtr(userID),
+		Action:       audit.ActionAttachmentUploaded,
+		ResourceType: "attachment",
+		ResourceID:   audit.StringPtr(created.ID),
+		HouseholdID:  audit.StringPtr(householdID),
+		NewValues:    audit.StructToMap(created),
+		Metadata: map[string]interface{}{
+			"movement_id": created.MovementID,
+		},
+		Success: true,
+	})
+
+	return created, nil
+}
+
+// List lists the attachments of a movement of the user's household
+func (s *service) List(ctx context.Context, userID, movementID string) ([]*Attachment, error) {
+	if _, err := s.authorizeMovement(ctx, userID, movementID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListByMovement(ctx, movementID)
+}
+
+// Open returns an attachment of a movement of the user's household with its content
+func (s *service) Open(ctx context.Context, userID, movementID, id string) (*Attachment, io.ReadCloser, error) {
+	attachment, _, err := s.getAuthorized(ctx, userID, movementID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	content, err := s.storage.Open(ctx, attachment.StorageKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return attachment, content, nil
+}
+
+// Delete deletes an attachment and its file
+func (s *service) Delete(ctx context.Context, userID, movementID, id string) error {
+	attachment, householdID, err := s.getAuthorized(ctx, userID, movementID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(ctx, id); err != nil {
+		s.auditService.LogAsync(ctx, &audit.LogInput{
+			UserID:       audit.StringPtr(userID),
+			Action:       audit.ActionAttachmentDeleted,
+			ResourceType: "attachment",
+			ResourceID:   audit.StringPtr(id),
+			HouseholdID:  audit.StringPtr(householdID),
+			OldValues:    audit.StructToMap(attachment),
+			Success:      false,
+			ErrorMessage: audit.StringPtr(err.Error()),
+		})
+		return err
+	}
+	s.deleteFile(ctx, attachment.StorageKey)
+
+	s.auditService.LogAsync(ctx, &audit.LogInput{
+		UserID:       audit.StringPtr(userID),
+		Action:       audit.ActionAttachmentDeleted,
+		ResourceType: "attachment",
+		ResourceID:   audit.StringPtr(id),
+		HouseholdID:  audit.StringPtr(householdID),
+		OldValues:    audit.StructToMap(attachment),
+		Success:      true,
+	})
+
+	return nil
+}
+
+// DeleteMovementFiles removes the folder of a purged movement
+func (s *service) DeleteMovementFiles(ctx context.Context, householdID, movementID string) error {
+	return s.storage.DeletePrefix(ctx, movementPrefix(householdID, movementID))
+}
+
+// authorizeMovement verifies the movement belongs to the user's household and
+// returns the household ID
+func (s *service) authorizeMovement(ctx context.Context, userID, movementID string) (string, error) {
+	householdID, err := s.householdsRepo.GetUserHouseholdID(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	movement, err := s.movementsRepo.GetByID(ctx, movementID)
+	if err != nil {
+		return "", err
+	}
+	if movement.HouseholdID != householdID {
+		return "", ErrNotAuthorized
+	}
+	return householdID, nil
+}
+
+// getAuthorized loads an attachment and verifies it belongs to the movement
+// and the movement to the user's household
+func (s *service) getAuthorized(ctx context.Context, userID, movementID, id string) (*Attachment, string, error) {
+	householdID, err := s.authorizeMovement(ctx, userID, movementID)
+	if err != nil {
+		return nil, "", err
+	}
+	attachment, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, "", err
+	}
+	if attachment.MovementID != movementID {
+		return nil, "", ErrAttachmentNotFound
+	}
+	return attachment, householdID, nil
+}
+
+// deleteFile removes a stored file, logging failures (an orphaned file is
+// better than failing the request that made it orphan)
+func (s *service) deleteFile(ctx context.Context, key string) {
+	if err := s.storage.Delete(ctx, key); err != nil {
+		s.logger.Error("failed to delete attachment file", "key", key, "error", err)
+	}
+}
+
+// movementPrefix is the storage folder of a movement's attachments
+func movementPrefix(householdID, movementID string) string {
+	return householdID + "/" + movementID
+}
+
+// detectContentType sniffs the content type. HEIC (iPhone photos) is not
+// known to http.DetectContentType, so its ftyp box is checked here.
+func detectContentType(head []byte) string {
+	if len(head) >= 12 && string(head[4:8]) == "ftyp" {
+		switch string(head[8:12]) {
+		case "heic", "heix", "heim", "heis", "mif1", "msf1":
+			return "image/heic"
+		}
+	}
+	contentType := http.DetectContentType(head)
+	if i := strings.IndexByte(contentType, ';'); i >= 0 {
+		contentType = contentType[:i]
+	}
+	return contentType
+}
+
+// cleanFileName keeps the base name the client sent, without control
+// characters or quotes, falling back to "adjunto" plus the extension
+func cleanFileName(name, ext string) string {
+	name = path.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || r == '"' {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+	if name == "" || name == "." || name == "/" {
+		name = "adjunto" + ext
+	}
+	for len(name) > maxFileNameLength || !utf8.ValidString(name) {
+		name = strings.ToValidUTF8(name, "")
+		if len(name) > maxFileNameLength {
+			name = name[:maxFileNameLength]
+		}
+	}
+	return name
+}
+
+// newID returns a random (version 4) UUID
+func newID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
+
+// countingReader counts the bytes read through it
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
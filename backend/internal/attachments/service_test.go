@@ -0,0 +1,57 @@
+package attachments
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDetectContentType(t *testing.T) {
+	tests := []struct {
+		name string
+		head string
+		want string
+	}{
+		{"jpeg", "\xff\xd8\xff\xe0\x00\x10JFIF\x00", "image/jpeg"},
+		{"png", "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR", "image/png"},
+		{"pdf", "%PDF-1.7\n", "application/pdf"},
+		{"heic", "\x00\x00\x00\x18ftypheic\x00\x00\x00\x00", "image/heic"},
+		{"html is not allowed", "<html><script>", "text/html"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := detectContentType([]byte(tt.head)); got != tt.want {
+				t.Errorf("detectContentType() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+	if _, ok := allowedTypes["text/html"]; ok {
+		t.Error("text/html must not be an allowed type")
+	}
+}
+
+func TestCleanFileName(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"keeps the name", "factura Éxito.pdf", "factura Éxito.pdf"},
+		{"drops windows folders", `C:\Users\ana\recibo.jpg`, "recibo.jpg"},
+		{"drops unix folders", "../../etc/passwd", "passwd"},
+		{"drops quotes and control characters", "a\"b\r\n.png", "ab.png"},
+		{"falls back when empty", "", "adjunto.pdf"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cleanFileName(tt.in, ".pdf"); got != tt.want {
+				t.Errorf("cleanFileName(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+
+	if got := cleanFileName(strings.Repeat("a", 300), ".pdf"); len(got) != maxFileNameLength {
+		t.Errorf("long name has %d bytes, want %d", len(got), maxFileNameLength)
+	}
+}
@@ -0,0 +1,64 @@
+package attachments
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocalStorage(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	storage, err := NewLocalStorage(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key := "household-1/movement-1/a.pdf"
+	if err := storage.Put(ctx, key, strings.NewReader("%PDF-1.4")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	f, err := storage.Open(ctx, key)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	content, _ := io.ReadAll(f)
+	f.Close()
+	if string(content) != "%PDF-1.4" {
+		t.Errorf("content = %q", content)
+	}
+
+	// No temporary files are left behind
+	entries, _ := os.ReadDir(filepath.Join(root, "household-1", "movement-1"))
+	if len(entries) != 1 {
+		t.Errorf("got %d files in the movement folder, want 1", len(entries))
+	}
+
+	if err := storage.DeletePrefix(ctx, "household-1/movement-1"); err != nil {
+		t.Fatalf("DeletePrefix() error = %v", err)
+	}
+	if _, err := storage.Open(ctx, key); !errors.Is(err, ErrAttachmentNotFound) {
+		t.Errorf("Open() after DeletePrefix error = %v, want ErrAttachmentNotFound", err)
+	}
+	if err := storage.Delete(ctx, key); err != nil {
+		t.Errorf("Delete() of a missing file error = %v, want nil", err)
+	}
+}
+
+func TestLocalStorage_RejectsKeysOutsideRoot(t *testing.T) {
+	storage, err := NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range []string{"", ".", "../x", "a/../../x", "/etc/passwd", "a//b", `a\..\x`} {
+		if err := storage.Put(context.Background(), key, strings.NewReader("x")); !errors.Is(err, ErrInvalidStorageKey) {
+			t.Errorf("Put(%q) error = %v, want ErrInvalidStorageKey", key, err)
+		}
+	}
+}
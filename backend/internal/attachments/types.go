@@ -0,0 +1,89 @@
+package attachments
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+const (
+	MaxFileSize           = 10 << 20 // 10MB, a phone photo or a scanned invoice
+	MaxPerMovement        = 10
+	maxFileNameLength     = 255
+	contentSniffingLength = 512 // What http.DetectContentType looks at
+)
+
+// Errors for movement attachments
+var (
+	ErrAttachmentNotFound = errors.New("attachment not found")
+	ErrNotAuthorized      = errors.New("not authorized")
+	ErrEmptyFile          = errors.New("file is empty")
+	ErrFileTooLarge       = errors.New("file must be 10MB or less")
+	ErrUnsupportedType    = errors.New("unsupported file type, use JPEG, PNG, WebP, HEIC or PDF")
+	ErrTooManyAttachments = errors.New("a movement can have at most 10 attachments")
+	ErrInvalidStorageKey  = errors.New("invalid storage key")
+)
+
+// allowedTypes are the content types accepted for attachments: receipt
+// photos and invoice PDFs
+var allowedTypes = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/webp":      ".webp",
+	"image/heic":      ".heic",
+	"application/pdf": ".pdf",
+}
+
+// Attachment is a file attached to a movement
+type Attachment struct {
+	ID          string    `json:"id"`
+	MovementID  string    `json:"movement_id"`
+	HouseholdID string    `json:"household_id"`
+	FileName    string    `json:"file_name"`
+	ContentType string    `json:"content_type"`
+	SizeBytes   int64     `json:"size_bytes"`
+	StorageKey  string    `json:"-"`
+	UploadedBy  *string   `json:"uploaded_by,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// UploadInput is a file being attached to a movement
+type UploadInput struct {
+	MovementID string
+	FileName   string
+	Size       int64 // As reported by the multipart header
+	Content    io.Reader
+}
+
+// Storage keeps attachment contents. Keys are slash-separated paths
+// (<household_id>/<movement_id>/<attachment_id><ext>).
+type Storage interface {
+	Put(ctx context.Context, key string, r io.Reader) error
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	// DeletePrefix removes every key under prefix (a movement's folder)
+	DeletePrefix(ctx context.Context, prefix string) error
+}
+
+// Repository defines the interface for attachment data access
+type Repository interface {
+	Create(ctx context.Context, attachment *Attachment) (*Attachment, error)
+	GetByID(ctx context.Context, id string) (*Attachment, error)
+	ListByMovement(ctx context.Context, movementID string) ([]*Attachment, error)
+	CountByMovement(ctx context.Context, movementID string) (int, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// Service defines the interface for attachment business logic
+type Service interface {
+	Upload(ctx context.Context, userID string, input *UploadInput) (*Attachment, error)
+	List(ctx context.Context, userID, movementID string) ([]*Attachment, error)
+	// Open returns the attachment and its content; the caller closes it
+	Open(ctx context.Context, userID, movementID, id string) (*Attachment, io.ReadCloser, error)
+	Delete(ctx context.Context, userID, movementID, id string) error
+
+	// DeleteMovementFiles removes the files of a purged movement
+	// (implements movements.AttachmentCleaner)
+	DeleteMovementFiles(ctx context.Context, householdID, movementID string) error
+}
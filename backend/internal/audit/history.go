@@ -0,0 +1,110 @@
+package audit
+
+import (
+	"reflect"
+	"sort"
+	"time"
+)
+
+// FieldChange is a field that changed between two versions of a record
+type FieldChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+// HistoryEntry is one event in the change timeline of a record
+type HistoryEntry struct {
+	VersionID string        `json:"version_id"` // Audit log ID, used to revert to this version
+	Action    Action        `json:"action"`
+	At        time.Time     `json:"at"`
+	UserID    *string       `json:"user_id,omitempty"`
+	UserName  string        `json:"user_name,omitempty"`
+	Changes   []FieldChange `json:"changes"`
+	// Revertible is set on versions with a snapshot of the record (created,
+	// updated or restored)
+	Revertible bool `json:"revertible"`
+}
+
+// View turns a snapshot of a record into the fields shown to users (names
+// instead of IDs, dates without time)
+type View func(snapshot map[string]interface{}) map[string]interface{}
+
+// Diff returns the fields that differ between two views, sorted by field.
+// A nil old view lists every field set in the new one.
+func Diff(old, new map[string]interface{}) []FieldChange {
+	fields := make(map[string]bool)
+	for field := range old {
+		fields[field] = true
+	}
+	for field := range new {
+		fields[field] = true
+	}
+
+	changes := make([]FieldChange, 0)
+	for field := range fields {
+		before, after := old[field], new[field]
+		if isEmpty(before) && isEmpty(after) {
+			continue
+		}
+		if reflect.DeepEqual(before, after) {
+			continue
+		}
+		changes = append(changes, FieldChange{Field: field, Old: before, New: after})
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes
+}
+
+// isEmpty reports whether a JSON value is missing, null or an empty list
+func isEmpty(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	if list, ok := v.([]interface{}); ok {
+		return len(list) == 0
+	}
+	return false
+}
+
+// BuildHistory turns the successful audit logs of a record (newest first, as
+// Query returns them) into its change timeline. userNames resolves user IDs
+// for display.
+func BuildHistory(logs []*AuditLog, view View, userNames map[string]string) []*HistoryEntry {
+	history := make([]*HistoryEntry, 0, len(logs))
+	for _, log := range logs {
+		if !log.Success {
+			continue
+		}
+		entry := &HistoryEntry{
+			VersionID:  log.ID,
+			Action:     log.Action,
+			At:         log.CreatedAt,
+			UserID:     log.UserID,
+			Changes:    []FieldChange{},
+			Revertible: log.NewValues != nil,
+		}
+		if log.UserID != nil {
+			entry.UserName = userNames[*log.UserID]
+		}
+		if log.NewValues != nil {
+			var old map[string]interface{}
+			if log.OldValues != nil {
+				old = view(log.OldValues)
+			}
+			entry.Changes = Diff(old, view(log.NewValues))
+		}
+		history = append(history, entry)
+	}
+	return history
+}
+
+// FindVersion returns the log of a revertible version among a record's logs
+func FindVersion(logs []*AuditLog, versionID string) *AuditLog {
+	for _, log := range logs {
+		if log.ID == versionID && log.Success && log.NewValues != nil {
+			return log
+		}
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package audit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDiff(t *testing.T) {
+	old := map[string]interface{}{
+		"amount":      50000.0,
+		"description": "Mercado",
+		"tags":        []interface{}{},
+		"event":       nil,
+	}
+	new := map[string]interface{}{
+		"amount":      65000.0,
+		"description": "Mercado",
+		"tags":        nil,
+		"event":       "Viaje",
+	}
+
+	changes := Diff(old, new)
+	if len(changes) != 2 {
+		t.Fatalf("Diff() = %+v, want amount and event", changes)
+	}
+	if changes[0].Field != "amount" || changes[0].Old != 50000.0 || changes[0].New != 65000.0 {
+		t.Errorf("changes[0] = %+v", changes[0])
+	}
+	if changes[1].Field != "event" || changes[1].Old != nil || changes[1].New != "Viaje" {
+		t.Errorf("changes[1] = %+v", changes[1])
+	}
+
+	// A creation lists the fields that were set
+	created := Diff(nil, new)
+	if len(created) != 3 {
+		t.Errorf("Diff(nil, new) = %+v, want amount, description and event", created)
+	}
+}
+
+func TestBuildHistory(t *testing.T) {
+	user := "user-1"
+	identity := func(m map[string]interface{}) map[string]interface{} { return m }
+	logs := []*AuditLog{
+		{ID: "3", Action: ActionMovementDeleted, UserID: &user, Success: true, OldValues: map[string]interface{}{"amount": 2.0}},
+		{ID: "2", Action: ActionMovementUpdated, UserID: &user, Success: true,
+			OldValues: map[string]interface{}{"amount": 1.0}, NewValues: map[string]interface{}{"amount": 2.0}},
+		{ID: "x", Action: ActionMovementUpdated, Success: false},
+		{ID: "1", Action: ActionMovementCreated, Success: true, CreatedAt: time.Now(), NewValues: map[string]interface{}{"amount": 1.0}},
+	}
+
+	history := BuildHistory(logs, identity, map[string]string{user: "Ana"})
+	if len(history) != 3 {
+		t.Fatalf("got %d entries, want failed logs skipped", len(history))
+	}
+	if history[0].Revertible || len(history[0].Changes) != 0 {
+		t.Errorf("deletion entry = %+v, want no changes and not revertible", history[0])
+	}
+	if !history[1].Revertible || history[1].UserName != "Ana" || len(history[1].Changes) != 1 {
+		t.Errorf("update entry = %+v", history[1])
+	}
+	if FindVersion(logs, "3") != nil || FindVersion(logs, "x") != nil || FindVersion(logs, "1") == nil {
+		t.Error("FindVersion() should only find successful logs with a snapshot")
+	}
+}
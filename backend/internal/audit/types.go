@@ -44,6 +44,9 @@ ActionContactDeactivated Action = "CONTACT_DEACTIVATED"
 ActionAccountCreated Action = "ACCOUNT_CREATED"
 ActionAccountUpdated Action = "ACCOUNT_UPDATED"
 ActionAccountDeleted Action = "ACCOUNT_DELETED"
+ActionAccountCheckpointCreated Action = "ACCOUNT_CHECKPOINT_CREATED"
+ActionAccountCheckpointDeleted Action = "ACCOUNT_CHECKPOINT_DELETED"
+ActionAccountBalanceAdjusted Action = "ACCOUNT_BALANCE_ADJUSTED"
 
 // Payment Methods
 ActionPaymentMethodCreated Action = "PAYMENT_METHOD_CREATED"
@@ -54,11 +57,15 @@ ActionPaymentMethodDeleted Action = "PAYMENT_METHOD_DELETED"
 ActionIncomeCreated Action = "INCOME_CREATED"
 ActionIncomeUpdated Action = "INCOME_UPDATED"
 ActionIncomeDeleted Action = "INCOME_DELETED"
+ActionIncomeRestored Action = "INCOME_RESTORED"
 
 // Movements
 ActionMovementCreated Action = "MOVEMENT_CREATED"
 ActionMovementUpdated Action = "MOVEMENT_UPDATED"
 ActionMovementDeleted Action = "MOVEMENT_DELETED"
+ActionMovementConfirmed Action = "MOVEMENT_CONFIRMED"
+ActionMovementDisputed  Action = "MOVEMENT_DISPUTED"
+ActionMovementRestored  Action = "MOVEMENT_RESTORED"
 
 // Categories
 ActionCategoryCreated      Action = "CATEGORY_CREATED"
@@ -75,7 +82,38 @@ ActionBudgetDeleted Action = "BUDGET_DELETED"
 
 // Credit Card Payments
 ActionCreditCardPaymentCreated Action = "CREDIT_CARD_PAYMENT_CREATED"
+ActionCreditCardPaymentUpdated Action = "CREDIT_CARD_PAYMENT_UPDATED"
 ActionCreditCardPaymentDeleted Action = "CREDIT_CARD_PAYMENT_DELETED"
+
+// Credit Card Statements
+ActionCardStatementClosed     Action = "CARD_STATEMENT_CLOSED"
+ActionCardStatementReconciled Action = "CARD_STATEMENT_RECONCILED"
+ActionCardStatementDeleted    Action = "CARD_STATEMENT_DELETED"
+
+// Events
+ActionEventCreated Action = "EVENT_CREATED"
+ActionEventUpdated Action = "EVENT_UPDATED"
+ActionEventDeleted Action = "EVENT_DELETED"
+ActionEventClosed  Action = "EVENT_CLOSED"
+
+// Exchange rates
+ActionFXRateCreated   Action = "FX_RATE_CREATED"
+ActionFXRateDeleted   Action = "FX_RATE_DELETED"
+ActionFXRatesImported Action = "FX_RATES_IMPORTED"
+
+// Statement imports
+ActionImportDraftsCreated  Action = "IMPORT_DRAFTS_CREATED"
+ActionImportDraftConfirmed Action = "IMPORT_DRAFT_CONFIRMED"
+ActionImportDraftDiscarded Action = "IMPORT_DRAFT_DISCARDED"
+
+// Categorization rules
+ActionRuleCreated Action = "RULE_CREATED"
+ActionRuleUpdated Action = "RULE_UPDATED"
+ActionRuleDeleted Action = "RULE_DELETED"
+
+// Movement attachments
+ActionAttachmentUploaded Action = "ATTACHMENT_UPLOADED"
+ActionAttachmentDeleted  Action = "ATTACHMENT_DELETED"
 )
 
 // AuditLog represents a single audit log entry
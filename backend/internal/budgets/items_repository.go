@@ -6,6 +6,8 @@ import (
 	"strings"
 
 	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/blanquicet/conti/backend/internal/money"
 )
 
 type budgetItemsRepository struct {
@@ -723,12 +725,12 @@ func (r *budgetItemsRepository) GetMostRecentMonth(ctx context.Context, househol
 }
 
 // GetItemsSumForCategory returns the sum of all item amounts for a category in a month
-func (r *budgetItemsRepository) GetItemsSumForCategory(ctx context.Context, householdID, categoryID, month string) (float64, error) {
+func (r *budgetItemsRepository) GetItemsSumForCategory(ctx context.Context, householdID, categoryID, month string) (money.Amount, error) {
 	monthDate, err := ParseMonth(month)
 	if err != nil {
 		return 0, ErrInvalidMonth
 	}
-	var sum float64
+	var sum money.Amount
 	err = r.pool.QueryRow(ctx, `
 		SELECT COALESCE(SUM(amount), 0)
 		FROM monthly_budget_items
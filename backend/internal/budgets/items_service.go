@@ -7,13 +7,15 @@ import (
 	"time"
 
 	"github.com/jackc/pgx/v5"
+
+	"github.com/blanquicet/conti/backend/internal/money"
 )
 
 // BudgetItemsService handles business logic for monthly budget items
 type BudgetItemsService struct {
 	itemsRepo      BudgetItemsRepository
 	logger         *slog.Logger
-	syncTemplateFn func(ctx context.Context, templateID string, amount float64, name string) error
+	syncTemplateFn func(ctx context.Context, templateID string, amount money.Amount, name string) error
 	budgetSyncFn   func(ctx context.Context, householdID, categoryID, month string) error
 }
 
@@ -26,7 +28,7 @@ func NewBudgetItemsService(itemsRepo BudgetItemsRepository, logger *slog.Logger)
 }
 
 // SetSyncTemplateFn sets the function used to sync budget item changes back to the master template
-func (s *BudgetItemsService) SetSyncTemplateFn(fn func(ctx context.Context, templateID string, amount float64, name string) error) {
+func (s *BudgetItemsService) SetSyncTemplateFn(fn func(ctx context.Context, templateID string, amount money.Amount, name string) error) {
 	s.syncTemplateFn = fn
 }
 
@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/blanquicet/conti/backend/internal/money"
 	"github.com/blanquicet/conti/backend/internal/movements"
 )
 
@@ -25,7 +26,7 @@ type MonthlyBudgetItem struct {
 
 	Name        string  `json:"name"`
 	Description *string `json:"description,omitempty"`
-	Amount      float64 `json:"amount"`
+	Amount      money.Amount `json:"amount"`
 	Currency    string  `json:"currency"`
 
 	MovementType *movements.MovementType `json:"movement_type,omitempty"`
@@ -76,7 +77,7 @@ type CreateBudgetItemInput struct {
 	Month       string  `json:"month"` // YYYY-MM
 	Name        string  `json:"name"`
 	Description *string `json:"description,omitempty"`
-	Amount      float64 `json:"amount"`
+	Amount      money.Amount `json:"amount"`
 
 	MovementType *movements.MovementType `json:"movement_type,omitempty"`
 	AutoGenerate bool                    `json:"auto_generate"`
@@ -106,7 +107,7 @@ type BudgetItemParticipantInput struct {
 type UpdateBudgetItemInput struct {
 	Name        *string  `json:"name,omitempty"`
 	Description *string  `json:"description,omitempty"`
-	Amount      *float64 `json:"amount,omitempty"`
+	Amount      *money.Amount `json:"amount,omitempty"`
 
 	MovementType *movements.MovementType `json:"movement_type,omitempty"`
 	AutoGenerate *bool                   `json:"auto_generate,omitempty"`
@@ -174,5 +175,5 @@ type BudgetItemsRepository interface {
 	GetParticipantsBatch(ctx context.Context, itemIDs []string) (map[string][]BudgetItemParticipant, error)
 
 	// GetItemsSumForCategory returns the sum of all item amounts for a category in a month
-	GetItemsSumForCategory(ctx context.Context, householdID, categoryID, month string) (float64, error)
+	GetItemsSumForCategory(ctx context.Context, householdID, categoryID, month string) (money.Amount, error)
 }
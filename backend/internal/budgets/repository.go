@@ -5,6 +5,8 @@ import (
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/blanquicet/conti/backend/internal/money"
 )
 
 // PostgresRepository implements Repository using PostgreSQL
@@ -45,7 +47,7 @@ func (r *PostgresRepository) GetByMonth(ctx context.Context, householdID, month
 				ELSE GREATEST(COALESCE(ib.amount, 0), COALESCE(mb.amount, 0))
 			END as amount,
 			COALESCE(mb.currency, 'COP') as currency,
-			COALESCE(SUM(m.amount), 0) as spent,
+			COALESCE(SUM(m.signed_base_amount), 0) as spent, -- In the household currency, net of refunds
 			mb.created_at,
 			mb.updated_at
 		FROM categories c
@@ -62,6 +64,8 @@ func (r *PostgresRepository) GetByMonth(ctx context.Context, householdID, month
 		LEFT JOIN items_budget ib ON ib.category_id = c.id
 		LEFT JOIN movements m ON m.category_id = c.id
 			AND m.household_id = $1
+			AND m.deleted_at IS NULL
+			AND m.type <> 'TRANSFER'
 			AND DATE_TRUNC('month', m.movement_date) = $2
 		WHERE c.household_id = $1
 			AND c.is_active = true
@@ -98,7 +102,7 @@ func (r *PostgresRepository) GetByMonth(ctx context.Context, householdID, month
 
 		// Calculate percentage and status
 		if budget.Amount > 0 {
-			budget.Percentage = (budget.Spent / budget.Amount) * 100
+			budget.Percentage = budget.Spent.Ratio(budget.Amount) * 100
 		} else {
 			budget.Percentage = 0
 		}
@@ -227,18 +231,20 @@ func (r *PostgresRepository) CopyBudgets(ctx context.Context, householdID, fromM
 }
 
 // GetSpentForCategory returns total spent for a category in a month
-func (r *PostgresRepository) GetSpentForCategory(ctx context.Context, householdID, categoryID, month string) (float64, error) {
+func (r *PostgresRepository) GetSpentForCategory(ctx context.Context, householdID, categoryID, month string) (money.Amount, error) {
 	monthDate, err := ParseMonth(month)
 	if err != nil {
 		return 0, ErrInvalidMonth
 	}
 
-	var spent float64
+	var spent money.Amount
 	err = r.pool.QueryRow(ctx, `
-		SELECT COALESCE(SUM(amount), 0)
+		SELECT COALESCE(SUM(signed_base_amount), 0)
 		FROM movements
 		WHERE household_id = $1
 			AND category_id = $2
+			AND deleted_at IS NULL
+			AND type <> 'TRANSFER'
 			AND DATE_TRUNC('month', movement_date) = $3
 	`, householdID, categoryID, monthDate).Scan(&spent)
 	if err != nil {
@@ -267,12 +273,12 @@ func (r *PostgresRepository) DeleteFutureRecords(ctx context.Context, householdI
 // GetEffectiveBudget returns the effective displayed budget amount for a category at a given month.
 // This matches the GetByMonth LATERAL JOIN + CASE logic: considers both monthly_budgets inheritance
 // and monthly_budget_items sum.
-func (r *PostgresRepository) GetEffectiveBudget(ctx context.Context, householdID, categoryID, month string) (float64, error) {
+func (r *PostgresRepository) GetEffectiveBudget(ctx context.Context, householdID, categoryID, month string) (money.Amount, error) {
 	monthDate, err := ParseMonth(month)
 	if err != nil {
 		return 0, ErrInvalidMonth
 	}
-	var amount float64
+	var amount money.Amount
 	err = r.pool.QueryRow(ctx, `
 		WITH items_budget AS (
 			SELECT COALESCE(SUM(amount), 0) as amount
@@ -300,7 +306,7 @@ func (r *PostgresRepository) GetEffectiveBudget(ctx context.Context, householdID
 }
 
 // PinMonthIfMissing inserts a budget record for the given month only if none exists yet
-func (r *PostgresRepository) PinMonthIfMissing(ctx context.Context, householdID, categoryID, month string, amount float64) error {
+func (r *PostgresRepository) PinMonthIfMissing(ctx context.Context, householdID, categoryID, month string, amount money.Amount) error {
 	monthDate, err := ParseMonth(month)
 	if err != nil {
 		return ErrInvalidMonth
@@ -315,7 +321,7 @@ func (r *PostgresRepository) PinMonthIfMissing(ctx context.Context, householdID,
 
 // UpsertBudgetFromItems creates or updates a monthly_budgets record to match items sum.
 // Always sets amount = items sum so the budget total tracks the actual templates.
-func (r *PostgresRepository) UpsertBudgetFromItems(ctx context.Context, householdID, categoryID, month string, itemsSum float64) error {
+func (r *PostgresRepository) UpsertBudgetFromItems(ctx context.Context, householdID, categoryID, month string, itemsSum money.Amount) error {
 	monthDate, err := ParseMonth(month)
 	if err != nil {
 		return ErrInvalidMonth
@@ -330,7 +336,7 @@ func (r *PostgresRepository) UpsertBudgetFromItems(ctx context.Context, househol
 }
 
 // UpdateAllRecords updates all budget records for a category to a new amount
-func (r *PostgresRepository) UpdateAllRecords(ctx context.Context, householdID, categoryID string, amount float64) (int64, error) {
+func (r *PostgresRepository) UpdateAllRecords(ctx context.Context, householdID, categoryID string, amount money.Amount) (int64, error) {
 	result, err := r.pool.Exec(ctx, `
 		UPDATE monthly_budgets SET amount = $3, updated_at = NOW()
 		WHERE household_id = $1 AND category_id = $2
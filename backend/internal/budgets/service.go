@@ -6,6 +6,7 @@ import (
 	"github.com/blanquicet/conti/backend/internal/audit"
 	"github.com/blanquicet/conti/backend/internal/categories"
 	"github.com/blanquicet/conti/backend/internal/households"
+	"github.com/blanquicet/conti/backend/internal/money"
 )
 
 // BudgetService implements Service
@@ -61,7 +62,7 @@ func (s *BudgetService) GetByMonth(ctx context.Context, userID, month string) (*
 	}
 
 	// Calculate totals
-	var totalBudget, totalSpent float64
+	var totalBudget, totalSpent money.Amount
 	for _, budget := range budgets {
 		totalBudget += budget.Amount
 		totalSpent += budget.Spent
@@ -69,7 +70,7 @@ func (s *BudgetService) GetByMonth(ctx context.Context, userID, month string) (*
 
 	var totalPercentage float64
 	if totalBudget > 0 {
-		totalPercentage = (totalSpent / totalBudget) * 100
+		totalPercentage = totalSpent.Ratio(totalBudget) * 100
 	}
 
 	return &GetBudgetResponse{
@@ -126,7 +127,7 @@ func (s *BudgetService) Set(ctx context.Context, userID string, input *SetBudget
 	}
 
 	// For scope=THIS, capture old budget value before upsert so we can pin the next month
-	var oldAmount float64
+	var oldAmount money.Amount
 	if scope == ScopeThis {
 		oldAmount, _ = s.repo.GetEffectiveBudget(ctx, householdID, input.CategoryID, input.Month)
 	}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"time"
+
+	"github.com/blanquicet/conti/backend/internal/money"
 )
 
 // Errors for budget operations
@@ -22,7 +24,7 @@ var (
 // TemplatesSumCalculator is an interface for calculating template sums
 // Used to avoid import cycles between budgets and recurringmovements packages
 type TemplatesSumCalculator interface {
-	CalculateTemplatesSum(ctx context.Context, userID, categoryID string) (float64, error)
+	CalculateTemplatesSum(ctx context.Context, userID, categoryID string) (money.Amount, error)
 }
 
 // MonthlyBudget represents a budget for a category in a specific month
@@ -31,7 +33,7 @@ type MonthlyBudget struct {
 	HouseholdID string    `json:"household_id"`
 	CategoryID  string    `json:"category_id"`
 	Month       time.Time `json:"month"` // First day of month
-	Amount      float64   `json:"amount"`
+	Amount      money.Amount   `json:"amount"`
 	Currency    string    `json:"currency"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
@@ -46,9 +48,9 @@ type BudgetWithSpent struct {
 	CategoryGroupName  *string    `json:"category_group_name,omitempty"`
 	CategoryGroupIcon  *string    `json:"category_group_icon,omitempty"`
 	GroupDisplayOrder  *int       `json:"group_display_order,omitempty"`
-	Amount             float64    `json:"amount"`
+	Amount             money.Amount    `json:"amount"`
 	Currency           string     `json:"currency"`
-	Spent              float64    `json:"spent"`
+	Spent              money.Amount    `json:"spent"`
 	Percentage         float64    `json:"percentage"` // (spent / amount) * 100
 	Status             string     `json:"status"`     // "under_budget" | "on_track" | "exceeded"
 	CreatedAt          *time.Time `json:"created_at,omitempty"`
@@ -57,8 +59,8 @@ type BudgetWithSpent struct {
 
 // BudgetTotals represents total budget and spent for a month
 type BudgetTotals struct {
-	TotalBudget float64 `json:"total_budget"`
-	TotalSpent  float64 `json:"total_spent"`
+	TotalBudget money.Amount `json:"total_budget"`
+	TotalSpent  money.Amount `json:"total_spent"`
 	Percentage  float64 `json:"percentage"`
 }
 
@@ -73,7 +75,7 @@ type GetBudgetResponse struct {
 type SetBudgetInput struct {
 	CategoryID string      `json:"category_id"`
 	Month      string      `json:"month"` // YYYY-MM format
-	Amount     float64     `json:"amount"`
+	Amount     money.Amount     `json:"amount"`
 	Scope      BudgetScope `json:"scope,omitempty"` // THIS, FUTURE, ALL (default: FUTURE)
 }
 
@@ -148,22 +150,22 @@ type Repository interface {
 	CopyBudgets(ctx context.Context, householdID, fromMonth, toMonth string) (int, error)
 	
 	// GetSpentForCategory returns total spent for a category in a month
-	GetSpentForCategory(ctx context.Context, householdID, categoryID, month string) (float64, error)
+	GetSpentForCategory(ctx context.Context, householdID, categoryID, month string) (money.Amount, error)
 	
 	// DeleteFutureRecords deletes budget records for a category after a month
 	DeleteFutureRecords(ctx context.Context, householdID, categoryID, afterMonth string) (int64, error)
 	
 	// UpdateAllRecords updates all budget records for a category to a new amount
-	UpdateAllRecords(ctx context.Context, householdID, categoryID string, amount float64) (int64, error)
+	UpdateAllRecords(ctx context.Context, householdID, categoryID string, amount money.Amount) (int64, error)
 
 	// GetEffectiveBudget returns the effective budget amount for a category at a given month
-	GetEffectiveBudget(ctx context.Context, householdID, categoryID, month string) (float64, error)
+	GetEffectiveBudget(ctx context.Context, householdID, categoryID, month string) (money.Amount, error)
 
 	// PinMonthIfMissing inserts a budget record only if none exists for that month
-	PinMonthIfMissing(ctx context.Context, householdID, categoryID, month string, amount float64) error
+	PinMonthIfMissing(ctx context.Context, householdID, categoryID, month string, amount money.Amount) error
 
 	// UpsertBudgetFromItems creates or updates budget to match items sum (preserves user buffer)
-	UpsertBudgetFromItems(ctx context.Context, householdID, categoryID, month string, itemsSum float64) error
+	UpsertBudgetFromItems(ctx context.Context, householdID, categoryID, month string, itemsSum money.Amount) error
 }
 
 // Service defines the interface for budget business logic
@@ -282,7 +282,8 @@ func (r *PostgresRepository) CheckNameExists(ctx context.Context, householdID, n
 	return count > 0, nil
 }
 
-// IsUsedInMovements checks if a category is used in any movements
+// IsUsedInMovements checks if a category is used in any movements, including
+// the ones in the trash (they still reference it)
 func (r *PostgresRepository) IsUsedInMovements(ctx context.Context, categoryID string) (bool, error) {
 	var count int
 	err := r.pool.QueryRow(ctx, `
@@ -50,6 +50,22 @@ type Config struct {
 	SpeechRegion     string
 	SpeechLanguage   string
 	SpeechResourceID string
+
+	// Bank notification email ingestion (disabled unless a path or IMAP address is set)
+	MailIngestPath     string // Maildir, folder of .eml files or mbox file
+	MailIngestIMAPAddr string // host:port of a local IMAP server
+	MailIngestIMAPTLS  bool
+	MailIngestIMAPUser string
+	MailIngestIMAPPass string
+	MailIngestMailbox  string
+	MailIngestUser     string // Email of the user the alerts belong to
+	MailIngestInterval time.Duration
+
+	// Movement attachments (receipts, invoices) on the local filesystem
+	AttachmentsDir string
+
+	// How long deleted movements and income stay in the trash
+	TrashRetention time.Duration
 }
 
 // Load reads configuration from environment variables.
@@ -146,6 +162,28 @@ func Load() (*Config, error) {
 	}
 	speechResourceID := os.Getenv("SPEECH_RESOURCE_ID")
 
+	// Bank notification emails
+	mailIngestInterval := 15 * time.Minute
+	if s := os.Getenv("MAIL_INGEST_INTERVAL"); s != "" {
+		if d, err := time.ParseDuration(s); err == nil && d > 0 {
+			mailIngestInterval = d
+		}
+	}
+
+	// Movement attachments
+	attachmentsDir := os.Getenv("ATTACHMENTS_DIR")
+	if attachmentsDir == "" {
+		attachmentsDir = "data/attachments"
+	}
+
+	// Trash
+	trashRetention := 30 * 24 * time.Hour
+	if s := os.Getenv("TRASH_RETENTION_DAYS"); s != "" {
+		if days, err := strconv.Atoi(s); err == nil && days > 0 {
+			trashRetention = time.Duration(days) * 24 * time.Hour
+		}
+	}
+
 	return &Config{
 		ServerAddr:            serverAddr,
 		DatabaseURL:           databaseURL,
@@ -170,5 +208,15 @@ func Load() (*Config, error) {
 		SpeechRegion:          speechRegion,
 		SpeechLanguage:        speechLanguage,
 		SpeechResourceID:      speechResourceID,
+		MailIngestPath:        os.Getenv("MAIL_INGEST_PATH"),
+		MailIngestIMAPAddr:    os.Getenv("MAIL_INGEST_IMAP_ADDR"),
+		MailIngestIMAPTLS:     os.Getenv("MAIL_INGEST_IMAP_TLS") == "true",
+		MailIngestIMAPUser:    os.Getenv("MAIL_INGEST_IMAP_USER"),
+		MailIngestIMAPPass:    os.Getenv("MAIL_INGEST_IMAP_PASSWORD"),
+		MailIngestMailbox:     os.Getenv("MAIL_INGEST_MAILBOX"),
+		MailIngestUser:        os.Getenv("MAIL_INGEST_USER_EMAIL"),
+		MailIngestInterval:    mailIngestInterval,
+		AttachmentsDir:        attachmentsDir,
+		TrashRetention:        trashRetention,
 	}, nil
 }
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/blanquicet/conti/backend/internal/auth"
+	"github.com/blanquicet/conti/backend/internal/money"
 )
 
 // Handler handles HTTP requests for credit card payments
@@ -28,13 +29,25 @@ func NewHandler(service Service, authService *auth.Service, cookieName string, l
 	}
 }
 
-// CreateRequest represents the request body for creating a credit card payment
+// CreateRequest represents the request body for creating a credit card payment.
+// The payment is drawn either from source_account_id or from sources.
 type CreateRequest struct {
-	CreditCardID    string  `json:"credit_card_id"`
-	Amount          float64 `json:"amount"`
-	PaymentDate     string  `json:"payment_date"` // YYYY-MM-DD format
-	Notes           *string `json:"notes,omitempty"`
-	SourceAccountID string  `json:"source_account_id"`
+	CreditCardID    string           `json:"credit_card_id"`
+	Amount          money.Amount     `json:"amount"`
+	PaymentDate     string           `json:"payment_date"` // YYYY-MM-DD format
+	Notes           *string          `json:"notes,omitempty"`
+	SourceAccountID string           `json:"source_account_id,omitempty"`
+	Sources         []*PaymentSource `json:"sources,omitempty"`
+}
+
+// UpdateRequest represents the request body for updating a credit card payment
+type UpdateRequest struct {
+	CreditCardID    *string          `json:"credit_card_id,omitempty"`
+	Amount          *money.Amount    `json:"amount,omitempty"`
+	PaymentDate     *string          `json:"payment_date,omitempty"` // YYYY-MM-DD format
+	Notes           *string          `json:"notes,omitempty"`
+	SourceAccountID *string          `json:"source_account_id,omitempty"`
+	Sources         []*PaymentSource `json:"sources,omitempty"`
 }
 
 // getUserFromSession extracts user from session cookie
@@ -74,27 +87,12 @@ func (h *Handler) HandleCreate(w http.ResponseWriter, r *http.Request) {
 		PaymentDate:     paymentDate,
 		Notes:           req.Notes,
 		SourceAccountID: req.SourceAccountID,
+		Sources:         req.Sources,
 	}
 
 	payment, err := h.service.Create(r.Context(), user.ID, input)
 	if err != nil {
-		switch {
-		case errors.Is(err, ErrInvalidAmount):
-			http.Error(w, err.Error(), http.StatusBadRequest)
-		case errors.Is(err, ErrCreditCardNotFound):
-			http.Error(w, err.Error(), http.StatusNotFound)
-		case errors.Is(err, ErrSourceAccountNotFound):
-			http.Error(w, err.Error(), http.StatusNotFound)
-		case errors.Is(err, ErrNotACreditCard):
-			http.Error(w, err.Error(), http.StatusBadRequest)
-		case errors.Is(err, ErrSourceMustBeSavings):
-			http.Error(w, err.Error(), http.StatusBadRequest)
-		case errors.Is(err, ErrNotAuthorized):
-			http.Error(w, err.Error(), http.StatusForbidden)
-		default:
-			h.logger.Error("failed to create payment", "error", err)
-			http.Error(w, "Internal server error", http.StatusInternalServerError)
-		}
+		h.writeError(w, "failed to create payment", err)
 		return
 	}
 
@@ -103,6 +101,74 @@ func (h *Handler) HandleCreate(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(payment)
 }
 
+// HandleUpdate handles PATCH /credit-card-payments/:id
+func (h *Handler) HandleUpdate(w http.ResponseWriter, r *http.Request) {
+	user, err := h.getUserFromSession(r)
+	if err != nil {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	paymentID := r.PathValue("id")
+
+	var req UpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	input := &UpdateInput{
+		CreditCardID:    req.CreditCardID,
+		Amount:          req.Amount,
+		Notes:           req.Notes,
+		SourceAccountID: req.SourceAccountID,
+		Sources:         req.Sources,
+	}
+	if req.PaymentDate != nil {
+		paymentDate, err := time.Parse("2006-01-02", *req.PaymentDate)
+		if err != nil {
+			http.Error(w, "Invalid payment_date format, use YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		input.PaymentDate = &paymentDate
+	}
+
+	payment, err := h.service.Update(r.Context(), user.ID, paymentID, input)
+	if err != nil {
+		h.writeError(w, "failed to update payment", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(payment)
+}
+
+// writeError maps create and update errors to HTTP responses
+func (h *Handler) writeError(w http.ResponseWriter, msg string, err error) {
+	switch {
+	case errors.Is(err, ErrPaymentNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, ErrCreditCardNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, ErrSourceAccountNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, ErrNotAuthorized):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, ErrInvalidAmount),
+		errors.Is(err, ErrNotACreditCard),
+		errors.Is(err, ErrSourceCannotHoldFunds),
+		errors.Is(err, ErrSourcesRequired),
+		errors.Is(err, ErrSourceAccountRequired),
+		errors.Is(err, ErrSourcesConflict),
+		errors.Is(err, ErrInvalidSourceAmount),
+		errors.Is(err, ErrDuplicateSource),
+		errors.Is(err, ErrSourcesMismatch):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		h.logger.Error(msg, "error", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	}
+}
+
 // HandleGet handles GET /credit-card-payments/:id
 func (h *Handler) HandleGet(w http.ResponseWriter, r *http.Request) {
 	user, err := h.getUserFromSession(r)
@@ -7,6 +7,8 @@ import (
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/blanquicet/conti/backend/internal/money"
 )
 
 // repository implements Repository using PostgreSQL
@@ -19,36 +21,36 @@ func NewRepository(pool *pgxpool.Pool) Repository {
 	return &repository{pool: pool}
 }
 
-// Create creates a new credit card payment
+// Create creates a new credit card payment with its sources
 func (r *repository) Create(ctx context.Context, payment *CreditCardPayment) (*CreditCardPayment, error) {
-	var result CreditCardPayment
-	err := r.pool.QueryRow(ctx, `
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var id string
+	err = tx.QueryRow(ctx, `
 		INSERT INTO credit_card_payments (
-			household_id, credit_card_id, amount, payment_date, notes,
-			source_account_id, created_by
+			household_id, credit_card_id, amount, payment_date, notes, created_by
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
-		RETURNING id, household_id, credit_card_id, amount, payment_date, notes,
-		          source_account_id, created_at, updated_at, created_by
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
 	`, payment.HouseholdID, payment.CreditCardID, payment.Amount, payment.PaymentDate,
-		payment.Notes, payment.SourceAccountID, payment.CreatedBy).Scan(
-		&result.ID,
-		&result.HouseholdID,
-		&result.CreditCardID,
-		&result.Amount,
-		&result.PaymentDate,
-		&result.Notes,
-		&result.SourceAccountID,
-		&result.CreatedAt,
-		&result.UpdatedAt,
-		&result.CreatedBy,
-	)
-
+		payment.Notes, payment.CreatedBy).Scan(&id)
 	if err != nil {
 		return nil, err
 	}
 
-	return &result, nil
+	if err := insertSources(ctx, tx, id, payment.Sources); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(ctx, id)
 }
 
 // GetByID retrieves a credit card payment by ID
@@ -56,12 +58,11 @@ func (r *repository) GetByID(ctx context.Context, id string) (*CreditCardPayment
 	var payment CreditCardPayment
 	err := r.pool.QueryRow(ctx, `
 		SELECT ccp.id, ccp.household_id, ccp.credit_card_id, ccp.amount,
-		       ccp.payment_date, ccp.notes, ccp.source_account_id,
+		       ccp.payment_date, ccp.notes,
 		       ccp.created_at, ccp.updated_at, ccp.created_by,
-		       pm.name as credit_card_name, a.name as source_account_name
+		       pm.name as credit_card_name
 		FROM credit_card_payments ccp
 		JOIN payment_methods pm ON ccp.credit_card_id = pm.id
-		JOIN accounts a ON ccp.source_account_id = a.id
 		WHERE ccp.id = $1
 	`, id).Scan(
 		&payment.ID,
@@ -70,12 +71,10 @@ func (r *repository) GetByID(ctx context.Context, id string) (*CreditCardPayment
 		&payment.Amount,
 		&payment.PaymentDate,
 		&payment.Notes,
-		&payment.SourceAccountID,
 		&payment.CreatedAt,
 		&payment.UpdatedAt,
 		&payment.CreatedBy,
 		&payment.CreditCardName,
-		&payment.SourceAccountName,
 	)
 
 	if err != nil {
@@ -85,10 +84,110 @@ func (r *repository) GetByID(ctx context.Context, id string) (*CreditCardPayment
 		return nil, err
 	}
 
+	if err := r.loadSources(ctx, []*CreditCardPayment{&payment}); err != nil {
+		return nil, err
+	}
+
 	return &payment, nil
 }
 
-// Delete deletes a credit card payment
+// Update updates a credit card payment and replaces its sources
+func (r *repository) Update(ctx context.Context, payment *CreditCardPayment) (*CreditCardPayment, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx, `
+		UPDATE credit_card_payments
+		SET credit_card_id = $2,
+		    amount = $3,
+		    payment_date = $4,
+		    notes = $5,
+		    updated_at = NOW()
+		WHERE id = $1
+	`, payment.ID, payment.CreditCardID, payment.Amount, payment.PaymentDate, payment.Notes)
+	if err != nil {
+		return nil, err
+	}
+	if result.RowsAffected() == 0 {
+		return nil, ErrPaymentNotFound
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM credit_card_payment_sources WHERE payment_id = $1`, payment.ID); err != nil {
+		return nil, err
+	}
+	if err := insertSources(ctx, tx, payment.ID, payment.Sources); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(ctx, payment.ID)
+}
+
+// insertSources stores the accounts a payment draws from
+func insertSources(ctx context.Context, tx pgx.Tx, paymentID string, sources []*PaymentSource) error {
+	for _, source := range sources {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO credit_card_payment_sources (payment_id, account_id, amount)
+			VALUES ($1, $2, $3)
+		`, paymentID, source.AccountID, source.Amount)
+		if err != nil {
+			return fmt.Errorf("insert payment source: %w", err)
+		}
+	}
+	return nil
+}
+
+// loadSources fills in the sources of payments and their source account names
+func (r *repository) loadSources(ctx context.Context, payments []*CreditCardPayment) error {
+	if len(payments) == 0 {
+		return nil
+	}
+
+	byID := make(map[string]*CreditCardPayment, len(payments))
+	ids := make([]string, 0, len(payments))
+	for _, p := range payments {
+		p.Sources = []*PaymentSource{}
+		byID[p.ID] = p
+		ids = append(ids, p.ID)
+	}
+
+	rows, err := r.pool.Query(ctx, `
+		SELECT s.payment_id, s.account_id, s.amount, a.name
+		FROM credit_card_payment_sources s
+		JOIN accounts a ON s.account_id = a.id
+		WHERE s.payment_id = ANY($1)
+		ORDER BY s.amount DESC, a.name
+	`, ids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var paymentID string
+		source := &PaymentSource{}
+		if err := rows.Scan(&paymentID, &source.AccountID, &source.Amount, &source.AccountName); err != nil {
+			return err
+		}
+		byID[paymentID].Sources = append(byID[paymentID].Sources, source)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, p := range payments {
+		p.SourceAccountName = sourceAccountNames(p.Sources)
+	}
+	return nil
+}
+
+// Delete deletes a credit card payment (its sources go by cascade)
 func (r *repository) Delete(ctx context.Context, id string) error {
 	result, err := r.pool.Exec(ctx, `
 		DELETE FROM credit_card_payments WHERE id = $1
@@ -109,12 +208,11 @@ func (r *repository) Delete(ctx context.Context, id string) error {
 func (r *repository) ListByHousehold(ctx context.Context, householdID string, filter *ListFilter) (*ListResponse, error) {
 	query := `
 		SELECT ccp.id, ccp.household_id, ccp.credit_card_id, ccp.amount,
-		       ccp.payment_date, ccp.notes, ccp.source_account_id,
+		       ccp.payment_date, ccp.notes,
 		       ccp.created_at, ccp.updated_at, ccp.created_by,
-		       pm.name as credit_card_name, a.name as source_account_name
+		       pm.name as credit_card_name
 		FROM credit_card_payments ccp
 		JOIN payment_methods pm ON ccp.credit_card_id = pm.id
-		JOIN accounts a ON ccp.source_account_id = a.id
 		WHERE ccp.household_id = $1
 	`
 	args := []any{householdID}
@@ -147,7 +245,7 @@ func (r *repository) ListByHousehold(ctx context.Context, householdID string, fi
 	defer rows.Close()
 
 	var payments []*CreditCardPayment
-	var total float64
+	var total money.Amount
 
 	for rows.Next() {
 		var payment CreditCardPayment
@@ -158,12 +256,10 @@ func (r *repository) ListByHousehold(ctx context.Context, householdID string, fi
 			&payment.Amount,
 			&payment.PaymentDate,
 			&payment.Notes,
-			&payment.SourceAccountID,
 			&payment.CreatedAt,
 			&payment.UpdatedAt,
 			&payment.CreatedBy,
 			&payment.CreditCardName,
-			&payment.SourceAccountName,
 		)
 		if err != nil {
 			return nil, err
@@ -175,6 +271,11 @@ func (r *repository) ListByHousehold(ctx context.Context, householdID string, fi
 	if err = rows.Err(); err != nil {
 		return nil, err
 	}
+	rows.Close()
+
+	if err := r.loadSources(ctx, payments); err != nil {
+		return nil, err
+	}
 
 	return &ListResponse{
 		Payments: payments,
@@ -52,45 +52,25 @@ func (s *service) Create(ctx context.Context, userID string, input *CreateInput)
 		return nil, err
 	}
 
-	// Verify credit card exists and is a credit card
-	creditCard, err := s.paymentMethodsRepo.GetByID(ctx, input.CreditCardID)
+	creditCard, err := s.getCreditCard(ctx, householdID, input.CreditCardID)
 	if err != nil {
-		if err == paymentmethods.ErrPaymentMethodNotFound {
-			return nil, ErrCreditCardNotFound
-		}
 		return nil, err
 	}
-	if creditCard.HouseholdID != householdID {
-		return nil, ErrNotAuthorized
-	}
-	if creditCard.Type != paymentmethods.TypeCreditCard {
-		return nil, ErrNotACreditCard
-	}
 
-	// Verify source account exists and is a savings account
-	sourceAccount, err := s.accountsRepo.GetByID(ctx, input.SourceAccountID)
-	if err != nil {
-		if err == accounts.ErrAccountNotFound {
-			return nil, ErrSourceAccountNotFound
-		}
+	sources := input.PaymentSources()
+	if err := s.checkSources(ctx, householdID, sources); err != nil {
 		return nil, err
 	}
-	if sourceAccount.HouseholdID != householdID {
-		return nil, ErrNotAuthorized
-	}
-	if sourceAccount.Type != accounts.TypeSavings {
-		return nil, ErrSourceMustBeSavings
-	}
 
 	// Create the payment
 	payment := &CreditCardPayment{
-		HouseholdID:     householdID,
-		CreditCardID:    input.CreditCardID,
-		Amount:          input.Amount,
-		PaymentDate:     input.PaymentDate,
-		Notes:           input.Notes,
-		SourceAccountID: input.SourceAccountID,
-		CreatedBy:       userID,
+		HouseholdID:  householdID,
+		CreditCardID: input.CreditCardID,
+		Amount:       input.Amount,
+		PaymentDate:  input.PaymentDate,
+		Notes:        input.Notes,
+		Sources:      sources,
+		CreatedBy:    userID,
 	}
 
 	result, err := s.repo.Create(ctx, payment)
@@ -108,7 +88,7 @@ func (s *service) Create(ctx context.Context, userID string, input *CreateInput)
 
 	// Add names from the lookups we already did
 	result.CreditCardName = creditCard.Name
-	result.SourceAccountName = sourceAccount.Name
+	result.SourceAccountName = sourceAccountNames(sources)
 
 	// Log successful creation
 	s.auditService.LogAsync(ctx, &audit.LogInput{
@@ -145,6 +125,74 @@ func (s *service) GetByID(ctx context.Context, userID, id string) (*CreditCardPa
 	return payment, nil
 }
 
+// Update changes the fields of a credit card payment given in the input
+func (s *service) Update(ctx context.Context, userID, id string, input *UpdateInput) (*CreditCardPayment, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Get user's household
+	householdID, err := s.householdRepo.GetUserHouseholdID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get payment to verify ownership
+	existing, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if existing.HouseholdID != householdID {
+		return nil, ErrNotAuthorized
+	}
+	// Kept before the repository can change the payment it returned
+	oldValues := audit.StructToMap(existing)
+
+	payment := input.apply(existing)
+	if err := validateSources(payment.Sources, payment.Amount); err != nil {
+		return nil, err
+	}
+
+	creditCard, err := s.getCreditCard(ctx, householdID, payment.CreditCardID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkSources(ctx, householdID, payment.Sources); err != nil {
+		return nil, err
+	}
+
+	result, err := s.repo.Update(ctx, payment)
+	if err != nil {
+		s.auditService.LogAsync(ctx, &audit.LogInput{
+			UserID:       audit.StringPtr(userID),
+			Action:       audit.ActionCreditCardPaymentUpdated,
+			ResourceType: "credit_card_payment",
+			ResourceID:   audit.StringPtr(id),
+			HouseholdID:  audit.StringPtr(householdID),
+			Success:      false,
+			ErrorMessage: audit.StringPtr(err.Error()),
+		})
+		return nil, err
+	}
+
+	result.CreditCardName = creditCard.Name
+	result.SourceAccountName = sourceAccountNames(result.Sources)
+
+	// Log successful update
+	s.auditService.LogAsync(ctx, &audit.LogInput{
+		UserID:       audit.StringPtr(userID),
+		Action:       audit.ActionCreditCardPaymentUpdated,
+		ResourceType: "credit_card_payment",
+		ResourceID:   audit.StringPtr(id),
+		HouseholdID:  audit.StringPtr(householdID),
+		OldValues:    oldValues,
+		NewValues:    audit.StructToMap(result),
+		Success:      true,
+	})
+
+	return result, nil
+}
+
 // Delete deletes a credit card payment
 func (s *service) Delete(ctx context.Context, userID, id string) error {
 	// Get user's household
@@ -202,3 +250,43 @@ func (s *service) List(ctx context.Context, userID string, filter *ListFilter) (
 
 	return s.repo.ListByHousehold(ctx, householdID, filter)
 }
+
+// getCreditCard returns a credit card of the household
+func (s *service) getCreditCard(ctx context.Context, householdID, id string) (*paymentmethods.PaymentMethod, error) {
+	creditCard, err := s.paymentMethodsRepo.GetByID(ctx, id)
+	if err != nil {
+		if err == paymentmethods.ErrPaymentMethodNotFound {
+			return nil, ErrCreditCardNotFound
+		}
+		return nil, err
+	}
+	if creditCard.HouseholdID != householdID {
+		return nil, ErrNotAuthorized
+	}
+	if creditCard.Type != paymentmethods.TypeCreditCard {
+		return nil, ErrNotACreditCard
+	}
+	return creditCard, nil
+}
+
+// checkSources verifies that every source is an account of the household that
+// can hold funds, and fills in the account names
+func (s *service) checkSources(ctx context.Context, householdID string, sources []*PaymentSource) error {
+	for _, source := range sources {
+		account, err := s.accountsRepo.GetByID(ctx, source.AccountID)
+		if err != nil {
+			if err == accounts.ErrAccountNotFound {
+				return ErrSourceAccountNotFound
+			}
+			return err
+		}
+		if account.HouseholdID != householdID {
+			return ErrNotAuthorized
+		}
+		if !account.Type.HoldsFunds() {
+			return ErrSourceCannotHoldFunds
+		}
+		source.AccountName = account.Name
+	}
+	return nil
+}
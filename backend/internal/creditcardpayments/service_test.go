@@ -9,6 +9,7 @@ import (
 	"github.com/blanquicet/conti/backend/internal/accounts"
 	"github.com/blanquicet/conti/backend/internal/audit"
 	"github.com/blanquicet/conti/backend/internal/households"
+	"github.com/blanquicet/conti/backend/internal/money"
 	"github.com/blanquicet/conti/backend/internal/paymentmethods"
 )
 
@@ -42,6 +43,15 @@ func (m *MockRepository) GetByID(ctx context.Context, id string) (*CreditCardPay
 	return payment, nil
 }
 
+func (m *MockRepository) Update(ctx context.Context, payment *CreditCardPayment) (*CreditCardPayment, error) {
+	if _, ok := m.payments[payment.ID]; !ok {
+		return nil, ErrPaymentNotFound
+	}
+	payment.UpdatedAt = time.Now()
+	m.payments[payment.ID] = payment
+	return payment, nil
+}
+
 func (m *MockRepository) Delete(ctx context.Context, id string) error {
 	if _, ok := m.payments[id]; !ok {
 		return ErrPaymentNotFound
@@ -52,7 +62,7 @@ func (m *MockRepository) Delete(ctx context.Context, id string) error {
 
 func (m *MockRepository) ListByHousehold(ctx context.Context, householdID string, filter *ListFilter) (*ListResponse, error) {
 	var result []*CreditCardPayment
-	var total float64
+	var total money.Amount
 	for _, p := range m.payments {
 		if p.HouseholdID == householdID {
 			if filter != nil && filter.CreditCardID != nil && p.CreditCardID != *filter.CreditCardID {
@@ -293,10 +303,38 @@ func (m *MockAccountsRepository) FindByName(ctx context.Context, householdID, na
 	}
 	return nil, accounts.ErrAccountNotFound
 }
-func (m *MockAccountsRepository) GetBalance(ctx context.Context, id string) (float64, error) {
+func (m *MockAccountsRepository) GetBalance(ctx context.Context, id string) (money.Amount, error) {
 	return 0, nil
 }
 
+func (m *MockAccountsRepository) GetBalanceAt(ctx context.Context, id string, asOf time.Time) (money.Amount, error) {
+	return 0, nil
+}
+
+func (m *MockAccountsRepository) ListActivity(ctx context.Context, id string, after *time.Time, upTo time.Time) ([]*accounts.Activity, error) {
+	return nil, nil
+}
+
+func (m *MockAccountsRepository) CreateCheckpoint(ctx context.Context, checkpoint *accounts.Checkpoint) (*accounts.Checkpoint, error) {
+	return checkpoint, nil
+}
+
+func (m *MockAccountsRepository) GetCheckpoint(ctx context.Context, id string) (*accounts.Checkpoint, error) {
+	return nil, accounts.ErrCheckpointNotFound
+}
+
+func (m *MockAccountsRepository) ListCheckpoints(ctx context.Context, accountID string) ([]*accounts.Checkpoint, error) {
+	return nil, nil
+}
+
+func (m *MockAccountsRepository) SetCheckpointAdjustment(ctx context.Context, id string, adjustment money.Amount) error {
+	return nil
+}
+
+func (m *MockAccountsRepository) DeleteCheckpoint(ctx context.Context, id string) error {
+	return nil
+}
+
 // MockAuditService for testing
 type MockAuditService struct{}
 
@@ -321,7 +359,7 @@ func generateID(n int) string {
 func TestCreateInput_Validate(t *testing.T) {
 	validInput := &CreateInput{
 		CreditCardID:    "card-1",
-		Amount:          100.0,
+		Amount:          money.New(100),
 		PaymentDate:     time.Now(),
 		SourceAccountID: "account-1",
 	}
@@ -332,7 +370,7 @@ func TestCreateInput_Validate(t *testing.T) {
 
 	// Test missing credit card ID
 	invalidInput := &CreateInput{
-		Amount:          100.0,
+		Amount:          money.New(100),
 		PaymentDate:     time.Now(),
 		SourceAccountID: "account-1",
 	}
@@ -354,7 +392,7 @@ func TestCreateInput_Validate(t *testing.T) {
 	// Test negative amount
 	invalidInput = &CreateInput{
 		CreditCardID:    "card-1",
-		Amount:          -50.0,
+		Amount:          money.New(-50),
 		PaymentDate:     time.Now(),
 		SourceAccountID: "account-1",
 	}
@@ -365,7 +403,7 @@ func TestCreateInput_Validate(t *testing.T) {
 	// Test missing source account
 	invalidInput = &CreateInput{
 		CreditCardID: "card-1",
-		Amount:       100.0,
+		Amount:       money.New(100),
 		PaymentDate:  time.Now(),
 	}
 	if err := invalidInput.Validate(); err == nil {
@@ -375,7 +413,7 @@ func TestCreateInput_Validate(t *testing.T) {
 	// Test missing payment date
 	invalidInput = &CreateInput{
 		CreditCardID:    "card-1",
-		Amount:          100.0,
+		Amount:          money.New(100),
 		SourceAccountID: "account-1",
 	}
 	if err := invalidInput.Validate(); err == nil {
@@ -398,7 +436,7 @@ func TestCreate_Success(t *testing.T) {
 
 	input := &CreateInput{
 		CreditCardID:    "card-1",
-		Amount:          100.0,
+		Amount:          money.New(100),
 		PaymentDate:     time.Now(),
 		SourceAccountID: "account-1",
 	}
@@ -407,7 +445,7 @@ func TestCreate_Success(t *testing.T) {
 	if err != nil {
 		t.Errorf("Create() error = %v", err)
 	}
-	if payment.Amount != 100.0 {
+	if payment.Amount != money.New(100) {
 		t.Errorf("Create() amount = %v, want 100.0", payment.Amount)
 	}
 	if payment.CreditCardName != "AMEX" {
@@ -433,7 +471,7 @@ func TestCreate_CreditCardNotFound(t *testing.T) {
 
 	input := &CreateInput{
 		CreditCardID:    "card-1",
-		Amount:          100.0,
+		Amount:          money.New(100),
 		PaymentDate:     time.Now(),
 		SourceAccountID: "account-1",
 	}
@@ -459,7 +497,7 @@ func TestCreate_NotACreditCard(t *testing.T) {
 
 	input := &CreateInput{
 		CreditCardID:    "card-1",
-		Amount:          100.0,
+		Amount:          money.New(100),
 		PaymentDate:     time.Now(),
 		SourceAccountID: "account-1",
 	}
@@ -470,7 +508,7 @@ func TestCreate_NotACreditCard(t *testing.T) {
 	}
 }
 
-func TestCreate_SourceAccountNotSavings(t *testing.T) {
+func TestCreate_SourceAccountChecking(t *testing.T) {
 	repo := NewMockRepository()
 	householdRepo := NewMockHouseholdRepository()
 	pmRepo := NewMockPaymentMethodsRepository()
@@ -485,14 +523,62 @@ func TestCreate_SourceAccountNotSavings(t *testing.T) {
 
 	input := &CreateInput{
 		CreditCardID:    "card-1",
-		Amount:          100.0,
+		Amount:          money.New(100),
 		PaymentDate:     time.Now(),
 		SourceAccountID: "account-1",
 	}
 
-	_, err := svc.Create(context.Background(), "user-1", input)
-	if err != ErrSourceMustBeSavings {
-		t.Errorf("Create() error = %v, want ErrSourceMustBeSavings", err)
+	payment, err := svc.Create(context.Background(), "user-1", input)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if len(payment.Sources) != 1 || payment.Sources[0].AccountID != "account-1" || payment.Sources[0].Amount != money.New(100) {
+		t.Errorf("Create() sources = %+v, want account-1 for the whole amount", payment.Sources)
+	}
+}
+
+func TestCreate_MultipleSources(t *testing.T) {
+	repo := NewMockRepository()
+	householdRepo := NewMockHouseholdRepository()
+	pmRepo := NewMockPaymentMethodsRepository()
+	accRepo := NewMockAccountsRepository()
+	auditSvc := &MockAuditService{}
+
+	householdRepo.AddTestMember("household-1", "user-1", households.RoleOwner)
+	pmRepo.AddTestCard("card-1", "household-1", "AMEX", paymentmethods.TypeCreditCard)
+	accRepo.AddTestAccount("account-1", "household-1", "Savings", accounts.TypeSavings)
+	accRepo.AddTestAccount("account-2", "household-1", "Efectivo", accounts.TypeCash)
+
+	svc := NewService(repo, householdRepo, pmRepo, accRepo, auditSvc, nil)
+
+	input := &CreateInput{
+		CreditCardID: "card-1",
+		Amount:       money.New(500),
+		PaymentDate:  time.Now(),
+		Sources: []*PaymentSource{
+			{AccountID: "account-1", Amount: money.New(400)},
+			{AccountID: "account-2", Amount: money.New(100)},
+		},
+	}
+
+	payment, err := svc.Create(context.Background(), "user-1", input)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if payment.SourceAccountName != "Savings, Efectivo" {
+		t.Errorf("Create() source account name = %v, want Savings, Efectivo", payment.SourceAccountName)
+	}
+
+	// Sources must add up to the payment
+	input.Sources[1].Amount = money.New(50)
+	if _, err := svc.Create(context.Background(), "user-1", input); err != ErrSourcesMismatch {
+		t.Errorf("Create() with short sources error = %v, want ErrSourcesMismatch", err)
+	}
+
+	// An account can't be a source twice
+	input.Sources[1] = &PaymentSource{AccountID: "account-1", Amount: money.New(100)}
+	if _, err := svc.Create(context.Background(), "user-1", input); err != ErrDuplicateSource {
+		t.Errorf("Create() with a repeated source error = %v, want ErrDuplicateSource", err)
 	}
 }
 
@@ -511,7 +597,7 @@ func TestCreate_NotAuthorized_DifferentHousehold(t *testing.T) {
 
 	input := &CreateInput{
 		CreditCardID:    "card-1",
-		Amount:          100.0,
+		Amount:          money.New(100),
 		PaymentDate:     time.Now(),
 		SourceAccountID: "account-1",
 	}
@@ -533,12 +619,12 @@ func TestDelete_Success(t *testing.T) {
 
 	// Create a payment directly in repo
 	payment := &CreditCardPayment{
-		HouseholdID:     "household-1",
-		CreditCardID:    "card-1",
-		Amount:          100.0,
-		PaymentDate:     time.Now(),
-		SourceAccountID: "account-1",
-		CreatedBy:       "user-1",
+		HouseholdID:  "household-1",
+		CreditCardID: "card-1",
+		Amount:       money.New(100),
+		PaymentDate:  time.Now(),
+		Sources:      []*PaymentSource{{AccountID: "account-1", Amount: money.New(100)}},
+		CreatedBy:    "user-1",
 	}
 	createdPayment, _ := repo.Create(context.Background(), payment)
 
@@ -568,12 +654,12 @@ func TestDelete_NotAuthorized(t *testing.T) {
 
 	// Create a payment in household-2
 	payment := &CreditCardPayment{
-		HouseholdID:     "household-2",
-		CreditCardID:    "card-1",
-		Amount:          100.0,
-		PaymentDate:     time.Now(),
-		SourceAccountID: "account-1",
-		CreatedBy:       "user-2",
+		HouseholdID:  "household-2",
+		CreditCardID: "card-1",
+		Amount:       money.New(100),
+		PaymentDate:  time.Now(),
+		Sources:      []*PaymentSource{{AccountID: "account-1", Amount: money.New(100)}},
+		CreatedBy:    "user-2",
 	}
 	createdPayment, _ := repo.Create(context.Background(), payment)
 
@@ -599,19 +685,19 @@ func TestList_FilterByCreditCard(t *testing.T) {
 	repo.Create(context.Background(), &CreditCardPayment{
 		HouseholdID:  "household-1",
 		CreditCardID: "card-1",
-		Amount:       100.0,
+		Amount:       money.New(100),
 		PaymentDate:  time.Now(),
 	})
 	repo.Create(context.Background(), &CreditCardPayment{
 		HouseholdID:  "household-1",
 		CreditCardID: "card-2",
-		Amount:       200.0,
+		Amount:       money.New(200),
 		PaymentDate:  time.Now(),
 	})
 	repo.Create(context.Background(), &CreditCardPayment{
 		HouseholdID:  "household-1",
 		CreditCardID: "card-1",
-		Amount:       150.0,
+		Amount:       money.New(150),
 		PaymentDate:  time.Now(),
 	})
 
@@ -627,7 +713,80 @@ func TestList_FilterByCreditCard(t *testing.T) {
 	if len(response.Payments) != 2 {
 		t.Errorf("List() returned %d payments, want 2", len(response.Payments))
 	}
-	if response.Total != 250.0 {
+	if response.Total != money.New(250) {
 		t.Errorf("List() total = %v, want 250.0", response.Total)
 	}
 }
+
+func TestUpdate(t *testing.T) {
+	repo := NewMockRepository()
+	householdRepo := NewMockHouseholdRepository()
+	pmRepo := NewMockPaymentMethodsRepository()
+	accRepo := NewMockAccountsRepository()
+	auditSvc := &MockAuditService{}
+
+	householdRepo.AddTestMember("household-1", "user-1", households.RoleOwner)
+	householdRepo.AddTestMember("household-2", "user-2", households.RoleOwner)
+	pmRepo.AddTestCard("card-1", "household-1", "AMEX", paymentmethods.TypeCreditCard)
+	accRepo.AddTestAccount("account-1", "household-1", "Savings", accounts.TypeSavings)
+	accRepo.AddTestAccount("account-2", "household-1", "Checking", accounts.TypeChecking)
+
+	svc := NewService(repo, householdRepo, pmRepo, accRepo, auditSvc, nil)
+
+	created, err := svc.Create(context.Background(), "user-1", &CreateInput{
+		CreditCardID:    "card-1",
+		Amount:          money.New(100),
+		PaymentDate:     time.Now(),
+		SourceAccountID: "account-1",
+	})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	// Changing only the amount of a single-source payment moves its source
+	amount := money.New(120)
+	notes := "Pago total"
+	updated, err := svc.Update(context.Background(), "user-1", created.ID, &UpdateInput{Amount: &amount, Notes: &notes})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if updated.Amount != amount || updated.Sources[0].Amount != amount {
+		t.Errorf("Update() amount = %v, source = %v, want 120", updated.Amount, updated.Sources[0].Amount)
+	}
+	if updated.Notes == nil || *updated.Notes != "Pago total" {
+		t.Errorf("Update() notes = %v, want Pago total", updated.Notes)
+	}
+
+	// Split it across two accounts
+	updated, err = svc.Update(context.Background(), "user-1", created.ID, &UpdateInput{Sources: []*PaymentSource{
+		{AccountID: "account-1", Amount: money.New(20)},
+		{AccountID: "account-2", Amount: money.New(100)},
+	}})
+	if err != nil {
+		t.Fatalf("Update() with sources error = %v", err)
+	}
+	if len(updated.Sources) != 2 {
+		t.Errorf("Update() sources = %d, want 2", len(updated.Sources))
+	}
+
+	// With several sources, a new amount needs new sources
+	amount = money.New(150)
+	if _, err := svc.Update(context.Background(), "user-1", created.ID, &UpdateInput{Amount: &amount}); err != ErrSourcesMismatch {
+		t.Errorf("Update() amount only error = %v, want ErrSourcesMismatch", err)
+	}
+
+	// Empty notes clear them
+	empty := ""
+	updated, err = svc.Update(context.Background(), "user-1", created.ID, &UpdateInput{Notes: &empty})
+	if err != nil {
+		t.Fatalf("Update() clearing notes error = %v", err)
+	}
+	if updated.Notes != nil {
+		t.Errorf("Update() notes = %v, want nil", *updated.Notes)
+	}
+
+	// Another household can't edit it
+	if _, err := svc.Update(context.Background(), "user-2", created.ID, &UpdateInput{Notes: &notes}); err != ErrNotAuthorized {
+		t.Errorf("Update() by another household error = %v, want ErrNotAuthorized", err)
+	}
+}
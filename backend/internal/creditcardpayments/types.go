@@ -3,7 +3,10 @@ package creditcardpayments
 import (
 	"context"
 	"errors"
+	"strings"
 	"time"
+
+	"github.com/blanquicet/conti/backend/internal/money"
 )
 
 // Errors for credit card payment operations
@@ -14,7 +17,13 @@ var (
 	ErrCreditCardNotFound       = errors.New("credit card not found")
 	ErrSourceAccountNotFound    = errors.New("source account not found")
 	ErrNotACreditCard           = errors.New("payment method is not a credit card")
-	ErrSourceMustBeSavings      = errors.New("source account must be a savings account")
+	ErrSourceCannotHoldFunds    = errors.New("source account can't hold funds")
+	ErrSourcesRequired          = errors.New("source_account_id or sources is required")
+	ErrSourceAccountRequired    = errors.New("every source needs an account_id")
+	ErrSourcesConflict          = errors.New("use either source_account_id or sources, not both")
+	ErrInvalidSourceAmount      = errors.New("source amount must be greater than 0")
+	ErrDuplicateSource          = errors.New("an account can only be a source once")
+	ErrSourcesMismatch          = errors.New("source amounts must add up to the payment amount")
 )
 
 // CreditCardPayment represents a payment made to a credit card
@@ -22,26 +31,35 @@ type CreditCardPayment struct {
 	ID                string    `json:"id"`
 	HouseholdID       string    `json:"household_id"`
 	CreditCardID      string    `json:"credit_card_id"`
-	Amount            float64   `json:"amount"`
+	Amount            money.Amount   `json:"amount"`
 	PaymentDate       time.Time `json:"payment_date"`
 	Notes             *string   `json:"notes,omitempty"`
-	SourceAccountID   string    `json:"source_account_id"`
+	Sources           []*PaymentSource `json:"sources"` // Accounts the payment draws from, largest first
 	CreatedAt         time.Time `json:"created_at"`
 	UpdatedAt         time.Time `json:"updated_at"`
 	CreatedBy         string    `json:"created_by"`
 
 	// Populated from joins - not in DB table
 	CreditCardName    string    `json:"credit_card_name,omitempty"`
-	SourceAccountName string    `json:"source_account_name,omitempty"`
+	SourceAccountName string    `json:"source_account_name,omitempty"` // Names of the source accounts, comma-separated
+}
+
+// PaymentSource is the part of a payment drawn from one account
+type PaymentSource struct {
+	AccountID   string       `json:"account_id"`
+	Amount      money.Amount `json:"amount"`
+	AccountName string       `json:"account_name,omitempty"` // Populated from join
 }
 
-// CreateInput contains the fields needed to create a credit card payment
+// CreateInput contains the fields needed to create a credit card payment.
+// SourceAccountID is a shorthand for a single source paying the whole amount.
 type CreateInput struct {
 	CreditCardID    string    `json:"credit_card_id"`
-	Amount          float64   `json:"amount"`
+	Amount          money.Amount   `json:"amount"`
 	PaymentDate     time.Time `json:"payment_date"`
 	Notes           *string   `json:"notes,omitempty"`
-	SourceAccountID string    `json:"source_account_id"`
+	SourceAccountID string    `json:"source_account_id,omitempty"`
+	Sources         []*PaymentSource `json:"sources,omitempty"`
 }
 
 // Validate validates the create input
@@ -52,12 +70,109 @@ func (i *CreateInput) Validate() error {
 	if i.Amount <= 0 {
 		return ErrInvalidAmount
 	}
-	if i.SourceAccountID == "" {
-		return errors.New("source_account_id is required")
+	if i.SourceAccountID == "" && len(i.Sources) == 0 {
+		return ErrSourcesRequired
+	}
+	if i.SourceAccountID != "" && len(i.Sources) > 0 {
+		return ErrSourcesConflict
 	}
 	if i.PaymentDate.IsZero() {
 		return errors.New("payment_date is required")
 	}
+	return validateSources(i.PaymentSources(), i.Amount)
+}
+
+// PaymentSources returns the accounts the payment draws from
+func (i *CreateInput) PaymentSources() []*PaymentSource {
+	if i.SourceAccountID != "" {
+		return []*PaymentSource{{AccountID: i.SourceAccountID, Amount: i.Amount}}
+	}
+	return i.Sources
+}
+
+// UpdateInput contains the fields of a credit card payment to change (nil
+// leaves a field as it is). An empty notes string clears the notes. When only
+// the amount changes, a payment with a single source keeps drawing it all from
+// that account; one with several needs its sources too.
+type UpdateInput struct {
+	CreditCardID    *string          `json:"credit_card_id,omitempty"`
+	Amount          *money.Amount    `json:"amount,omitempty"`
+	PaymentDate     *time.Time       `json:"payment_date,omitempty"`
+	Notes           *string          `json:"notes,omitempty"`
+	SourceAccountID *string          `json:"source_account_id,omitempty"`
+	Sources         []*PaymentSource `json:"sources,omitempty"`
+}
+
+// Validate validates the update input
+func (i *UpdateInput) Validate() error {
+	if i.Amount != nil && *i.Amount <= 0 {
+		return ErrInvalidAmount
+	}
+	if i.SourceAccountID != nil && len(i.Sources) > 0 {
+		return ErrSourcesConflict
+	}
+	return nil
+}
+
+// apply returns the payment with the changes of the input
+func (i *UpdateInput) apply(payment *CreditCardPayment) *CreditCardPayment {
+	updated := *payment
+	if i.CreditCardID != nil {
+		updated.CreditCardID = *i.CreditCardID
+	}
+	if i.Amount != nil {
+		updated.Amount = *i.Amount
+	}
+	if i.PaymentDate != nil {
+		updated.PaymentDate = *i.PaymentDate
+	}
+	if i.Notes != nil {
+		updated.Notes = i.Notes
+		if *i.Notes == "" {
+			updated.Notes = nil
+		}
+	}
+	switch {
+	case len(i.Sources) > 0:
+		updated.Sources = i.Sources
+	case i.SourceAccountID != nil:
+		updated.Sources = []*PaymentSource{{AccountID: *i.SourceAccountID, Amount: updated.Amount}}
+	case i.Amount != nil && len(payment.Sources) == 1:
+		updated.Sources = []*PaymentSource{{AccountID: payment.Sources[0].AccountID, Amount: updated.Amount}}
+	}
+	return &updated
+}
+
+// sourceAccountNames returns the names of the source accounts, comma-separated
+func sourceAccountNames(sources []*PaymentSource) string {
+	names := make([]string, len(sources))
+	for i, source := range sources {
+		names[i] = source.AccountName
+	}
+	return strings.Join(names, ", ")
+}
+
+// validateSources checks that the sources are different accounts whose
+// amounts add up to the payment amount
+func validateSources(sources []*PaymentSource, amount money.Amount) error {
+	seen := make(map[string]bool, len(sources))
+	var total money.Amount
+	for _, source := range sources {
+		if source.AccountID == "" {
+			return ErrSourceAccountRequired
+		}
+		if source.Amount <= 0 {
+			return ErrInvalidSourceAmount
+		}
+		if seen[source.AccountID] {
+			return ErrDuplicateSource
+		}
+		seen[source.AccountID] = true
+		total += source.Amount
+	}
+	if total != amount {
+		return ErrSourcesMismatch
+	}
 	return nil
 }
 
@@ -71,13 +186,14 @@ type ListFilter struct {
 // ListResponse contains the list of payments and totals
 type ListResponse struct {
 	Payments []*CreditCardPayment `json:"payments"`
-	Total    float64              `json:"total"`
+	Total    money.Amount              `json:"total"`
 }
 
 // Repository defines the interface for credit card payment persistence
 type Repository interface {
 	Create(ctx context.Context, payment *CreditCardPayment) (*CreditCardPayment, error)
 	GetByID(ctx context.Context, id string) (*CreditCardPayment, error)
+	Update(ctx context.Context, payment *CreditCardPayment) (*CreditCardPayment, error)
 	Delete(ctx context.Context, id string) error
 	ListByHousehold(ctx context.Context, householdID string, filter *ListFilter) (*ListResponse, error)
 }
@@ -86,6 +202,7 @@ type Repository interface {
 type Service interface {
 	Create(ctx context.Context, userID string, input *CreateInput) (*CreditCardPayment, error)
 	GetByID(ctx context.Context, userID, id string) (*CreditCardPayment, error)
+	Update(ctx context.Context, userID, id string, input *UpdateInput) (*CreditCardPayment, error)
 	Delete(ctx context.Context, userID, id string) error
 	List(ctx context.Context, userID string, filter *ListFilter) (*ListResponse, error)
 }
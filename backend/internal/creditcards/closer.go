@@ -0,0 +1,64 @@
+package creditcards
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Closer periodically closes the billing cycles that ended into statements
+type Closer struct {
+	service  Service
+	logger   *slog.Logger
+	stopChan chan struct{}
+}
+
+// NewCloser creates a new statement closer
+func NewCloser(service Service, logger *slog.Logger) *Closer {
+	return &Closer{
+		service:  service,
+		logger:   logger,
+		stopChan: make(chan struct{}),
+	}
+}
+
+// Start begins the closing loop (runs every 12 hours)
+func (c *Closer) Start(ctx context.Context) {
+	ticker := time.NewTicker(12 * time.Hour)
+	defer ticker.Stop()
+
+	c.logger.Info("card statement closer started (runs every 12 hours)")
+
+	// Run immediately on start
+	c.close(ctx, time.Now())
+
+	for {
+		select {
+		case <-ticker.C:
+			c.close(ctx, time.Now())
+		case <-c.stopChan:
+			c.logger.Info("card statement closer stopped")
+			return
+		case <-ctx.Done():
+			c.logger.Info("card statement closer context canceled")
+			return
+		}
+	}
+}
+
+// Stop stops the closer
+func (c *Closer) Stop() {
+	close(c.stopChan)
+}
+
+// close closes the cycles that ended by now
+func (c *Closer) close(ctx context.Context, now time.Time) {
+	count, err := c.service.CloseEndedCycles(ctx, now)
+	if err != nil {
+		c.logger.Error("failed to close card statements", "error", err)
+		return
+	}
+	if count > 0 {
+		c.logger.Info("closed card statements", "count", count)
+	}
+}
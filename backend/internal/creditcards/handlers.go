@@ -140,3 +140,217 @@ func (h *Handler) HandleGetCardMovements(w http.ResponseWriter, r *http.Request)
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(movements)
 }
+
+// HandleGetInstallments handles GET /credit-cards/{id}/installments
+// Query params:
+//   - as_of: optional, date within the first billing cycle to list (default: today), format: YYYY-MM-DD
+func (h *Handler) HandleGetInstallments(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// Get user from session cookie
+	cookie, err := r.Cookie(h.cookieName)
+	if err != nil {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	user, err := h.authSvc.GetUserBySession(ctx, cookie.Value)
+	if err != nil {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	cardID := r.PathValue("id")
+	if cardID == "" {
+		http.Error(w, "card ID required", http.StatusBadRequest)
+		return
+	}
+
+	// Parse as-of date (default to today)
+	asOf := time.Now()
+	if dateStr := r.URL.Query().Get("as_of"); dateStr != "" {
+		parsed, err := time.Parse("2006-01-02", dateStr)
+		if err != nil {
+			http.Error(w, "invalid as_of format, expected YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		asOf = parsed
+	}
+
+	installments, err := h.service.GetInstallments(ctx, user.ID, cardID, asOf)
+	if err != nil {
+		if errors.Is(err, ErrNotAuthorized) {
+			http.Error(w, "not authorized", http.StatusForbidden)
+			return
+		}
+		if errors.Is(err, ErrCardNotFound) {
+			http.Error(w, "credit card not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(installments)
+}
+
+// HandleListStatements handles GET /credit-cards/{id}/statements
+func (h *Handler) HandleListStatements(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, ok := h.getUser(w, r)
+	if !ok {
+		return
+	}
+
+	statements, err := h.service.ListStatements(ctx, user.ID, r.PathValue("id"))
+	if err != nil {
+		h.writeStatementError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"statements": statements,
+	})
+}
+
+// HandleCloseStatement handles POST /credit-cards/{id}/statements
+// Body (optional): {"cycle_date": "YYYY-MM-DD"}, a date within the billing
+// cycle to close (default: the last cycle that has ended)
+func (h *Handler) HandleCloseStatement(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, ok := h.getUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		CycleDate *string `json:"cycle_date"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	var cycleDate *time.Time
+	if req.CycleDate != nil && *req.CycleDate != "" {
+		parsed, err := time.Parse("2006-01-02", *req.CycleDate)
+		if err != nil {
+			http.Error(w, "invalid cycle_date format, expected YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		cycleDate = &parsed
+	}
+
+	statement, err := h.service.CloseStatement(ctx, user.ID, r.PathValue("id"), cycleDate)
+	if err != nil {
+		h.writeStatementError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(statement)
+}
+
+// HandleGetStatement handles GET /credit-cards/{id}/statements/{statementId}
+func (h *Handler) HandleGetStatement(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, ok := h.getUser(w, r)
+	if !ok {
+		return
+	}
+
+	statement, err := h.service.GetStatement(ctx, user.ID, r.PathValue("id"), r.PathValue("statementId"))
+	if err != nil {
+		h.writeStatementError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(statement)
+}
+
+// HandleReconcileStatement handles POST /credit-cards/{id}/statements/{statementId}/reconcile
+// Body: {"bank_total": 1234567.89}, the total of the bank's statement
+func (h *Handler) HandleReconcileStatement(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, ok := h.getUser(w, r)
+	if !ok {
+		return
+	}
+
+	var input ReconcileInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	reconciliation, err := h.service.ReconcileStatement(ctx, user.ID, r.PathValue("id"), r.PathValue("statementId"), &input)
+	if err != nil {
+		h.writeStatementError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reconciliation)
+}
+
+// HandleReopenStatement handles DELETE /credit-cards/{id}/statements/{statementId}
+func (h *Handler) HandleReopenStatement(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, ok := h.getUser(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.ReopenStatement(ctx, user.ID, r.PathValue("id"), r.PathValue("statementId")); err != nil {
+		h.writeStatementError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getUser returns the user of the session cookie, writing 401 when there is none
+func (h *Handler) getUser(w http.ResponseWriter, r *http.Request) (*auth.User, bool) {
+	cookie, err := r.Cookie(h.cookieName)
+	if err != nil {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return nil, false
+	}
+
+	user, err := h.authSvc.GetUserBySession(r.Context(), cookie.Value)
+	if err != nil {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return nil, false
+	}
+
+	return user, true
+}
+
+// writeStatementError maps statement errors to HTTP responses
+func (h *Handler) writeStatementError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrNotAuthorized):
+		http.Error(w, "not authorized", http.StatusForbidden)
+	case errors.Is(err, ErrCardNotFound):
+		http.Error(w, "credit card not found", http.StatusNotFound)
+	case errors.Is(err, ErrStatementNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, ErrStatementExists), errors.Is(err, ErrNotLatestStatement):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, ErrCycleNotEnded), errors.Is(err, ErrInvalidBankTotal):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,164 @@
+package creditcards
+
+import (
+	"sort"
+	"time"
+
+	"github.com/blanquicet/conti/backend/internal/money"
+)
+
+// InstallmentPurchase is a card purchase deferred over several billing cycles
+// (compra a cuotas)
+type InstallmentPurchase struct {
+	MovementID   string       `json:"movement_id"`
+	Type         string       `json:"type"`
+	Description  string       `json:"description"`
+	MovementDate time.Time    `json:"movement_date"`
+	Amount       money.Amount `json:"amount"` // Purchase amount in the household currency
+	CategoryName *string      `json:"category_name,omitempty"`
+	PayerName    string       `json:"payer_name"`
+
+	Installments int       `json:"installments"`
+	Rate         float64   `json:"installment_rate"` // Monthly interest on the outstanding capital
+	FirstCycle   time.Time `json:"-"`                // A date in the cycle of the first installment
+}
+
+// Installment is what a purchase adds to one billing cycle: an equal part of
+// the capital plus a month of interest on what was still owed
+type Installment struct {
+	Number       int          `json:"number"` // 1-based
+	Count        int          `json:"count"`
+	BillingCycle BillingCycle `json:"billing_cycle"`
+	Capital      money.Amount `json:"capital"`
+	Interest     money.Amount `json:"interest"`
+	Amount       money.Amount `json:"amount"` // Capital + interest
+}
+
+// InstallmentPlan is a purchase with the installments it still has to bill
+type InstallmentPlan struct {
+	*InstallmentPurchase
+	Billed          int            `json:"billed"` // Installments in cycles before the current one
+	Remaining       []*Installment `json:"remaining"`
+	RemainingAmount money.Amount   `json:"remaining_amount"`
+}
+
+// InstallmentsResponse represents the response for a card's installments endpoint
+type InstallmentsResponse struct {
+	CreditCard   CardInfo           `json:"credit_card"`
+	BillingCycle BillingCycle       `json:"billing_cycle"` // The current cycle
+	Purchases    []*InstallmentPlan `json:"purchases"`
+	Total        money.Amount       `json:"total"` // Everything still to be billed
+}
+
+// Schedule returns every installment of the purchase. Capital is split in
+// equal parts (the first ones take the leftover cents) and interest is charged
+// on the capital outstanding at the start of each cycle.
+func (p *InstallmentPurchase) Schedule(cutoffDay *int) []*Installment {
+	weights := make([]float64, p.Installments)
+	for i := range weights {
+		weights[i] = 1
+	}
+	capital := p.Amount.Allocate(weights)
+
+	year, month := statementMonth(CalculateBillingCycle(p.FirstCycle, cutoffDay))
+	outstanding := p.Amount
+	schedule := make([]*Installment, p.Installments)
+	for i := range schedule {
+		interest := outstanding.Mul(p.Rate)
+		schedule[i] = &Installment{
+			Number:       i + 1,
+			Count:        p.Installments,
+			BillingCycle: cycleOfStatement(year, month+time.Month(i), cutoffDay, p.FirstCycle.Location()),
+			Capital:      capital[i],
+			Interest:     interest,
+			Amount:       capital[i] + interest,
+		}
+		outstanding -= capital[i]
+	}
+	return schedule
+}
+
+// InstallmentIn returns the installment the purchase bills in a cycle, or nil
+// when the cycle is before the first or after the last one
+func (p *InstallmentPurchase) InstallmentIn(cycle BillingCycle, cutoffDay *int) *Installment {
+	i := monthsBetween(CalculateBillingCycle(p.FirstCycle, cutoffDay), cycle)
+	if i < 0 || i >= p.Installments {
+		return nil
+	}
+	return p.Schedule(cutoffDay)[i]
+}
+
+// Plan returns the installments the purchase has yet to bill from the cycle
+// on, or nil when it is fully billed
+func (p *InstallmentPurchase) Plan(cycle BillingCycle, cutoffDay *int) *InstallmentPlan {
+	billed := monthsBetween(CalculateBillingCycle(p.FirstCycle, cutoffDay), cycle)
+	if billed < 0 {
+		billed = 0
+	}
+	if billed >= p.Installments {
+		return nil
+	}
+
+	plan := &InstallmentPlan{
+		InstallmentPurchase: p,
+		Billed:              billed,
+		Remaining:           p.Schedule(cutoffDay)[billed:],
+	}
+	for _, installment := range plan.Remaining {
+		plan.RemainingAmount += installment.Amount
+	}
+	return plan
+}
+
+// installmentCharge returns the card movement for an installment
+func installmentCharge(p *InstallmentPurchase, installment *Installment) *CardMovement {
+	return &CardMovement{
+		ID:           p.MovementID,
+		Type:         p.Type,
+		Description:  p.Description,
+		Amount:       installment.Amount,
+		MovementDate: p.MovementDate,
+		CategoryName: p.CategoryName,
+		PayerName:    p.PayerName,
+		Installment:  installment,
+	}
+}
+
+// addInstallments adds the installments billed in the cycle to the charges,
+// keeping them ordered by date, newest first
+func addInstallments(charges []*CardMovement, total money.Amount, purchases []*InstallmentPurchase, cycle BillingCycle, cutoffDay *int) ([]*CardMovement, money.Amount) {
+	added := false
+	for _, p := range purchases {
+		if installment := p.InstallmentIn(cycle, cutoffDay); installment != nil {
+			charges = append(charges, installmentCharge(p, installment))
+			total += installment.Amount
+			added = true
+		}
+	}
+	if added {
+		sort.SliceStable(charges, func(i, j int) bool {
+			return charges[i].MovementDate.After(charges[j].MovementDate)
+		})
+	}
+	return charges, total
+}
+
+// statementMonth returns the month a cycle closes in
+func statementMonth(cycle BillingCycle) (int, time.Month) {
+	last := cycle.EndDate.AddDate(0, 0, -1)
+	return last.Year(), last.Month()
+}
+
+// cycleOfStatement returns the cycle that closes in a month (month may run
+// past December). The 1st is never after a cutoff day, so the cycle of the
+// 1st is the one closing that month.
+func cycleOfStatement(year int, month time.Month, cutoffDay *int, loc *time.Location) BillingCycle {
+	return CalculateBillingCycle(time.Date(year, month, 1, 0, 0, 0, 0, loc), cutoffDay)
+}
+
+// monthsBetween returns how many cycles after from the cycle to closes
+func monthsBetween(from, to BillingCycle) int {
+	fromYear, fromMonth := statementMonth(from)
+	toYear, toMonth := statementMonth(to)
+	return (toYear-fromYear)*12 + int(toMonth-fromMonth)
+}
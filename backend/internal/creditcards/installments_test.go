@@ -0,0 +1,143 @@
+package creditcards
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blanquicet/conti/backend/internal/money"
+)
+
+func TestInstallmentSchedule(t *testing.T) {
+	// 1.000.000 over 3 installments at 2% monthly, bought Jan 20 on a card
+	// cutting on the 15th: the first installment is billed Jan 16 - Feb 15
+	cutoff := 15
+	p := &InstallmentPurchase{
+		Amount:       money.New(1000000),
+		Installments: 3,
+		Rate:         0.02,
+		FirstCycle:   time.Date(2026, time.January, 20, 0, 0, 0, 0, time.UTC),
+	}
+
+	schedule := p.Schedule(&cutoff)
+	if len(schedule) != 3 {
+		t.Fatalf("len(schedule) = %d, want 3", len(schedule))
+	}
+
+	wantCapital := []money.Amount{money.FromMinor(33333334), money.FromMinor(33333333), money.FromMinor(33333333)}
+	wantInterest := []money.Amount{money.New(20000), money.FromMinor(1333333), money.FromMinor(666667)}
+	wantStart := []time.Time{
+		time.Date(2026, time.January, 16, 0, 0, 0, 0, time.UTC),
+		time.Date(2026, time.February, 16, 0, 0, 0, 0, time.UTC),
+		time.Date(2026, time.March, 16, 0, 0, 0, 0, time.UTC),
+	}
+	var capital money.Amount
+	for i, installment := range schedule {
+		if installment.Number != i+1 || installment.Count != 3 {
+			t.Errorf("installment %d: Number/Count = %d/%d", i, installment.Number, installment.Count)
+		}
+		if installment.Capital != wantCapital[i] {
+			t.Errorf("installment %d: Capital = %v, want %v", i+1, installment.Capital, wantCapital[i])
+		}
+		if installment.Interest != wantInterest[i] {
+			t.Errorf("installment %d: Interest = %v, want %v", i+1, installment.Interest, wantInterest[i])
+		}
+		if installment.Amount != installment.Capital+installment.Interest {
+			t.Errorf("installment %d: Amount = %v, want capital + interest", i+1, installment.Amount)
+		}
+		if !installment.BillingCycle.StartDate.Equal(wantStart[i]) {
+			t.Errorf("installment %d: cycle starts %v, want %v", i+1, installment.BillingCycle.StartDate, wantStart[i])
+		}
+		capital += installment.Capital
+	}
+	if capital != p.Amount {
+		t.Errorf("capital adds up to %v, want %v", capital, p.Amount)
+	}
+}
+
+func TestInstallmentIn(t *testing.T) {
+	// Bought Nov 30 on a card cutting at month end, over 4 installments:
+	// billed Nov, Dec, Jan and Feb
+	p := &InstallmentPurchase{
+		Amount:       money.New(400),
+		Installments: 4,
+		FirstCycle:   time.Date(2025, time.November, 30, 0, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		date time.Time
+		want int // 0 means none
+	}{
+		{time.Date(2025, time.October, 31, 0, 0, 0, 0, time.UTC), 0},
+		{time.Date(2025, time.November, 1, 0, 0, 0, 0, time.UTC), 1},
+		{time.Date(2026, time.January, 31, 0, 0, 0, 0, time.UTC), 3},
+		{time.Date(2026, time.February, 28, 0, 0, 0, 0, time.UTC), 4},
+		{time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC), 0},
+	}
+	for _, tt := range tests {
+		installment := p.InstallmentIn(CalculateBillingCycle(tt.date, nil), nil)
+		got := 0
+		if installment != nil {
+			got = installment.Number
+			if installment.Amount != money.New(100) {
+				t.Errorf("%s: Amount = %v, want 100", tt.date.Format("2006-01-02"), installment.Amount)
+			}
+		}
+		if got != tt.want {
+			t.Errorf("%s: installment %d, want %d", tt.date.Format("2006-01-02"), got, tt.want)
+		}
+	}
+}
+
+func TestInstallmentPlan(t *testing.T) {
+	cutoff := 25
+	p := &InstallmentPurchase{
+		Amount:       money.New(600),
+		Installments: 6,
+		FirstCycle:   time.Date(2026, time.January, 10, 0, 0, 0, 0, time.UTC),
+	}
+
+	// Third cycle (Feb 26 - Mar 25): two billed, four remaining
+	plan := p.Plan(CalculateBillingCycle(time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC), &cutoff), &cutoff)
+	if plan == nil {
+		t.Fatal("Plan() = nil, want remaining installments")
+	}
+	if plan.Billed != 2 || len(plan.Remaining) != 4 || plan.Remaining[0].Number != 3 {
+		t.Errorf("Billed = %d, remaining = %d starting at %d; want 2, 4 starting at 3",
+			plan.Billed, len(plan.Remaining), plan.Remaining[0].Number)
+	}
+	if plan.RemainingAmount != money.New(400) {
+		t.Errorf("RemainingAmount = %v, want 400", plan.RemainingAmount)
+	}
+
+	// Before the first cycle everything remains
+	if plan := p.Plan(CalculateBillingCycle(time.Date(2025, time.December, 1, 0, 0, 0, 0, time.UTC), &cutoff), &cutoff); plan == nil || plan.Billed != 0 || len(plan.Remaining) != 6 {
+		t.Errorf("Plan() before the first cycle = %+v, want all 6 remaining", plan)
+	}
+
+	// After the last cycle nothing does
+	if plan := p.Plan(CalculateBillingCycle(time.Date(2026, time.July, 1, 0, 0, 0, 0, time.UTC), &cutoff), &cutoff); plan != nil {
+		t.Errorf("Plan() after the last cycle = %+v, want nil", plan)
+	}
+}
+
+func TestAddInstallments(t *testing.T) {
+	cycle := CalculateBillingCycle(time.Date(2026, time.February, 10, 0, 0, 0, 0, time.UTC), nil)
+	charges := []*CardMovement{
+		{ID: "single", Amount: money.New(50), MovementDate: time.Date(2026, time.February, 3, 0, 0, 0, 0, time.UTC)},
+	}
+	purchases := []*InstallmentPurchase{
+		{MovementID: "tv", Amount: money.New(300), Installments: 3, MovementDate: time.Date(2026, time.January, 5, 0, 0, 0, 0, time.UTC), FirstCycle: time.Date(2026, time.January, 5, 0, 0, 0, 0, time.UTC)},
+		{MovementID: "done", Amount: money.New(200), Installments: 2, MovementDate: time.Date(2025, time.October, 5, 0, 0, 0, 0, time.UTC), FirstCycle: time.Date(2025, time.October, 5, 0, 0, 0, 0, time.UTC)},
+	}
+
+	charges, total := addInstallments(charges, money.New(50), purchases, cycle, nil)
+	if len(charges) != 2 {
+		t.Fatalf("len(charges) = %d, want 2", len(charges))
+	}
+	if total != money.New(150) {
+		t.Errorf("total = %v, want 150", total)
+	}
+	if charges[1].ID != "tv" || charges[1].Installment == nil || charges[1].Installment.Number != 2 {
+		t.Errorf("charges[1] = %+v, want installment 2 of tv", charges[1])
+	}
+}
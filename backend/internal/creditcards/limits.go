@@ -0,0 +1,104 @@
+package creditcards
+
+import (
+	"sort"
+	"time"
+
+	"github.com/blanquicet/conti/backend/internal/money"
+)
+
+// dueSoonDays is how close a payment due date has to be to count as approaching
+const dueSoonDays = 7
+
+// PaymentDueDate returns when the statement of a cycle must be paid: the first
+// due day after the cut-off (the last day of the cycle). Due days past the end
+// of a shorter month fall on its last day.
+func PaymentDueDate(cycle BillingCycle, dueDay int) time.Time {
+	closing := cycle.EndDate.AddDate(0, 0, -1)
+	due := dayOfMonth(closing.Year(), closing.Month(), dueDay, closing.Location())
+	if !due.After(closing) {
+		due = dayOfMonth(closing.Year(), closing.Month()+1, dueDay, closing.Location())
+	}
+	return due
+}
+
+// dayOfMonth returns the day of a month (month may run past December),
+// clamped to the month's last day
+func dayOfMonth(year int, month time.Month, day int, loc *time.Location) time.Time {
+	first := time.Date(year, month, 1, 0, 0, 0, 0, loc)
+	if last := lastDayOfMonth(first.Year(), first.Month()); day > last {
+		day = last
+	}
+	return time.Date(first.Year(), first.Month(), day, 0, 0, 0, 0, loc)
+}
+
+// statementDue is what the statement of a card's last closed cycle asks to pay
+type statementDue struct {
+	cycle   BillingCycle
+	closing money.Amount // Closing balance of the statement
+	paid    money.Amount // Paid since the cycle closed
+}
+
+// left returns what is still to pay of the statement
+func (d statementDue) left() money.Amount {
+	if d.paid >= d.closing {
+		return 0
+	}
+	return d.closing - d.paid
+}
+
+// applyLimits fills in the card's available credit and utilization from its
+// balance, and the due date, balance and minimum payment of the statement of
+// its last closed cycle
+func applyLimits(card *CardSummary, balance money.Amount, due statementDue) {
+	card.Balance = balance
+	if card.CreditLimit != nil {
+		available := *card.CreditLimit - balance
+		utilization := balance.Ratio(*card.CreditLimit)
+		card.AvailableCredit = &available
+		card.Utilization = &utilization
+	}
+	card.StatementBalance = due.left()
+	if card.PaymentDueDay != nil {
+		date := PaymentDueDate(due.cycle, *card.PaymentDueDay)
+		card.PaymentDueDate = &date
+	}
+	if card.MinimumPaymentPercentage != nil && card.StatementBalance > 0 {
+		// Payments made since the cycle closed count toward the minimum
+		minimum := due.closing.Mul(*card.MinimumPaymentPercentage) - due.paid
+		if minimum < 0 {
+			minimum = 0
+		}
+		card.MinimumPayment = &minimum
+	}
+}
+
+// flagPaymentsAtRisk flags the cards whose statement is due within
+// dueSoonDays of today and that the available cash can't cover. Cards are
+// paid in due date order, so a card is at risk when the cash runs out before
+// reaching it.
+func flagPaymentsAtRisk(cards []*CardSummary, available money.Amount, today time.Time) {
+	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+	horizon := today.AddDate(0, 0, dueSoonDays)
+
+	var due []*CardSummary
+	for _, card := range cards {
+		if card.PaymentDueDate == nil || card.StatementBalance <= 0 {
+			continue
+		}
+		date := time.Date(card.PaymentDueDate.Year(), card.PaymentDueDate.Month(), card.PaymentDueDate.Day(), 0, 0, 0, 0, time.UTC)
+		if date.Before(today) || date.After(horizon) {
+			continue
+		}
+		due = append(due, card)
+	}
+	sort.SliceStable(due, func(i, j int) bool {
+		return due[i].PaymentDueDate.Before(*due[j].PaymentDueDate)
+	})
+
+	var needed money.Amount
+	for _, card := range due {
+		needed += card.StatementBalance
+		card.PaymentAtRisk = needed > available
+	}
+}
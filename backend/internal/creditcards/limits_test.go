@@ -0,0 +1,121 @@
+package creditcards
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blanquicet/conti/backend/internal/money"
+)
+
+func TestPaymentDueDate(t *testing.T) {
+	cutoff15 := 15
+	cutoff31 := 31
+	tests := []struct {
+		name      string
+		cycleDate time.Time
+		cutoffDay *int
+		dueDay    int
+		want      time.Time
+	}{
+		{"due later the same month", time.Date(2026, time.March, 10, 0, 0, 0, 0, time.UTC), &cutoff15, 30, time.Date(2026, time.March, 30, 0, 0, 0, 0, time.UTC)},
+		{"due the next month", time.Date(2026, time.March, 10, 0, 0, 0, 0, time.UTC), &cutoff15, 5, time.Date(2026, time.April, 5, 0, 0, 0, 0, time.UTC)},
+		{"due on the cut-off day moves a month", time.Date(2026, time.March, 10, 0, 0, 0, 0, time.UTC), &cutoff15, 15, time.Date(2026, time.April, 15, 0, 0, 0, 0, time.UTC)},
+		{"month end cut-off", time.Date(2026, time.January, 20, 0, 0, 0, 0, time.UTC), nil, 20, time.Date(2026, time.February, 20, 0, 0, 0, 0, time.UTC)},
+		{"clamped to a short month", time.Date(2026, time.January, 20, 0, 0, 0, 0, time.UTC), &cutoff31, 30, time.Date(2026, time.February, 28, 0, 0, 0, 0, time.UTC)},
+		{"across the year", time.Date(2025, time.December, 20, 0, 0, 0, 0, time.UTC), &cutoff15, 2, time.Date(2026, time.February, 2, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := PaymentDueDate(CalculateBillingCycle(tt.cycleDate, tt.cutoffDay), tt.dueDay)
+			if !got.Equal(tt.want) {
+				t.Errorf("PaymentDueDate() = %v, want %v", got.Format("2006-01-02"), tt.want.Format("2006-01-02"))
+			}
+		})
+	}
+}
+
+func TestApplyLimits(t *testing.T) {
+	limit := money.New(5000000)
+	dueDay := 5
+	minimum := 0.05
+	open := CalculateBillingCycle(time.Date(2026, time.March, 10, 0, 0, 0, 0, time.UTC), nil)
+	card := &CardSummary{
+		BillingCycle:             open,
+		NetDebt:                  money.New(300000), // Of the open cycle, not due yet
+		CreditLimit:              &limit,
+		PaymentDueDay:            &dueDay,
+		MinimumPaymentPercentage: &minimum,
+	}
+	// February closed at 800000 and 10000 has been paid since
+	due := statementDue{
+		cycle:   CalculateBillingCycle(time.Date(2026, time.February, 10, 0, 0, 0, 0, time.UTC), nil),
+		closing: money.New(800000),
+		paid:    money.New(10000),
+	}
+
+	applyLimits(card, money.New(2000000), due)
+
+	if card.AvailableCredit == nil || *card.AvailableCredit != money.New(3000000) {
+		t.Errorf("AvailableCredit = %v, want 3000000", card.AvailableCredit)
+	}
+	if card.Utilization == nil || *card.Utilization != 0.4 {
+		t.Errorf("Utilization = %v, want 0.4", card.Utilization)
+	}
+	if card.PaymentDueDate == nil || !card.PaymentDueDate.Equal(time.Date(2026, time.March, 5, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("PaymentDueDate = %v, want 2026-03-05", card.PaymentDueDate)
+	}
+	if card.StatementBalance != money.New(790000) {
+		t.Errorf("StatementBalance = %v, want 790000", card.StatementBalance)
+	}
+	if card.MinimumPayment == nil || *card.MinimumPayment != money.New(30000) {
+		t.Errorf("MinimumPayment = %v, want 30000", card.MinimumPayment)
+	}
+
+	// Without a limit or due day nothing is derived
+	plain := &CardSummary{NetDebt: money.New(100)}
+	applyLimits(plain, money.New(100), statementDue{})
+	if plain.AvailableCredit != nil || plain.Utilization != nil || plain.PaymentDueDate != nil || plain.MinimumPayment != nil {
+		t.Errorf("applyLimits() on a card without limits = %+v", plain)
+	}
+}
+
+func TestStatementDue_Left(t *testing.T) {
+	due := statementDue{closing: money.New(500), paid: money.New(200)}
+	if got := due.left(); got != money.New(300) {
+		t.Errorf("left() = %v, want 300", got)
+	}
+	due.paid = money.New(700)
+	if got := due.left(); got != 0 {
+		t.Errorf("left() when overpaid = %v, want 0", got)
+	}
+}
+
+func TestFlagPaymentsAtRisk(t *testing.T) {
+	today := time.Date(2026, time.April, 1, 0, 0, 0, 0, time.UTC)
+	card := func(statement int64, due time.Time) *CardSummary {
+		return &CardSummary{StatementBalance: money.New(statement), PaymentDueDate: &due}
+	}
+	first := card(300, time.Date(2026, time.April, 3, 0, 0, 0, 0, time.UTC))
+	second := card(300, time.Date(2026, time.April, 6, 0, 0, 0, 0, time.UTC))
+	later := card(1000, time.Date(2026, time.April, 20, 0, 0, 0, 0, time.UTC))
+	overdue := card(1000, time.Date(2026, time.March, 25, 0, 0, 0, 0, time.UTC))
+	noDueDay := &CardSummary{StatementBalance: money.New(1000)}
+	// Open cycle charges aren't due yet, whatever the net debt
+	paid := &CardSummary{NetDebt: money.New(5000), PaymentDueDate: &today}
+
+	flagPaymentsAtRisk([]*CardSummary{second, later, first, overdue, noDueDay, paid}, money.New(500), today)
+
+	if first.PaymentAtRisk {
+		t.Error("first card is covered by the available cash")
+	}
+	if !second.PaymentAtRisk {
+		t.Error("second card is due soon and the cash runs out before it")
+	}
+	if later.PaymentAtRisk || overdue.PaymentAtRisk || noDueDay.PaymentAtRisk {
+		t.Error("cards not due within a week are never at risk")
+	}
+	if paid.PaymentAtRisk {
+		t.Error("a card whose statement is paid is never at risk")
+	}
+}
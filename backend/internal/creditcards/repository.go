@@ -2,18 +2,34 @@ package creditcards
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/blanquicet/conti/backend/internal/money"
+	"github.com/blanquicet/conti/backend/internal/paymentmethods"
 )
 
 // Repository handles database operations for credit card summaries
 type Repository interface {
 	GetCreditCards(ctx context.Context, householdID string) ([]*CardSummary, error)
-	GetCardCharges(ctx context.Context, cardID string, startDate, endDate time.Time) ([]*CardMovement, float64, error)
-	GetCardPayments(ctx context.Context, cardID string, startDate, endDate time.Time) ([]*CardPayment, float64, error)
+	GetCardCharges(ctx context.Context, cardID string, startDate, endDate time.Time) ([]*CardMovement, money.Amount, error)
+	GetInstallmentPurchases(ctx context.Context, cardID string) ([]*InstallmentPurchase, error)
+	GetCardPayments(ctx context.Context, cardID string, startDate, endDate time.Time) ([]*CardPayment, money.Amount, error)
+	GetCardBalance(ctx context.Context, cardID string, asOfDate time.Time) (money.Amount, error)
 	GetSavingsBalances(ctx context.Context, householdID string, asOfDate time.Time) ([]*AccountBalance, error)
+
+	// Statements
+	GetAllCreditCards(ctx context.Context) ([]*paymentmethods.PaymentMethod, error)
+	CreateStatement(ctx context.Context, st *Statement) (*Statement, error)
+	GetStatement(ctx context.Context, id string) (*Statement, error)
+	ListStatements(ctx context.Context, cardID string) ([]*Statement, error)
+	SetStatementBankTotal(ctx context.Context, id string, bankTotal money.Amount, reconciled bool) error
+	DeleteStatement(ctx context.Context, id string) error
 }
 
 type repository struct {
@@ -35,7 +51,10 @@ func (r *repository) GetCreditCards(ctx context.Context, householdID string) ([]
 			u.name as owner_name,
 			pm.cutoff_day,
 			pm.institution,
-			pm.last4
+			pm.last4,
+			pm.credit_limit,
+			pm.payment_due_day,
+			pm.minimum_payment_percentage::float8
 		FROM payment_methods pm
 		JOIN users u ON pm.owner_id = u.id
 		WHERE pm.household_id = $1
@@ -61,6 +80,9 @@ func (r *repository) GetCreditCards(ctx context.Context, householdID string) ([]
 			&card.CutoffDay,
 			&card.Institution,
 			&card.Last4,
+			&card.CreditLimit,
+			&card.PaymentDueDay,
+			&card.MinimumPaymentPercentage,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("scan credit card: %w", err)
@@ -71,15 +93,20 @@ func (r *repository) GetCreditCards(ctx context.Context, householdID string) ([]
 	return cards, nil
 }
 
-// GetCardCharges returns all movements charged to a credit card in a date range
-func (r *repository) GetCardCharges(ctx context.Context, cardID string, startDate, endDate time.Time) ([]*CardMovement, float64, error) {
+// GetCardCharges returns all movements charged to a credit card in a date range.
+// Refunds count negatively in the cycle they post in. Purchases in installments
+// are left out (see GetInstallmentPurchases).
+func (r *repository) GetCardCharges(ctx context.Context, cardID string, startDate, endDate time.Time) ([]*CardMovement, money.Amount, error) {
 	query := `
 		SELECT 
 			m.id,
 			m.type,
 			m.description,
-			m.amount,
+			m.signed_base_amount,
 			m.movement_date,
+			CASE WHEN m.exchange_rate IS NOT NULL AND m.refund_of_id IS NULL THEN m.amount
+			     WHEN m.exchange_rate IS NOT NULL THEN -m.amount END as original_amount,
+			CASE WHEN m.exchange_rate IS NOT NULL THEN m.currency END as original_currency,
 			c.name as category_name,
 			COALESCE(u.name, ct.name, 'Unknown') as payer_name
 		FROM movements m
@@ -87,8 +114,10 @@ func (r *repository) GetCardCharges(ctx context.Context, cardID string, startDat
 		LEFT JOIN users u ON m.payer_user_id = u.id
 		LEFT JOIN contacts ct ON m.payer_contact_id = ct.id
 		WHERE m.payment_method_id = $1
+			AND m.deleted_at IS NULL
 			AND m.movement_date >= $2
 			AND m.movement_date < $3
+			AND (m.installments IS NULL OR m.installments <= 1)
 		ORDER BY m.movement_date DESC
 	`
 
@@ -99,7 +128,7 @@ func (r *repository) GetCardCharges(ctx context.Context, cardID string, startDat
 	defer rows.Close()
 
 	var movements []*CardMovement
-	var total float64
+	var total money.Amount
 	for rows.Next() {
 		m := &CardMovement{}
 		err := rows.Scan(
@@ -108,6 +137,8 @@ func (r *repository) GetCardCharges(ctx context.Context, cardID string, startDat
 			&m.Description,
 			&m.Amount,
 			&m.MovementDate,
+			&m.OriginalAmount,
+			&m.OriginalCurrency,
 			&m.CategoryName,
 			&m.PayerName,
 		)
@@ -121,17 +152,74 @@ func (r *repository) GetCardCharges(ctx context.Context, cardID string, startDat
 	return movements, total, nil
 }
 
+// GetInstallmentPurchases returns the purchases in installments charged to a
+// credit card
+func (r *repository) GetInstallmentPurchases(ctx context.Context, cardID string) ([]*InstallmentPurchase, error) {
+	query := `
+		SELECT 
+			m.id,
+			m.type,
+			m.description,
+			m.movement_date,
+			m.base_amount,
+			c.name as category_name,
+			COALESCE(u.name, ct.name, 'Unknown') as payer_name,
+			m.installments,
+			COALESCE(m.installment_rate, 0)::float8,
+			COALESCE(m.installments_first_cycle, m.movement_date)
+		FROM movements m
+		LEFT JOIN categories c ON m.category_id = c.id
+		LEFT JOIN users u ON m.payer_user_id = u.id
+		LEFT JOIN contacts ct ON m.payer_contact_id = ct.id
+		WHERE m.payment_method_id = $1
+			AND m.deleted_at IS NULL
+			AND m.installments > 1
+		ORDER BY m.movement_date DESC
+	`
+
+	rows, err := r.pool.Query(ctx, query, cardID)
+	if err != nil {
+		return nil, fmt.Errorf("query installment purchases: %w", err)
+	}
+	defer rows.Close()
+
+	var purchases []*InstallmentPurchase
+	for rows.Next() {
+		p := &InstallmentPurchase{}
+		err := rows.Scan(
+			&p.MovementID,
+			&p.Type,
+			&p.Description,
+			&p.MovementDate,
+			&p.Amount,
+			&p.CategoryName,
+			&p.PayerName,
+			&p.Installments,
+			&p.Rate,
+			&p.FirstCycle,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan installment purchase: %w", err)
+		}
+		purchases = append(purchases, p)
+	}
+
+	return purchases, nil
+}
+
 // GetCardPayments returns all payments made to a credit card in a date range
-func (r *repository) GetCardPayments(ctx context.Context, cardID string, startDate, endDate time.Time) ([]*CardPayment, float64, error) {
+func (r *repository) GetCardPayments(ctx context.Context, cardID string, startDate, endDate time.Time) ([]*CardPayment, money.Amount, error) {
 	query := `
 		SELECT 
 			ccp.id,
 			ccp.amount,
 			ccp.payment_date,
-			a.name as source_account_name,
+			COALESCE((SELECT string_agg(a.name, ', ' ORDER BY s.amount DESC, a.name)
+			          FROM credit_card_payment_sources s
+			          JOIN accounts a ON s.account_id = a.id
+			          WHERE s.payment_id = ccp.id), '') as source_account_name,
 			ccp.notes
 		FROM credit_card_payments ccp
-		JOIN accounts a ON ccp.source_account_id = a.id
 		WHERE ccp.credit_card_id = $1
 			AND ccp.payment_date >= $2
 			AND ccp.payment_date < $3
@@ -145,7 +233,7 @@ func (r *repository) GetCardPayments(ctx context.Context, cardID string, startDa
 	defer rows.Close()
 
 	var payments []*CardPayment
-	var total float64
+	var total money.Amount
 	for rows.Next() {
 		p := &CardPayment{}
 		err := rows.Scan(
@@ -165,8 +253,32 @@ func (r *repository) GetCardPayments(ctx context.Context, cardID string, startDa
 	return payments, total, nil
 }
 
+// GetCardBalance returns what is owed on a credit card up to a date: every
+// charge (purchases in installments in full) minus every payment
+func (r *repository) GetCardBalance(ctx context.Context, cardID string, asOfDate time.Time) (money.Amount, error) {
+	query := `
+		SELECT
+			(SELECT COALESCE(SUM(m.signed_base_amount), 0)
+			 FROM movements m
+			 WHERE m.payment_method_id = $1
+			   AND m.deleted_at IS NULL
+			   AND m.movement_date <= $2)
+			-
+			(SELECT COALESCE(SUM(ccp.amount), 0)
+			 FROM credit_card_payments ccp
+			 WHERE ccp.credit_card_id = $1
+			   AND ccp.payment_date <= $2)
+	`
+
+	var balance money.Amount
+	if err := r.pool.QueryRow(ctx, query, cardID, asOfDate).Scan(&balance); err != nil {
+		return 0, fmt.Errorf("query card balance: %w", err)
+	}
+	return balance, nil
+}
+
 // GetSavingsBalances calculates balances for all savings and cash accounts
-// Balance = initial_balance + income - debit_spending - card_payments
+// Balance = initial_balance + income + transfers_in - transfers_out - debit_spending - card_payments + adjustments
 func (r *repository) GetSavingsBalances(ctx context.Context, householdID string, asOfDate time.Time) ([]*AccountBalance, error) {
 	query := `
 		WITH account_income AS (
@@ -174,37 +286,60 @@ func (r *repository) GetSavingsBalances(ctx context.Context, householdID string,
 				account_id,
 				COALESCE(SUM(amount), 0) as total_income
 			FROM income
+			WHERE deleted_at IS NULL
 			GROUP BY account_id
 		),
 		account_debit_spending AS (
 			-- Movements paid by debit cards linked to each account
 			SELECT 
 				pm.linked_account_id as account_id,
-				COALESCE(SUM(m.amount), 0) as total_spent
+				COALESCE(SUM(m.signed_base_amount), 0) as total_spent
 			FROM movements m
 			JOIN payment_methods pm ON m.payment_method_id = pm.id
 			WHERE pm.type = 'debit_card'
 				AND pm.linked_account_id IS NOT NULL
+				AND m.deleted_at IS NULL
 			GROUP BY pm.linked_account_id
 		),
 		account_card_payments AS (
-			-- Credit card payments from each account
+			-- Credit card payments from each account (a payment can draw from several)
 			SELECT 
-				source_account_id as account_id,
+				account_id,
 				COALESCE(SUM(amount), 0) as total_payments
-			FROM credit_card_payments
-			GROUP BY source_account_id
+			FROM credit_card_payment_sources
+			GROUP BY account_id
 		),
 		cash_spending AS (
 			-- Movements paid with cash payment method
 			SELECT 
 				pm.linked_account_id as account_id,
-				COALESCE(SUM(m.amount), 0) as total_spent
+				COALESCE(SUM(m.signed_base_amount), 0) as total_spent
 			FROM movements m
 			JOIN payment_methods pm ON m.payment_method_id = pm.id
 			WHERE pm.type = 'cash'
 				AND pm.linked_account_id IS NOT NULL
+				AND m.deleted_at IS NULL
 			GROUP BY pm.linked_account_id
+		),
+		account_transfers AS (
+			-- Transfers between accounts: received minus sent
+			SELECT account_id, SUM(amount) as net_transfers
+			FROM (
+				SELECT receiver_account_id as account_id, base_amount as amount
+				FROM movements
+				WHERE type = 'TRANSFER' AND deleted_at IS NULL
+				UNION ALL
+				SELECT source_account_id as account_id, -base_amount as amount
+				FROM movements
+				WHERE type = 'TRANSFER' AND deleted_at IS NULL
+			) t
+			GROUP BY account_id
+		),
+		account_adjustments AS (
+			-- Adjustments that close the gap with the real balance at a checkpoint
+			SELECT account_id, SUM(adjustment) as total_adjustments
+			FROM account_checkpoints
+			GROUP BY account_id
 		)
 		SELECT 
 			a.id,
@@ -214,12 +349,16 @@ func (r *repository) GetSavingsBalances(ctx context.Context, householdID string,
 				+ COALESCE(ai.total_income, 0) 
 				- COALESCE(ads.total_spent, 0) 
 				- COALESCE(acp.total_payments, 0)
-				- COALESCE(cs.total_spent, 0) as balance
+				- COALESCE(cs.total_spent, 0)
+				+ COALESCE(tr.net_transfers, 0)
+				+ COALESCE(aa.total_adjustments, 0) as balance
 		FROM accounts a
 		LEFT JOIN account_income ai ON a.id = ai.account_id
 		LEFT JOIN account_debit_spending ads ON a.id = ads.account_id
 		LEFT JOIN account_card_payments acp ON a.id = acp.account_id
 		LEFT JOIN cash_spending cs ON a.id = cs.account_id
+		LEFT JOIN account_transfers tr ON a.id = tr.account_id
+		LEFT JOIN account_adjustments aa ON a.id = aa.account_id
 		WHERE a.household_id = $1
 			AND a.type IN ('savings', 'cash')
 		ORDER BY a.name
@@ -243,3 +382,234 @@ func (r *repository) GetSavingsBalances(ctx context.Context, householdID string,
 
 	return accounts, nil
 }
+
+// GetAllCreditCards returns the active credit cards of every household, with
+// what closing their cycles needs
+func (r *repository) GetAllCreditCards(ctx context.Context) ([]*paymentmethods.PaymentMethod, error) {
+	query := `
+		SELECT 
+			pm.id,
+			pm.household_id,
+			pm.name,
+			pm.cutoff_day,
+			pm.payment_due_day,
+			pm.minimum_payment_percentage::float8
+		FROM payment_methods pm
+		WHERE pm.type = 'credit_card'
+			AND pm.is_active = true
+		ORDER BY pm.household_id, pm.name
+	`
+
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("query credit cards: %w", err)
+	}
+	defer rows.Close()
+
+	var cards []*paymentmethods.PaymentMethod
+	for rows.Next() {
+		card := &paymentmethods.PaymentMethod{Type: paymentmethods.TypeCreditCard, IsActive: true}
+		err := rows.Scan(
+			&card.ID,
+			&card.HouseholdID,
+			&card.Name,
+			&card.CutoffDay,
+			&card.PaymentDueDay,
+			&card.MinimumPaymentPercentage,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan credit card: %w", err)
+		}
+		cards = append(cards, card)
+	}
+
+	return cards, nil
+}
+
+// CreateStatement stores a closed cycle with its items. A cycle closes once:
+// a second statement for it returns ErrStatementExists.
+func (r *repository) CreateStatement(ctx context.Context, st *Statement) (*Statement, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO card_statements (
+			household_id, credit_card_id, period_start, period_end,
+			opening_balance, charges, payments, interest, closing_balance,
+			due_date, minimum_payment, closed_by
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING id, created_at
+	`,
+		st.HouseholdID, st.CreditCardID, st.BillingCycle.StartDate, st.BillingCycle.EndDate,
+		st.OpeningBalance, st.Charges, st.Payments, st.Interest, st.ClosingBalance,
+		st.DueDate, st.MinimumPayment, st.ClosedBy,
+	).Scan(&st.ID, &st.CreatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" { // unique_violation
+			return nil, ErrStatementExists
+		}
+		return nil, fmt.Errorf("insert statement: %w", err)
+	}
+
+	for _, item := range st.Items {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO card_statement_items (
+				statement_id, kind, movement_id, payment_id, description,
+				item_date, amount, interest, installment_number, installment_count
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		`,
+			st.ID, item.Kind, item.MovementID, item.PaymentID, item.Description,
+			item.Date, item.Amount, item.Interest, item.InstallmentNumber, item.InstallmentCount,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("insert statement item: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return st, nil
+}
+
+// statementColumns are the card_statements columns scanStatement reads
+const statementColumns = `
+	id, household_id, credit_card_id, period_start, period_end,
+	opening_balance, charges, payments, interest, closing_balance,
+	due_date, minimum_payment, bank_total, reconciled_at, closed_by, created_at
+`
+
+// scanStatement scans a row of statementColumns
+func scanStatement(row pgx.Row) (*Statement, error) {
+	st := &Statement{}
+	err := row.Scan(
+		&st.ID,
+		&st.HouseholdID,
+		&st.CreditCardID,
+		&st.BillingCycle.StartDate,
+		&st.BillingCycle.EndDate,
+		&st.OpeningBalance,
+		&st.Charges,
+		&st.Payments,
+		&st.Interest,
+		&st.ClosingBalance,
+		&st.DueDate,
+		&st.MinimumPayment,
+		&st.BankTotal,
+		&st.ReconciledAt,
+		&st.ClosedBy,
+		&st.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	st.BillingCycle.Label = formatCycleLabel(st.BillingCycle.StartDate, st.BillingCycle.EndDate.AddDate(0, 0, -1))
+	return st, nil
+}
+
+// GetStatement returns a statement with its items
+func (r *repository) GetStatement(ctx context.Context, id string) (*Statement, error) {
+	st, err := scanStatement(r.pool.QueryRow(ctx, `SELECT `+statementColumns+` FROM card_statements WHERE id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrStatementNotFound
+		}
+		return nil, fmt.Errorf("query statement: %w", err)
+	}
+
+	rows, err := r.pool.Query(ctx, `
+		SELECT kind, movement_id, payment_id, description, item_date,
+		       amount, interest, installment_number, installment_count
+		FROM card_statement_items
+		WHERE statement_id = $1
+		ORDER BY kind, item_date DESC
+	`, id)
+	if err != nil {
+		return nil, fmt.Errorf("query statement items: %w", err)
+	}
+	defer rows.Close()
+
+	st.Items = []*StatementItem{}
+	for rows.Next() {
+		item := &StatementItem{}
+		err := rows.Scan(
+			&item.Kind,
+			&item.MovementID,
+			&item.PaymentID,
+			&item.Description,
+			&item.Date,
+			&item.Amount,
+			&item.Interest,
+			&item.InstallmentNumber,
+			&item.InstallmentCount,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan statement item: %w", err)
+		}
+		st.Items = append(st.Items, item)
+	}
+
+	return st, nil
+}
+
+// ListStatements returns the statements of a card, latest first, without
+// their items
+func (r *repository) ListStatements(ctx context.Context, cardID string) ([]*Statement, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+statementColumns+`
+		FROM card_statements
+		WHERE credit_card_id = $1
+		ORDER BY period_start DESC
+	`, cardID)
+	if err != nil {
+		return nil, fmt.Errorf("query statements: %w", err)
+	}
+	defer rows.Close()
+
+	statements := []*Statement{}
+	for rows.Next() {
+		st, err := scanStatement(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan statement: %w", err)
+		}
+		statements = append(statements, st)
+	}
+
+	return statements, nil
+}
+
+// SetStatementBankTotal records the bank's total for a statement, and when
+// it was reconciled (cleared when it no longer is)
+func (r *repository) SetStatementBankTotal(ctx context.Context, id string, bankTotal money.Amount, reconciled bool) error {
+	result, err := r.pool.Exec(ctx, `
+		UPDATE card_statements
+		SET bank_total = $2,
+		    reconciled_at = CASE WHEN $3::boolean THEN COALESCE(reconciled_at, NOW()) END,
+		    updated_at = NOW()
+		WHERE id = $1
+	`, id, bankTotal, reconciled)
+	if err != nil {
+		return fmt.Errorf("update statement: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrStatementNotFound
+	}
+	return nil
+}
+
+// DeleteStatement deletes a statement and its items, reopening its cycle
+func (r *repository) DeleteStatement(ctx context.Context, id string) error {
+	result, err := r.pool.Exec(ctx, `DELETE FROM card_statements WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("delete statement: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrStatementNotFound
+	}
+	return nil
+}
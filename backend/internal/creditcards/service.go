@@ -7,7 +7,9 @@ import (
 	"log/slog"
 	"time"
 
+	"github.com/blanquicet/conti/backend/internal/audit"
 	"github.com/blanquicet/conti/backend/internal/households"
+	"github.com/blanquicet/conti/backend/internal/money"
 	"github.com/blanquicet/conti/backend/internal/paymentmethods"
 )
 
@@ -20,12 +22,22 @@ var (
 type Service interface {
 	GetSummary(ctx context.Context, userID string, cycleDate time.Time, filter *SummaryFilter) (*SummaryResponse, error)
 	GetCardMovements(ctx context.Context, userID string, cardID string, cycleDate time.Time) (*CardMovementsResponse, error)
+	GetInstallments(ctx context.Context, userID string, cardID string, asOf time.Time) (*InstallmentsResponse, error)
+
+	// Statements
+	ListStatements(ctx context.Context, userID string, cardID string) ([]*Statement, error)
+	GetStatement(ctx context.Context, userID string, cardID string, statementID string) (*Statement, error)
+	CloseStatement(ctx context.Context, userID string, cardID string, cycleDate *time.Time) (*Statement, error)
+	ReconcileStatement(ctx context.Context, userID string, cardID string, statementID string, input *ReconcileInput) (*Reconciliation, error)
+	ReopenStatement(ctx context.Context, userID string, cardID string, statementID string) error
+	CloseEndedCycles(ctx context.Context, now time.Time) (int, error)
 }
 
 type service struct {
 	repo               Repository
 	householdsRepo     households.HouseholdRepository
 	paymentMethodsRepo paymentmethods.Repository
+	auditService       audit.Service
 	logger             *slog.Logger
 }
 
@@ -34,17 +46,20 @@ func NewService(
 	repo Repository,
 	householdsRepo households.HouseholdRepository,
 	paymentMethodsRepo paymentmethods.Repository,
+	auditService audit.Service,
 	logger *slog.Logger,
 ) Service {
 	return &service{
 		repo:               repo,
 		householdsRepo:     householdsRepo,
 		paymentMethodsRepo: paymentMethodsRepo,
+		auditService:       auditService,
 		logger:             logger,
 	}
 }
 
-// GetSummary returns the credit cards summary for a billing cycle
+// GetSummary returns the credit cards summary for a billing cycle. Cards whose
+// cycle was closed into a statement report the statement's totals.
 func (s *service) GetSummary(ctx context.Context, userID string, cycleDate time.Time, filter *SummaryFilter) (*SummaryResponse, error) {
 	// Get household ID for authorization
 	householdID, err := s.householdsRepo.GetUserHouseholdID(ctx, userID)
@@ -63,10 +78,6 @@ func (s *service) GetSummary(ctx context.Context, userID string, cycleDate time.
 		cards = s.applyFilters(cards, filter)
 	}
 
-	// Calculate calendar month for payments (1st to last day of the month)
-	calendarMonthStart := time.Date(cycleDate.Year(), cycleDate.Month(), 1, 0, 0, 0, 0, cycleDate.Location())
-	calendarMonthEnd := calendarMonthStart.AddDate(0, 1, 0) // First day of next month
-
 	var totals Totals
 
 	// Calculate billing cycle and charges/payments for each card
@@ -74,23 +85,28 @@ func (s *service) GetSummary(ctx context.Context, userID string, cycleDate time.
 		cycle := CalculateBillingCycle(cycleDate, card.CutoffDay)
 		card.BillingCycle = cycle
 
-		// Charges use the card's billing cycle
-		movements, chargesTotal, err := s.repo.GetCardCharges(ctx, card.ID, cycle.StartDate, cycle.EndDate)
-		if err != nil {
-			return nil, fmt.Errorf("get card charges for %s: %w", card.ID, err)
-		}
-
-		// Payments use calendar month (1st to last day)
-		payments, paymentsTotal, err := s.repo.GetCardPayments(ctx, card.ID, calendarMonthStart, calendarMonthEnd)
+		activity, err := s.getCycleActivity(ctx, card.ID, card.CutoffDay, cycle)
 		if err != nil {
-			return nil, fmt.Errorf("get card payments for %s: %w", card.ID, err)
+			return nil, fmt.Errorf("get card activity for %s: %w", card.ID, err)
 		}
+		chargesTotal, paymentsTotal := activity.chargesTotal, activity.paymentsTotal
 
 		card.TotalCharges = chargesTotal
 		card.TotalPayments = paymentsTotal
 		card.NetDebt = chargesTotal - paymentsTotal
-		card.MovementCount = len(movements)
-		card.PaymentCount = len(payments)
+		card.MovementCount = len(activity.charges)
+		card.PaymentCount = len(activity.payments)
+
+		// Limit, and what the last closed cycle's statement asks to pay
+		balance, err := s.repo.GetCardBalance(ctx, card.ID, cycleDate)
+		if err != nil {
+			return nil, fmt.Errorf("get card balance for %s: %w", card.ID, err)
+		}
+		due, err := s.statementDue(ctx, card.ID, card.CutoffDay, cycle, cycleDate)
+		if err != nil {
+			return nil, fmt.Errorf("get card statement for %s: %w", card.ID, err)
+		}
+		applyLimits(card, balance, due)
 
 		totals.TotalCharges += chargesTotal
 		totals.TotalPayments += paymentsTotal
@@ -109,6 +125,7 @@ func (s *service) GetSummary(ctx context.Context, userID string, cycleDate time.
 	for _, acc := range balances {
 		availableCash.Total += acc.Balance
 	}
+	flagPaymentsAtRisk(cards, availableCash.Total, cycleDate)
 
 	// Calculate billing cycle for response (use first card's cutoff or default)
 	var defaultCutoff *int
@@ -128,6 +145,460 @@ func (s *service) GetSummary(ctx context.Context, userID string, cycleDate time.
 
 // GetCardMovements returns detailed movements and payments for a single card
 func (s *service) GetCardMovements(ctx context.Context, userID string, cardID string, cycleDate time.Time) (*CardMovementsResponse, error) {
+	card, err := s.getCard(ctx, userID, cardID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Calculate billing cycle for this card (used for charges)
+	cycle := CalculateBillingCycle(cycleDate, card.CutoffDay)
+
+	activity, err := s.getCycleActivity(ctx, card.ID, card.CutoffDay, cycle)
+	if err != nil {
+		return nil, fmt.Errorf("get card activity: %w", err)
+	}
+
+	response := &CardMovementsResponse{
+		CreditCard: CardInfo{
+			ID:        card.ID,
+			Name:      card.Name,
+			OwnerName: card.OwnerName,
+			CutoffDay: card.CutoffDay,
+		},
+		BillingCycle: cycle,
+		NetDebt:      activity.chargesTotal - activity.paymentsTotal,
+		Statement:    activity.statement,
+	}
+
+	response.Charges.Movements = activity.charges
+	response.Charges.Total = activity.chargesTotal
+	response.Payments.Items = activity.payments
+	response.Payments.Total = activity.paymentsTotal
+
+	return response, nil
+}
+
+// GetInstallments returns the installments each purchase on a card has yet to
+// bill, from the cycle of asOf on
+func (s *service) GetInstallments(ctx context.Context, userID string, cardID string, asOf time.Time) (*InstallmentsResponse, error) {
+	card, err := s.getCard(ctx, userID, cardID)
+	if err != nil {
+		return nil, err
+	}
+
+	purchases, err := s.repo.GetInstallmentPurchases(ctx, card.ID)
+	if err != nil {
+		return nil, fmt.Errorf("get installment purchases: %w", err)
+	}
+
+	cycle := CalculateBillingCycle(asOf, card.CutoffDay)
+	response := &InstallmentsResponse{
+		CreditCard: CardInfo{
+			ID:        card.ID,
+			Name:      card.Name,
+			OwnerName: card.OwnerName,
+			CutoffDay: card.CutoffDay,
+		},
+		BillingCycle: cycle,
+		Purchases:    []*InstallmentPlan{},
+	}
+	for _, p := range purchases {
+		if plan := p.Plan(cycle, card.CutoffDay); plan != nil {
+			response.Purchases = append(response.Purchases, plan)
+			response.Total += plan.RemainingAmount
+		}
+	}
+
+	return response, nil
+}
+
+// ListStatements returns the statements of a card, latest first
+func (s *service) ListStatements(ctx context.Context, userID string, cardID string) ([]*Statement, error) {
+	card, err := s.getCard(ctx, userID, cardID)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.ListStatements(ctx, card.ID)
+}
+
+// GetStatement returns a statement of a card with its items
+func (s *service) GetStatement(ctx context.Context, userID string, cardID string, statementID string) (*Statement, error) {
+	card, err := s.getCard(ctx, userID, cardID)
+	if err != nil {
+		return nil, err
+	}
+	return s.getStatement(ctx, card.ID, statementID)
+}
+
+// CloseStatement closes the billing cycle of cycleDate into a statement. A nil
+// cycleDate closes the last cycle that has ended.
+func (s *service) CloseStatement(ctx context.Context, userID string, cardID string, cycleDate *time.Time) (*Statement, error) {
+	card, err := s.getCard(ctx, userID, cardID)
+	if err != nil {
+		return nil, err
+	}
+
+	today := time.Now()
+	var cycle BillingCycle
+	if cycleDate == nil {
+		cycle = lastEndedCycle(today, card.CutoffDay)
+	} else {
+		cycle = CalculateBillingCycle(*cycleDate, card.CutoffDay)
+		if !cycleEnded(cycle, today) {
+			return nil, ErrCycleNotEnded
+		}
+	}
+
+	statement, err := s.closeCycle(ctx, card, cycle, &userID)
+	if err != nil {
+		if !errors.Is(err, ErrStatementExists) {
+			s.auditService.LogAsync(ctx, &audit.LogInput{
+				UserID:       audit.StringPtr(userID),
+				Action:       audit.ActionCardStatementClosed,
+				ResourceType: "card_statement",
+				HouseholdID:  audit.StringPtr(card.HouseholdID),
+				Success:      false,
+				ErrorMessage: audit.StringPtr(err.Error()),
+			})
+		}
+		return nil, err
+	}
+
+	return statement, nil
+}
+
+// ReconcileStatement compares a statement with the bank's total and with what
+// its cycle bills now, and records the bank total. The statement is reconciled
+// when both totals match.
+func (s *service) ReconcileStatement(ctx context.Context, userID string, cardID string, statementID string, input *ReconcileInput) (*Reconciliation, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	card, err := s.getCard(ctx, userID, cardID)
+	if err != nil {
+		return nil, err
+	}
+
+	statement, err := s.getStatement(ctx, card.ID, statementID)
+	if err != nil {
+		return nil, err
+	}
+
+	live, err := s.cycleItems(ctx, card, statement.BillingCycle)
+	if err != nil {
+		return nil, err
+	}
+	result := reconcile(statement, *input.BankTotal, live)
+
+	if err := s.repo.SetStatementBankTotal(ctx, statement.ID, result.BankTotal, result.Reconciled); err != nil {
+		return nil, err
+	}
+	oldBankTotal := statement.BankTotal
+	statement.BankTotal = &result.BankTotal
+	if !result.Reconciled {
+		statement.ReconciledAt = nil
+	} else if statement.ReconciledAt == nil {
+		now := time.Now()
+		statement.ReconciledAt = &now
+	}
+
+	s.auditService.LogAsync(ctx, &audit.LogInput{
+		UserID:       audit.StringPtr(userID),
+		Action:       audit.ActionCardStatementReconciled,
+		ResourceType: "card_statement",
+		ResourceID:   audit.StringPtr(statement.ID),
+		HouseholdID:  audit.StringPtr(card.HouseholdID),
+		OldValues:    map[string]interface{}{"bank_total": oldBankTotal},
+		NewValues: map[string]interface{}{
+			"bank_total": result.BankTotal,
+			"difference": result.Difference,
+			"reconciled": result.Reconciled,
+			"missing":    len(result.Missing),
+			"unmatched":  len(result.Unmatched),
+		},
+		Success: true,
+	})
+
+	return result, nil
+}
+
+// ReopenStatement deletes the latest statement of a card, so its cycle is
+// computed from its movements again (and can be closed anew)
+func (s *service) ReopenStatement(ctx context.Context, userID string, cardID string, statementID string) error {
+	card, err := s.getCard(ctx, userID, cardID)
+	if err != nil {
+		return err
+	}
+
+	statements, err := s.repo.ListStatements(ctx, card.ID)
+	if err != nil {
+		return err
+	}
+	var statement *Statement
+	for _, st := range statements {
+		if st.ID == statementID {
+			statement = st
+			break
+		}
+	}
+	if statement == nil {
+		return ErrStatementNotFound
+	}
+	if statements[0].ID != statement.ID {
+		return ErrNotLatestStatement
+	}
+
+	if err := s.repo.DeleteStatement(ctx, statement.ID); err != nil {
+		return err
+	}
+
+	s.auditService.LogAsync(ctx, &audit.LogInput{
+		UserID:       audit.StringPtr(userID),
+		Action:       audit.ActionCardStatementDeleted,
+		ResourceType: "card_statement",
+		ResourceID:   audit.StringPtr(statement.ID),
+		HouseholdID:  audit.StringPtr(card.HouseholdID),
+		OldValues:    audit.StructToMap(statement),
+		Success:      true,
+	})
+
+	return nil
+}
+
+// CloseEndedCycles closes the last ended cycle of every credit card that
+// hasn't been closed yet, and returns how many it closed. A failing card
+// doesn't stop the others.
+func (s *service) CloseEndedCycles(ctx context.Context, now time.Time) (int, error) {
+	cards, err := s.repo.GetAllCreditCards(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("get credit cards: %w", err)
+	}
+
+	closed := 0
+	for _, card := range cards {
+		_, err := s.closeCycle(ctx, card, lastEndedCycle(now, card.CutoffDay), nil)
+		if errors.Is(err, ErrStatementExists) {
+			continue
+		}
+		if err != nil {
+			s.logger.Error("failed to close card statement", "card_id", card.ID, "error", err)
+			continue
+		}
+		closed++
+	}
+
+	return closed, nil
+}
+
+// closeCycle closes a card's cycle into a statement. The opening balance is
+// the closing balance of the previous cycle's statement or, without one, what
+// had been billed on the card before the cycle. closedBy is nil when the cycle
+// is closed automatically.
+func (s *service) closeCycle(ctx context.Context, card *paymentmethods.PaymentMethod, cycle BillingCycle, closedBy *string) (*Statement, error) {
+	statements, err := s.repo.ListStatements(ctx, card.ID)
+	if err != nil {
+		return nil, err
+	}
+	var previous *Statement
+	for _, st := range statements {
+		if sameDay(st.BillingCycle.StartDate, cycle.StartDate) {
+			return nil, ErrStatementExists
+		}
+		if previous == nil && dateOf(st.BillingCycle.StartDate).Before(dateOf(cycle.StartDate)) {
+			previous = st
+		}
+	}
+
+	var opening money.Amount
+	if previous != nil && sameDay(previous.BillingCycle.EndDate, cycle.StartDate) {
+		opening = previous.ClosingBalance
+	} else {
+		balance, err := s.repo.GetCardBalance(ctx, card.ID, cycle.StartDate.AddDate(0, 0, -1))
+		if err != nil {
+			return nil, fmt.Errorf("get card balance: %w", err)
+		}
+		purchases, err := s.repo.GetInstallmentPurchases(ctx, card.ID)
+		if err != nil {
+			return nil, fmt.Errorf("get installment purchases: %w", err)
+		}
+		opening = billedBalance(balance, purchases, cycle, card.CutoffDay)
+	}
+
+	charges, _, err := s.getCharges(ctx, card.ID, card.CutoffDay, cycle)
+	if err != nil {
+		return nil, fmt.Errorf("get card charges: %w", err)
+	}
+	payments, _, err := s.repo.GetCardPayments(ctx, card.ID, cycle.StartDate, cycle.EndDate)
+	if err != nil {
+		return nil, fmt.Errorf("get card payments: %w", err)
+	}
+
+	statement := buildStatement(cycle, opening, charges, payments)
+	statement.HouseholdID = card.HouseholdID
+	statement.CreditCardID = card.ID
+	statement.ClosedBy = closedBy
+	if card.PaymentDueDay != nil {
+		due := PaymentDueDate(cycle, *card.PaymentDueDay)
+		statement.DueDate = &due
+	}
+	if card.MinimumPaymentPercentage != nil && statement.ClosingBalance > 0 {
+		minimum := statement.ClosingBalance.Mul(*card.MinimumPaymentPercentage)
+		statement.MinimumPayment = &minimum
+	}
+
+	result, err := s.repo.CreateStatement(ctx, statement)
+	if err != nil {
+		return nil, err
+	}
+
+	s.auditService.LogAsync(ctx, &audit.LogInput{
+		UserID:       closedBy,
+		Action:       audit.ActionCardStatementClosed,
+		ResourceType: "card_statement",
+		ResourceID:   audit.StringPtr(result.ID),
+		HouseholdID:  audit.StringPtr(card.HouseholdID),
+		NewValues:    audit.StructToMap(result),
+		Success:      true,
+	})
+
+	return result, nil
+}
+
+// getStatement returns a statement of a card with its items
+func (s *service) getStatement(ctx context.Context, cardID, statementID string) (*Statement, error) {
+	statement, err := s.repo.GetStatement(ctx, statementID)
+	if err != nil {
+		return nil, err
+	}
+	if statement.CreditCardID != cardID {
+		return nil, ErrStatementNotFound
+	}
+	return statement, nil
+}
+
+// findStatement returns the statement a card's cycle closed into, nil when it
+// is still open
+func (s *service) findStatement(ctx context.Context, cardID string, cycle BillingCycle) (*Statement, error) {
+	statements, err := s.repo.ListStatements(ctx, cardID)
+	if err != nil {
+		return nil, err
+	}
+	for _, st := range statements {
+		if sameDay(st.BillingCycle.StartDate, cycle.StartDate) {
+			return st, nil
+		}
+	}
+	return nil, nil
+}
+
+// statementDue returns what the statement of the cycle before open asks to
+// pay as of a date. The closing balance is the statement's when the cycle was
+// closed, or else what had been billed on the card before open.
+func (s *service) statementDue(ctx context.Context, cardID string, cutoffDay *int, open BillingCycle, asOf time.Time) (statementDue, error) {
+	due := statementDue{cycle: CalculateBillingCycle(open.StartDate.AddDate(0, 0, -1), cutoffDay)}
+
+	statement, err := s.findStatement(ctx, cardID, due.cycle)
+	if err != nil {
+		return due, err
+	}
+	if statement != nil {
+		due.closing = statement.ClosingBalance
+	} else {
+		balance, err := s.repo.GetCardBalance(ctx, cardID, open.StartDate.AddDate(0, 0, -1))
+		if err != nil {
+			return due, err
+		}
+		purchases, err := s.repo.GetInstallmentPurchases(ctx, cardID)
+		if err != nil {
+			return due, err
+		}
+		due.closing = billedBalance(balance, purchases, open, cutoffDay)
+	}
+
+	// Paid from the day after the cut-off up to asOf
+	until := time.Date(asOf.Year(), asOf.Month(), asOf.Day()+1, 0, 0, 0, 0, open.StartDate.Location())
+	_, due.paid, err = s.repo.GetCardPayments(ctx, cardID, open.StartDate, until)
+	if err != nil {
+		return due, err
+	}
+
+	return due, nil
+}
+
+// cycleActivity is what a card billed in a cycle and what was paid to it
+type cycleActivity struct {
+	charges       []*CardMovement
+	chargesTotal  money.Amount
+	payments      []*CardPayment
+	paymentsTotal money.Amount
+	statement     *Statement // The statement the cycle closed into (without items), nil while open
+}
+
+// getCycleActivity returns a card's charges and payments for a cycle, both
+// from its start to its cut-off. An open cycle is computed from its movements.
+// A closed cycle is served from its statement: the charges and payments it had
+// at closing time, whatever was edited since (see ReconcileStatement for the
+// drift).
+func (s *service) getCycleActivity(ctx context.Context, cardID string, cutoffDay *int, cycle BillingCycle) (*cycleActivity, error) {
+	statement, err := s.findStatement(ctx, cardID, cycle)
+	if err != nil {
+		return nil, err
+	}
+
+	if statement == nil {
+		activity := &cycleActivity{}
+		activity.charges, activity.chargesTotal, err = s.getCharges(ctx, cardID, cutoffDay, cycle)
+		if err != nil {
+			return nil, err
+		}
+
+		// Same window the statement closes over (see closeCycle)
+		activity.payments, activity.paymentsTotal, err = s.repo.GetCardPayments(ctx, cardID, cycle.StartDate, cycle.EndDate)
+		if err != nil {
+			return nil, err
+		}
+		return activity, nil
+	}
+
+	closed, err := s.repo.GetStatement(ctx, statement.ID)
+	if err != nil {
+		return nil, err
+	}
+	// The live cycle only lends the details the statement doesn't keep
+	charges, _, err := s.getCharges(ctx, cardID, cutoffDay, cycle)
+	if err != nil {
+		return nil, err
+	}
+	payments, _, err := s.repo.GetCardPayments(ctx, cardID, cycle.StartDate, cycle.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cycleActivity{
+		charges:       closedCharges(closed, charges),
+		chargesTotal:  closed.Charges + closed.Interest,
+		payments:      closedPayments(closed, payments),
+		paymentsTotal: closed.Payments,
+		statement:     statement,
+	}, nil
+}
+
+// cycleItems returns the statement lines a card's cycle has now
+func (s *service) cycleItems(ctx context.Context, card *paymentmethods.PaymentMethod, cycle BillingCycle) ([]*StatementItem, error) {
+	charges, _, err := s.getCharges(ctx, card.ID, card.CutoffDay, cycle)
+	if err != nil {
+		return nil, fmt.Errorf("get card charges: %w", err)
+	}
+	payments, _, err := s.repo.GetCardPayments(ctx, card.ID, cycle.StartDate, cycle.EndDate)
+	if err != nil {
+		return nil, fmt.Errorf("get card payments: %w", err)
+	}
+	return statementItems(charges, payments), nil
+}
+
+// getCard returns a credit card of the user's household
+func (s *service) getCard(ctx context.Context, userID, cardID string) (*paymentmethods.PaymentMethod, error) {
 	// Get household ID for authorization
 	householdID, err := s.householdsRepo.GetUserHouseholdID(ctx, userID)
 	if err != nil {
@@ -151,42 +622,22 @@ func (s *service) GetCardMovements(ctx context.Context, userID string, cardID st
 		return nil, ErrCardNotFound
 	}
 
-	// Calculate billing cycle for this card (used for charges)
-	cycle := CalculateBillingCycle(cycleDate, card.CutoffDay)
-
-	// Calculate calendar month for payments (1st to last day of the month)
-	calendarMonthStart := time.Date(cycleDate.Year(), cycleDate.Month(), 1, 0, 0, 0, 0, cycleDate.Location())
-	calendarMonthEnd := calendarMonthStart.AddDate(0, 1, 0) // First day of next month
+	return card, nil
+}
 
-	// Get movements (charges) - uses billing cycle
-	movements, chargesTotal, err := s.repo.GetCardCharges(ctx, card.ID, cycle.StartDate, cycle.EndDate)
+// getCharges returns what a card bills in a cycle: the movements made in it,
+// plus one installment of every purchase in installments that reaches it
+func (s *service) getCharges(ctx context.Context, cardID string, cutoffDay *int, cycle BillingCycle) ([]*CardMovement, money.Amount, error) {
+	movements, total, err := s.repo.GetCardCharges(ctx, cardID, cycle.StartDate, cycle.EndDate)
 	if err != nil {
-		return nil, fmt.Errorf("get card charges: %w", err)
+		return nil, 0, err
 	}
-
-	// Get payments - uses calendar month
-	payments, paymentsTotal, err := s.repo.GetCardPayments(ctx, card.ID, calendarMonthStart, calendarMonthEnd)
+	purchases, err := s.repo.GetInstallmentPurchases(ctx, cardID)
 	if err != nil {
-		return nil, fmt.Errorf("get card payments: %w", err)
+		return nil, 0, err
 	}
-
-	response := &CardMovementsResponse{
-		CreditCard: CardInfo{
-			ID:        card.ID,
-			Name:      card.Name,
-			OwnerName: card.OwnerName,
-			CutoffDay: card.CutoffDay,
-		},
-		BillingCycle: cycle,
-		NetDebt:      chargesTotal - paymentsTotal,
-	}
-
-	response.Charges.Movements = movements
-	response.Charges.Total = chargesTotal
-	response.Payments.Items = payments
-	response.Payments.Total = paymentsTotal
-
-	return response, nil
+	movements, total = addInstallments(movements, total, purchases, cycle, cutoffDay)
+	return movements, total, nil
 }
 
 // applyFilters filters cards based on the provided filter criteria
@@ -1,8 +1,12 @@
 package creditcards
 
 import (
+	"context"
 	"testing"
 	"time"
+
+	"github.com/blanquicet/conti/backend/internal/households"
+	"github.com/blanquicet/conti/backend/internal/money"
 )
 
 func TestCalculateBillingCycle_NilCutoff(t *testing.T) {
@@ -225,3 +229,182 @@ func TestApplyFilters_CombinedFilters(t *testing.T) {
 		t.Errorf("applyFilters returned wrong card: %s, want card1", result[0].ID)
 	}
 }
+
+// fakeHouseholds puts every user in household h1
+type fakeHouseholds struct {
+	households.HouseholdRepository
+}
+
+func (fakeHouseholds) GetUserHouseholdID(ctx context.Context, userID string) (string, error) {
+	return "h1", nil
+}
+
+// fakeRepository keeps the cards, charges, payments and statements of a
+// household in memory
+type fakeRepository struct {
+	Repository
+	cards      []*CardSummary
+	charges    []*CardMovement
+	payments   []*CardPayment
+	statements []*Statement
+	savings    money.Amount
+}
+
+func (r *fakeRepository) GetCreditCards(ctx context.Context, householdID string) ([]*CardSummary, error) {
+	return r.cards, nil
+}
+
+func (r *fakeRepository) GetCardCharges(ctx context.Context, cardID string, startDate, endDate time.Time) ([]*CardMovement, money.Amount, error) {
+	var list []*CardMovement
+	var total money.Amount
+	for _, m := range r.charges {
+		if !m.MovementDate.Before(startDate) && m.MovementDate.Before(endDate) {
+			list = append(list, m)
+			total += m.Amount
+		}
+	}
+	return list, total, nil
+}
+
+func (r *fakeRepository) GetInstallmentPurchases(ctx context.Context, cardID string) ([]*InstallmentPurchase, error) {
+	return nil, nil
+}
+
+func (r *fakeRepository) GetCardPayments(ctx context.Context, cardID string, startDate, endDate time.Time) ([]*CardPayment, money.Amount, error) {
+	var list []*CardPayment
+	var total money.Amount
+	for _, p := range r.payments {
+		if !p.PaymentDate.Before(startDate) && p.PaymentDate.Before(endDate) {
+			list = append(list, p)
+			total += p.Amount
+		}
+	}
+	return list, total, nil
+}
+
+func (r *fakeRepository) GetCardBalance(ctx context.Context, cardID string, asOfDate time.Time) (money.Amount, error) {
+	var balance money.Amount
+	for _, m := range r.charges {
+		if !m.MovementDate.After(asOfDate) {
+			balance += m.Amount
+		}
+	}
+	for _, p := range r.payments {
+		if !p.PaymentDate.After(asOfDate) {
+			balance -= p.Amount
+		}
+	}
+	return balance, nil
+}
+
+func (r *fakeRepository) GetSavingsBalances(ctx context.Context, householdID string, asOfDate time.Time) ([]*AccountBalance, error) {
+	return []*AccountBalance{{ID: "a1", Name: "Ahorros", Type: "savings", Balance: r.savings}}, nil
+}
+
+func (r *fakeRepository) ListStatements(ctx context.Context, cardID string) ([]*Statement, error) {
+	return r.statements, nil
+}
+
+func (r *fakeRepository) GetStatement(ctx context.Context, id string) (*Statement, error) {
+	for _, st := range r.statements {
+		if st.ID == id {
+			return st, nil
+		}
+	}
+	return nil, ErrStatementNotFound
+}
+
+func TestGetSummary_ClosedStatementDueSoon(t *testing.T) {
+	cutoff, dueDay := 15, 25
+	day := func(month time.Month, d int) time.Time { return time.Date(2026, month, d, 0, 0, 0, 0, time.UTC) }
+	newRepo := func() *fakeRepository {
+		return &fakeRepository{
+			cards: []*CardSummary{{ID: "visa", Name: "Visa", CutoffDay: &cutoff, PaymentDueDay: &dueDay}},
+			charges: []*CardMovement{
+				{ID: "m1", Amount: money.New(900000), MovementDate: day(time.October, 2)},  // Cycle Sep 16 - Oct 15
+				{ID: "m2", Amount: money.New(400000), MovementDate: day(time.October, 18)}, // Open cycle
+			},
+			payments: []*CardPayment{{ID: "p1", Amount: money.New(100000), PaymentDate: day(time.October, 17)}},
+			savings:  money.New(500000),
+		}
+	}
+
+	tests := []struct {
+		name       string
+		statements []*Statement
+		want       money.Amount
+		wantRisk   bool
+	}{
+		// Closed Oct 15 at 900000 (computed), 100000 paid since
+		{"closing balance from movements", nil, money.New(800000), true},
+		// The snapshot says 550000, whatever the movements say now
+		{"closing balance from the statement", []*Statement{{
+			ID:             "st1",
+			BillingCycle:   CalculateBillingCycle(day(time.October, 1), &cutoff),
+			ClosingBalance: money.New(550000),
+		}}, money.New(450000), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newRepo()
+			repo.statements = tt.statements
+			s := &service{repo: repo, householdsRepo: fakeHouseholds{}}
+
+			summary, err := s.GetSummary(context.Background(), "u1", day(time.October, 20), nil)
+			if err != nil {
+				t.Fatalf("GetSummary() error = %v", err)
+			}
+			card := summary.Cards[0]
+
+			// Due Oct 25 for the cycle closed Oct 15, not Nov 25 for the open one
+			if card.PaymentDueDate == nil || !card.PaymentDueDate.Equal(day(time.October, 25)) {
+				t.Errorf("PaymentDueDate = %v, want 2026-10-25", card.PaymentDueDate)
+			}
+			if card.StatementBalance != tt.want {
+				t.Errorf("StatementBalance = %v, want %v", card.StatementBalance, tt.want)
+			}
+			if card.PaymentAtRisk != tt.wantRisk {
+				t.Errorf("PaymentAtRisk = %v, want %v", card.PaymentAtRisk, tt.wantRisk)
+			}
+		})
+	}
+}
+
+func TestGetSummary_ClosedCycleFromStatement(t *testing.T) {
+	cutoff := 15
+	day := func(month time.Month, d int) time.Time { return time.Date(2026, month, d, 0, 0, 0, 0, time.UTC) }
+	charges := []*CardMovement{
+		{ID: "m1", Description: "Mercado", Amount: money.New(200000), MovementDate: day(time.September, 20)},
+		{ID: "m2", Description: "Cine", Amount: money.New(50000), MovementDate: day(time.October, 1)},
+	}
+	payments := []*CardPayment{{ID: "p1", Amount: money.New(30000), PaymentDate: day(time.October, 5)}}
+	cycle := CalculateBillingCycle(day(time.October, 1), &cutoff)
+	statement := buildStatement(cycle, 0, charges, payments)
+	statement.ID = "st1"
+
+	// After closing, m2 was deleted and a late charge was added to the cycle
+	repo := &fakeRepository{
+		cards: []*CardSummary{{ID: "visa", Name: "Visa", CutoffDay: &cutoff}},
+		charges: []*CardMovement{
+			charges[0],
+			{ID: "m3", Description: "Taxi", Amount: money.New(15000), MovementDate: day(time.October, 10)},
+		},
+		payments:   payments,
+		statements: []*Statement{statement},
+	}
+	s := &service{repo: repo, householdsRepo: fakeHouseholds{}}
+
+	summary, err := s.GetSummary(context.Background(), "u1", day(time.October, 1), nil)
+	if err != nil {
+		t.Fatalf("GetSummary() error = %v", err)
+	}
+	card := summary.Cards[0]
+	if card.TotalCharges != money.New(250000) || card.TotalPayments != money.New(30000) || card.NetDebt != money.New(220000) {
+		t.Errorf("totals = %v charges, %v payments, %v net, want the statement's 250000, 30000 and 220000",
+			card.TotalCharges, card.TotalPayments, card.NetDebt)
+	}
+	if card.MovementCount != 2 || card.PaymentCount != 1 {
+		t.Errorf("counts = %d movements, %d payments, want 2 and 1", card.MovementCount, card.PaymentCount)
+	}
+}
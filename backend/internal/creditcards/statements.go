@@ -0,0 +1,318 @@
+package creditcards
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/blanquicet/conti/backend/internal/money"
+)
+
+var (
+	ErrStatementNotFound  = errors.New("statement not found")
+	ErrStatementExists    = errors.New("the billing cycle already has a statement")
+	ErrCycleNotEnded      = errors.New("the billing cycle has not ended yet")
+	ErrNotLatestStatement = errors.New("only the latest statement can be reopened")
+	ErrInvalidBankTotal   = errors.New("bank_total is required")
+)
+
+// StatementItemKind is what a statement line is
+type StatementItemKind string
+
+const (
+	ItemCharge  StatementItemKind = "CHARGE"  // A movement, or one installment of a purchase
+	ItemPayment StatementItemKind = "PAYMENT" // A payment to the card
+)
+
+// Statement is a billing cycle closed into a snapshot. Its amounts are the
+// ones of closing time; later edits to its movements show up in Reconcile.
+type Statement struct {
+	ID             string       `json:"id"`
+	HouseholdID    string       `json:"household_id"`
+	CreditCardID   string       `json:"credit_card_id"`
+	BillingCycle   BillingCycle `json:"billing_cycle"`
+	OpeningBalance money.Amount `json:"opening_balance"`
+	Charges        money.Amount `json:"charges"`  // Capital only, interest is apart
+	Payments       money.Amount `json:"payments"` // Made during the cycle
+	Interest       money.Amount `json:"interest"` // Interest of the installments billed
+	ClosingBalance money.Amount `json:"closing_balance"`
+
+	DueDate        *time.Time    `json:"due_date,omitempty"`
+	MinimumPayment *money.Amount `json:"minimum_payment,omitempty"`
+
+	// Reconciliation (ReconciledAt is set when BankTotal matched the closing balance)
+	BankTotal    *money.Amount `json:"bank_total,omitempty"`
+	ReconciledAt *time.Time    `json:"reconciled_at,omitempty"`
+
+	ClosedBy  *string   `json:"closed_by,omitempty"` // nil when closed automatically
+	CreatedAt time.Time `json:"created_at"`
+
+	Items []*StatementItem `json:"items,omitempty"`
+}
+
+// defaultPaymentDescription describes a payment line without notes
+const defaultPaymentDescription = "Pago"
+
+// StatementItem is a line of a statement
+type StatementItem struct {
+	Kind              StatementItemKind `json:"kind"`
+	MovementID        *string           `json:"movement_id,omitempty"`
+	PaymentID         *string           `json:"payment_id,omitempty"`
+	Description       string            `json:"description"`
+	Date              time.Time         `json:"date"`
+	Amount            money.Amount      `json:"amount"` // Including interest
+	Interest          money.Amount      `json:"interest,omitempty"`
+	InstallmentNumber *int              `json:"installment_number,omitempty"`
+	InstallmentCount  *int              `json:"installment_count,omitempty"`
+}
+
+// key identifies what an item is, so the same charge or payment can be found
+// in the snapshot and in the live cycle
+func (i *StatementItem) key() string {
+	switch {
+	case i.PaymentID != nil:
+		return "payment:" + *i.PaymentID
+	case i.MovementID != nil && i.InstallmentNumber != nil:
+		return fmt.Sprintf("movement:%s:%d", *i.MovementID, *i.InstallmentNumber)
+	case i.MovementID != nil:
+		return "movement:" + *i.MovementID
+	default:
+		return ""
+	}
+}
+
+// ReconcileInput is the bank's statement to reconcile against
+type ReconcileInput struct {
+	BankTotal *money.Amount `json:"bank_total"`
+}
+
+// Validate validates the reconcile input
+func (i *ReconcileInput) Validate() error {
+	if i.BankTotal == nil {
+		return ErrInvalidBankTotal
+	}
+	return nil
+}
+
+// Reconciliation compares a statement with the bank's total and with what
+// its cycle bills today
+type Reconciliation struct {
+	Statement  *Statement   `json:"statement"`
+	BankTotal  money.Amount `json:"bank_total"`
+	Difference money.Amount `json:"difference"` // Bank total - closing balance
+
+	// Missing are charges and payments the cycle has now that the statement
+	// doesn't (added, moved into the cycle or changed after closing).
+	// Unmatched are statement items the cycle no longer has as they were.
+	Missing   []*StatementItem `json:"missing"`
+	Unmatched []*StatementItem `json:"unmatched"`
+
+	Reconciled bool `json:"reconciled"` // The bank total matches the closing balance
+}
+
+// lastEndedCycle returns the latest billing cycle that ended by today
+func lastEndedCycle(today time.Time, cutoffDay *int) BillingCycle {
+	current := CalculateBillingCycle(dateOf(today), cutoffDay)
+	return CalculateBillingCycle(current.StartDate.AddDate(0, 0, -1), cutoffDay)
+}
+
+// cycleEnded reports whether a billing cycle ended by today (its end date is
+// the day after the cut-off)
+func cycleEnded(cycle BillingCycle, today time.Time) bool {
+	return !dateOf(cycle.EndDate).After(dateOf(today))
+}
+
+// sameDay reports whether two times fall on the same calendar day, whatever
+// their location (DATE columns scan as UTC)
+func sameDay(a, b time.Time) bool {
+	return dateOf(a).Equal(dateOf(b))
+}
+
+// dateOf returns the calendar day of t as midnight UTC
+func dateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// buildStatement returns the statement a cycle closes into
+func buildStatement(cycle BillingCycle, opening money.Amount, charges []*CardMovement, payments []*CardPayment) *Statement {
+	st := &Statement{
+		BillingCycle:   cycle,
+		OpeningBalance: opening,
+		Items:          statementItems(charges, payments),
+	}
+	for _, item := range st.Items {
+		switch item.Kind {
+		case ItemCharge:
+			st.Charges += item.Amount - item.Interest
+			st.Interest += item.Interest
+		case ItemPayment:
+			st.Payments += item.Amount
+		}
+	}
+	st.ClosingBalance = st.OpeningBalance + st.Charges + st.Interest - st.Payments
+	return st
+}
+
+// statementItems returns the lines of a cycle's charges and payments
+func statementItems(charges []*CardMovement, payments []*CardPayment) []*StatementItem {
+	items := make([]*StatementItem, 0, len(charges)+len(payments))
+	for _, m := range charges {
+		id := m.ID
+		item := &StatementItem{
+			Kind:        ItemCharge,
+			MovementID:  &id,
+			Description: m.Description,
+			Date:        m.MovementDate,
+			Amount:      m.Amount,
+		}
+		if m.Installment != nil {
+			number, count := m.Installment.Number, m.Installment.Count
+			item.Interest = m.Installment.Interest
+			item.InstallmentNumber = &number
+			item.InstallmentCount = &count
+		}
+		items = append(items, item)
+	}
+	for _, p := range payments {
+		id := p.ID
+		description := defaultPaymentDescription
+		if p.Notes != nil && *p.Notes != "" {
+			description = *p.Notes
+		}
+		items = append(items, &StatementItem{
+			Kind:        ItemPayment,
+			PaymentID:   &id,
+			Description: description,
+			Date:        p.PaymentDate,
+			Amount:      p.Amount,
+		})
+	}
+	return items
+}
+
+// closedCharges returns the charges a statement billed as card movements,
+// with the details (type, category, payer) of the live charges that still
+// match them. Amounts and dates are always the statement's.
+func closedCharges(st *Statement, live []*CardMovement) []*CardMovement {
+	current := make(map[string]*CardMovement, len(live))
+	for i, item := range statementItems(live, nil) {
+		current[item.key()] = live[i]
+	}
+
+	charges := []*CardMovement{}
+	for _, item := range st.Items {
+		if item.Kind != ItemCharge || item.MovementID == nil {
+			continue
+		}
+		charge := &CardMovement{ID: *item.MovementID}
+		if m, ok := current[item.key()]; ok {
+			now := *m
+			charge = &now
+		}
+		charge.Description = item.Description
+		charge.Amount = item.Amount
+		charge.MovementDate = item.Date
+		charge.Installment = nil
+		if item.InstallmentNumber != nil && item.InstallmentCount != nil {
+			charge.Installment = &Installment{
+				Number:       *item.InstallmentNumber,
+				Count:        *item.InstallmentCount,
+				BillingCycle: st.BillingCycle,
+				Capital:      item.Amount - item.Interest,
+				Interest:     item.Interest,
+				Amount:       item.Amount,
+			}
+		}
+		charges = append(charges, charge)
+	}
+	return charges
+}
+
+// closedPayments returns the payments a statement counted, with the source
+// accounts of the live payments that are still there
+func closedPayments(st *Statement, live []*CardPayment) []*CardPayment {
+	current := make(map[string]*CardPayment, len(live))
+	for _, p := range live {
+		current[p.ID] = p
+	}
+
+	payments := []*CardPayment{}
+	for _, item := range st.Items {
+		if item.Kind != ItemPayment || item.PaymentID == nil {
+			continue
+		}
+		payment := &CardPayment{ID: *item.PaymentID}
+		if p, ok := current[*item.PaymentID]; ok {
+			now := *p
+			payment = &now
+		} else if item.Description != defaultPaymentDescription {
+			notes := item.Description
+			payment.Notes = &notes
+		}
+		payment.Amount = item.Amount
+		payment.PaymentDate = item.Date
+		payments = append(payments, payment)
+	}
+	return payments
+}
+
+// reconcile compares a statement with the bank total and the cycle's items
+// as they are now. An item matches when the same charge or payment is there
+// with the same amount.
+func reconcile(st *Statement, bankTotal money.Amount, live []*StatementItem) *Reconciliation {
+	r := &Reconciliation{
+		Statement:  st,
+		BankTotal:  bankTotal,
+		Difference: bankTotal - st.ClosingBalance,
+		Missing:    []*StatementItem{},
+		Unmatched:  []*StatementItem{},
+	}
+	r.Reconciled = r.Difference == 0
+
+	current := make(map[string]*StatementItem, len(live))
+	for _, item := range live {
+		current[item.key()] = item
+	}
+	closed := make(map[string]bool, len(st.Items))
+	for _, item := range st.Items {
+		closed[item.key()] = true
+		now, ok := current[item.key()]
+		if !ok || now.Amount != item.Amount {
+			r.Unmatched = append(r.Unmatched, item)
+		}
+	}
+	for _, item := range live {
+		if !closed[item.key()] {
+			r.Missing = append(r.Missing, item)
+			continue
+		}
+		// Changed after closing: unmatched as it was, missing as it is
+		for _, old := range st.Items {
+			if old.key() == item.key() && old.Amount != item.Amount {
+				r.Missing = append(r.Missing, item)
+				break
+			}
+		}
+	}
+	return r
+}
+
+// billedBalance returns what had been billed on a card before a cycle, from
+// its balance up to the day before: purchases in installments count in the
+// balance in full, but only their installments billed before the cycle (with
+// their interest) had been billed
+func billedBalance(balance money.Amount, purchases []*InstallmentPurchase, cycle BillingCycle, cutoffDay *int) money.Amount {
+	for _, p := range purchases {
+		if !p.MovementDate.Before(cycle.StartDate) {
+			continue
+		}
+		for _, installment := range p.Schedule(cutoffDay) {
+			if installment.BillingCycle.StartDate.Before(cycle.StartDate) {
+				balance += installment.Interest
+			} else {
+				balance -= installment.Capital
+			}
+		}
+	}
+	return balance
+}
@@ -0,0 +1,207 @@
+package creditcards
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blanquicet/conti/backend/internal/money"
+)
+
+func TestBuildStatement(t *testing.T) {
+	cutoff := 15
+	cycle := CalculateBillingCycle(time.Date(2026, time.March, 10, 0, 0, 0, 0, time.UTC), &cutoff)
+	charges := []*CardMovement{
+		{ID: "m1", Description: "Mercado", Amount: money.New(150000), MovementDate: time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)},
+		{ID: "m2", Description: "Devolución", Amount: money.New(-20000), MovementDate: time.Date(2026, time.March, 2, 0, 0, 0, 0, time.UTC)},
+		{ID: "m3", Description: "Televisor", Amount: money.New(102000), MovementDate: time.Date(2026, time.January, 10, 0, 0, 0, 0, time.UTC),
+			Installment: &Installment{Number: 3, Count: 3, Capital: money.New(100000), Interest: money.New(2000), Amount: money.New(102000)}},
+	}
+	payments := []*CardPayment{
+		{ID: "p1", Amount: money.New(300000), PaymentDate: time.Date(2026, time.February, 20, 0, 0, 0, 0, time.UTC)},
+	}
+
+	st := buildStatement(cycle, money.New(500000), charges, payments)
+
+	if st.Charges != money.New(230000) {
+		t.Errorf("Charges = %v, want 230000", st.Charges)
+	}
+	if st.Interest != money.New(2000) {
+		t.Errorf("Interest = %v, want 2000", st.Interest)
+	}
+	if st.Payments != money.New(300000) {
+		t.Errorf("Payments = %v, want 300000", st.Payments)
+	}
+	// 500000 + 230000 + 2000 - 300000
+	if st.ClosingBalance != money.New(432000) {
+		t.Errorf("ClosingBalance = %v, want 432000", st.ClosingBalance)
+	}
+	if len(st.Items) != 4 {
+		t.Fatalf("len(Items) = %d, want 4", len(st.Items))
+	}
+	if item := st.Items[2]; item.InstallmentNumber == nil || *item.InstallmentNumber != 3 || item.Interest != money.New(2000) {
+		t.Errorf("installment item = %+v, want installment 3 with 2000 interest", item)
+	}
+	if item := st.Items[3]; item.Kind != ItemPayment || item.Description != "Pago" {
+		t.Errorf("payment item = %+v, want a PAYMENT described as Pago", item)
+	}
+}
+
+func TestClosedCharges(t *testing.T) {
+	cutoff := 15
+	cycle := CalculateBillingCycle(time.Date(2026, time.March, 10, 0, 0, 0, 0, time.UTC), &cutoff)
+	category := "Mercado"
+	closedWith := []*CardMovement{
+		{ID: "m1", Type: "HOUSEHOLD", Description: "Mercado", Amount: money.New(150000), MovementDate: time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC), CategoryName: &category},
+		{ID: "m2", Description: "Cine", Amount: money.New(40000), MovementDate: time.Date(2026, time.March, 5, 0, 0, 0, 0, time.UTC)},
+		{ID: "m3", Description: "Televisor", Amount: money.New(102000), MovementDate: time.Date(2026, time.January, 10, 0, 0, 0, 0, time.UTC),
+			Installment: &Installment{Number: 3, Count: 3, Capital: money.New(100000), Interest: money.New(2000), Amount: money.New(102000)}},
+	}
+	payments := []*CardPayment{
+		{ID: "p1", Amount: money.New(300000), PaymentDate: time.Date(2026, time.February, 20, 0, 0, 0, 0, time.UTC), SourceAccountName: "Ahorros"},
+	}
+	st := buildStatement(cycle, 0, closedWith, payments)
+
+	// Since closing, m1 went up and m2 was deleted
+	edited := *closedWith[0]
+	edited.Amount = money.New(180000)
+	live := []*CardMovement{&edited, closedWith[2]}
+
+	charges := closedCharges(st, live)
+	if len(charges) != 3 {
+		t.Fatalf("len(charges) = %d, want 3", len(charges))
+	}
+	if m := charges[0]; m.Amount != money.New(150000) || m.CategoryName == nil || *m.CategoryName != "Mercado" {
+		t.Errorf("edited charge = %+v, want the closing amount with the live category", m)
+	}
+	if m := charges[1]; m.ID != "m2" || m.Amount != money.New(40000) {
+		t.Errorf("deleted charge = %+v, want it as the statement has it", m)
+	}
+	if m := charges[2]; m.Installment == nil || m.Installment.Number != 3 || m.Installment.Capital != money.New(100000) {
+		t.Errorf("installment charge = %+v, want installment 3 of 100000 capital", m)
+	}
+	if edited.Amount != money.New(180000) {
+		t.Error("closedCharges() changed a live charge")
+	}
+
+	closedPaid := closedPayments(st, nil)
+	if len(closedPaid) != 1 || closedPaid[0].ID != "p1" || closedPaid[0].Amount != money.New(300000) {
+		t.Errorf("closedPayments() = %+v, want p1 for 300000", closedPaid)
+	}
+	if paid := closedPayments(st, payments); paid[0].SourceAccountName != "Ahorros" {
+		t.Errorf("SourceAccountName = %q, want the live payment's", paid[0].SourceAccountName)
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	id := func(s string) *string { return &s }
+	number := 2
+	st := &Statement{
+		ClosingBalance: money.New(350000),
+		Items: []*StatementItem{
+			{Kind: ItemCharge, MovementID: id("kept"), Amount: money.New(100000)},
+			{Kind: ItemCharge, MovementID: id("edited"), Amount: money.New(50000)},
+			{Kind: ItemCharge, MovementID: id("deleted"), Amount: money.New(200000)},
+			{Kind: ItemCharge, MovementID: id("cuotas"), InstallmentNumber: &number, Amount: money.New(30000)},
+			{Kind: ItemPayment, PaymentID: id("pago"), Amount: money.New(30000)},
+		},
+	}
+	live := []*StatementItem{
+		{Kind: ItemCharge, MovementID: id("kept"), Amount: money.New(100000)},
+		{Kind: ItemCharge, MovementID: id("edited"), Amount: money.New(55000)},
+		{Kind: ItemCharge, MovementID: id("cuotas"), InstallmentNumber: &number, Amount: money.New(30000)},
+		{Kind: ItemCharge, MovementID: id("added"), Amount: money.New(10000)},
+		{Kind: ItemPayment, PaymentID: id("pago"), Amount: money.New(30000)},
+	}
+
+	r := reconcile(st, money.New(360000), live)
+
+	if r.Difference != money.New(10000) {
+		t.Errorf("Difference = %v, want 10000", r.Difference)
+	}
+	if r.Reconciled {
+		t.Error("Reconciled = true, want false")
+	}
+	if got := itemIDs(r.Unmatched); got != "edited,deleted" {
+		t.Errorf("Unmatched = %s, want edited,deleted", got)
+	}
+	if got := itemIDs(r.Missing); got != "edited,added" {
+		t.Errorf("Missing = %s, want edited,added", got)
+	}
+
+	if r := reconcile(st, money.New(350000), st.Items); !r.Reconciled || len(r.Missing) != 0 || len(r.Unmatched) != 0 {
+		t.Errorf("reconcile() against itself = %+v, want reconciled with nothing missing or unmatched", r)
+	}
+}
+
+// itemIDs returns the movement or payment IDs of items, comma-separated
+func itemIDs(items []*StatementItem) string {
+	ids := ""
+	for i, item := range items {
+		if i > 0 {
+			ids += ","
+		}
+		if item.MovementID != nil {
+			ids += *item.MovementID
+		} else {
+			ids += *item.PaymentID
+		}
+	}
+	return ids
+}
+
+func TestBilledBalance(t *testing.T) {
+	cutoff := 15
+	purchase := &InstallmentPurchase{
+		MovementID:   "tv",
+		MovementDate: time.Date(2026, time.January, 10, 0, 0, 0, 0, time.UTC),
+		FirstCycle:   time.Date(2026, time.January, 10, 0, 0, 0, 0, time.UTC),
+		Amount:       money.New(300000),
+		Installments: 3,
+		Rate:         0.01,
+	}
+	cycle := CalculateBillingCycle(time.Date(2026, time.March, 10, 0, 0, 0, 0, time.UTC), &cutoff)
+
+	// The balance has the purchase in full; two installments were billed
+	// before the cycle (100000 + 3000 and 100000 + 2000)
+	got := billedBalance(money.New(300000), []*InstallmentPurchase{purchase}, cycle, &cutoff)
+	if got != money.New(205000) {
+		t.Errorf("billedBalance() = %v, want 205000", got)
+	}
+
+	// A purchase made in the cycle isn't in the balance before it
+	later := *purchase
+	later.MovementDate = time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	later.FirstCycle = later.MovementDate
+	if got := billedBalance(0, []*InstallmentPurchase{&later}, cycle, &cutoff); got != 0 {
+		t.Errorf("billedBalance() with a purchase in the cycle = %v, want 0", got)
+	}
+}
+
+func TestLastEndedCycle(t *testing.T) {
+	cutoff := 15
+	tests := []struct {
+		name      string
+		today     time.Time
+		cutoffDay *int
+		wantStart time.Time
+	}{
+		{"mid cycle", time.Date(2026, time.March, 10, 0, 0, 0, 0, time.UTC), &cutoff, time.Date(2026, time.January, 16, 0, 0, 0, 0, time.UTC)},
+		{"day after the cut-off", time.Date(2026, time.March, 16, 0, 0, 0, 0, time.UTC), &cutoff, time.Date(2026, time.February, 16, 0, 0, 0, 0, time.UTC)},
+		{"month end cut-off", time.Date(2026, time.April, 1, 0, 0, 0, 0, time.UTC), nil, time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cycle := lastEndedCycle(tt.today, tt.cutoffDay)
+			if !cycle.StartDate.Equal(tt.wantStart) {
+				t.Errorf("StartDate = %v, want %v", cycle.StartDate.Format("2006-01-02"), tt.wantStart.Format("2006-01-02"))
+			}
+			if !cycleEnded(cycle, tt.today) {
+				t.Errorf("cycleEnded(%s) = false, want true", cycle.Label)
+			}
+			if current := CalculateBillingCycle(tt.today, tt.cutoffDay); cycleEnded(current, tt.today) {
+				t.Errorf("cycleEnded(%s) = true for the current cycle, want false", current.Label)
+			}
+		})
+	}
+}
@@ -2,6 +2,8 @@ package creditcards
 
 import (
 	"time"
+
+	"github.com/blanquicet/conti/backend/internal/money"
 )
 
 // BillingCycle represents a billing cycle period
@@ -21,11 +23,29 @@ type CardSummary struct {
 	Institution   *string      `json:"institution,omitempty"`
 	Last4         *string      `json:"last4,omitempty"`
 	BillingCycle  BillingCycle `json:"billing_cycle"` // This card's billing cycle
-	TotalCharges  float64      `json:"total_charges"` // Sum of movements paid with this card
-	TotalPayments float64      `json:"total_payments"` // Sum of credit_card_payments
-	NetDebt       float64      `json:"net_debt"`       // charges - payments
+	TotalCharges  money.Amount      `json:"total_charges"` // Sum of movements paid with this card
+	TotalPayments money.Amount      `json:"total_payments"` // Sum of credit_card_payments
+	NetDebt       money.Amount      `json:"net_debt"`       // charges - payments
 	MovementCount int          `json:"movement_count"`
 	PaymentCount  int          `json:"payment_count"`
+
+	// Limit and utilization (nil when the card has no credit limit). The
+	// balance is everything charged and not paid yet, including the capital
+	// of installments still to be billed.
+	CreditLimit     *money.Amount `json:"credit_limit,omitempty"`
+	Balance         money.Amount  `json:"balance"`
+	AvailableCredit *money.Amount `json:"available_credit,omitempty"`
+	Utilization     *float64      `json:"utilization,omitempty"` // Balance / limit (0.0 to 1.0, more when over the limit)
+
+	// Payment of the statement of the last closed cycle, the one before
+	// BillingCycle (the due date is nil when the card has no due day).
+	// StatementBalance is its closing balance less the payments made since.
+	PaymentDueDay            *int          `json:"payment_due_day,omitempty"`
+	MinimumPaymentPercentage *float64      `json:"minimum_payment_percentage,omitempty"`
+	StatementBalance         money.Amount  `json:"statement_balance"`
+	PaymentDueDate           *time.Time    `json:"payment_due_date,omitempty"`
+	MinimumPayment           *money.Amount `json:"minimum_payment,omitempty"`
+	PaymentAtRisk            bool          `json:"payment_at_risk"` // Due soon and available cash doesn't cover it
 }
 
 // AccountBalance represents a savings account with its calculated balance
@@ -33,20 +53,20 @@ type AccountBalance struct {
 	ID      string  `json:"id"`
 	Name    string  `json:"name"`
 	Type    string  `json:"type"`
-	Balance float64 `json:"balance"`
+	Balance money.Amount `json:"balance"`
 }
 
 // AvailableCash represents the total available cash across savings accounts
 type AvailableCash struct {
-	Total    float64           `json:"total"`
+	Total    money.Amount           `json:"total"`
 	Accounts []*AccountBalance `json:"accounts"`
 }
 
 // Totals represents aggregate totals across all cards
 type Totals struct {
-	TotalCharges  float64 `json:"total_charges"`
-	TotalPayments float64 `json:"total_payments"`
-	TotalDebt     float64 `json:"total_debt"`
+	TotalCharges  money.Amount `json:"total_charges"`
+	TotalPayments money.Amount `json:"total_payments"`
+	TotalDebt     money.Amount `json:"total_debt"`
 }
 
 // SummaryResponse represents the full response for the credit cards summary endpoint
@@ -63,18 +83,27 @@ type CardMovement struct {
 	ID           string    `json:"id"`
 	Type         string    `json:"type"` // HOUSEHOLD, SPLIT, DEBT_PAYMENT
 	Description  string    `json:"description"`
-	Amount       float64   `json:"amount"` // Full amount, not split portion
+	Amount       money.Amount   `json:"amount"` // Full amount (not split portion), in the household currency; negative for refunds
 	MovementDate time.Time `json:"movement_date"`
+	
+	// Charge as made, when it was in another currency
+	OriginalAmount   *money.Amount `json:"original_amount,omitempty"`
+	OriginalCurrency *string       `json:"original_currency,omitempty"`
+
 	CategoryName *string   `json:"category_name,omitempty"`
 	PayerName    string    `json:"payer_name"`
+
+	// Set when the charge is one installment of a purchase (Amount is then
+	// the installment, not the purchase)
+	Installment *Installment `json:"installment,omitempty"`
 }
 
 // CardPayment represents a payment made to a credit card
 type CardPayment struct {
 	ID                string    `json:"id"`
-	Amount            float64   `json:"amount"`
+	Amount            money.Amount   `json:"amount"`
 	PaymentDate       time.Time `json:"payment_date"`
-	SourceAccountName string    `json:"source_account_name"`
+	SourceAccountName string    `json:"source_account_name"` // Comma-separated when it drew from several accounts
 	Notes             *string   `json:"notes,omitempty"`
 }
 
@@ -84,13 +113,18 @@ type CardMovementsResponse struct {
 	BillingCycle BillingCycle `json:"billing_cycle"`
 	Charges      struct {
 		Movements []*CardMovement `json:"movements"`
-		Total     float64         `json:"total"`
+		Total     money.Amount         `json:"total"`
 	} `json:"charges"`
 	Payments struct {
 		Items []*CardPayment `json:"items"`
-		Total float64        `json:"total"`
+		Total money.Amount        `json:"total"`
 	} `json:"payments"`
-	NetDebt float64 `json:"net_debt"`
+	NetDebt money.Amount `json:"net_debt"`
+
+	// The statement the cycle closed into (nil while it is open). Charges,
+	// payments and totals of a closed cycle are the statement's, as billed at
+	// closing time; later edits to its movements show up in its reconciliation.
+	Statement *Statement `json:"statement,omitempty"`
 }
 
 // CardInfo represents basic credit card info
@@ -0,0 +1,263 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/blanquicet/conti/backend/internal/auth"
+)
+
+// Handler handles HTTP requests for events
+type Handler struct {
+	service    Service
+	authSvc    *auth.Service
+	cookieName string
+	logger     *slog.Logger
+}
+
+// NewHandler creates a new events handler
+func NewHandler(service Service, authService *auth.Service, cookieName string, logger *slog.Logger) *Handler {
+	return &Handler{
+		service:    service,
+		authSvc:    authService,
+		cookieName: cookieName,
+		logger:     logger,
+	}
+}
+
+// CreateRequest represents the request body for creating an event
+type CreateRequest struct {
+	Name         string             `json:"name"`
+	Description  *string            `json:"description,omitempty"`
+	StartDate    string             `json:"start_date"` // YYYY-MM-DD format
+	EndDate      string             `json:"end_date"`   // YYYY-MM-DD format
+	Participants []ParticipantInput `json:"participants,omitempty"`
+}
+
+// UpdateRequest represents the request body for updating an event
+type UpdateRequest struct {
+	Name         *string             `json:"name,omitempty"`
+	Description  *string             `json:"description,omitempty"`
+	StartDate    *string             `json:"start_date,omitempty"` // YYYY-MM-DD format
+	EndDate      *string             `json:"end_date,omitempty"`   // YYYY-MM-DD format
+	Participants *[]ParticipantInput `json:"participants,omitempty"`
+}
+
+// getUserFromSession extracts user from session cookie
+func (h *Handler) getUserFromSession(r *http.Request) (*auth.User, error) {
+	cookie, err := r.Cookie(h.cookieName)
+	if err != nil {
+		return nil, err
+	}
+	return h.authSvc.GetUserBySession(r.Context(), cookie.Value)
+}
+
+// HandleCreate handles POST /events
+func (h *Handler) HandleCreate(w http.ResponseWriter, r *http.Request) {
+	user, err := h.getUserFromSession(r)
+	if err != nil {
+		h.logger.Error("unauthorized", "error", err)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req CreateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	startDate, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		http.Error(w, "Invalid start_date format, use YYYY-MM-DD", http.StatusBadRequest)
+		return
+	}
+	endDate, err := time.Parse("2006-01-02", req.EndDate)
+	if err != nil {
+		http.Error(w, "Invalid end_date format, use YYYY-MM-DD", http.StatusBadRequest)
+		return
+	}
+
+	event, err := h.service.Create(r.Context(), user.ID, &CreateEventInput{
+		Name:         req.Name,
+		Description:  req.Description,
+		StartDate:    startDate,
+		EndDate:      endDate,
+		Participants: req.Participants,
+	})
+	if err != nil {
+		h.writeError(w, "failed to create event", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(event)
+}
+
+// HandleList handles GET /events?status=open|closed
+func (h *Handler) HandleList(w http.ResponseWriter, r *http.Request) {
+	user, err := h.getUserFromSession(r)
+	if err != nil {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var status *EventStatus
+	if s := r.URL.Query().Get("status"); s != "" {
+		st := EventStatus(s)
+		if st != StatusOpen && st != StatusClosed {
+			http.Error(w, "Invalid status, use open or closed", http.StatusBadRequest)
+			return
+		}
+		status = &st
+	}
+
+	events, err := h.service.List(r.Context(), user.ID, status)
+	if err != nil {
+		h.writeError(w, "failed to list events", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"events": events,
+	})
+}
+
+// HandleGet handles GET /events/{id}
+func (h *Handler) HandleGet(w http.ResponseWriter, r *http.Request) {
+	user, err := h.getUserFromSession(r)
+	if err != nil {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	event, err := h.service.GetByID(r.Context(), user.ID, r.PathValue("id"))
+	if err != nil {
+		h.writeError(w, "failed to get event", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(event)
+}
+
+// HandleUpdate handles PATCH /events/{id}
+func (h *Handler) HandleUpdate(w http.ResponseWriter, r *http.Request) {
+	user, err := h.getUserFromSession(r)
+	if err != nil {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req UpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	input := &UpdateEventInput{
+		Name:         req.Name,
+		Description:  req.Description,
+		Participants: req.Participants,
+	}
+	if req.StartDate != nil {
+		startDate, err := time.Parse("2006-01-02", *req.StartDate)
+		if err != nil {
+			http.Error(w, "Invalid start_date format, use YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		input.StartDate = &startDate
+	}
+	if req.EndDate != nil {
+		endDate, err := time.Parse("2006-01-02", *req.EndDate)
+		if err != nil {
+			http.Error(w, "Invalid end_date format, use YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		input.EndDate = &endDate
+	}
+
+	event, err := h.service.Update(r.Context(), user.ID, r.PathValue("id"), input)
+	if err != nil {
+		h.writeError(w, "failed to update event", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(event)
+}
+
+// HandleDelete handles DELETE /events/{id}
+func (h *Handler) HandleDelete(w http.ResponseWriter, r *http.Request) {
+	user, err := h.getUserFromSession(r)
+	if err != nil {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), user.ID, r.PathValue("id")); err != nil {
+		h.writeError(w, "failed to delete event", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleGetSummary handles GET /events/{id}/summary
+func (h *Handler) HandleGetSummary(w http.ResponseWriter, r *http.Request) {
+	user, err := h.getUserFromSession(r)
+	if err != nil {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	summary, err := h.service.GetSummary(r.Context(), user.ID, r.PathValue("id"))
+	if err != nil {
+		h.writeError(w, "failed to get event summary", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(summary)
+}
+
+// HandleClose handles POST /events/{id}/close
+func (h *Handler) HandleClose(w http.ResponseWriter, r *http.Request) {
+	user, err := h.getUserFromSession(r)
+	if err != nil {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	summary, err := h.service.Close(r.Context(), user.ID, r.PathValue("id"))
+	if err != nil {
+		h.writeError(w, "failed to close event", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(summary)
+}
+
+// writeError maps service errors to HTTP status codes
+func (h *Handler) writeError(w http.ResponseWriter, msg string, err error) {
+	switch {
+	case errors.Is(err, ErrEventNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, ErrNotAuthorized):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, ErrEventClosed):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, ErrNameRequired), errors.Is(err, ErrNameTooLong),
+		errors.Is(err, ErrInvalidDateRange), errors.Is(err, ErrInvalidParticipant):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		h.logger.Error(msg, "error", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	}
+}
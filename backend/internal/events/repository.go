@@ -0,0 +1,274 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// repository implements Repository using PostgreSQL
+type repository struct {
+	pool *pgxpool.Pool
+}
+
+// NewRepository creates a new events repository
+func NewRepository(pool *pgxpool.Pool) Repository {
+	return &repository{pool: pool}
+}
+
+// Create creates a new event with its participants
+func (r *repository) Create(ctx context.Context, householdID, createdBy string, input *CreateEventInput) (*Event, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var id string
+	err = tx.QueryRow(ctx, `
+		INSERT INTO events (household_id, name, description, start_date, end_date, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`, householdID, input.Name, input.Description, input.StartDate, input.EndDate, createdBy).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := insertParticipants(ctx, tx, id, input.Participants); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(ctx, id)
+}
+
+// insertParticipants inserts event participants within a transaction
+func insertParticipants(ctx context.Context, tx pgx.Tx, eventID string, participants []ParticipantInput) error {
+	for _, p := range participants {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO event_participants (event_id, participant_user_id, participant_contact_id)
+			VALUES ($1, $2, $3)
+			ON CONFLICT DO NOTHING
+		`, eventID, p.ParticipantUserID, p.ParticipantContactID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetByID retrieves an event by ID with its participants
+func (r *repository) GetByID(ctx context.Context, id string) (*Event, error) {
+	var e Event
+	err := r.pool.QueryRow(ctx, `
+		SELECT id, household_id, name, description, start_date, end_date,
+		       status, closed_at, created_by, created_at, updated_at
+		FROM events
+		WHERE id = $1
+	`, id).Scan(
+		&e.ID,
+		&e.HouseholdID,
+		&e.Name,
+		&e.Description,
+		&e.StartDate,
+		&e.EndDate,
+		&e.Status,
+		&e.ClosedAt,
+		&e.CreatedBy,
+		&e.CreatedAt,
+		&e.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrEventNotFound
+		}
+		return nil, err
+	}
+
+	participants, err := r.getParticipants(ctx, e.ID)
+	if err != nil {
+		return nil, err
+	}
+	e.Participants = participants
+
+	return &e, nil
+}
+
+// getParticipants retrieves participants for an event
+func (r *repository) getParticipants(ctx context.Context, eventID string) ([]Participant, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT ep.id, ep.event_id, ep.participant_user_id, ep.participant_contact_id,
+		       COALESCE(u.name, c.name) as participant_name
+		FROM event_participants ep
+		LEFT JOIN users u ON ep.participant_user_id = u.id
+		LEFT JOIN contacts c ON ep.participant_contact_id = c.id
+		WHERE ep.event_id = $1
+		ORDER BY ep.created_at ASC
+	`, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	participants := make([]Participant, 0)
+	for rows.Next() {
+		var p Participant
+		if err := rows.Scan(
+			&p.ID,
+			&p.EventID,
+			&p.ParticipantUserID,
+			&p.ParticipantContactID,
+			&p.ParticipantName,
+		); err != nil {
+			return nil, err
+		}
+		participants = append(participants, p)
+	}
+
+	return participants, rows.Err()
+}
+
+// ListByHousehold lists events for a household, most recent first
+func (r *repository) ListByHousehold(ctx context.Context, householdID string, status *EventStatus) ([]*Event, error) {
+	query := `
+		SELECT id FROM events
+		WHERE household_id = $1
+	`
+	args := []interface{}{householdID}
+	if status != nil {
+		query += " AND status = $2"
+		args = append(args, *status)
+	}
+	query += " ORDER BY start_date DESC, created_at DESC"
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	events := make([]*Event, 0, len(ids))
+	for _, id := range ids {
+		e, err := r.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+
+	return events, nil
+}
+
+// Update updates an event and optionally replaces its participants
+func (r *repository) Update(ctx context.Context, id string, input *UpdateEventInput) (*Event, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var setClauses []string
+	var args []interface{}
+	argNum := 1
+
+	if input.Name != nil {
+		setClauses = append(setClauses, fmt.Sprintf("name = $%d", argNum))
+		args = append(args, *input.Name)
+		argNum++
+	}
+	if input.Description != nil {
+		setClauses = append(setClauses, fmt.Sprintf("description = $%d", argNum))
+		args = append(args, *input.Description)
+		argNum++
+	}
+	if input.StartDate != nil {
+		setClauses = append(setClauses, fmt.Sprintf("start_date = $%d", argNum))
+		args = append(args, *input.StartDate)
+		argNum++
+	}
+	if input.EndDate != nil {
+		setClauses = append(setClauses, fmt.Sprintf("end_date = $%d", argNum))
+		args = append(args, *input.EndDate)
+		argNum++
+	}
+
+	if len(setClauses) > 0 {
+		setClauses = append(setClauses, "updated_at = NOW()")
+		args = append(args, id)
+
+		query := fmt.Sprintf(`
+			UPDATE events SET %s WHERE id = $%d RETURNING id
+		`, strings.Join(setClauses, ", "), argNum)
+
+		var updatedID string
+		if err := tx.QueryRow(ctx, query, args...).Scan(&updatedID); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, ErrEventNotFound
+			}
+			return nil, err
+		}
+	}
+
+	if input.Participants != nil {
+		if _, err := tx.Exec(ctx, "DELETE FROM event_participants WHERE event_id = $1", id); err != nil {
+			return nil, err
+		}
+		if err := insertParticipants(ctx, tx, id, *input.Participants); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(ctx, id)
+}
+
+// Close marks an event as closed
+func (r *repository) Close(ctx context.Context, id string) (*Event, error) {
+	result, err := r.pool.Exec(ctx, `
+		UPDATE events
+		SET status = 'closed', closed_at = NOW(), updated_at = NOW()
+		WHERE id = $1
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	if result.RowsAffected() == 0 {
+		return nil, ErrEventNotFound
+	}
+
+	return r.GetByID(ctx, id)
+}
+
+// Delete deletes an event (movements keep existing with event_id set to NULL)
+func (r *repository) Delete(ctx context.Context, id string) error {
+	result, err := r.pool.Exec(ctx, "DELETE FROM events WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrEventNotFound
+	}
+	return nil
+}
@@ -0,0 +1,369 @@
+package events
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+
+	"github.com/blanquicet/conti/backend/internal/audit"
+	"github.com/blanquicet/conti/backend/internal/households"
+	"github.com/blanquicet/conti/backend/internal/money"
+	"github.com/blanquicet/conti/backend/internal/movements"
+)
+
+// service implements Service interface
+type service struct {
+	repo             Repository
+	householdsRepo   households.HouseholdRepository
+	movementsService movements.Service
+	auditService     audit.Service
+	logger           *slog.Logger
+}
+
+// NewService creates a new events service
+func NewService(
+	repo Repository,
+	householdsRepo households.HouseholdRepository,
+	movementsService movements.Service,
+	auditService audit.Service,
+	logger *slog.Logger,
+) Service {
+	return &service{
+		repo:             repo,
+		householdsRepo:   householdsRepo,
+		movementsService: movementsService,
+		auditService:     auditService,
+		logger:           logger,
+	}
+}
+
+// Create creates a new event for the user's household
+func (s *service) Create(ctx context.Context, userID string, input *CreateEventInput) (*Event, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	householdID, err := s.householdsRepo.GetUserHouseholdID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.validateParticipants(ctx, householdID, input.Participants); err != nil {
+		return nil, err
+	}
+
+	event, err := s.repo.Create(ctx, householdID, userID, input)
+	if err != nil {
+		s.auditService.LogAsync(ctx, &audit.LogInput{
+			UserID:       audit.StringPtr(userID),
+			Action:       audit.ActionEventCreated,
+			ResourceType: "event",
+			HouseholdID:  audit.StringPtr(householdID),
+			Success:      false,
+			ErrorMessage: audit.StringPtr(err.Error()),
+		})
+		return nil, err
+	}
+
+	s.auditService.LogAsync(ctx, &audit.LogInput{
+		UserID:       audit.StringPtr(userID),
+		Action:       audit.ActionEventCreated,
+		ResourceType: "event",
+		ResourceID:   audit.StringPtr(event.ID),
+		HouseholdID:  audit.StringPtr(householdID),
+		NewValues:    audit.StructToMap(event),
+		Success:      true,
+	})
+
+	return event, nil
+}
+
+// GetByID retrieves an event, verifying it belongs to the user's household
+func (s *service) GetByID(ctx context.Context, userID, id string) (*Event, error) {
+	event, _, err := s.getAuthorized(ctx, userID, id)
+	return event, err
+}
+
+// List lists events for the user's household, optionally filtered by status
+func (s *service) List(ctx context.Context, userID string, status *EventStatus) ([]*Event, error) {
+	householdID, err := s.householdsRepo.GetUserHouseholdID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.ListByHousehold(ctx, householdID, status)
+}
+
+// Update updates an open event
+func (s *service) Update(ctx context.Context, userID, id string, input *UpdateEventInput) (*Event, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	existing, householdID, err := s.getAuthorized(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if existing.Status == StatusClosed {
+		return nil, ErrEventClosed
+	}
+
+	// Validate the final date range (input merged with existing)
+	startDate := existing.StartDate
+	if input.StartDate != nil {
+		startDate = *input.StartDate
+	}
+	endDate := existing.EndDate
+	if input.EndDate != nil {
+		endDate = *input.EndDate
+	}
+	if endDate.Before(startDate) {
+		return nil, ErrInvalidDateRange
+	}
+
+	if input.Participants != nil {
+		if err := s.validateParticipants(ctx, householdID, *input.Participants); err != nil {
+			return nil, err
+		}
+	}
+
+	updated, err := s.repo.Update(ctx, id, input)
+	if err != nil {
+		s.auditService.LogAsync(ctx, &audit.LogInput{
+			UserID:       audit.StringPtr(userID),
+			Action:       audit.ActionEventUpdated,
+			ResourceType: "event",
+			ResourceID:   audit.StringPtr(id),
+			HouseholdID:  audit.StringPtr(householdID),
+			OldValues:    audit.StructToMap(existing),
+			Success:      false,
+			ErrorMessage: audit.StringPtr(err.Error()),
+		})
+		return nil, err
+	}
+
+	s.auditService.LogAsync(ctx, &audit.LogInput{
+		UserID:       audit.StringPtr(userID),
+		Action:       audit.ActionEventUpdated,
+		ResourceType: "event",
+		ResourceID:   audit.StringPtr(id),
+		HouseholdID:  audit.StringPtr(householdID),
+		OldValues:    audit.StructToMap(existing),
+		NewValues:    audit.StructToMap(updated),
+		Success:      true,
+	})
+
+	return updated, nil
+}
+
+// Delete deletes an event. Its movements are kept and detached from the event.
+func (s *service) Delete(ctx context.Context, userID, id string) error {
+	existing, householdID, err := s.getAuthorized(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(ctx, id); err != nil {
+		s.auditService.LogAsync(ctx, &audit.LogInput{
+			UserID:       audit.StringPtr(userID),
+			Action:       audit.ActionEventDeleted,
+			ResourceType: "event",
+			ResourceID:   audit.StringPtr(id),
+			HouseholdID:  audit.StringPtr(householdID),
+			OldValues:    audit.StructToMap(existing),
+			Success:      false,
+			ErrorMessage: audit.StringPtr(err.Error()),
+		})
+		return err
+	}
+
+	s.auditService.LogAsync(ctx, &audit.LogInput{
+		UserID:       audit.StringPtr(userID),
+		Action:       audit.ActionEventDeleted,
+		ResourceType: "event",
+		ResourceID:   audit.StringPtr(id),
+		HouseholdID:  audit.StringPtr(householdID),
+		OldValues:    audit.StructToMap(existing),
+		Success:      true,
+	})
+
+	return nil
+}
+
+// GetSummary computes the consolidated summary of an event (open or closed)
+func (s *service) GetSummary(ctx context.Context, userID, id string) (*EventSummary, error) {
+	event, _, err := s.getAuthorized(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.summarize(ctx, userID, event)
+}
+
+// Close closes an event and returns its final summary.
+// Closed events no longer accept movements.
+func (s *service) Close(ctx context.Context, userID, id string) (*EventSummary, error) {
+	existing, householdID, err := s.getAuthorized(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if existing.Status == StatusClosed {
+		return nil, ErrEventClosed
+	}
+
+	closed, err := s.repo.Close(ctx, id)
+	if err != nil {
+		s.auditService.LogAsync(ctx, &audit.LogInput{
+			UserID:       audit.StringPtr(userID),
+			Action:       audit.ActionEventClosed,
+			ResourceType: "event",
+			ResourceID:   audit.StringPtr(id),
+			HouseholdID:  audit.StringPtr(householdID),
+			Success:      false,
+			ErrorMessage: audit.StringPtr(err.Error()),
+		})
+		return nil, err
+	}
+
+	summary, err := s.summarize(ctx, userID, closed)
+	if err != nil {
+		return nil, err
+	}
+
+	s.auditService.LogAsync(ctx, &audit.LogInput{
+		UserID:       audit.StringPtr(userID),
+		Action:       audit.ActionEventClosed,
+		ResourceType: "event",
+		ResourceID:   audit.StringPtr(id),
+		HouseholdID:  audit.StringPtr(householdID),
+		OldValues:    audit.StructToMap(existing),
+		NewValues: map[string]interface{}{
+			"status":         closed.Status,
+			"total_spent":    summary.TotalSpent,
+			"movement_count": summary.MovementCount,
+		},
+		Success: true,
+	})
+
+	return summary, nil
+}
+
+// summarize loads the event's movements and builds its summary
+func (s *service) summarize(ctx context.Context, userID string, event *Event) (*EventSummary, error) {
+	resp, err := s.movementsService.ListByHousehold(ctx, userID, &movements.ListMovementsFilters{
+		EventID: &event.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	household, err := s.householdsRepo.GetByID(ctx, event.HouseholdID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Linked contacts count as their user, as in the debt consolidation
+	contacts, err := s.householdsRepo.ListContacts(ctx, event.HouseholdID)
+	if err != nil {
+		return nil, err
+	}
+	return buildSummary(event, household.Currency, contacts, resp.Movements), nil
+}
+
+// buildSummary aggregates event movements into totals, per-payer amounts and
+// net balances, in the household currency. DEBT_PAYMENT movements settle debts
+// and don't count as spending. Contacts linked to a user are counted as that
+// user.
+func buildSummary(event *Event, currency string, contacts []*households.Contact, list []*movements.Movement) *EventSummary {
+	summary := &EventSummary{
+		Event:      event,
+		Currency:   currency,
+		ByPayer:    make([]PayerTotal, 0),
+		ByCategory: make(map[string]money.Amount),
+		Balances:   make([]movements.DebtBalance, 0),
+	}
+
+	ledger := movements.NewDebtLedger(currency)
+	ledger.LinkContacts(contacts)
+	payers := make(map[string]*PayerTotal)
+
+	for _, m := range list {
+		summary.MovementCount++
+		ledger.AddMovement(m)
+
+		if m.Type == movements.TypeDebtPayment || m.Type == movements.TypeTransfer {
+			continue
+		}
+
+		summary.TotalSpent += m.NetHouseholdAmount()
+
+		payerID := ledger.PersonID(m.PayerUserID, m.PayerContactID)
+		if payers[payerID] == nil {
+			payers[payerID] = &PayerTotal{ID: payerID, Name: m.PayerName}
+		}
+		payers[payerID].Amount += m.NetHouseholdAmount()
+		payers[payerID].Count++
+
+		category := "Sin categoría"
+		if m.CategoryName != nil {
+			category = *m.CategoryName
+		}
+		summary.ByCategory[category] += m.NetHouseholdAmount()
+	}
+
+	for _, p := range payers {
+		summary.ByPayer = append(summary.ByPayer, *p)
+	}
+	sort.Slice(summary.ByPayer, func(i, j int) bool {
+		return summary.ByPayer[i].Amount > summary.ByPayer[j].Amount
+	})
+
+	summary.Balances = append(summary.Balances, ledger.Balances()...)
+	sort.Slice(summary.Balances, func(i, j int) bool {
+		return summary.Balances[i].Amount > summary.Balances[j].Amount
+	})
+
+	return summary
+}
+
+// getAuthorized loads an event and verifies it belongs to the user's household
+func (s *service) getAuthorized(ctx context.Context, userID, id string) (*Event, string, error) {
+	event, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, "", err
+	}
+
+	householdID, err := s.householdsRepo.GetUserHouseholdID(ctx, userID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if event.HouseholdID != householdID {
+		return nil, "", ErrNotAuthorized
+	}
+
+	return event, householdID, nil
+}
+
+// validateParticipants verifies members belong to the household and contacts are its own
+func (s *service) validateParticipants(ctx context.Context, householdID string, participants []ParticipantInput) error {
+	for _, p := range participants {
+		if p.ParticipantUserID != nil && *p.ParticipantUserID != "" {
+			isMember, err := s.householdsRepo.IsUserMember(ctx, householdID, *p.ParticipantUserID)
+			if err != nil {
+				return err
+			}
+			if !isMember {
+				return ErrNotAuthorized
+			}
+			continue
+		}
+
+		contact, err := s.householdsRepo.GetContact(ctx, *p.ParticipantContactID)
+		if err != nil {
+			return err
+		}
+		if contact.HouseholdID != householdID {
+			return ErrNotAuthorized
+		}
+	}
+	return nil
+}
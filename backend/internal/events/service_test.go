@@ -0,0 +1,154 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blanquicet/conti/backend/internal/households"
+	"github.com/blanquicet/conti/backend/internal/money"
+	"github.com/blanquicet/conti/backend/internal/movements"
+)
+
+func strPtr(s string) *string { return &s }
+
+func TestBuildSummary(t *testing.T) {
+	date := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	event := &Event{ID: "event-1", Name: "Cartagena", Status: StatusOpen}
+
+	list := []*movements.Movement{
+		{
+			// Ana pays the hotel, split 50/50 with Luis (a contact)
+			ID:           "m1",
+			Type:         movements.TypeSplit,
+			Description:  "Hotel",
+			Amount:       money.New(400000),
+			MovementDate: date,
+			CategoryName: strPtr("Viajes"),
+			PayerUserID:  strPtr("ana"),
+			PayerName:    "Ana",
+			Participants: []movements.Participant{
+				{ParticipantUserID: strPtr("ana"), ParticipantName: "Ana", Percentage: 0.5},
+				{ParticipantContactID: strPtr("luis"), ParticipantName: "Luis", Percentage: 0.5},
+			},
+		},
+		{
+			// Luis pays dinner, split 50/50 with Ana
+			ID:             "m2",
+			Type:           movements.TypeSplit,
+			Description:    "Cena",
+			Amount:         money.New(100000),
+			MovementDate:   date,
+			CategoryName:   strPtr("Restaurantes"),
+			PayerContactID: strPtr("luis"),
+			PayerName:      "Luis",
+			Participants: []movements.Participant{
+				{ParticipantUserID: strPtr("ana"), ParticipantName: "Ana", Percentage: 0.5},
+				{ParticipantContactID: strPtr("luis"), ParticipantName: "Luis", Percentage: 0.5},
+			},
+		},
+		{
+			// Luis pays back part of his debt
+			ID:                 "m3",
+			Type:               movements.TypeDebtPayment,
+			Description:        "Abono",
+			Amount:             money.New(50000),
+			MovementDate:       date,
+			PayerContactID:     strPtr("luis"),
+			PayerName:          "Luis",
+			CounterpartyUserID: strPtr("ana"),
+			CounterpartyName:   strPtr("Ana"),
+		},
+	}
+
+	summary := buildSummary(event, "COP", nil, list)
+
+	if summary.MovementCount != 3 {
+		t.Errorf("MovementCount = %d, want 3", summary.MovementCount)
+	}
+	if summary.TotalSpent != money.New(500000) {
+		t.Errorf("TotalSpent = %v, want 500000 (debt payments are not spending)", summary.TotalSpent)
+	}
+
+	if len(summary.ByPayer) != 2 {
+		t.Fatalf("len(ByPayer) = %d, want 2", len(summary.ByPayer))
+	}
+	if summary.ByPayer[0].ID != "ana" || summary.ByPayer[0].Amount != money.New(400000) {
+		t.Errorf("ByPayer[0] = %+v, want ana paying 400000", summary.ByPayer[0])
+	}
+	if summary.ByPayer[1].ID != "luis" || summary.ByPayer[1].Amount != money.New(100000) {
+		t.Errorf("ByPayer[1] = %+v, want luis paying 100000", summary.ByPayer[1])
+	}
+
+	if summary.ByCategory["Viajes"] != money.New(400000) || summary.ByCategory["Restaurantes"] != money.New(100000) {
+		t.Errorf("ByCategory = %v", summary.ByCategory)
+	}
+
+	// Luis owes 200000 (hotel) - 50000 (Ana's dinner share) - 50000 (payment) = 100000
+	if len(summary.Balances) != 1 {
+		t.Fatalf("len(Balances) = %d, want 1", len(summary.Balances))
+	}
+	b := summary.Balances[0]
+	if b.DebtorID != "luis" || b.CreditorID != "ana" {
+		t.Errorf("balance direction = %s -> %s, want luis -> ana", b.DebtorID, b.CreditorID)
+	}
+	if b.Amount != money.New(100000) {
+		t.Errorf("balance amount = %v, want 100000", b.Amount)
+	}
+	if len(b.Movements) != 3 {
+		t.Errorf("balance movements = %d, want 3", len(b.Movements))
+	}
+}
+
+func TestBuildSummary_Empty(t *testing.T) {
+	summary := buildSummary(&Event{ID: "event-1"}, "COP", nil, nil)
+
+	if summary.TotalSpent != 0 || summary.MovementCount != 0 {
+		t.Errorf("expected empty totals, got %+v", summary)
+	}
+	if summary.ByPayer == nil || summary.Balances == nil {
+		t.Error("expected non-nil slices so JSON encodes [] instead of null")
+	}
+}
+
+func TestBuildSummary_LinkedContact(t *testing.T) {
+	date := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	// Luis is a contact linked to the user luis-user
+	contacts := []*households.Contact{{ID: "luis", Name: "Luis", LinkedUserID: strPtr("luis-user")}}
+
+	list := []*movements.Movement{
+		{
+			// Ana pays, split 50/50 with Luis recorded as a contact
+			ID:           "m1",
+			Type:         movements.TypeSplit,
+			Amount:       money.New(200000),
+			MovementDate: date,
+			PayerUserID:  strPtr("ana"),
+			PayerName:    "Ana",
+			Participants: []movements.Participant{
+				{ParticipantUserID: strPtr("ana"), ParticipantName: "Ana", Percentage: 0.5},
+				{ParticipantContactID: strPtr("luis"), ParticipantName: "Luis", Percentage: 0.5},
+			},
+		},
+		{
+			// Luis pays back, recorded with his user
+			ID:                 "m2",
+			Type:               movements.TypeDebtPayment,
+			Amount:             money.New(40000),
+			MovementDate:       date,
+			PayerUserID:        strPtr("luis-user"),
+			PayerName:          "Luis",
+			CounterpartyUserID: strPtr("ana"),
+			CounterpartyName:   strPtr("Ana"),
+		},
+	}
+
+	summary := buildSummary(&Event{ID: "event-1"}, "COP", contacts, list)
+
+	if len(summary.Balances) != 1 {
+		t.Fatalf("len(Balances) = %d, want 1 (Luis once)", len(summary.Balances))
+	}
+	b := summary.Balances[0]
+	if b.DebtorID != "luis-user" || b.CreditorID != "ana" || b.Amount != money.New(60000) {
+		t.Errorf("balance = %s -> %s %v, want luis-user -> ana 60000", b.DebtorID, b.CreditorID, b.Amount)
+	}
+}
@@ -0,0 +1,175 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/blanquicet/conti/backend/internal/money"
+	"github.com/blanquicet/conti/backend/internal/movements"
+)
+
+// Errors for event operations
+var (
+	ErrEventNotFound      = errors.New("event not found")
+	ErrNotAuthorized      = errors.New("not authorized")
+	ErrEventClosed        = errors.New("event is closed")
+	ErrNameRequired       = errors.New("event name is required")
+	ErrNameTooLong        = errors.New("event name must be 100 characters or less")
+	ErrInvalidDateRange   = errors.New("end_date must be on or after start_date")
+	ErrInvalidParticipant = errors.New("participant must have either user_id or contact_id")
+)
+
+// EventStatus represents the lifecycle state of an event
+type EventStatus string
+
+const (
+	StatusOpen   EventStatus = "open"   // Accepts new movements
+	StatusClosed EventStatus = "closed" // Frozen, summary is final
+)
+
+// Event represents a temporary shared context (trip, dinner, party)
+// that groups movements for a consolidated summary
+type Event struct {
+	ID           string        `json:"id"`
+	HouseholdID  string        `json:"household_id"`
+	Name         string        `json:"name"`
+	Description  *string       `json:"description,omitempty"`
+	StartDate    time.Time     `json:"start_date"`
+	EndDate      time.Time     `json:"end_date"`
+	Status       EventStatus   `json:"status"`
+	ClosedAt     *time.Time    `json:"closed_at,omitempty"`
+	CreatedBy    string        `json:"created_by"`
+	Participants []Participant `json:"participants"`
+	CreatedAt    time.Time     `json:"created_at"`
+	UpdatedAt    time.Time     `json:"updated_at"`
+}
+
+// Participant represents a household member or contact taking part in an event
+type Participant struct {
+	ID                   string  `json:"id"`
+	EventID              string  `json:"event_id"`
+	ParticipantUserID    *string `json:"participant_user_id,omitempty"`
+	ParticipantContactID *string `json:"participant_contact_id,omitempty"`
+	ParticipantName      string  `json:"participant_name"` // Populated from join
+}
+
+// ParticipantInput represents input for an event participant
+type ParticipantInput struct {
+	ParticipantUserID    *string `json:"participant_user_id,omitempty"`
+	ParticipantContactID *string `json:"participant_contact_id,omitempty"`
+}
+
+// Validate checks that exactly one participant identifier is set
+func (p *ParticipantInput) Validate() error {
+	hasUser := p.ParticipantUserID != nil && *p.ParticipantUserID != ""
+	hasContact := p.ParticipantContactID != nil && *p.ParticipantContactID != ""
+	if hasUser == hasContact {
+		return ErrInvalidParticipant
+	}
+	return nil
+}
+
+// CreateEventInput represents input for creating an event
+type CreateEventInput struct {
+	Name         string             `json:"name"`
+	Description  *string            `json:"description,omitempty"`
+	StartDate    time.Time          `json:"start_date"`
+	EndDate      time.Time          `json:"end_date"`
+	Participants []ParticipantInput `json:"participants,omitempty"`
+}
+
+// Validate validates the create event input
+func (i *CreateEventInput) Validate() error {
+	if i.Name == "" {
+		return ErrNameRequired
+	}
+	if len(i.Name) > 100 {
+		return ErrNameTooLong
+	}
+	if i.StartDate.IsZero() || i.EndDate.IsZero() {
+		return errors.New("start_date and end_date are required")
+	}
+	if i.EndDate.Before(i.StartDate) {
+		return ErrInvalidDateRange
+	}
+	for _, p := range i.Participants {
+		if err := p.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UpdateEventInput represents input for updating an event
+type UpdateEventInput struct {
+	Name         *string             `json:"name,omitempty"`
+	Description  *string             `json:"description,omitempty"`
+	StartDate    *time.Time          `json:"start_date,omitempty"`
+	EndDate      *time.Time          `json:"end_date,omitempty"`
+	Participants *[]ParticipantInput `json:"participants,omitempty"` // Replaces all participants when set
+}
+
+// Validate validates the update event input
+func (i *UpdateEventInput) Validate() error {
+	if i.Name != nil {
+		if *i.Name == "" {
+			return ErrNameRequired
+		}
+		if len(*i.Name) > 100 {
+			return ErrNameTooLong
+		}
+	}
+	if i.StartDate != nil && i.EndDate != nil && i.EndDate.Before(*i.StartDate) {
+		return ErrInvalidDateRange
+	}
+	if i.Participants != nil {
+		for _, p := range *i.Participants {
+			if err := p.Validate(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// PayerTotal represents how much one person paid for an event
+type PayerTotal struct {
+	ID     string       `json:"id"`
+	Name   string       `json:"name"`
+	Amount money.Amount `json:"amount"`
+	Count  int          `json:"count"`
+}
+
+// EventSummary is the consolidated view of an event: total spent,
+// who paid what, and the net balances between participants
+type EventSummary struct {
+	Event         *Event                  `json:"event"`
+	Currency      string                  `json:"currency"` // Household currency of every amount
+	TotalSpent    money.Amount            `json:"total_spent"`
+	MovementCount int                     `json:"movement_count"`
+	ByPayer       []PayerTotal            `json:"by_payer"`
+	ByCategory    map[string]money.Amount `json:"by_category"`
+	Balances      []movements.DebtBalance `json:"balances"`
+}
+
+// Repository defines the interface for event data access
+type Repository interface {
+	Create(ctx context.Context, householdID, createdBy string, input *CreateEventInput) (*Event, error)
+	GetByID(ctx context.Context, id string) (*Event, error)
+	ListByHousehold(ctx context.Context, householdID string, status *EventStatus) ([]*Event, error)
+	Update(ctx context.Context, id string, input *UpdateEventInput) (*Event, error)
+	Close(ctx context.Context, id string) (*Event, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// Service defines the interface for event business logic
+type Service interface {
+	Create(ctx context.Context, userID string, input *CreateEventInput) (*Event, error)
+	GetByID(ctx context.Context, userID, id string) (*Event, error)
+	List(ctx context.Context, userID string, status *EventStatus) ([]*Event, error)
+	Update(ctx context.Context, userID, id string, input *UpdateEventInput) (*Event, error)
+	Delete(ctx context.Context, userID, id string) error
+	GetSummary(ctx context.Context, userID, id string) (*EventSummary, error)
+	Close(ctx context.Context, userID, id string) (*EventSummary, error)
+}
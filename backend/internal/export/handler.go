@@ -0,0 +1,128 @@
+package export
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/blanquicet/conti/backend/internal/auth"
+	"github.com/blanquicet/conti/backend/internal/movements"
+)
+
+// Handler handles HTTP requests for exports
+type Handler struct {
+	service    Service
+	authSvc    *auth.Service
+	cookieName string
+	logger     *slog.Logger
+}
+
+// NewHandler creates a new export handler
+func NewHandler(service Service, authService *auth.Service, cookieName string, logger *slog.Logger) *Handler {
+	return &Handler{
+		service:    service,
+		authSvc:    authService,
+		cookieName: cookieName,
+		logger:     logger,
+	}
+}
+
+// getUserFromSession extracts user from session cookie
+func (h *Handler) getUserFromSession(r *http.Request) (*auth.User, error) {
+	cookie, err := r.Cookie(h.cookieName)
+	if err != nil {
+		return nil, err
+	}
+	return h.authSvc.GetUserBySession(r.Context(), cookie.Value)
+}
+
+// HandleExport handles GET /movements/export?format=csv|xlsx|json. It takes
+// the query parameters of GET /movements (limit and cursor are ignored) plus
+// include=movements,income,credit_card_payments (all by default).
+func (h *Handler) HandleExport(w http.ResponseWriter, r *http.Request) {
+	user, err := h.getUserFromSession(r)
+	if err != nil {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	opts, err := parseOptions(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	filename := fmt.Sprintf("conti-%s.%s", time.Now().Format("2006-01-02"), opts.Format)
+	w.Header().Set("Content-Type", opts.Format.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	w.Header().Set("Cache-Control", "no-store")
+
+	out := &startedWriter{w: w}
+	if err := h.service.Export(r.Context(), user.ID, opts, out); err != nil {
+		if out.started {
+			// The status is already sent; the client gets a truncated file
+			h.logger.Error("export failed while streaming", "error", err, "user_id", user.ID)
+			return
+		}
+		w.Header().Del("Content-Disposition")
+		w.Header().Del("Cache-Control")
+		switch {
+		case errors.Is(err, movements.ErrInvalidSort), errors.Is(err, movements.ErrInvalidLimit),
+			errors.Is(err, movements.ErrInvalidCursor), errors.Is(err, movements.ErrInvalidAmountRange):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			h.logger.Error("failed to export", "error", err, "user_id", user.ID)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+		}
+	}
+}
+
+// parseOptions reads the format, the record kinds and the movement filters
+func parseOptions(r *http.Request) (*Options, error) {
+	opts := &Options{Format: Format(r.URL.Query().Get("format"))}
+	if opts.Format == "" {
+		opts.Format = FormatCSV
+	}
+	switch opts.Format {
+	case FormatCSV, FormatXLSX, FormatJSON:
+	default:
+		return nil, ErrInvalidFormat
+	}
+
+	if include := r.URL.Query().Get("include"); include != "" {
+		for _, name := range strings.Split(include, ",") {
+			switch strings.TrimSpace(name) {
+			case "movements":
+				opts.Records = append(opts.Records, RecordMovement)
+			case "income":
+				opts.Records = append(opts.Records, RecordIncome)
+			case "credit_card_payments":
+				opts.Records = append(opts.Records, RecordCreditCardPayment)
+			default:
+				return nil, ErrInvalidInclude
+			}
+		}
+	}
+
+	filters, err := movements.ParseListFilters(r)
+	if err != nil {
+		return nil, err
+	}
+	opts.Filters = filters
+	return opts, nil
+}
+
+// startedWriter remembers whether the response body was started, after
+// which errors can no longer change the status
+type startedWriter struct {
+	w       http.ResponseWriter
+	started bool
+}
+
+func (s *startedWriter) Write(p []byte) (int, error) {
+	s.started = true
+	return s.w.Write(p)
+}
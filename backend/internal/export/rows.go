@@ -0,0 +1,83 @@
+package export
+
+import (
+	"github.com/blanquicet/conti/backend/internal/creditcardpayments"
+	"github.com/blanquicet/conti/backend/internal/income"
+	"github.com/blanquicet/conti/backend/internal/movements"
+)
+
+// movementRow converts a movement
+func movementRow(m *movements.Movement) *Row {
+	row := &Row{
+		Record:        RecordMovement,
+		ID:            m.ID,
+		Date:          formatDate(m.MovementDate),
+		Type:          string(m.Type),
+		Description:   m.Description,
+		Amount:        m.Amount,
+		Currency:      m.Currency,
+		BaseAmount:    m.NetHouseholdAmount(),
+		CategoryGroup: deref(m.CategoryGroupName),
+		Category:      deref(m.CategoryName),
+		Payer:         m.PayerName,
+		Counterparty:  deref(m.CounterpartyName),
+		PaymentMethod: deref(m.PaymentMethodName),
+		Account:       deref(m.ReceiverAccountName),
+		Event:         deref(m.EventName),
+		Tags:          m.Tags,
+	}
+	// Refunds are written as negative amounts, like their shares
+	if m.IsRefund() {
+		row.Amount = -m.Amount
+		row.Notes = "Reembolso"
+	}
+
+	shares := m.ParticipantShares()
+	for i, p := range m.Participants {
+		row.Participants = append(row.Participants, Share{
+			Name:       p.ParticipantName,
+			Percentage: p.Percentage,
+			Amount:     shares[i],
+		})
+	}
+	return row
+}
+
+// incomeRow converts an income entry (always in the household currency)
+func incomeRow(i *income.Income, currency string) *Row {
+	return &Row{
+		Record:      RecordIncome,
+		ID:          i.ID,
+		Date:        formatDate(i.IncomeDate),
+		Type:        string(i.Type),
+		Description: i.Description,
+		Amount:      i.Amount,
+		Currency:    currency,
+		BaseAmount:  i.Amount,
+		Payer:       i.MemberName,
+		Account:     i.AccountName,
+	}
+}
+
+// creditCardPaymentRow converts a credit card payment (always in the household currency)
+func creditCardPaymentRow(p *creditcardpayments.CreditCardPayment, currency string) *Row {
+	return &Row{
+		Record:        RecordCreditCardPayment,
+		ID:            p.ID,
+		Date:          formatDate(p.PaymentDate),
+		Description:   "Pago " + p.CreditCardName,
+		Amount:        p.Amount,
+		Currency:      currency,
+		BaseAmount:    p.Amount,
+		PaymentMethod: p.CreditCardName,
+		Account:       p.SourceAccountName,
+		Notes:         deref(p.Notes),
+	}
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
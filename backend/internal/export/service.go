@@ -0,0 +1,159 @@
+package export
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/blanquicet/conti/backend/internal/creditcardpayments"
+	"github.com/blanquicet/conti/backend/internal/households"
+	"github.com/blanquicet/conti/backend/internal/income"
+	"github.com/blanquicet/conti/backend/internal/movements"
+)
+
+// pageSize is how many movements are read at a time
+const pageSize = 500
+
+// service implements Service interface
+type service struct {
+	movementsRepo  movements.Repository
+	incomeRepo     income.Repository
+	paymentsRepo   creditcardpayments.Repository
+	householdsRepo households.HouseholdRepository
+	logger         *slog.Logger
+}
+
+// NewService creates a new export service
+func NewService(
+	movementsRepo movements.Repository,
+	incomeRepo income.Repository,
+	paymentsRepo creditcardpayments.Repository,
+	householdsRepo households.HouseholdRepository,
+	logger *slog.Logger,
+) Service {
+	return &service{
+		movementsRepo:  movementsRepo,
+		incomeRepo:     incomeRepo,
+		paymentsRepo:   paymentsRepo,
+		householdsRepo: householdsRepo,
+		logger:         logger,
+	}
+}
+
+// Export writes movements, then income, then credit card payments
+func (s *service) Export(ctx context.Context, userID string, opts *Options, w io.Writer) error {
+	if opts.Filters == nil {
+		opts.Filters = &movements.ListMovementsFilters{}
+	}
+	if err := opts.Filters.Validate(); err != nil {
+		return err
+	}
+
+	householdID, err := s.householdsRepo.GetUserHouseholdID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	household, err := s.householdsRepo.GetByID(ctx, householdID)
+	if err != nil {
+		return err
+	}
+	currency := household.Currency
+	if currency == "" {
+		currency = "COP"
+	}
+
+	out, err := NewWriter(opts.Format, w)
+	if err != nil {
+		return err
+	}
+
+	if opts.includes(RecordMovement) {
+		if err := s.writeMovements(ctx, householdID, opts.Filters, out); err != nil {
+			return err
+		}
+	}
+
+	start, end := dateRange(opts.Filters)
+	if opts.includes(RecordIncome) {
+		entries, err := s.incomeRepo.ListByHousehold(ctx, householdID, &income.ListIncomeFilters{
+			StartDate: start,
+			EndDate:   end,
+		})
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if err := out.Write(incomeRow(entry, currency)); err != nil {
+				return err
+			}
+		}
+	}
+	if opts.includes(RecordCreditCardPayment) {
+		payments, err := s.paymentsRepo.ListByHousehold(ctx, householdID, &creditcardpayments.ListFilter{
+			StartDate: start,
+			EndDate:   end,
+		})
+		if err != nil {
+			return err
+		}
+		for _, payment := range payments.Payments {
+			if err := out.Write(creditCardPaymentRow(payment, currency)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return out.Close()
+}
+
+// writeMovements pages through the filtered movements so a multi-year
+// history is never held in memory at once
+func (s *service) writeMovements(ctx context.Context, householdID string, filters *movements.ListMovementsFilters, out Writer) error {
+	page := *filters
+	page.Limit = pageSize
+	page.Cursor = nil
+	for {
+		list, err := s.movementsRepo.ListByHousehold(ctx, householdID, &page)
+		if err != nil {
+			return err
+		}
+		for _, m := range list {
+			if err := out.Write(movementRow(m)); err != nil {
+				return err
+			}
+		}
+		if len(list) < pageSize {
+			return nil
+		}
+
+		last := list[len(list)-1]
+		page.Cursor = &movements.Cursor{
+			Sort:         page.Sort,
+			Ascending:    page.Ascending,
+			MovementDate: last.MovementDate,
+			BaseAmount:   last.BaseAmount,
+			CreatedAt:    last.CreatedAt,
+			ID:           last.ID,
+		}
+	}
+}
+
+// dateRange returns the date range of the filters for income and credit card
+// payments; a month becomes its first and last day
+func dateRange(filters *movements.ListMovementsFilters) (*time.Time, *time.Time) {
+	start, end := filters.StartDate, filters.EndDate
+	if filters.Month != nil {
+		if month, err := time.Parse("2006-01", *filters.Month); err == nil {
+			first := month
+			last := month.AddDate(0, 1, -1)
+			if start == nil || first.After(*start) {
+				start = &first
+			}
+			if end == nil || last.Before(*end) {
+				end = &last
+			}
+		}
+	}
+	return start, end
+}
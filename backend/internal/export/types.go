@@ -0,0 +1,120 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/blanquicet/conti/backend/internal/money"
+	"github.com/blanquicet/conti/backend/internal/movements"
+)
+
+// Errors for exports
+var (
+	ErrInvalidFormat  = errors.New("invalid format, use csv, xlsx or json")
+	ErrInvalidInclude = errors.New("invalid include, use movements, income and/or credit_card_payments")
+)
+
+// Format is the file format of an export
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatXLSX Format = "xlsx"
+	FormatJSON Format = "json"
+)
+
+// ContentType returns the MIME type of the format
+func (f Format) ContentType() string {
+	switch f {
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case FormatJSON:
+		return "application/json"
+	default:
+		return "text/csv; charset=utf-8"
+	}
+}
+
+// Record is the kind of entry a row comes from
+type Record string
+
+const (
+	RecordMovement          Record = "MOVEMENT"
+	RecordIncome            Record = "INCOME"
+	RecordCreditCardPayment Record = "CREDIT_CARD_PAYMENT"
+)
+
+// Options selects what an export contains
+type Options struct {
+	Format Format
+	// Filters apply to movements. Their date range (month, start_date,
+	// end_date) also applies to income and credit card payments.
+	Filters *movements.ListMovementsFilters
+	Records []Record // Defaults to every record kind
+}
+
+// includes reports whether the export contains a record kind
+func (o *Options) includes(record Record) bool {
+	if len(o.Records) == 0 {
+		return true
+	}
+	for _, r := range o.Records {
+		if r == record {
+			return true
+		}
+	}
+	return false
+}
+
+// Row is one exported entry. Every record kind shares the same columns so the
+// whole history fits in one sheet.
+type Row struct {
+	Record        Record       `json:"record"`
+	ID            string       `json:"id"`
+	Date          string       `json:"date"` // YYYY-MM-DD
+	Type          string       `json:"type"`
+	Description   string       `json:"description"`
+	Amount        money.Amount `json:"amount"`
+	Currency      string       `json:"currency"`
+	BaseAmount    money.Amount `json:"base_amount"` // In the household currency
+	CategoryGroup string       `json:"category_group,omitempty"`
+	Category      string       `json:"category,omitempty"`
+	Payer         string       `json:"payer,omitempty"` // Member for income
+	Counterparty  string       `json:"counterparty,omitempty"`
+	PaymentMethod string       `json:"payment_method,omitempty"` // Card paid for credit card payments
+	Account       string       `json:"account,omitempty"`        // Receiving account, or source of a card payment
+	Participants  []Share      `json:"participants,omitempty"`
+	Event         string       `json:"event,omitempty"`
+	Tags          []string     `json:"tags,omitempty"`
+	Notes         string       `json:"notes,omitempty"`
+}
+
+// Share is what a participant owes of a SPLIT movement
+type Share struct {
+	Name       string       `json:"name"`
+	Percentage float64      `json:"percentage"`
+	Amount     money.Amount `json:"amount"` // In the movement currency
+}
+
+// Writer writes rows in an export format
+type Writer interface {
+	Write(row *Row) error
+	// Close finishes the file (it doesn't close the underlying writer)
+	Close() error
+}
+
+// Service defines the interface for exports
+type Service interface {
+	// Export writes the household's history to w as it is read, page by page
+	Export(ctx context.Context, userID string, opts *Options, w io.Writer) error
+}
+
+// dateFormat is how dates are written in CSV and JSON exports
+const dateFormat = "2006-01-02"
+
+// formatDate formats a date column
+func formatDate(t time.Time) string {
+	return t.Format(dateFormat)
+}
@@ -0,0 +1,260 @@
+package export
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// columns are the headers of CSV and XLSX exports, in order
+var columns = []string{
+	"record", "id", "date", "type", "description", "amount", "currency", "base_amount",
+	"category_group", "category", "payer", "counterparty", "payment_method", "account",
+	"participants", "event", "tags", "notes",
+}
+
+// NewWriter returns a writer for the format
+func NewWriter(format Format, w io.Writer) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w)
+	case FormatXLSX:
+		return newXLSXWriter(w)
+	case FormatJSON:
+		return newJSONWriter(w), nil
+	default:
+		return nil, ErrInvalidFormat
+	}
+}
+
+// cells returns the text of every column of a row
+func (r *Row) cells() []string {
+	participants := make([]string, len(r.Participants))
+	for i, p := range r.Participants {
+		participants[i] = p.Name + ": " + p.Amount.String()
+	}
+	return []string{
+		string(r.Record), r.ID, r.Date, r.Type, r.Description, r.Amount.String(), r.Currency, r.BaseAmount.String(),
+		r.CategoryGroup, r.Category, r.Payer, r.Counterparty, r.PaymentMethod, r.Account,
+		strings.Join(participants, "; "), r.Event, strings.Join(r.Tags, ", "), r.Notes,
+	}
+}
+
+// csvWriter writes a CSV file with a header row. A UTF-8 byte order mark is
+// written first so spreadsheet apps don't garble accents.
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func newCSVWriter(w io.Writer) (*csvWriter, error) {
+	if _, err := io.WriteString(w, "\ufeff"); err != nil {
+		return nil, err
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.Write(columns); err != nil {
+		return nil, err
+	}
+	return &csvWriter{w: cw}, nil
+}
+
+func (c *csvWriter) Write(row *Row) error {
+	return c.w.Write(row.cells())
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// jsonWriter writes {"rows": [...]} one row at a time
+type jsonWriter struct {
+	w     *bufio.Writer
+	count int
+}
+
+func newJSONWriter(w io.Writer) *jsonWriter {
+	return &jsonWriter{w: bufio.NewWriter(w)}
+}
+
+func (j *jsonWriter) Write(row *Row) error {
+	prefix := ",\n"
+	if j.count == 0 {
+		prefix = `{"rows":[` + "\n"
+	}
+	j.count++
+	data, err := json.Marshal(row)
+	if err != nil {
+		return err
+	}
+	if _, err := j.w.WriteString(prefix); err != nil {
+		return err
+	}
+	_, err = j.w.Write(data)
+	return err
+}
+
+func (j *jsonWriter) Close() error {
+	end := "\n]}\n"
+	if j.count == 0 {
+		end = `{"rows":[]}` + "\n"
+	}
+	if _, err := j.w.WriteString(end); err != nil {
+		return err
+	}
+	return j.w.Flush()
+}
+
+// xlsxWriter writes a single-sheet workbook. The sheet is streamed into the
+// zip as rows come, with inline strings (no shared strings table to keep in
+// memory), dates as date cells and amounts as number cells.
+type xlsxWriter struct {
+	zip   *zip.Writer
+	sheet *bufio.Writer
+	rows  int
+}
+
+// Cell styles, see xlsxStyles
+const (
+	xlsxStyleDate   = 1
+	xlsxStyleAmount = 2
+)
+
+// xlsxAmountColumns are the columns written as numbers
+var xlsxAmountColumns = map[int]bool{5: true, 7: true}
+
+// xlsxDateColumn is the column written as a date
+const xlsxDateColumn = 2
+
+func newXLSXWriter(w io.Writer) (*xlsxWriter, error) {
+	zw := zip.NewWriter(w)
+	for _, part := range []struct{ name, content string }{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRels},
+		{"xl/workbook.xml", xlsxWorkbook},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+		{"xl/styles.xml", xlsxStyles},
+	} {
+		f, err := zw.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(f, part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	f, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	x := &xlsxWriter{zip: zw, sheet: bufio.NewWriter(f)}
+	x.sheet.WriteString(xml.Header)
+	x.sheet.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	x.sheet.WriteString(`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>`)
+	x.sheet.WriteString(`<sheetData>`)
+	if err := x.writeRow(columns, nil); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+func (x *xlsxWriter) Write(row *Row) error {
+	return x.writeRow(row.cells(), row)
+}
+
+// writeRow writes the cells of a row; typed is nil for the header
+func (x *xlsxWriter) writeRow(cells []string, typed *Row) error {
+	x.rows++
+	fmt.Fprintf(x.sheet, `<row r="%d">`, x.rows)
+	for i, value := range cells {
+		if value == "" {
+			continue
+		}
+		ref := xlsxColumn(i) + strconv.Itoa(x.rows)
+		switch {
+		case typed != nil && xlsxAmountColumns[i]:
+			fmt.Fprintf(x.sheet, `<c r="%s" s="%d"><v>%s</v></c>`, ref, xlsxStyleAmount, value)
+		case typed != nil && i == xlsxDateColumn:
+			date, err := time.Parse(dateFormat, value)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(x.sheet, `<c r="%s" s="%d"><v>%d</v></c>`, ref, xlsxStyleDate, xlsxSerial(date))
+		default:
+			fmt.Fprintf(x.sheet, `<c r="%s" t="inlineStr"><is><t xml:space="preserve">`, ref)
+			if err := xml.EscapeText(x.sheet, []byte(value)); err != nil {
+				return err
+			}
+			x.sheet.WriteString(`</t></is></c>`)
+		}
+	}
+	_, err := x.sheet.WriteString(`</row>`)
+	return err
+}
+
+func (x *xlsxWriter) Close() error {
+	x.sheet.WriteString(`</sheetData></worksheet>`)
+	if err := x.sheet.Flush(); err != nil {
+		return err
+	}
+	return x.zip.Close()
+}
+
+// xlsxColumn returns the letters of a zero-based column index (0 is A, 26 is AA)
+func xlsxColumn(i int) string {
+	name := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+	return name
+}
+
+// xlsxSerial returns the spreadsheet serial number of a date (days since
+// 1899-12-30)
+func xlsxSerial(date time.Time) int {
+	epoch := time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	return int(day.Sub(epoch).Hours() / 24)
+}
+
+const xlsxContentTypes = xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+	`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+	`<Default Extension="xml" ContentType="application/xml"/>` +
+	`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+	`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+	`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+	`</Types>`
+
+const xlsxRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+const xlsxWorkbook = xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+	`<sheets><sheet name="Conti" sheetId="1" r:id="rId1"/></sheets>` +
+	`</workbook>`
+
+const xlsxWorkbookRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+	`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+	`</Relationships>`
+
+// xlsxStyles defines the cell formats: 0 default, 1 date (built-in format
+// 14), 2 amount with thousands separator (built-in format 4)
+const xlsxStyles = xml.Header + `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<fonts count="1"><font><sz val="11"/><name val="Calibri"/></font></fonts>` +
+	`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+	`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="3">` +
+	`<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="14" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`<xf numFmtId="4" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`</cellXfs>` +
+	`</styleSheet>`
@@ -0,0 +1,195 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blanquicet/conti/backend/internal/money"
+	"github.com/blanquicet/conti/backend/internal/movements"
+)
+
+func testRow() *Row {
+	return &Row{
+		Record:        RecordMovement,
+		ID:            "m1",
+		Date:          "2025-03-15",
+		Type:          "SPLIT",
+		Description:   "Mercado & café",
+		Amount:        money.New(120000),
+		Currency:      "COP",
+		BaseAmount:    money.New(120000),
+		CategoryGroup: "Casa",
+		Category:      "Mercado",
+		Payer:         "Ana",
+		PaymentMethod: "Débito",
+		Participants: []Share{
+			{Name: "Ana", Percentage: 0.5, Amount: money.New(60000)},
+			{Name: "Luis", Percentage: 0.5, Amount: money.New(60000)},
+		},
+		Tags: []string{"viaje"},
+	}
+}
+
+func TestCSVWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(FormatCSV, &buf)
+	if err != nil {
+		t.Fatalf("NewWriter() error = %v", err)
+	}
+	if err := w.Write(testRow()); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if !strings.HasPrefix(buf.String(), "\ufeff") {
+		t.Error("CSV should start with a byte order mark")
+	}
+	records, err := csv.NewReader(strings.NewReader(strings.TrimPrefix(buf.String(), "\ufeff"))).ReadAll()
+	if err != nil {
+		t.Fatalf("reading CSV: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want header and one row", len(records))
+	}
+	if records[0][0] != "record" || len(records[0]) != len(columns) {
+		t.Errorf("header = %v", records[0])
+	}
+	if got := records[1][14]; got != "Ana: 60000; Luis: 60000" {
+		t.Errorf("participants = %q", got)
+	}
+	if got := records[1][4]; got != "Mercado & café" {
+		t.Errorf("description = %q", got)
+	}
+}
+
+func TestJSONWriter(t *testing.T) {
+	tests := []struct {
+		name string
+		rows int
+	}{
+		{"empty", 0},
+		{"one row", 1},
+		{"several rows", 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w, _ := NewWriter(FormatJSON, &buf)
+			for i := 0; i < tt.rows; i++ {
+				if err := w.Write(testRow()); err != nil {
+					t.Fatalf("Write() error = %v", err)
+				}
+			}
+			if err := w.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+
+			var doc struct {
+				Rows []Row `json:"rows"`
+			}
+			if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+				t.Fatalf("invalid JSON %q: %v", buf.String(), err)
+			}
+			if len(doc.Rows) != tt.rows {
+				t.Errorf("got %d rows, want %d", len(doc.Rows), tt.rows)
+			}
+		})
+	}
+}
+
+func TestXLSXWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(FormatXLSX, &buf)
+	if err != nil {
+		t.Fatalf("NewWriter() error = %v", err)
+	}
+	if err := w.Write(testRow()); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("not a zip: %v", err)
+	}
+	parts := map[string]string{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("opening %s: %v", f.Name, err)
+		}
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		parts[f.Name] = string(data)
+	}
+	for _, name := range []string{"[Content_Types].xml", "xl/workbook.xml", "xl/styles.xml", "xl/worksheets/sheet1.xml"} {
+		if _, ok := parts[name]; !ok {
+			t.Errorf("missing part %s", name)
+		}
+	}
+
+	sheet := parts["xl/worksheets/sheet1.xml"]
+	for _, want := range []string{
+		`<c r="C2" s="1"><v>45731</v></c>`,  // 2025-03-15 as a date serial
+		`<c r="F2" s="2"><v>120000</v></c>`, // Amount as a number
+		`Mercado &amp; café`,                // Escaped text
+		`<c r="A1" t="inlineStr"><is><t xml:space="preserve">record</t></is></c>`,
+	} {
+		if !strings.Contains(sheet, want) {
+			t.Errorf("sheet should contain %s", want)
+		}
+	}
+}
+
+func TestXLSXColumn(t *testing.T) {
+	tests := map[int]string{0: "A", 17: "R", 25: "Z", 26: "AA", 27: "AB", 701: "ZZ", 702: "AAA"}
+	for i, want := range tests {
+		if got := xlsxColumn(i); got != want {
+			t.Errorf("xlsxColumn(%d) = %q, want %q", i, got, want)
+		}
+	}
+}
+
+func TestDateRange(t *testing.T) {
+	date := func(s string) *time.Time {
+		d, _ := time.Parse("2006-01-02", s)
+		return &d
+	}
+	month := "2025-02"
+
+	tests := []struct {
+		name      string
+		filters   movements.ListMovementsFilters
+		wantStart string
+		wantEnd   string
+	}{
+		{"none", movements.ListMovementsFilters{}, "", ""},
+		{"month", movements.ListMovementsFilters{Month: &month}, "2025-02-01", "2025-02-28"},
+		{"range", movements.ListMovementsFilters{StartDate: date("2025-01-10"), EndDate: date("2025-03-05")}, "2025-01-10", "2025-03-05"},
+		{"month narrows range", movements.ListMovementsFilters{Month: &month, StartDate: date("2025-02-10")}, "2025-02-10", "2025-02-28"},
+	}
+	format := func(t *time.Time) string {
+		if t == nil {
+			return ""
+		}
+		return t.Format("2006-01-02")
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := dateRange(&tt.filters)
+			if format(start) != tt.wantStart || format(end) != tt.wantEnd {
+				t.Errorf("dateRange() = %s..%s, want %s..%s", format(start), format(end), tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package forecast
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/blanquicet/conti/backend/internal/auth"
+)
+
+// Handler handles HTTP requests for cash-flow forecasts
+type Handler struct {
+	service    Service
+	authSvc    *auth.Service
+	cookieName string
+	logger     *slog.Logger
+}
+
+// NewHandler creates a new forecast handler
+func NewHandler(service Service, authService *auth.Service, cookieName string, logger *slog.Logger) *Handler {
+	return &Handler{
+		service:    service,
+		authSvc:    authService,
+		cookieName: cookieName,
+		logger:     logger,
+	}
+}
+
+// getUserFromSession extracts user from session cookie
+func (h *Handler) getUserFromSession(r *http.Request) (*auth.User, error) {
+	cookie, err := r.Cookie(h.cookieName)
+	if err != nil {
+		return nil, err
+	}
+	return h.authSvc.GetUserBySession(r.Context(), cookie.Value)
+}
+
+// HandleGetForecast handles GET /forecast?days=90: the expected balance of
+// every account and of the household over the next days (90 by default),
+// with the first date any of them goes negative
+func (h *Handler) HandleGetForecast(w http.ResponseWriter, r *http.Request) {
+	user, err := h.getUserFromSession(r)
+	if err != nil {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	days := DefaultDays
+	if daysStr := r.URL.Query().Get("days"); daysStr != "" {
+		days, err = strconv.Atoi(daysStr)
+		if err != nil {
+			http.Error(w, ErrInvalidDays.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	forecast, err := h.service.GetForecast(r.Context(), user.ID, days)
+	if err != nil {
+		h.writeError(w, "failed to get forecast", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(forecast)
+}
+
+// writeError maps service errors to HTTP responses
+func (h *Handler) writeError(w http.ResponseWriter, msg string, err error) {
+	switch {
+	case errors.Is(err, ErrInvalidDays):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		h.logger.Error(msg, "error", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,340 @@
+package forecast
+
+import (
+	"sort"
+	"time"
+
+	"github.com/blanquicet/conti/backend/internal/creditcardpayments"
+	"github.com/blanquicet/conti/backend/internal/creditcards"
+	"github.com/blanquicet/conti/backend/internal/income"
+	"github.com/blanquicet/conti/backend/internal/money"
+	"github.com/blanquicet/conti/backend/internal/movements"
+	"github.com/blanquicet/conti/backend/internal/paymentmethods"
+	"github.com/blanquicet/conti/backend/internal/recurringmovements"
+)
+
+// cardCharge is a recurring movement expected on a credit card, paid with
+// the statement of its cycle
+type cardCharge struct {
+	CardID string
+	Date   time.Time
+	Amount money.Amount
+}
+
+// salaryEvents expects every salary paid in at least two of the last months
+// again each month, on the day and for the amount of the latest one
+func salaryEvents(entries []*income.Income, today, until time.Time) []*Event {
+	since := time.Date(today.Year(), today.Month()-(salaryMonths-1), 1, 0, 0, 0, 0, time.UTC)
+
+	type pattern struct {
+		latest *income.Income
+		months map[time.Month]bool
+	}
+	patterns := make(map[string]*pattern)
+	var keys []string
+	for _, entry := range entries {
+		if entry.Type != income.TypeSalary || entry.DeletedAt != nil || entry.IncomeDate.Before(since) {
+			continue
+		}
+		key := entry.MemberID + "/" + entry.AccountID
+		p, ok := patterns[key]
+		if !ok {
+			p = &pattern{months: make(map[time.Month]bool)}
+			patterns[key] = p
+			keys = append(keys, key)
+		}
+		p.months[entry.IncomeDate.Month()] = true
+		if p.latest == nil || entry.IncomeDate.After(p.latest.IncomeDate) {
+			p.latest = entry
+		}
+	}
+
+	var events []*Event
+	for _, key := range keys {
+		p := patterns[key]
+		if len(p.months) < 2 {
+			continue
+		}
+		latest := p.latest
+		for i := 1; ; i++ {
+			date := dayOfMonth(latest.IncomeDate.Year(), latest.IncomeDate.Month()+time.Month(i), latest.IncomeDate.Day())
+			if date.After(until) {
+				break
+			}
+			if !date.After(today) {
+				continue
+			}
+			events = append(events, &Event{
+				Date:        date,
+				Kind:        EventSalary,
+				SourceID:    latest.ID,
+				Description: latest.Description,
+				AccountID:   &latest.AccountID,
+				AccountName: &latest.AccountName,
+				Amount:      latest.Amount,
+			})
+		}
+	}
+	return events
+}
+
+// templateEvents expects the scheduled occurrences of the recurring movement
+// templates: paid from the account linked to their payment method (or charged
+// on a credit card), and received in their receiver account
+func templateEvents(templates []*recurringmovements.RecurringMovementTemplate, methods map[string]*paymentmethods.PaymentMethod, accountNames map[string]string, today, until time.Time) ([]*Event, []*cardCharge) {
+	var events []*Event
+	var charges []*cardCharge
+	for _, t := range templates {
+		if t.MovementType == nil {
+			continue // Budget display only
+		}
+		for _, date := range t.Occurrences(until) {
+			date = dateOf(date)
+			if !date.After(today) {
+				continue
+			}
+
+			// A contact paying doesn't take money out of the household
+			if t.PayerContactID == nil {
+				var method *paymentmethods.PaymentMethod
+				if t.PaymentMethodID != nil {
+					method = methods[*t.PaymentMethodID]
+				}
+				if method != nil && method.Type == paymentmethods.TypeCreditCard {
+					charges = append(charges, &cardCharge{CardID: method.ID, Date: date, Amount: t.Amount})
+				} else {
+					var accountID *string
+					if method != nil {
+						accountID = method.LinkedAccountID
+					}
+					events = append(events, templateEvent(t, date, accountID, accountNames, -t.Amount))
+				}
+			}
+
+			if *t.MovementType == movements.TypeDebtPayment && t.ReceiverAccountID != nil {
+				events = append(events, templateEvent(t, date, t.ReceiverAccountID, accountNames, t.Amount))
+			}
+		}
+	}
+	return events, charges
+}
+
+func templateEvent(t *recurringmovements.RecurringMovementTemplate, date time.Time, accountID *string, accountNames map[string]string, amount money.Amount) *Event {
+	return &Event{
+		Date:        date,
+		Kind:        EventRecurringMovement,
+		SourceID:    t.ID,
+		Description: t.Name,
+		AccountID:   accountID,
+		AccountName: accountName(accountID, accountNames),
+		Amount:      amount,
+	}
+}
+
+// cardEvents expects each card statement to be paid on its due date (the end
+// of the cycle when the card has no due day), from the account that paid the
+// card last: what the last closed statement still owes, the current cycle's
+// charges, the installments billed in later cycles and the recurring
+// movements charged on the card. Statements already due are expected
+// tomorrow.
+func cardEvents(cards []*creditcards.CardSummary, installments map[string][]*creditcards.InstallmentPlan, charges []*cardCharge, methods map[string]*paymentmethods.PaymentMethod, payers map[string]*creditcardpayments.PaymentSource, today, until time.Time) []*Event {
+	type statement struct {
+		cardID string
+		due    time.Time
+	}
+	owed := make(map[statement]money.Amount)
+	var order []statement
+	add := func(cardID string, due time.Time, amount money.Amount) {
+		due = dateOf(due)
+		if !due.After(today) {
+			due = today.AddDate(0, 0, 1)
+		}
+		key := statement{cardID, due}
+		if _, ok := owed[key]; !ok {
+			order = append(order, key)
+		}
+		owed[key] += amount
+	}
+
+	names := make(map[string]string)
+	for _, card := range cards {
+		names[card.ID] = card.Name
+
+		// The closed statement, less what was paid since it closed
+		if card.StatementBalance > 0 {
+			due := card.PaymentDueDate
+			if due == nil {
+				closed := creditcards.CalculateBillingCycle(card.BillingCycle.StartDate.AddDate(0, 0, -1), card.CutoffDay)
+				due = &closed.EndDate
+			}
+			add(card.ID, *due, card.StatementBalance)
+		}
+
+		// The current cycle, with its installments (payments made during it
+		// go to the closed statement)
+		if card.TotalCharges > 0 {
+			add(card.ID, dueDate(card.BillingCycle, card.PaymentDueDay), card.TotalCharges)
+		}
+
+		for _, plan := range installments[card.ID] {
+			for _, installment := range plan.Remaining {
+				if installment.BillingCycle.StartDate.After(card.BillingCycle.StartDate) {
+					add(card.ID, dueDate(installment.BillingCycle, card.PaymentDueDay), installment.Amount)
+				}
+			}
+		}
+	}
+	for _, charge := range charges {
+		method := methods[charge.CardID]
+		names[method.ID] = method.Name
+		add(method.ID, dueDate(creditcards.CalculateBillingCycle(charge.Date, method.CutoffDay), method.PaymentDueDay), charge.Amount)
+	}
+
+	var events []*Event
+	for _, key := range order {
+		if key.due.After(until) || owed[key] <= 0 {
+			continue
+		}
+		event := &Event{
+			Date:        key.due,
+			Kind:        EventCardPayment,
+			SourceID:    key.cardID,
+			Description: names[key.cardID],
+			Amount:      -owed[key],
+		}
+		if payer := payers[key.cardID]; payer != nil {
+			event.AccountID = &payer.AccountID
+			event.AccountName = &payer.AccountName
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+// cardPayers returns, for each card, the account that paid most of its
+// latest payment
+func cardPayers(payments []*creditcardpayments.CreditCardPayment) map[string]*creditcardpayments.PaymentSource {
+	latest := make(map[string]*creditcardpayments.CreditCardPayment)
+	for _, payment := range payments {
+		if l, ok := latest[payment.CreditCardID]; !ok || payment.PaymentDate.After(l.PaymentDate) {
+			latest[payment.CreditCardID] = payment
+		}
+	}
+
+	payers := make(map[string]*creditcardpayments.PaymentSource)
+	for cardID, payment := range latest {
+		for _, source := range payment.Sources {
+			if payers[cardID] == nil || source.Amount > payers[cardID].Amount {
+				payers[cardID] = source
+			}
+		}
+	}
+	return payers
+}
+
+// project replays the events day by day from the current account balances.
+// Events without a known account only change the household total.
+func project(today time.Time, days int, accounts []*AccountForecast, events []*Event) *Forecast {
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Date.Before(events[j].Date)
+	})
+
+	forecast := &Forecast{
+		From:     today,
+		To:       today.AddDate(0, 0, days),
+		Accounts: accounts,
+		Events:   events,
+		Days:     make([]*DayBalance, 0, days),
+	}
+
+	byID := make(map[string]*AccountForecast, len(accounts))
+	balances := make(map[string]money.Amount, len(accounts))
+	for _, account := range accounts {
+		byID[account.AccountID] = account
+		balances[account.AccountID] = account.StartBalance
+		account.LowestBalance = account.StartBalance
+		account.LowestDate = today
+		forecast.StartBalance += account.StartBalance
+	}
+	total := forecast.StartBalance
+	forecast.LowestBalance = total
+	forecast.LowestDate = today
+
+	check := func(date time.Time) {
+		for _, account := range accounts {
+			balance := balances[account.AccountID]
+			if balance < account.LowestBalance {
+				account.LowestBalance = balance
+				account.LowestDate = date
+			}
+			if balance < 0 && account.FirstNegativeDate == nil {
+				account.FirstNegativeDate = &date
+			}
+		}
+		if total < forecast.LowestBalance {
+			forecast.LowestBalance = total
+			forecast.LowestDate = date
+		}
+		if total < 0 && forecast.FirstNegativeDate == nil {
+			forecast.FirstNegativeDate = &date
+		}
+	}
+	check(today)
+
+	next := 0
+	for date := today.AddDate(0, 0, 1); !date.After(forecast.To); date = date.AddDate(0, 0, 1) {
+		for next < len(events) && !events[next].Date.After(date) {
+			event := events[next]
+			if event.AccountID != nil && byID[*event.AccountID] != nil {
+				balances[*event.AccountID] += event.Amount
+			}
+			total += event.Amount
+			next++
+		}
+		check(date)
+		forecast.Days = append(forecast.Days, &DayBalance{Date: date, Balance: total})
+	}
+
+	forecast.EndBalance = total
+	forecast.Covered = forecast.FirstNegativeDate == nil
+	for _, account := range accounts {
+		account.EndBalance = balances[account.AccountID]
+		if account.FirstNegativeDate != nil {
+			forecast.Covered = false
+		}
+	}
+	return forecast
+}
+
+// dueDate returns when the statement of a cycle is paid
+func dueDate(cycle creditcards.BillingCycle, dueDay *int) time.Time {
+	if dueDay == nil {
+		return cycle.EndDate
+	}
+	return creditcards.PaymentDueDate(cycle, *dueDay)
+}
+
+func accountName(accountID *string, names map[string]string) *string {
+	if accountID == nil {
+		return nil
+	}
+	if name, ok := names[*accountID]; ok {
+		return &name
+	}
+	return nil
+}
+
+// dayOfMonth returns the day of a month (month may run past December),
+// clamped to the month's last day
+func dayOfMonth(year int, month time.Month, day int) time.Time {
+	last := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC)
+	if day > last.Day() {
+		return last
+	}
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// dateOf returns the date of t at midnight UTC
+func dateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
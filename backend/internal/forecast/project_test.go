@@ -0,0 +1,216 @@
+package forecast
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blanquicet/conti/backend/internal/creditcardpayments"
+	"github.com/blanquicet/conti/backend/internal/creditcards"
+	"github.com/blanquicet/conti/backend/internal/income"
+	"github.com/blanquicet/conti/backend/internal/money"
+	"github.com/blanquicet/conti/backend/internal/movements"
+	"github.com/blanquicet/conti/backend/internal/paymentmethods"
+	"github.com/blanquicet/conti/backend/internal/recurringmovements"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func strPtr(s string) *string { return &s }
+
+func intPtr(i int) *int { return &i }
+
+func TestSalaryEvents(t *testing.T) {
+	today := date(2026, time.April, 10)
+	salary := func(id, member string, day time.Time, amount int64) *income.Income {
+		return &income.Income{ID: id, MemberID: member, AccountID: "acc-" + member, AccountName: "Nómina", Type: income.TypeSalary, IncomeDate: day, Amount: money.New(amount)}
+	}
+	entries := []*income.Income{
+		salary("s1", "ana", date(2026, time.February, 28), 1000),
+		salary("s2", "ana", date(2026, time.March, 31), 1200),
+		// Paid only once: not a pattern
+		salary("s3", "luis", date(2026, time.March, 15), 900),
+		// Not a salary
+		{ID: "b1", MemberID: "ana", AccountID: "acc-ana", Type: income.TypeBonus, IncomeDate: date(2026, time.January, 20), Amount: money.New(500)},
+	}
+
+	events := salaryEvents(entries, today, date(2026, time.June, 30))
+
+	want := []time.Time{date(2026, time.April, 30), date(2026, time.May, 31), date(2026, time.June, 30)}
+	if len(events) != len(want) {
+		t.Fatalf("salaryEvents() = %d events, want %d", len(events), len(want))
+	}
+	for i, event := range events {
+		if !event.Date.Equal(want[i]) || event.Amount != money.New(1200) || *event.AccountID != "acc-ana" || event.SourceID != "s2" {
+			t.Errorf("event %d = %s %v %s, want %s 1200 from s2", i, event.Date.Format("2006-01-02"), event.Amount, *event.AccountID, want[i].Format("2006-01-02"))
+		}
+	}
+}
+
+func TestTemplateAndCardEvents(t *testing.T) {
+	today := date(2026, time.April, 10)
+	until := date(2026, time.May, 31)
+	monthly := recurringmovements.RecurrenceMonthly
+	household := movements.TypeHousehold
+	debtPayment := movements.TypeDebtPayment
+	next := func(d time.Time) *time.Time { return &d }
+
+	methods := map[string]*paymentmethods.PaymentMethod{
+		"debit": {ID: "debit", Name: "Débito", Type: paymentmethods.TypeDebitCard, LinkedAccountID: strPtr("savings")},
+		"visa":  {ID: "visa", Name: "Visa", Type: paymentmethods.TypeCreditCard, CutoffDay: intPtr(15), PaymentDueDay: intPtr(5)},
+	}
+	templates := []*recurringmovements.RecurringMovementTemplate{
+		{ID: "rent", Name: "Arriendo", IsActive: true, MovementType: &household, Amount: money.New(800),
+			PaymentMethodID: strPtr("debit"), RecurrencePattern: &monthly, DayOfMonth: intPtr(1), NextScheduledDate: next(date(2026, time.May, 1))},
+		{ID: "netflix", Name: "Netflix", IsActive: true, MovementType: &household, Amount: money.New(50),
+			PaymentMethodID: strPtr("visa"), RecurrencePattern: &monthly, DayOfMonth: intPtr(20), NextScheduledDate: next(date(2026, time.April, 20))},
+		{ID: "loan", Name: "Préstamo", IsActive: true, MovementType: &debtPayment, Amount: money.New(100),
+			PayerContactID: strPtr("pedro"), ReceiverAccountID: strPtr("savings"), RecurrencePattern: &monthly, DayOfMonth: intPtr(25), NextScheduledDate: next(date(2026, time.April, 25))},
+		// Budget display only
+		{ID: "food", Name: "Mercado", IsActive: true, Amount: money.New(300), RecurrencePattern: &monthly, NextScheduledDate: next(date(2026, time.April, 15))},
+	}
+
+	events, charges := templateEvents(templates, methods, map[string]string{"savings": "Ahorros"}, today, until)
+
+	if len(events) != 3 {
+		t.Fatalf("templateEvents() = %d events, want rent on May 1 and the loan twice", len(events))
+	}
+	if events[0].SourceID != "rent" || events[0].Amount != money.New(-800) || *events[0].AccountID != "savings" || *events[0].AccountName != "Ahorros" {
+		t.Errorf("rent event = %+v", events[0])
+	}
+	if events[1].SourceID != "loan" || events[1].Amount != money.New(100) {
+		t.Errorf("loan event = %+v, want 100 received", events[1])
+	}
+	if len(charges) != 2 {
+		t.Fatalf("templateEvents() = %d card charges, want Netflix twice", len(charges))
+	}
+
+	cards := []*creditcards.CardSummary{{
+		ID:            "visa",
+		Name:          "Visa",
+		BillingCycle:  creditcards.CalculateBillingCycle(today, intPtr(15)),
+		TotalCharges:  money.New(400),
+		PaymentDueDay: intPtr(5),
+	}}
+	payers := cardPayers([]*creditcardpayments.CreditCardPayment{
+		{CreditCardID: "visa", PaymentDate: date(2026, time.February, 5), Sources: []*creditcardpayments.PaymentSource{{AccountID: "old", Amount: money.New(1)}}},
+		{CreditCardID: "visa", PaymentDate: date(2026, time.March, 5), Sources: []*creditcardpayments.PaymentSource{
+			{AccountID: "cash", Amount: money.New(10)},
+			{AccountID: "savings", AccountName: "Ahorros", Amount: money.New(300)},
+		}},
+	})
+
+	cardList := cardEvents(cards, nil, charges, methods, payers, today, until)
+
+	// Current cycle (Mar 16 - Apr 15): 400, due May 5. Netflix of Apr 20 is in
+	// the next cycle, due Jun 5 (after until); May 20 is due Jul 5.
+	if len(cardList) != 1 {
+		t.Fatalf("cardEvents() = %d events, want 1", len(cardList))
+	}
+	if !cardList[0].Date.Equal(date(2026, time.May, 5)) || cardList[0].Amount != money.New(-400) || *cardList[0].AccountID != "savings" {
+		t.Errorf("card event = %s %v %v, want 2026-05-05 -400 from savings", cardList[0].Date.Format("2006-01-02"), cardList[0].Amount, cardList[0].AccountID)
+	}
+}
+
+func TestCardEvents_ClosedStatementAndInstallments(t *testing.T) {
+	today := date(2026, time.October, 20)
+	until := date(2026, time.December, 31)
+	cutoff, dueDay := intPtr(15), intPtr(25)
+	open := creditcards.CalculateBillingCycle(today, cutoff)
+	closedDue := date(2026, time.October, 25)
+
+	// 400 in 4 installments, the first one billed in the closed cycle
+	purchase := &creditcards.InstallmentPurchase{
+		MovementID:   "tv",
+		Amount:       money.New(400),
+		Installments: 4,
+		FirstCycle:   date(2026, time.September, 20),
+	}
+	plan := purchase.Plan(open, cutoff)
+	if plan == nil || len(plan.Remaining) != 3 {
+		t.Fatalf("Plan() = %+v, want 3 installments left", plan)
+	}
+
+	cards := []*creditcards.CardSummary{{
+		ID:               "visa",
+		Name:             "Visa",
+		CutoffDay:        cutoff,
+		PaymentDueDay:    dueDay,
+		BillingCycle:     open,
+		TotalCharges:     money.New(350), // Installment 2 included
+		StatementBalance: money.New(800), // Closed Oct 15, less what was paid since
+		PaymentDueDate:   &closedDue,
+	}}
+	installments := map[string][]*creditcards.InstallmentPlan{"visa": {plan}}
+
+	events := cardEvents(cards, installments, nil, nil, nil, today, until)
+
+	// Installment 4 (cycle Dec 16 - Jan 15) is due Jan 25, after until
+	want := []struct {
+		date   time.Time
+		amount money.Amount
+	}{
+		{date(2026, time.October, 25), money.New(-800)},
+		{date(2026, time.November, 25), money.New(-350)},
+		{date(2026, time.December, 25), money.New(-100)},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("cardEvents() = %d events, want %d", len(events), len(want))
+	}
+	for i, event := range events {
+		if !event.Date.Equal(want[i].date) || event.Amount != want[i].amount {
+			t.Errorf("event %d = %s %v, want %s %v", i, event.Date.Format("2006-01-02"), event.Amount, want[i].date.Format("2006-01-02"), want[i].amount)
+		}
+	}
+
+	// A statement already due is still owed: expected tomorrow
+	overdue := date(2026, time.October, 18)
+	cards[0].PaymentDueDate = &overdue
+	events = cardEvents(cards, nil, nil, nil, nil, today, until)
+	if len(events) == 0 {
+		t.Fatal("cardEvents() = no events, want the overdue statement")
+	}
+	if !events[0].Date.Equal(date(2026, time.October, 21)) || events[0].Amount != money.New(-800) {
+		t.Errorf("overdue statement event = %s %v, want 2026-10-21 -800", events[0].Date.Format("2006-01-02"), events[0].Amount)
+	}
+}
+
+func TestProject(t *testing.T) {
+	today := date(2026, time.April, 10)
+	accounts := []*AccountForecast{
+		{AccountID: "savings", AccountName: "Ahorros", StartBalance: money.New(500)},
+		{AccountID: "cash", AccountName: "Efectivo", StartBalance: money.New(100)},
+	}
+	events := []*Event{
+		{Date: date(2026, time.April, 20), AccountID: strPtr("savings"), Amount: money.New(1000)},
+		{Date: date(2026, time.April, 12), AccountID: strPtr("savings"), Amount: money.New(-800)},
+		{Date: date(2026, time.April, 15), Amount: money.New(-100)}, // No account
+	}
+
+	forecast := project(today, 30, accounts, events)
+
+	if len(forecast.Days) != 30 || !forecast.To.Equal(date(2026, time.May, 10)) {
+		t.Fatalf("project() = %d days to %s, want 30 to 2026-05-10", len(forecast.Days), forecast.To.Format("2006-01-02"))
+	}
+	if forecast.StartBalance != money.New(600) || forecast.EndBalance != money.New(700) {
+		t.Errorf("balance = %v to %v, want 600 to 700", forecast.StartBalance, forecast.EndBalance)
+	}
+	if forecast.FirstNegativeDate == nil || !forecast.FirstNegativeDate.Equal(date(2026, time.April, 12)) {
+		t.Errorf("FirstNegativeDate = %v, want 2026-04-12", forecast.FirstNegativeDate)
+	}
+	if forecast.LowestBalance != money.New(-300) || !forecast.LowestDate.Equal(date(2026, time.April, 15)) {
+		t.Errorf("lowest = %v on %s, want -300 on 2026-04-15", forecast.LowestBalance, forecast.LowestDate.Format("2006-01-02"))
+	}
+	if forecast.Covered {
+		t.Error("Covered = true, want false")
+	}
+
+	savings, cash := accounts[0], accounts[1]
+	if savings.FirstNegativeDate == nil || !savings.FirstNegativeDate.Equal(date(2026, time.April, 12)) || savings.EndBalance != money.New(700) {
+		t.Errorf("savings = %+v, want negative from 2026-04-12 and ending at 700", savings)
+	}
+	if cash.FirstNegativeDate != nil || cash.EndBalance != money.New(100) {
+		t.Errorf("cash = %+v, want untouched", cash)
+	}
+}
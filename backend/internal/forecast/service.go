@@ -0,0 +1,131 @@
+package forecast
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/blanquicet/conti/backend/internal/accounts"
+	"github.com/blanquicet/conti/backend/internal/creditcardpayments"
+	"github.com/blanquicet/conti/backend/internal/creditcards"
+	"github.com/blanquicet/conti/backend/internal/households"
+	"github.com/blanquicet/conti/backend/internal/income"
+	"github.com/blanquicet/conti/backend/internal/paymentmethods"
+	"github.com/blanquicet/conti/backend/internal/recurringmovements"
+)
+
+// service implements Service interface
+type service struct {
+	householdsRepo     households.HouseholdRepository
+	accountsRepo       accounts.Repository
+	incomeRepo         income.Repository
+	templatesRepo      recurringmovements.Repository
+	paymentMethodsRepo paymentmethods.Repository
+	ccPaymentsRepo     creditcardpayments.Repository
+	creditCardsService creditcards.Service
+	logger             *slog.Logger
+}
+
+// NewService creates a new forecast service
+func NewService(
+	householdsRepo households.HouseholdRepository,
+	accountsRepo accounts.Repository,
+	incomeRepo income.Repository,
+	templatesRepo recurringmovements.Repository,
+	paymentMethodsRepo paymentmethods.Repository,
+	ccPaymentsRepo creditcardpayments.Repository,
+	creditCardsService creditcards.Service,
+	logger *slog.Logger,
+) Service {
+	return &service{
+		householdsRepo:     householdsRepo,
+		accountsRepo:       accountsRepo,
+		incomeRepo:         incomeRepo,
+		templatesRepo:      templatesRepo,
+		paymentMethodsRepo: paymentMethodsRepo,
+		ccPaymentsRepo:     ccPaymentsRepo,
+		creditCardsService: creditCardsService,
+		logger:             logger,
+	}
+}
+
+// GetForecast projects the household balances over the next days from the
+// current account balances, the expected salaries, the scheduled recurring
+// movements and the credit card statements coming due
+func (s *service) GetForecast(ctx context.Context, userID string, days int) (*Forecast, error) {
+	if days < 1 || days > maxDays {
+		return nil, ErrInvalidDays
+	}
+
+	householdID, err := s.householdsRepo.GetUserHouseholdID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get household: %w", err)
+	}
+
+	today := dateOf(time.Now())
+	until := today.AddDate(0, 0, days)
+
+	accountList, err := s.accountsRepo.ListByHousehold(ctx, householdID)
+	if err != nil {
+		return nil, fmt.Errorf("list accounts: %w", err)
+	}
+	forecasts := make([]*AccountForecast, 0, len(accountList))
+	accountNames := make(map[string]string, len(accountList))
+	for _, account := range accountList {
+		accountNames[account.ID] = account.Name
+		forecast := &AccountForecast{AccountID: account.ID, AccountName: account.Name}
+		if account.CurrentBalance != nil {
+			forecast.StartBalance = *account.CurrentBalance
+		}
+		forecasts = append(forecasts, forecast)
+	}
+
+	// Salaries
+	since := time.Date(today.Year(), today.Month()-(salaryMonths-1), 1, 0, 0, 0, 0, time.UTC)
+	entries, err := s.incomeRepo.ListByHousehold(ctx, householdID, &income.ListIncomeFilters{StartDate: &since})
+	if err != nil {
+		return nil, fmt.Errorf("list income: %w", err)
+	}
+	events := salaryEvents(entries, today, until)
+
+	// Recurring movements
+	methodList, err := s.paymentMethodsRepo.ListByHousehold(ctx, householdID)
+	if err != nil {
+		return nil, fmt.Errorf("list payment methods: %w", err)
+	}
+	methods := make(map[string]*paymentmethods.PaymentMethod, len(methodList))
+	for _, method := range methodList {
+		methods[method.ID] = method
+	}
+
+	active := true
+	templates, err := s.templatesRepo.ListByHousehold(ctx, householdID, &recurringmovements.ListTemplatesFilters{IsActive: &active})
+	if err != nil {
+		return nil, fmt.Errorf("list recurring movements: %w", err)
+	}
+	templateList, charges := templateEvents(templates, methods, accountNames, today, until)
+	events = append(events, templateList...)
+
+	// Credit card statements
+	summary, err := s.creditCardsService.GetSummary(ctx, userID, today, nil)
+	if err != nil {
+		return nil, fmt.Errorf("get credit cards summary: %w", err)
+	}
+	installments := make(map[string][]*creditcards.InstallmentPlan, len(summary.Cards))
+	for _, card := range summary.Cards {
+		plans, err := s.creditCardsService.GetInstallments(ctx, userID, card.ID, today)
+		if err != nil {
+			return nil, fmt.Errorf("get installments of card %s: %w", card.ID, err)
+		}
+		installments[card.ID] = plans.Purchases
+	}
+	paymentsSince := today.AddDate(0, -6, 0)
+	payments, err := s.ccPaymentsRepo.ListByHousehold(ctx, householdID, &creditcardpayments.ListFilter{StartDate: &paymentsSince})
+	if err != nil {
+		return nil, fmt.Errorf("list credit card payments: %w", err)
+	}
+	events = append(events, cardEvents(summary.Cards, installments, charges, methods, cardPayers(payments.Payments), today, until)...)
+
+	return project(today, days, forecasts, events), nil
+}
@@ -0,0 +1,82 @@
+package forecast
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/blanquicet/conti/backend/internal/money"
+)
+
+// Errors for forecast operations
+var (
+	ErrInvalidDays = errors.New("days must be between 1 and 365")
+)
+
+const (
+	// DefaultDays is how far the forecast looks by default
+	DefaultDays = 90
+	maxDays     = 365
+
+	// salaryMonths is how many months back salaries are looked for, current
+	// one included; a salary paid in two of them is expected every month
+	salaryMonths = 4
+)
+
+// EventKind is what an expected entry of the forecast comes from
+type EventKind string
+
+const (
+	EventSalary            EventKind = "SALARY"             // A salary paid every month
+	EventRecurringMovement EventKind = "RECURRING_MOVEMENT" // A scheduled recurring movement template
+	EventCardPayment       EventKind = "CARD_PAYMENT"       // A credit card statement on its due date
+)
+
+// Event is an expected change of an account balance
+type Event struct {
+	Date        time.Time    `json:"date"`
+	Kind        EventKind    `json:"kind"`
+	SourceID    string       `json:"source_id"` // Of the income, template or card it comes from
+	Description string       `json:"description"`
+	AccountID   *string      `json:"account_id,omitempty"` // Nil when no account is known (household only)
+	AccountName *string      `json:"account_name,omitempty"`
+	Amount      money.Amount `json:"amount"` // Positive adds to the balance
+}
+
+// AccountForecast is how the balance of an account is expected to evolve
+type AccountForecast struct {
+	AccountID         string       `json:"account_id"`
+	AccountName       string       `json:"account_name"`
+	StartBalance      money.Amount `json:"start_balance"`
+	EndBalance        money.Amount `json:"end_balance"`
+	LowestBalance     money.Amount `json:"lowest_balance"`
+	LowestDate        time.Time    `json:"lowest_date"`
+	FirstNegativeDate *time.Time   `json:"first_negative_date,omitempty"`
+}
+
+// DayBalance is the expected household balance at the end of a day
+type DayBalance struct {
+	Date    time.Time    `json:"date"`
+	Balance money.Amount `json:"balance"`
+}
+
+// Forecast is the expected balance of the household accounts over the next days
+type Forecast struct {
+	From              time.Time          `json:"from"` // Today, balances are the current ones
+	To                time.Time          `json:"to"`
+	StartBalance      money.Amount       `json:"start_balance"`
+	EndBalance        money.Amount       `json:"end_balance"`
+	LowestBalance     money.Amount       `json:"lowest_balance"`
+	LowestDate        time.Time          `json:"lowest_date"`
+	FirstNegativeDate *time.Time         `json:"first_negative_date,omitempty"` // Of the household total
+	Covered           bool               `json:"covered"`                       // Neither the household nor any account goes negative
+	Accounts          []*AccountForecast `json:"accounts"`
+	Events            []*Event           `json:"events"`
+	Days              []*DayBalance      `json:"days"`
+}
+
+// Service defines the interface for cash-flow forecasts
+type Service interface {
+	// GetForecast projects the household balances over the next days
+	GetForecast(ctx context.Context, userID string, days int) (*Forecast, error)
+}
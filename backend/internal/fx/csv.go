@@ -0,0 +1,80 @@
+package fx
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// csvColumns are the columns expected in an exchange rates CSV, in any order:
+//
+//	date,from,to,rate
+//	2026-03-01,USD,COP,4012.35
+var csvColumns = []string{"date", "from", "to", "rate"}
+
+// ParseCSV reads exchange rates from a CSV with a header row. Dates use
+// YYYY-MM-DD and rates use a dot as decimal separator. The whole file is
+// rejected if any row is invalid, naming the offending line.
+func ParseCSV(r io.Reader) ([]*CreateRateInput, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%w: file is empty", ErrInvalidCSV)
+		}
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCSV, err)
+	}
+
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		index[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, col := range csvColumns {
+		if _, ok := index[col]; !ok {
+			return nil, fmt.Errorf("%w: missing column %q", ErrInvalidCSV, col)
+		}
+	}
+
+	var inputs []*CreateRateInput
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidCSV, err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		date, err := time.Parse("2006-01-02", strings.TrimSpace(record[index["date"]]))
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: invalid date, use YYYY-MM-DD", ErrInvalidCSV, line)
+		}
+		rate, err := strconv.ParseFloat(strings.TrimSpace(record[index["rate"]]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: invalid rate", ErrInvalidCSV, line)
+		}
+
+		input := &CreateRateInput{
+			FromCurrency: record[index["from"]],
+			ToCurrency:   record[index["to"]],
+			Rate:         rate,
+			RateDate:     date,
+		}
+		if err := input.Validate(); err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidCSV, line, err)
+		}
+		inputs = append(inputs, input)
+	}
+
+	if len(inputs) == 0 {
+		return nil, fmt.Errorf("%w: no rates found", ErrInvalidCSV)
+	}
+	return inputs, nil
+}
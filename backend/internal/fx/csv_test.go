@@ -0,0 +1,72 @@
+package fx
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseCSV(t *testing.T) {
+	input := "Date,From,To,Rate\n" +
+		"2026-03-01,usd,COP,4012.35\n" +
+		"2026-03-02, EUR , COP ,4380.1\n"
+
+	rates, err := ParseCSV(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseCSV() error = %v", err)
+	}
+	if len(rates) != 2 {
+		t.Fatalf("got %d rates, want 2", len(rates))
+	}
+
+	first := rates[0]
+	if first.FromCurrency != "USD" || first.ToCurrency != "COP" || first.Rate != 4012.35 {
+		t.Errorf("first rate = %+v", first)
+	}
+	if !first.RateDate.Equal(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("first rate date = %v", first.RateDate)
+	}
+	if rates[1].FromCurrency != "EUR" {
+		t.Errorf("second rate from = %q, want EUR", rates[1].FromCurrency)
+	}
+}
+
+func TestParseCSV_ColumnOrder(t *testing.T) {
+	rates, err := ParseCSV(strings.NewReader("rate,to,from,date\n0.00025,USD,COP,2026-03-01\n"))
+	if err != nil {
+		t.Fatalf("ParseCSV() error = %v", err)
+	}
+	if rates[0].FromCurrency != "COP" || rates[0].ToCurrency != "USD" || rates[0].Rate != 0.00025 {
+		t.Errorf("rate = %+v", rates[0])
+	}
+}
+
+func TestParseCSV_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantMsg string
+	}{
+		{"empty file", "", "empty"},
+		{"missing column", "date,from,to\n2026-03-01,USD,COP\n", `missing column "rate"`},
+		{"no rows", "date,from,to,rate\n", "no rates"},
+		{"bad date", "date,from,to,rate\n01/03/2026,USD,COP,4000\n", "line 2"},
+		{"bad rate", "date,from,to,rate\n2026-03-01,USD,COP,4.000,5\n", "line 2"},
+		{"negative rate", "date,from,to,rate\n2026-03-01,USD,COP,4000\n2026-03-02,USD,COP,-1\n", "line 3"},
+		{"same currency", "date,from,to,rate\n2026-03-01,COP,COP,1\n", "must be different"},
+		{"bad currency", "date,from,to,rate\n2026-03-01,US,COP,4000\n", "ISO 4217"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseCSV(strings.NewReader(tt.input))
+			if !errors.Is(err, ErrInvalidCSV) {
+				t.Fatalf("ParseCSV() error = %v, want ErrInvalidCSV", err)
+			}
+			if !strings.Contains(err.Error(), tt.wantMsg) {
+				t.Errorf("error %q does not mention %q", err, tt.wantMsg)
+			}
+		})
+	}
+}
@@ -0,0 +1,208 @@
+package fx
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/blanquicet/conti/backend/internal/auth"
+)
+
+// maxImportSize caps the size of an uploaded rates CSV
+const maxImportSize = 1 << 20 // 1MB
+
+// Handler handles HTTP requests for exchange rates
+type Handler struct {
+	service    Service
+	authSvc    *auth.Service
+	cookieName string
+	logger     *slog.Logger
+}
+
+// NewHandler creates a new exchange rates handler
+func NewHandler(service Service, authService *auth.Service, cookieName string, logger *slog.Logger) *Handler {
+	return &Handler{
+		service:    service,
+		authSvc:    authService,
+		cookieName: cookieName,
+		logger:     logger,
+	}
+}
+
+// CreateRateRequest represents the request body for entering a rate
+type CreateRateRequest struct {
+	FromCurrency string  `json:"from_currency"`
+	ToCurrency   string  `json:"to_currency"`
+	Rate         float64 `json:"rate"`
+	RateDate     string  `json:"rate_date"` // YYYY-MM-DD format
+}
+
+// getUserFromSession extracts user from session cookie
+func (h *Handler) getUserFromSession(r *http.Request) (*auth.User, error) {
+	cookie, err := r.Cookie(h.cookieName)
+	if err != nil {
+		return nil, err
+	}
+	return h.authSvc.GetUserBySession(r.Context(), cookie.Value)
+}
+
+// HandleCreate handles POST /fx-rates
+func (h *Handler) HandleCreate(w http.ResponseWriter, r *http.Request) {
+	user, err := h.getUserFromSession(r)
+	if err != nil {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req CreateRateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	rateDate, err := time.Parse("2006-01-02", req.RateDate)
+	if err != nil {
+		http.Error(w, "Invalid rate_date format, use YYYY-MM-DD", http.StatusBadRequest)
+		return
+	}
+
+	rate, err := h.service.Create(r.Context(), user.ID, &CreateRateInput{
+		FromCurrency: req.FromCurrency,
+		ToCurrency:   req.ToCurrency,
+		Rate:         req.Rate,
+		RateDate:     rateDate,
+	})
+	if err != nil {
+		h.writeError(w, "failed to create exchange rate", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(rate)
+}
+
+// HandleImport handles POST /fx-rates/import.
+// Accepts the CSV as a multipart "file" field or as the raw request body.
+func (h *Handler) HandleImport(w http.ResponseWriter, r *http.Request) {
+	user, err := h.getUserFromSession(r)
+	if err != nil {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+
+	var body io.Reader = r.Body
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == "multipart/form-data" {
+		if err := r.ParseMultipartForm(maxImportSize); err != nil {
+			http.Error(w, "File too large (max 1MB)", http.StatusBadRequest)
+			return
+		}
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			http.Error(w, "Field 'file' is required", http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+		body = file
+	}
+
+	result, err := h.service.Import(r.Context(), user.ID, body)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, "File too large (max 1MB)", http.StatusBadRequest)
+			return
+		}
+		h.writeError(w, "failed to import exchange rates", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// HandleList handles GET /fx-rates?currency=USD&start_date=YYYY-MM-DD&end_date=YYYY-MM-DD
+func (h *Handler) HandleList(w http.ResponseWriter, r *http.Request) {
+	user, err := h.getUserFromSession(r)
+	if err != nil {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	filters := &ListRatesFilters{}
+	query := r.URL.Query()
+	if c := query.Get("currency"); c != "" {
+		currency, err := NormalizeCurrency(c)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		filters.Currency = &currency
+	}
+	if s := query.Get("start_date"); s != "" {
+		startDate, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			http.Error(w, "Invalid start_date format, use YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		filters.StartDate = &startDate
+	}
+	if s := query.Get("end_date"); s != "" {
+		endDate, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			http.Error(w, "Invalid end_date format, use YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		filters.EndDate = &endDate
+	}
+
+	rates, err := h.service.List(r.Context(), user.ID, filters)
+	if err != nil {
+		h.writeError(w, "failed to list exchange rates", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"rates": rates,
+	})
+}
+
+// HandleDelete handles DELETE /fx-rates/{id}
+func (h *Handler) HandleDelete(w http.ResponseWriter, r *http.Request) {
+	user, err := h.getUserFromSession(r)
+	if err != nil {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), user.ID, r.PathValue("id")); err != nil {
+		h.writeError(w, "failed to delete exchange rate", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeError maps service errors to HTTP status codes
+func (h *Handler) writeError(w http.ResponseWriter, msg string, err error) {
+	switch {
+	case errors.Is(err, ErrRateNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, ErrNotAuthorized):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, ErrInvalidCurrency), errors.Is(err, ErrSameCurrency),
+		errors.Is(err, ErrInvalidRate), errors.Is(err, ErrRateDateRequired),
+		errors.Is(err, ErrInvalidCSV):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		h.logger.Error(msg, "error", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,166 @@
+package fx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// repository implements Repository using PostgreSQL
+type repository struct {
+	pool *pgxpool.Pool
+}
+
+// NewRepository creates a new exchange rates repository
+func NewRepository(pool *pgxpool.Pool) Repository {
+	return &repository{pool: pool}
+}
+
+const rateColumns = `id, household_id, from_currency, to_currency, rate::float8, rate_date,
+	       source, created_by, created_at, updated_at`
+
+// scanRate scans a row selected with rateColumns
+func scanRate(row pgx.Row) (*Rate, error) {
+	var rate Rate
+	err := row.Scan(
+		&rate.ID,
+		&rate.HouseholdID,
+		&rate.FromCurrency,
+		&rate.ToCurrency,
+		&rate.Rate,
+		&rate.RateDate,
+		&rate.Source,
+		&rate.CreatedBy,
+		&rate.CreatedAt,
+		&rate.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &rate, nil
+}
+
+// Upsert stores rates in a single transaction, replacing same-day rates
+func (r *repository) Upsert(ctx context.Context, householdID string, createdBy *string, source Source, inputs []*CreateRateInput) ([]*Rate, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	rates := make([]*Rate, 0, len(inputs))
+	for _, input := range inputs {
+		rate, err := scanRate(tx.QueryRow(ctx, `
+			INSERT INTO fx_rates (household_id, from_currency, to_currency, rate, rate_date, source, created_by)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT (household_id, from_currency, to_currency, rate_date)
+			DO UPDATE SET rate = EXCLUDED.rate, source = EXCLUDED.source,
+			              created_by = EXCLUDED.created_by, updated_at = NOW()
+			RETURNING `+rateColumns,
+			householdID, input.FromCurrency, input.ToCurrency, input.Rate, input.RateDate, source, createdBy,
+		))
+		if err != nil {
+			return nil, err
+		}
+		rates = append(rates, rate)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return rates, nil
+}
+
+// GetByID retrieves a rate by ID
+func (r *repository) GetByID(ctx context.Context, id string) (*Rate, error) {
+	rate, err := scanRate(r.pool.QueryRow(ctx, `
+		SELECT `+rateColumns+`
+		FROM fx_rates
+		WHERE id = $1
+	`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrRateNotFound
+	}
+	return rate, err
+}
+
+// ListByHousehold lists a household's rates, newest first
+func (r *repository) ListByHousehold(ctx context.Context, householdID string, filters *ListRatesFilters) ([]*Rate, error) {
+	conditions := []string{"household_id = $1"}
+	args := []interface{}{householdID}
+
+	if filters != nil {
+		if filters.Currency != nil {
+			args = append(args, *filters.Currency)
+			conditions = append(conditions, fmt.Sprintf("(from_currency = $%d OR to_currency = $%d)", len(args), len(args)))
+		}
+		if filters.StartDate != nil {
+			args = append(args, *filters.StartDate)
+			conditions = append(conditions, fmt.Sprintf("rate_date >= $%d", len(args)))
+		}
+		if filters.EndDate != nil {
+			args = append(args, *filters.EndDate)
+			conditions = append(conditions, fmt.Sprintf("rate_date <= $%d", len(args)))
+		}
+	}
+
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+rateColumns+`
+		FROM fx_rates
+		WHERE `+strings.Join(conditions, " AND ")+`
+		ORDER BY rate_date DESC, from_currency, to_currency
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rates []*Rate
+	for rows.Next() {
+		rate, err := scanRate(rows)
+		if err != nil {
+			return nil, err
+		}
+		rates = append(rates, rate)
+	}
+	return rates, rows.Err()
+}
+
+// Delete deletes a rate
+func (r *repository) Delete(ctx context.Context, id string) error {
+	result, err := r.pool.Exec(ctx, `DELETE FROM fx_rates WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrRateNotFound
+	}
+	return nil
+}
+
+// FindRate finds the latest rate for the pair on or before date.
+// On the same day, a rate stored for the pair wins over its inverse.
+func (r *repository) FindRate(ctx context.Context, householdID, from, to string, date time.Time) (float64, error) {
+	var rate float64
+	err := r.pool.QueryRow(ctx, `
+		SELECT CASE WHEN from_currency = $2 THEN rate ELSE 1 / rate END::float8
+		FROM fx_rates
+		WHERE household_id = $1
+		  AND ((from_currency = $2 AND to_currency = $3) OR (from_currency = $3 AND to_currency = $2))
+		  AND rate_date <= $4
+		ORDER BY rate_date DESC, (from_currency = $2) DESC
+		LIMIT 1
+	`, householdID, from, to, date).Scan(&rate)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, ErrRateNotFound
+	}
+	if err != nil {
+		return 0, err
+	}
+	return rate, nil
+}
@@ -0,0 +1,163 @@
+package fx
+
+import (
+	"context"
+	"io"
+	"log/slog"
+
+	"github.com/blanquicet/conti/backend/internal/audit"
+	"github.com/blanquicet/conti/backend/internal/households"
+)
+
+// service implements Service interface
+type service struct {
+	repo           Repository
+	householdsRepo households.HouseholdRepository
+	auditService   audit.Service
+	logger         *slog.Logger
+}
+
+// NewService creates a new exchange rates service
+func NewService(
+	repo Repository,
+	householdsRepo households.HouseholdRepository,
+	auditService audit.Service,
+	logger *slog.Logger,
+) Service {
+	return &service{
+		repo:           repo,
+		householdsRepo: householdsRepo,
+		auditService:   auditService,
+		logger:         logger,
+	}
+}
+
+// Create enters a rate manually, replacing any rate for the same pair and day
+func (s *service) Create(ctx context.Context, userID string, input *CreateRateInput) (*Rate, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	householdID, err := s.householdsRepo.GetUserHouseholdID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	rates, err := s.repo.Upsert(ctx, householdID, &userID, SourceManual, []*CreateRateInput{input})
+	if err != nil {
+		s.auditService.LogAsync(ctx, &audit.LogInput{
+			UserID:       audit.StringPtr(userID),
+			Action:       audit.ActionFXRateCreated,
+			ResourceType: "fx_rate",
+			HouseholdID:  audit.StringPtr(householdID),
+			Success:      false,
+			ErrorMessage: audit.StringPtr(err.Error()),
+		})
+		return nil, err
+	}
+	rate := rates[0]
+
+	s.auditService.LogAsync(ctx, &audit.LogInput{
+		UserID:       audit.StringPtr(userID),
+		Action:       audit.ActionFXRateCreated,
+		ResourceType: "fx_rate",
+		ResourceID:   audit.StringPtr(rate.ID),
+		HouseholdID:  audit.StringPtr(householdID),
+		NewValues:    audit.StructToMap(rate),
+		Success:      true,
+	})
+
+	return rate, nil
+}
+
+// Import loads rates from a CSV (see ParseCSV). Nothing is stored if any
+// row is invalid.
+func (s *service) Import(ctx context.Context, userID string, r io.Reader) (*ImportResult, error) {
+	householdID, err := s.householdsRepo.GetUserHouseholdID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	inputs, err := ParseCSV(r)
+	if err != nil {
+		return nil, err
+	}
+
+	rates, err := s.repo.Upsert(ctx, householdID, &userID, SourceCSV, inputs)
+	if err != nil {
+		s.auditService.LogAsync(ctx, &audit.LogInput{
+			UserID:       audit.StringPtr(userID),
+			Action:       audit.ActionFXRatesImported,
+			ResourceType: "fx_rate",
+			HouseholdID:  audit.StringPtr(householdID),
+			Success:      false,
+			ErrorMessage: audit.StringPtr(err.Error()),
+		})
+		return nil, err
+	}
+
+	s.auditService.LogAsync(ctx, &audit.LogInput{
+		UserID:       audit.StringPtr(userID),
+		Action:       audit.ActionFXRatesImported,
+		ResourceType: "fx_rate",
+		HouseholdID:  audit.StringPtr(householdID),
+		Metadata: map[string]interface{}{
+			"imported": len(rates),
+		},
+		Success: true,
+	})
+
+	return &ImportResult{Imported: len(rates)}, nil
+}
+
+// List lists the rates of the user's household
+func (s *service) List(ctx context.Context, userID string, filters *ListRatesFilters) ([]*Rate, error) {
+	householdID, err := s.householdsRepo.GetUserHouseholdID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.ListByHousehold(ctx, householdID, filters)
+}
+
+// Delete deletes a rate. Movements already converted with it keep their
+// stored exchange rate.
+func (s *service) Delete(ctx context.Context, userID, id string) error {
+	householdID, err := s.householdsRepo.GetUserHouseholdID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	existing, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if existing.HouseholdID != householdID {
+		return ErrNotAuthorized
+	}
+
+	if err := s.repo.Delete(ctx, id); err != nil {
+		s.auditService.LogAsync(ctx, &audit.LogInput{
+			UserID:       audit.StringPtr(userID),
+			Action:       audit.ActionFXRateDeleted,
+			ResourceType: "fx_rate",
+			ResourceID:   audit.StringPtr(id),
+			HouseholdID:  audit.StringPtr(householdID),
+			OldValues:    audit.StructToMap(existing),
+			Success:      false,
+			ErrorMessage: audit.StringPtr(err.Error()),
+		})
+		return err
+	}
+
+	s.auditService.LogAsync(ctx, &audit.LogInput{
+		UserID:       audit.StringPtr(userID),
+		Action:       audit.ActionFXRateDeleted,
+		ResourceType: "fx_rate",
+		ResourceID:   audit.StringPtr(id),
+		HouseholdID:  audit.StringPtr(householdID),
+		OldValues:    audit.StructToMap(existing),
+		Success:      true,
+	})
+
+	return nil
+}
@@ -0,0 +1,131 @@
+package fx
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/blanquicet/conti/backend/internal/money"
+)
+
+// Errors for exchange rate operations
+var (
+	ErrRateNotFound     = errors.New("exchange rate not found")
+	ErrNotAuthorized    = errors.New("not authorized")
+	ErrInvalidCurrency  = errors.New("currency must be a 3-letter ISO 4217 code")
+	ErrSameCurrency     = errors.New("from_currency and to_currency must be different")
+	ErrInvalidRate      = errors.New("rate must be greater than zero")
+	ErrRateDateRequired = errors.New("rate_date is required")
+	ErrInvalidCSV       = errors.New("invalid exchange rates CSV")
+)
+
+// Source tells where an exchange rate came from
+type Source string
+
+const (
+	SourceManual Source = "MANUAL" // Entered by a user
+	SourceCSV    Source = "CSV"    // Loaded from a CSV file
+)
+
+// Rate is the value of one unit of FromCurrency in ToCurrency on a given day
+type Rate struct {
+	ID           string    `json:"id"`
+	HouseholdID  string    `json:"household_id"`
+	FromCurrency string    `json:"from_currency"`
+	ToCurrency   string    `json:"to_currency"`
+	Rate         float64   `json:"rate"`
+	RateDate     time.Time `json:"rate_date"`
+	Source       Source    `json:"source"`
+	CreatedBy    *string   `json:"created_by,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// NormalizeCurrency upper-cases a currency code and checks it looks like
+// an ISO 4217 code (three letters)
+func NormalizeCurrency(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if len(code) != 3 {
+		return "", ErrInvalidCurrency
+	}
+	for _, c := range code {
+		if c < 'A' || c > 'Z' {
+			return "", ErrInvalidCurrency
+		}
+	}
+	return code, nil
+}
+
+// Convert converts an amount with the given rate, rounding to the minor unit
+func Convert(amount money.Amount, rate float64) money.Amount {
+	return amount.Mul(rate)
+}
+
+// CreateRateInput represents input for creating (or replacing) a rate
+type CreateRateInput struct {
+	FromCurrency string    `json:"from_currency"`
+	ToCurrency   string    `json:"to_currency"`
+	Rate         float64   `json:"rate"`
+	RateDate     time.Time `json:"rate_date"`
+}
+
+// Validate validates the input and normalizes its currency codes
+func (i *CreateRateInput) Validate() error {
+	from, err := NormalizeCurrency(i.FromCurrency)
+	if err != nil {
+		return err
+	}
+	to, err := NormalizeCurrency(i.ToCurrency)
+	if err != nil {
+		return err
+	}
+	if from == to {
+		return ErrSameCurrency
+	}
+	if i.Rate <= 0 {
+		return ErrInvalidRate
+	}
+	if i.RateDate.IsZero() {
+		return ErrRateDateRequired
+	}
+	i.FromCurrency = from
+	i.ToCurrency = to
+	return nil
+}
+
+// ListRatesFilters represents filters for listing rates
+type ListRatesFilters struct {
+	Currency  *string    // Matches either side of the pair
+	StartDate *time.Time // Inclusive
+	EndDate   *time.Time // Inclusive
+}
+
+// ImportResult summarizes a CSV import
+type ImportResult struct {
+	Imported int `json:"imported"`
+}
+
+// Repository defines the interface for exchange rate data access
+type Repository interface {
+	// Upsert stores the rates in one transaction, replacing any rate for the
+	// same pair and day
+	Upsert(ctx context.Context, householdID string, createdBy *string, source Source, inputs []*CreateRateInput) ([]*Rate, error)
+	GetByID(ctx context.Context, id string) (*Rate, error)
+	ListByHousehold(ctx context.Context, householdID string, filters *ListRatesFilters) ([]*Rate, error)
+	Delete(ctx context.Context, id string) error
+
+	// FindRate returns how many units of `to` one unit of `from` was worth on
+	// date, using the latest rate on or before it. A stored rate for the
+	// inverse pair is used (inverted) when there is none for the pair itself.
+	FindRate(ctx context.Context, householdID, from, to string, date time.Time) (float64, error)
+}
+
+// Service defines the interface for exchange rate business logic
+type Service interface {
+	Create(ctx context.Context, userID string, input *CreateRateInput) (*Rate, error)
+	Import(ctx context.Context, userID string, r io.Reader) (*ImportResult, error)
+	List(ctx context.Context, userID string, filters *ListRatesFilters) ([]*Rate, error)
+	Delete(ctx context.Context, userID, id string) error
+}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/blanquicet/conti/backend/internal/accounts"
 	"github.com/blanquicet/conti/backend/internal/ai"
+	"github.com/blanquicet/conti/backend/internal/attachments"
 	"github.com/blanquicet/conti/backend/internal/audit"
 	"github.com/blanquicet/conti/backend/internal/auth"
 	"github.com/blanquicet/conti/backend/internal/budgets"
@@ -22,14 +23,24 @@ import (
 	"github.com/blanquicet/conti/backend/internal/creditcardpayments"
 	"github.com/blanquicet/conti/backend/internal/creditcards"
 	"github.com/blanquicet/conti/backend/internal/email"
+	"github.com/blanquicet/conti/backend/internal/events"
+	"github.com/blanquicet/conti/backend/internal/export"
+	"github.com/blanquicet/conti/backend/internal/forecast"
+	"github.com/blanquicet/conti/backend/internal/fx"
 	"github.com/blanquicet/conti/backend/internal/households"
+	"github.com/blanquicet/conti/backend/internal/importer"
+	"github.com/blanquicet/conti/backend/internal/mailingest"
 	"github.com/blanquicet/conti/backend/internal/income"
 	"github.com/blanquicet/conti/backend/internal/middleware"
+	"github.com/blanquicet/conti/backend/internal/money"
 	"github.com/blanquicet/conti/backend/internal/movements"
+	"github.com/blanquicet/conti/backend/internal/networth"
 	"github.com/blanquicet/conti/backend/internal/paymentmethods"
 	"github.com/blanquicet/conti/backend/internal/recurringmovements"
+	"github.com/blanquicet/conti/backend/internal/rules"
 	"github.com/blanquicet/conti/backend/internal/sessions"
 	"github.com/blanquicet/conti/backend/internal/stt"
+	"github.com/blanquicet/conti/backend/internal/trash"
 	"github.com/blanquicet/conti/backend/internal/users"
 )
 
@@ -117,13 +128,54 @@ func New(ctx context.Context, cfg *config.Config, logger *slog.Logger) (*Server,
 		logger,
 	)
 	
+	// Create exchange rates service and handler (conversion to the household currency)
+	fxRepo := fx.NewRepository(pool)
+	fxService := fx.NewService(fxRepo, householdRepo, auditService, logger)
+	fxHandler := fx.NewHandler(fxService, authService, cfg.SessionCookieName, logger)
+	
 	// Create movements service and handler
 	movementsRepo := movements.NewRepository(pool)
+	
+	// Create categorization rules service and handler (they fill in new movements,
+	// so the movements service needs them)
+	categoriesRepo := categories.NewPostgresRepository(pool)
+	rulesRepo := rules.NewRepository(pool)
+	rulesService := rules.NewService(
+		rulesRepo,
+		householdRepo,
+		categoriesRepo,
+		paymentMethodsRepo,
+		movementsRepo,
+		auditService,
+		logger,
+	)
+	rulesHandler := rules.NewHandler(rulesService, authService, cfg.SessionCookieName, logger)
+	
+	// Create attachments service and handler (receipts and invoices; files are
+	// removed by the movements service when their movement is deleted)
+	attachmentStorage, err := attachments.NewLocalStorage(cfg.AttachmentsDir)
+	if err != nil {
+		pool.Close()
+		return nil, err
+	}
+	attachmentsService := attachments.NewService(
+		attachments.NewRepository(pool),
+		attachmentStorage,
+		movementsRepo,
+		householdRepo,
+		auditService,
+		logger,
+	)
+	attachmentsHandler := attachments.NewHandler(attachmentsService, authService, cfg.SessionCookieName, logger)
+	
 	movementsService := movements.NewService(
 		movementsRepo,
 		householdRepo,
 		paymentMethodsRepo,
 		accountsRepo,
+		fxRepo,
+		rulesService,
+		attachmentsService,
 		auditService,
 		logger,
 	)
@@ -134,6 +186,35 @@ func New(ctx context.Context, cfg *config.Config, logger *slog.Logger) (*Server,
 		logger,
 	)
 	
+	// Create statement import service and handler (bank/card statements -> review queue -> movements)
+	importerRepo := importer.NewRepository(pool)
+	importerService := importer.NewService(
+		importerRepo,
+		householdRepo,
+		paymentMethodsRepo,
+		accountsRepo,
+		movementsService,
+		auditService,
+		logger,
+	)
+	importerHandler := importer.NewHandler(importerService, authService, cfg.SessionCookieName, logger)
+	
+	// Create notification email ingestion service and handler (bank alerts -> same review queue)
+	mailIngestService := mailingest.NewService(
+		importerRepo,
+		householdRepo,
+		paymentMethodsRepo,
+		accountsRepo,
+		auditService,
+		logger,
+	)
+	mailIngestHandler := mailingest.NewHandler(mailIngestService, authService, cfg.SessionCookieName, logger)
+	
+	// Create events service and handler (trips and other shared temporary contexts)
+	eventsRepo := events.NewRepository(pool)
+	eventsService := events.NewService(eventsRepo, householdRepo, movementsService, auditService, logger)
+	eventsHandler := events.NewHandler(eventsService, authService, cfg.SessionCookieName, logger)
+	
 	// Create income service and handler
 	incomeRepo := income.NewRepository(pool)
 	incomeService := income.NewService(incomeRepo, accountsRepo, householdRepo, auditService, logger)
@@ -161,7 +242,6 @@ func New(ctx context.Context, cfg *config.Config, logger *slog.Logger) (*Server,
 	categoryGroupsRepo := categorygroups.NewRepository(pool)
 
 	// Create categories service and handler
-	categoriesRepo := categories.NewPostgresRepository(pool)
 	categoriesService := categories.NewService(categoriesRepo, householdRepo, auditService)
 	categoriesHandler := categories.NewHandler(
 		categoriesService,
@@ -221,7 +301,7 @@ func New(ctx context.Context, cfg *config.Config, logger *slog.Logger) (*Server,
 	budgetsService.SetTemplatesCalculator(recurringMovementsService)
 
 	// Wire template sync so budget item updates propagate to recurring movement templates
-	budgetItemsService.SetSyncTemplateFn(func(ctx context.Context, templateID string, amount float64, name string) error {
+	budgetItemsService.SetSyncTemplateFn(func(ctx context.Context, templateID string, amount money.Amount, name string) error {
 		_, err := recurringMovementsRepo.Update(ctx, templateID, &recurringmovements.UpdateTemplateInput{
 			Amount: &amount,
 			Name:   &name,
@@ -306,6 +386,29 @@ func New(ctx context.Context, cfg *config.Config, logger *slog.Logger) (*Server,
 	
 	// Start scheduler in background
 	go scheduler.Start(ctx)
+	
+	// Empty the trash of movements and income deleted before the retention period
+	purger := trash.NewPurger(map[string]trash.Purgeable{
+		"movements": movementsService,
+		"income":    incomeService,
+	}, cfg.TrashRetention, logger)
+	go purger.Start(ctx)
+	
+	// Poll a mailbox for bank notification emails, when one is configured
+	if cfg.MailIngestPath != "" || cfg.MailIngestIMAPAddr != "" {
+		mailUser, err := userRepo.GetByEmail(ctx, cfg.MailIngestUser)
+		if err != nil {
+			return nil, fmt.Errorf("mail ingest user %q: %w", cfg.MailIngestUser, err)
+		}
+		poller := mailingest.NewPoller(mailIngestService, mailUser.ID, cfg.MailIngestPath, &mailingest.IMAPConfig{
+			Addr:     cfg.MailIngestIMAPAddr,
+			TLS:      cfg.MailIngestIMAPTLS,
+			Username: cfg.MailIngestIMAPUser,
+			Password: cfg.MailIngestIMAPPass,
+			Mailbox:  cfg.MailIngestMailbox,
+		}, cfg.MailIngestInterval, logger)
+		go poller.Start(ctx)
+	}
 
 	// Create credit card payments service and handler
 	ccPaymentsRepo := creditcardpayments.NewRepository(pool)
@@ -319,16 +422,53 @@ func New(ctx context.Context, cfg *config.Config, logger *slog.Logger) (*Server,
 	)
 	ccPaymentsHandler := creditcardpayments.NewHandler(ccPaymentsService, authService, cfg.SessionCookieName, logger)
 
+	// Create export service and handler
+	exportService := export.NewService(movementsRepo, incomeRepo, ccPaymentsRepo, householdRepo, logger)
+	exportHandler := export.NewHandler(exportService, authService, cfg.SessionCookieName, logger)
+
 	// Create credit cards summary service and handler
 	creditCardsRepo := creditcards.NewRepository(pool)
 	creditCardsService := creditcards.NewService(
 		creditCardsRepo,
 		householdRepo,
 		paymentMethodsRepo,
+		auditService,
 		logger,
 	)
 	creditCardsHandler := creditcards.NewHandler(creditCardsService, authService, cfg.SessionCookieName)
 
+	// Close ended billing cycles into card statements
+	statementCloser := creditcards.NewCloser(creditCardsService, logger)
+	go statementCloser.Start(ctx)
+
+	// Create net worth service and handler
+	netWorthService := networth.NewService(
+		networth.NewRepository(pool),
+		householdRepo,
+		accountsRepo,
+		creditCardsService,
+		movementsService,
+		logger,
+	)
+	netWorthHandler := networth.NewHandler(netWorthService, authService, cfg.SessionCookieName, logger)
+
+	// Create cash-flow forecast service and handler
+	forecastService := forecast.NewService(
+		householdRepo,
+		accountsRepo,
+		incomeRepo,
+		recurringMovementsRepo,
+		paymentMethodsRepo,
+		ccPaymentsRepo,
+		creditCardsService,
+		logger,
+	)
+	forecastHandler := forecast.NewHandler(forecastService, authService, cfg.SessionCookieName, logger)
+
+	// Keep a monthly snapshot of every household's net worth
+	netWorthSnapshotter := networth.NewSnapshotter(netWorthService, logger)
+	go netWorthSnapshotter.Start(ctx)
+
 	// Create rate limiters for auth endpoints (if enabled)
 	// Login/Register: 5 requests per minute per IP (strict to prevent brute force)
 	// Password reset: 3 requests per minute per IP (even stricter)
@@ -403,9 +543,16 @@ func New(ctx context.Context, cfg *config.Config, logger *slog.Logger) (*Server,
 	// Accounts endpoints
 	mux.HandleFunc("POST /accounts", accountsHandler.CreateAccount)
 	mux.HandleFunc("GET /accounts", accountsHandler.ListAccounts)
+	mux.HandleFunc("GET /accounts/balance-history", accountsHandler.GetHouseholdBalanceHistory)
 	mux.HandleFunc("GET /accounts/{id}", accountsHandler.GetAccount)
 	mux.HandleFunc("PATCH /accounts/{id}", accountsHandler.UpdateAccount)
 	mux.HandleFunc("DELETE /accounts/{id}", accountsHandler.DeleteAccount)
+	mux.HandleFunc("GET /accounts/{id}/balance-history", accountsHandler.GetBalanceHistory)
+	mux.HandleFunc("GET /accounts/{id}/checkpoints", accountsHandler.ListCheckpoints)
+	mux.HandleFunc("POST /accounts/{id}/checkpoints", accountsHandler.CreateCheckpoint)
+	mux.HandleFunc("GET /accounts/{id}/checkpoints/{checkpointId}", accountsHandler.GetCheckpoint)
+	mux.HandleFunc("DELETE /accounts/{id}/checkpoints/{checkpointId}", accountsHandler.DeleteCheckpoint)
+	mux.HandleFunc("POST /accounts/{id}/checkpoints/{checkpointId}/adjust", accountsHandler.AdjustCheckpoint)
 
 	// Income endpoints
 	mux.HandleFunc("POST /income", incomeHandler.HandleCreate)
@@ -413,6 +560,14 @@ func New(ctx context.Context, cfg *config.Config, logger *slog.Logger) (*Server,
 	mux.HandleFunc("GET /income/{id}", incomeHandler.HandleGetByID)
 	mux.HandleFunc("PATCH /income/{id}", incomeHandler.HandleUpdate)
 	mux.HandleFunc("DELETE /income/{id}", incomeHandler.HandleDelete)
+	
+	// Income trash: deleted entries stay restorable until purged
+	mux.HandleFunc("GET /income/trash", incomeHandler.HandleListTrash)
+	mux.HandleFunc("POST /income/{id}/restore", incomeHandler.HandleRestore)
+	
+	// Income history from audit logs, and revert to one of its versions
+	mux.HandleFunc("GET /income/{id}/history", incomeHandler.HandleHistory)
+	mux.HandleFunc("POST /income/{id}/history/{versionId}/revert", incomeHandler.HandleRevert)
 
 	// Payment methods endpoints
 	mux.HandleFunc("POST /payment-methods", paymentMethodsHandler.CreatePaymentMethod)
@@ -429,9 +584,76 @@ func New(ctx context.Context, cfg *config.Config, logger *slog.Logger) (*Server,
 	mux.HandleFunc("PATCH /movements/{id}", movementsHandler.HandleUpdate)
 	mux.HandleFunc("DELETE /movements/{id}", movementsHandler.HandleDelete)
 	
+	// Movement trash: deleted movements stay restorable (participants, tags, attachments) until purged
+	mux.HandleFunc("GET /movements/trash", movementsHandler.HandleListTrash)
+	mux.HandleFunc("POST /movements/{id}/restore", movementsHandler.HandleRestore)
+	
+	// Movement history from audit logs, and revert to one of its versions (applied as a regular update)
+	mux.HandleFunc("GET /movements/{id}/history", movementsHandler.HandleHistory)
+	mux.HandleFunc("POST /movements/{id}/history/{versionId}/revert", movementsHandler.HandleRevert)
+	
+	// Refunds of part or all of a movement (recorded as a linked movement that counts negatively)
+	mux.HandleFunc("POST /movements/{id}/refund", movementsHandler.HandleRefund)
+	
 	// Debt consolidation (for Resume page)
 	mux.HandleFunc("GET /movements/debts/consolidate", movementsHandler.HandleGetDebtConsolidation)
 	
+	// Debt settlement ("Saldar"): propose minimal transfers, then create the chosen ones atomically
+	mux.HandleFunc("GET /movements/debts/settlements", movementsHandler.HandleGetSettlementPlan)
+	mux.HandleFunc("POST /movements/debts/settle", movementsHandler.HandleSettle)
+	
+	// CSV import: dry run (default) previews validation and duplicates, dry_run=false imports atomically
+	mux.HandleFunc("POST /movements/import", movementsHandler.HandleImport)
+	
+	// Tags (set through the tags field of create/update, filter with ?tag=)
+	mux.HandleFunc("GET /movements/tags", movementsHandler.HandleListTags)
+	
+	// Export: same filters as GET /movements, format=csv|xlsx|json, include= limits the record kinds
+	mux.HandleFunc("GET /movements/export", exportHandler.HandleExport)
+	
+	// Debt payment confirmation: the receiving household confirms or disputes payments to linked contacts
+	mux.HandleFunc("GET /movements/confirmations", movementsHandler.HandleListPendingConfirmations)
+	mux.HandleFunc("POST /movements/{id}/confirm", movementsHandler.HandleConfirm)
+	mux.HandleFunc("POST /movements/{id}/dispute", movementsHandler.HandleDispute)
+	
+	// Attachments: receipt photos and invoice PDFs (multipart field "file", max 10MB)
+	mux.HandleFunc("POST /movements/{id}/attachments", attachmentsHandler.HandleUpload)
+	mux.HandleFunc("GET /movements/{id}/attachments", attachmentsHandler.HandleList)
+	mux.HandleFunc("GET /movements/{id}/attachments/{attachmentId}", attachmentsHandler.HandleDownload)
+	mux.HandleFunc("DELETE /movements/{id}/attachments/{attachmentId}", attachmentsHandler.HandleDelete)
+	
+	// Categorization rules: fill in category, type, participants and event of new movements
+	mux.HandleFunc("POST /rules", rulesHandler.HandleCreate)
+	mux.HandleFunc("GET /rules", rulesHandler.HandleList)
+	mux.HandleFunc("POST /rules/test", rulesHandler.HandleTest)
+	mux.HandleFunc("PUT /rules/{id}", rulesHandler.HandleUpdate)
+	mux.HandleFunc("DELETE /rules/{id}", rulesHandler.HandleDelete)
+	mux.HandleFunc("GET /rules/{id}/test", rulesHandler.HandleTestSaved)
+	
+	// Events endpoints (trips, dinners, etc.)
+	mux.HandleFunc("POST /events", eventsHandler.HandleCreate)
+	mux.HandleFunc("GET /events", eventsHandler.HandleList)
+	mux.HandleFunc("GET /events/{id}", eventsHandler.HandleGet)
+	mux.HandleFunc("PATCH /events/{id}", eventsHandler.HandleUpdate)
+	mux.HandleFunc("DELETE /events/{id}", eventsHandler.HandleDelete)
+	mux.HandleFunc("GET /events/{id}/summary", eventsHandler.HandleGetSummary)
+	mux.HandleFunc("POST /events/{id}/close", eventsHandler.HandleClose)
+	
+	// Exchange rates endpoints (manual entry or CSV upload)
+	mux.HandleFunc("POST /fx-rates", fxHandler.HandleCreate)
+	mux.HandleFunc("POST /fx-rates/import", fxHandler.HandleImport)
+	mux.HandleFunc("GET /fx-rates", fxHandler.HandleList)
+	mux.HandleFunc("DELETE /fx-rates/{id}", fxHandler.HandleDelete)
+	
+	// Statement import endpoints (upload, then confirm or discard each draft)
+	mux.HandleFunc("POST /imports/statements", importerHandler.HandleImport)
+	mux.HandleFunc("GET /imports/drafts", importerHandler.HandleListDrafts)
+	mux.HandleFunc("POST /imports/drafts/{id}/confirm", importerHandler.HandleConfirm)
+	mux.HandleFunc("POST /imports/drafts/{id}/discard", importerHandler.HandleDiscard)
+	
+	// Notification email endpoint (upload .eml/mbox files; alerts land in the drafts queue)
+	mux.HandleFunc("POST /mail-ingest/messages", mailIngestHandler.HandleIngest)
+	
 	// Movement form config endpoint
 	mux.HandleFunc("GET /movement-form-config", formConfigHandler.GetFormConfig)
 
@@ -475,11 +697,25 @@ func New(ctx context.Context, cfg *config.Config, logger *slog.Logger) (*Server,
 	mux.HandleFunc("POST /credit-card-payments", ccPaymentsHandler.HandleCreate)
 	mux.HandleFunc("GET /credit-card-payments", ccPaymentsHandler.HandleList)
 	mux.HandleFunc("GET /credit-card-payments/{id}", ccPaymentsHandler.HandleGet)
+	mux.HandleFunc("PATCH /credit-card-payments/{id}", ccPaymentsHandler.HandleUpdate)
 	mux.HandleFunc("DELETE /credit-card-payments/{id}", ccPaymentsHandler.HandleDelete)
 
 	// Credit cards summary endpoints (for Tarjetas tab)
 	mux.HandleFunc("GET /credit-cards/summary", creditCardsHandler.HandleGetSummary)
 	mux.HandleFunc("GET /credit-cards/{id}/movements", creditCardsHandler.HandleGetCardMovements)
+	mux.HandleFunc("GET /credit-cards/{id}/installments", creditCardsHandler.HandleGetInstallments)
+	mux.HandleFunc("GET /credit-cards/{id}/statements", creditCardsHandler.HandleListStatements)
+	mux.HandleFunc("POST /credit-cards/{id}/statements", creditCardsHandler.HandleCloseStatement)
+	mux.HandleFunc("GET /credit-cards/{id}/statements/{statementId}", creditCardsHandler.HandleGetStatement)
+	mux.HandleFunc("POST /credit-cards/{id}/statements/{statementId}/reconcile", creditCardsHandler.HandleReconcileStatement)
+	mux.HandleFunc("DELETE /credit-cards/{id}/statements/{statementId}", creditCardsHandler.HandleReopenStatement)
+
+	// Net worth endpoints
+	mux.HandleFunc("GET /net-worth", netWorthHandler.HandleGetReport)
+	mux.HandleFunc("GET /net-worth/history", netWorthHandler.HandleListSnapshots)
+
+	// Cash-flow forecast endpoint
+	mux.HandleFunc("GET /forecast", forecastHandler.HandleGetForecast)
 	
 	// Admin audit log endpoints (TODO: add admin-only middleware)
 	mux.HandleFunc("GET /admin/audit-logs", auditHandler.ListAuditLogs)
@@ -501,7 +737,7 @@ func New(ctx context.Context, cfg *config.Config, logger *slog.Logger) (*Server,
 		if err != nil {
 			logger.Error("failed to create AI client, chat disabled", "error", err)
 		} else {
-			toolExecutor := ai.NewToolExecutor(movementsService, incomeService, budgetsService, categoriesRepo, categoryGroupsRepo, paymentMethodsRepo, householdRepo, accountsRepo)
+			toolExecutor := ai.NewToolExecutor(movementsService, incomeService, budgetsService, categoriesRepo, categoryGroupsRepo, paymentMethodsRepo, householdRepo, accountsRepo, rulesService)
 			chatService := ai.NewChatService(aiClient, toolExecutor, logger)
 			chatHandler := ai.NewHandler(chatService, authService, movementsService, householdRepo, cfg.SessionCookieName, logger)
 			mux.HandleFunc("POST /chat", chatHandler.HandleChat)
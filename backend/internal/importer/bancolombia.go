@@ -0,0 +1,209 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/blanquicet/conti/backend/internal/money"
+)
+
+// headerSearchRows is how far down an extract the table header may start
+// (exports begin with account details before the movements)
+const headerSearchRows = 30
+
+// ParseBancolombia reads a Bancolombia extract downloaded from the Sucursal
+// Virtual, either as CSV or Excel (.xlsx), for savings accounts or credit
+// cards. The movements table is found by its header (FECHA, DESCRIPCIÓN,
+// VALOR...) and rows that don't start with a date (totals, notes) are
+// skipped. Amounts keep the sign of the file: account extracts show money out
+// as negative, card extracts show charges as positive.
+func ParseBancolombia(data []byte) (*Statement, error) {
+	var rows [][]string
+	var err error
+	if isXLSX(data) {
+		rows, err = readXLSX(data)
+	} else {
+		rows, err = readStatementCSV(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	header := -1
+	var dateCol, descCol, amountCol, refCol int
+	for i := 0; i < len(rows) && i < headerSearchRows; i++ {
+		dateCol, descCol, amountCol, refCol = -1, -1, -1, -1
+		for j, cell := range rows[i] {
+			name := normalizeHeader(cell)
+			switch {
+			case dateCol < 0 && strings.HasPrefix(name, "FECHA"):
+				dateCol = j
+			case descCol < 0 && (strings.HasPrefix(name, "DESCRIPCION") || name == "CONCEPTO"):
+				descCol = j
+			case name == "VALOR" || (amountCol < 0 && strings.HasPrefix(name, "VALOR")):
+				amountCol = j
+			case refCol < 0 && (strings.HasPrefix(name, "DCTO") || strings.HasPrefix(name, "DOCUMENTO") ||
+				strings.HasPrefix(name, "REFERENCIA") || strings.Contains(name, "AUTORIZACION")):
+				refCol = j
+			}
+		}
+		if dateCol >= 0 && descCol >= 0 && amountCol >= 0 {
+			header = i
+			break
+		}
+	}
+	if header < 0 {
+		return nil, fmt.Errorf("%w: no FECHA, DESCRIPCIÓN and VALOR header found", ErrInvalidStatement)
+	}
+
+	stmt := &Statement{Source: SourceBancolombia}
+	for i, row := range rows[header+1:] {
+		line := header + i + 2
+		cell := func(j int) string {
+			if j < 0 || j >= len(row) {
+				return ""
+			}
+			return strings.TrimSpace(row[j])
+		}
+
+		date, ok := parseStatementDate(cell(dateCol))
+		if !ok {
+			continue
+		}
+		amount, err := ParseAmount(cell(amountCol))
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: invalid amount %q", ErrInvalidStatement, line, cell(amountCol))
+		}
+		if amount == 0 {
+			continue
+		}
+
+		stmt.Transactions = append(stmt.Transactions, Transaction{
+			Date:        date,
+			Description: strings.Join(strings.Fields(cell(descCol)), " "),
+			Amount:      amount,
+			ExternalID:  cell(refCol),
+		})
+	}
+
+	if len(stmt.Transactions) == 0 {
+		return nil, ErrNoTransactions
+	}
+	return stmt, nil
+}
+
+// readStatementCSV reads a CSV extract, guessing the delimiter. Extracts are
+// often Latin-1 encoded, so invalid UTF-8 is decoded as Latin-1.
+func readStatementCSV(data []byte) ([][]string, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	if !utf8.Valid(data) {
+		runes := make([]rune, len(data))
+		for i, b := range data {
+			runes[i] = rune(b)
+		}
+		data = []byte(string(runes))
+	}
+
+	firstLines := data[:min(len(data), 4096)]
+	comma := ','
+	best := bytes.Count(firstLines, []byte(","))
+	for _, d := range []rune{';', '\t'} {
+		if n := bytes.Count(firstLines, []byte(string(d))); n > best {
+			comma, best = d, n
+		}
+	}
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = comma
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidStatement, err)
+	}
+	return rows, nil
+}
+
+// accents maps upper-case Spanish accented letters to plain ones
+var accents = strings.NewReplacer("Á", "A", "É", "E", "Í", "I", "Ó", "O", "Ú", "U", "Ü", "U", "Ñ", "N")
+
+// normalizeHeader upper-cases a header and strips accents and punctuation
+// around it, so "Descripción" and "DESCRIPCION" match
+func normalizeHeader(s string) string {
+	return strings.Trim(accents.Replace(strings.ToUpper(strings.TrimSpace(s))), ".:*")
+}
+
+// statementDateLayouts are the date formats found in extracts
+var statementDateLayouts = []string{"2006/01/02", "02/01/2006", "2/01/2006", "2006-01-02", "02-01-2006", "20060102"}
+
+// parseStatementDate parses a date cell, including Excel serial dates
+func parseStatementDate(s string) (time.Time, bool) {
+	if s == "" {
+		return time.Time{}, false
+	}
+	for _, layout := range statementDateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	// Excel stores dates as days since 1899-12-30
+	if serial, err := strconv.ParseFloat(s, 64); err == nil && serial > 20000 && serial < 80000 {
+		return time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC).AddDate(0, 0, int(serial)), true
+	}
+	return time.Time{}, false
+}
+
+// ParseAmount parses amounts as banks write them, in either notation
+// ("-1.234.567,89" or "-1,234,567.89"), with or without a currency sign.
+// Parentheses mean negative.
+// A lone separator followed by exactly three digits is a thousands separator.
+func ParseAmount(s string) (money.Amount, error) {
+	s = strings.NewReplacer("$", "", " ", "", "\u00a0", "").Replace(s)
+	negative := false
+	if strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")") {
+		negative = true
+		s = s[1 : len(s)-1]
+	}
+
+	lastComma, lastDot := strings.LastIndex(s, ","), strings.LastIndex(s, ".")
+	decimal := ""
+	switch {
+	case lastComma >= 0 && lastDot >= 0:
+		decimal = "."
+		if lastComma > lastDot {
+			decimal = ","
+		}
+	case lastComma >= 0 || lastDot >= 0:
+		sep, last := ",", lastComma
+		if lastDot >= 0 {
+			sep, last = ".", lastDot
+		}
+		if strings.Count(s, sep) == 1 && len(s)-last-1 != 3 {
+			decimal = sep
+		}
+	}
+
+	switch decimal {
+	case ",":
+		s = strings.ReplaceAll(s, ".", "")
+		s = strings.Replace(s, ",", ".", 1)
+	case ".":
+		s = strings.ReplaceAll(s, ",", "")
+	default:
+		s = strings.NewReplacer(",", "", ".", "").Replace(s)
+	}
+
+	amount, err := money.Parse(s)
+	if err != nil {
+		return 0, err
+	}
+	if negative {
+		amount = -amount
+	}
+	return amount, nil
+}
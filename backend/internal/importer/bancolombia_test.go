@@ -0,0 +1,123 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/blanquicet/conti/backend/internal/money"
+)
+
+func TestParseBancolombia_CSV(t *testing.T) {
+	input := "CUENTA DE AHORROS,*1234\n" +
+		"DESDE,2026/03/01,HASTA,2026/03/31\n" +
+		"\n" +
+		"FECHA,DESCRIPCIÓN,SUCURSAL,DCTO.,VALOR,SALDO\n" +
+		"2026/03/05,COMPRA EN  EXITO COLINA,,000123,\"-85,000.00\",\"1,915,000.00\"\n" +
+		"2026/03/10,PAGO DE NOMI EMPRESA SAS,,,\"2,500,000.00\",\"4,415,000.00\"\n" +
+		"TOTAL,,,,\"2,415,000.00\",\n"
+
+	stmt, err := ParseBancolombia([]byte(input))
+	if err != nil {
+		t.Fatalf("ParseBancolombia() error = %v", err)
+	}
+	if len(stmt.Transactions) != 2 {
+		t.Fatalf("got %d transactions, want 2 (total row skipped)", len(stmt.Transactions))
+	}
+
+	first := stmt.Transactions[0]
+	if first.Amount != money.New(-85000) || first.ExternalID != "000123" || first.Description != "COMPRA EN EXITO COLINA" {
+		t.Errorf("first transaction = %+v", first)
+	}
+	if !first.Date.Equal(time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("first date = %v", first.Date)
+	}
+	if stmt.Transactions[1].Amount != money.New(2500000) {
+		t.Errorf("second amount = %v", stmt.Transactions[1].Amount)
+	}
+}
+
+func TestParseBancolombia_Latin1Semicolons(t *testing.T) {
+	// "Descripción" in ISO-8859-1, Colombian number format
+	input := []byte("Fecha;Descripci\xf3n;Valor original\n05/03/2026;RAPPI;$ 45.900,00\n")
+
+	stmt, err := ParseBancolombia(input)
+	if err != nil {
+		t.Fatalf("ParseBancolombia() error = %v", err)
+	}
+	if len(stmt.Transactions) != 1 || stmt.Transactions[0].Amount != money.New(45900) {
+		t.Errorf("transactions = %+v", stmt.Transactions)
+	}
+}
+
+func TestParseBancolombia_XLSX(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	files := map[string]string{
+		"xl/sharedStrings.xml": `<sst><si><t>FECHA</t></si><si><t>DESCRIPCIÓN</t></si><si><t>VALOR</t></si>` +
+			`<si><r><t>PAGO </t></r><r><t>PSE</t></r></si></sst>`,
+		"xl/worksheets/sheet1.xml": `<worksheet><sheetData>` +
+			`<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c><c r="D1" t="s"><v>2</v></c></row>` +
+			`<row r="2"><c r="A2"><v>46086</v></c><c r="B2" t="s"><v>3</v></c><c r="D2"><v>-120000.5</v></c></row>` +
+			`</sheetData></worksheet>`,
+	}
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(content))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	stmt, err := ParseBancolombia(buf.Bytes())
+	if err != nil {
+		t.Fatalf("ParseBancolombia() error = %v", err)
+	}
+	if len(stmt.Transactions) != 1 {
+		t.Fatalf("got %d transactions, want 1", len(stmt.Transactions))
+	}
+	txn := stmt.Transactions[0]
+	if txn.Description != "PAGO PSE" || txn.Amount != money.FromMinor(-12000050) {
+		t.Errorf("transaction = %+v", txn)
+	}
+	if !txn.Date.Equal(time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("date = %v, want 2026-03-05", txn.Date)
+	}
+}
+
+func TestParseBancolombia_NoHeader(t *testing.T) {
+	_, err := ParseBancolombia([]byte("a,b,c\n1,2,3\n"))
+	if !errors.Is(err, ErrInvalidStatement) {
+		t.Errorf("ParseBancolombia() error = %v, want ErrInvalidStatement", err)
+	}
+}
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		input string
+		want  money.Amount
+	}{
+		{"-1,234,567.89", money.FromMinor(-123456789)},
+		{"-1.234.567,89", money.FromMinor(-123456789)},
+		{"$ 12.500", money.New(12500)},
+		{"12,500", money.New(12500)},
+		{"45,9", money.FromMinor(4590)},
+		{"(1,000.00)", money.New(-1000)},
+		{"850000", money.New(850000)},
+	}
+	for _, tt := range tests {
+		got, err := ParseAmount(tt.input)
+		if err != nil {
+			t.Errorf("ParseAmount(%q) error = %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseAmount(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/blanquicet/conti/backend/internal/accounts"
+	"github.com/blanquicet/conti/backend/internal/auth"
+	"github.com/blanquicet/conti/backend/internal/fx"
+	"github.com/blanquicet/conti/backend/internal/movements"
+	"github.com/blanquicet/conti/backend/internal/paymentmethods"
+)
+
+// maxStatementSize caps the size of an uploaded statement
+const maxStatementSize = 10 << 20 // 10MB
+
+// Handler handles HTTP requests for statement imports
+type Handler struct {
+	service    Service
+	authSvc    *auth.Service
+	cookieName string
+	logger     *slog.Logger
+}
+
+// NewHandler creates a new statement import handler
+func NewHandler(service Service, authService *auth.Service, cookieName string, logger *slog.Logger) *Handler {
+	return &Handler{
+		service:    service,
+		authSvc:    authService,
+		cookieName: cookieName,
+		logger:     logger,
+	}
+}
+
+// getUserFromSession extracts user from session cookie
+func (h *Handler) getUserFromSession(r *http.Request) (*auth.User, error) {
+	cookie, err := r.Cookie(h.cookieName)
+	if err != nil {
+		return nil, err
+	}
+	return h.authSvc.GetUserBySession(r.Context(), cookie.Value)
+}
+
+// optionalString returns nil for empty form values
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// HandleImport handles POST /imports/statements (multipart form).
+// Fields: "file", "payment_method_id" or "account_id", and optionally
+// "source" (OFX or BANCOLOMBIA, detected when omitted) and "currency".
+func (h *Handler) HandleImport(w http.ResponseWriter, r *http.Request) {
+	user, err := h.getUserFromSession(r)
+	if err != nil {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxStatementSize)
+	if err := r.ParseMultipartForm(maxStatementSize); err != nil {
+		http.Error(w, "Invalid form or file too large (max 10MB)", http.StatusBadRequest)
+		return
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "Field 'file' is required", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	result, err := h.service.Import(r.Context(), user.ID, file, &ImportStatementInput{
+		Source:          Source(r.FormValue("source")),
+		Filename:        header.Filename,
+		PaymentMethodID: optionalString(r.FormValue("payment_method_id")),
+		AccountID:       optionalString(r.FormValue("account_id")),
+		Currency:        r.FormValue("currency"),
+	})
+	if err != nil {
+		h.writeError(w, "failed to import statement", err)
+		return
+	}
+
+	h.logger.Info("statement imported", "source", result.Source, "created", result.Created, "skipped", result.Skipped, "user_id", user.ID)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(result)
+}
+
+// HandleListDrafts handles GET /imports/drafts?status=PENDING&payment_method_id=...&account_id=...
+func (h *Handler) HandleListDrafts(w http.ResponseWriter, r *http.Request) {
+	user, err := h.getUserFromSession(r)
+	if err != nil {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	query := r.URL.Query()
+	filters := &ListDraftsFilters{
+		PaymentMethodID: optionalString(query.Get("payment_method_id")),
+		AccountID:       optionalString(query.Get("account_id")),
+	}
+	if s := query.Get("status"); s != "" {
+		status := DraftStatus(s)
+		switch status {
+		case DraftPending, DraftConfirmed, DraftDiscarded:
+		default:
+			http.Error(w, "Invalid status, use PENDING, CONFIRMED or DISCARDED", http.StatusBadRequest)
+			return
+		}
+		filters.Status = &status
+	}
+
+	drafts, err := h.service.ListDrafts(r.Context(), user.ID, filters)
+	if err != nil {
+		h.writeError(w, "failed to list import drafts", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"drafts": drafts,
+	})
+}
+
+// HandleConfirm handles POST /imports/drafts/{id}/confirm
+func (h *Handler) HandleConfirm(w http.ResponseWriter, r *http.Request) {
+	user, err := h.getUserFromSession(r)
+	if err != nil {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var input ConfirmDraftInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	movement, err := h.service.Confirm(r.Context(), user.ID, r.PathValue("id"), &input)
+	if err != nil {
+		h.writeError(w, "failed to confirm import draft", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(movement)
+}
+
+// HandleDiscard handles POST /imports/drafts/{id}/discard
+func (h *Handler) HandleDiscard(w http.ResponseWriter, r *http.Request) {
+	user, err := h.getUserFromSession(r)
+	if err != nil {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.service.Discard(r.Context(), user.ID, r.PathValue("id")); err != nil {
+		h.writeError(w, "failed to discard import draft", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeError maps service errors to HTTP status codes
+func (h *Handler) writeError(w http.ResponseWriter, msg string, err error) {
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		http.Error(w, "File too large (max 10MB)", http.StatusBadRequest)
+	case errors.Is(err, ErrDraftNotFound), errors.Is(err, movements.ErrEventNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, ErrNotAuthorized), errors.Is(err, movements.ErrNotAuthorized):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, ErrDraftNotPending), errors.Is(err, movements.ErrEventClosed):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, ErrInvalidSource), errors.Is(err, ErrTargetRequired),
+		errors.Is(err, ErrInvalidStatement), errors.Is(err, ErrNoTransactions),
+		errors.Is(err, ErrMovementTypeRequired), errors.Is(err, ErrCreditNeedsDebtPayment),
+		errors.Is(err, paymentmethods.ErrPaymentMethodNotFound), errors.Is(err, accounts.ErrAccountNotFound),
+		errors.Is(err, fx.ErrInvalidCurrency):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, movements.ErrInvalidMovementType), errors.Is(err, movements.ErrPayerRequired),
+		errors.Is(err, movements.ErrCounterpartyRequired), errors.Is(err, movements.ErrCounterpartyNotAllowed),
+		errors.Is(err, movements.ErrParticipantsRequired), errors.Is(err, movements.ErrParticipantsNotAllowed),
+		errors.Is(err, movements.ErrInvalidPercentageSum), errors.Is(err, movements.ErrInvalidParticipantAmounts),
+		errors.Is(err, movements.ErrCategoryRequired), errors.Is(err, movements.ErrPaymentMethodRequired),
+		errors.Is(err, movements.ErrExchangeRateNotFound):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		h.logger.Error(msg, "error", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	}
+}
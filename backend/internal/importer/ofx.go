@@ -0,0 +1,128 @@
+package importer
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/blanquicet/conti/backend/internal/money"
+)
+
+// isOFX reports whether data looks like an OFX/QFX file
+func isOFX(data []byte) bool {
+	head := bytes.ToUpper(data[:min(len(data), 1024)])
+	return bytes.Contains(head, []byte("OFXHEADER")) || bytes.Contains(head, []byte("<OFX>"))
+}
+
+// ParseOFX reads the transactions of an OFX or QFX statement. Both the SGML
+// flavour (1.x, where leaf elements are not closed) and XML (2.x) are read by
+// the same tokenizer, for bank (STMTRS) and credit card (CCSTMTRS) statements.
+// Amounts keep the OFX sign: negative is money out or a card charge.
+func ParseOFX(data []byte) (*Statement, error) {
+	start := bytes.Index(bytes.ToUpper(data), []byte("<OFX>"))
+	if start < 0 {
+		return nil, fmt.Errorf("%w: missing <OFX> element", ErrInvalidStatement)
+	}
+	body := string(data[start:])
+
+	stmt := &Statement{Source: SourceOFX}
+	var current map[string]string
+
+	finish := func() error {
+		if current == nil {
+			return nil
+		}
+		txn, err := ofxTransaction(current)
+		current = nil
+		if err != nil {
+			return err
+		}
+		stmt.Transactions = append(stmt.Transactions, txn)
+		return nil
+	}
+
+	for len(body) > 0 {
+		open := strings.IndexByte(body, '<')
+		if open < 0 {
+			break
+		}
+		end := strings.IndexByte(body[open:], '>')
+		if end < 0 {
+			return nil, fmt.Errorf("%w: unterminated tag", ErrInvalidStatement)
+		}
+		tag := strings.ToUpper(strings.TrimSpace(body[open+1 : open+end]))
+		body = body[open+end+1:]
+
+		// Leaf value: text up to the next tag
+		next := strings.IndexByte(body, '<')
+		if next < 0 {
+			next = len(body)
+		}
+		value := strings.TrimSpace(html.UnescapeString(body[:next]))
+
+		switch {
+		case tag == "STMTTRN":
+			if err := finish(); err != nil {
+				return nil, err
+			}
+			current = make(map[string]string)
+		case tag == "/STMTTRN", tag == "/BANKTRANLIST":
+			if err := finish(); err != nil {
+				return nil, err
+			}
+		case tag == "CURDEF":
+			stmt.Currency = strings.ToUpper(value)
+		case strings.HasPrefix(tag, "/"), strings.HasPrefix(tag, "?"), strings.HasPrefix(tag, "!"):
+		default:
+			if current != nil {
+				current[tag] = value
+			}
+		}
+	}
+	if err := finish(); err != nil {
+		return nil, err
+	}
+
+	if len(stmt.Transactions) == 0 {
+		return nil, ErrNoTransactions
+	}
+	return stmt, nil
+}
+
+// ofxTransaction builds a transaction from the elements of a STMTTRN
+func ofxTransaction(fields map[string]string) (Transaction, error) {
+	fitID := fields["FITID"]
+
+	posted := fields["DTPOSTED"]
+	if len(posted) < 8 {
+		return Transaction{}, fmt.Errorf("%w: transaction %q has an invalid DTPOSTED %q", ErrInvalidStatement, fitID, posted)
+	}
+	date, err := time.Parse("20060102", posted[:8])
+	if err != nil {
+		return Transaction{}, fmt.Errorf("%w: transaction %q has an invalid DTPOSTED %q", ErrInvalidStatement, fitID, posted)
+	}
+
+	// Some banks write the decimal separator as a comma
+	amount, err := money.Parse(strings.ReplaceAll(fields["TRNAMT"], ",", "."))
+	if err != nil {
+		return Transaction{}, fmt.Errorf("%w: transaction %q has an invalid TRNAMT %q", ErrInvalidStatement, fitID, fields["TRNAMT"])
+	}
+
+	description := fields["NAME"]
+	if memo := fields["MEMO"]; memo != "" && !strings.EqualFold(memo, description) {
+		if description == "" {
+			description = memo
+		} else {
+			description += " - " + memo
+		}
+	}
+
+	return Transaction{
+		Date:        date,
+		Description: description,
+		Amount:      amount,
+		ExternalID:  fitID,
+	}, nil
+}
@@ -0,0 +1,130 @@
+package importer
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/blanquicet/conti/backend/internal/money"
+)
+
+const sgmlStatement = `OFXHEADER:100
+DATA:OFXSGML
+VERSION:102
+ENCODING:USASCII
+
+<OFX>
+<BANKMSGSRSV1>
+<STMTTRNRS>
+<STMTRS>
+<CURDEF>cop
+<BANKTRANLIST>
+<DTSTART>20260301
+<DTEND>20260331
+<STMTTRN>
+<TRNTYPE>DEBIT
+<DTPOSTED>20260305120000[-5:COT]
+<TRNAMT>-85000.00
+<FITID>202603050001
+<NAME>EXITO COLINA
+<MEMO>COMPRA POS
+</STMTTRN>
+<STMTTRN>
+<TRNTYPE>CREDIT
+<DTPOSTED>20260310
+<TRNAMT>1500000,50
+<FITID>202603100002
+<MEMO>PAGO NOMINA
+</STMTTRN>
+</BANKTRANLIST>
+</STMTRS>
+</STMTTRNRS>
+</BANKMSGSRSV1>
+</OFX>
+`
+
+const xmlStatement = `<?xml version="1.0" encoding="UTF-8"?>
+<?OFX OFXHEADER="200" VERSION="220"?>
+<OFX>
+  <CREDITCARDMSGSRSV1><CCSTMTTRNRS><CCSTMTRS>
+    <CURDEF>USD</CURDEF>
+    <BANKTRANLIST>
+      <STMTTRN>
+        <TRNTYPE>DEBIT</TRNTYPE>
+        <DTPOSTED>20260402</DTPOSTED>
+        <TRNAMT>-12.99</TRNAMT>
+        <FITID>A1</FITID>
+        <NAME>NETFLIX &amp; CO</NAME>
+      </STMTTRN>
+    </BANKTRANLIST>
+  </CCSTMTRS></CCSTMTTRNRS></CREDITCARDMSGSRSV1>
+</OFX>
+`
+
+func TestParseOFX_SGML(t *testing.T) {
+	stmt, err := ParseOFX([]byte(sgmlStatement))
+	if err != nil {
+		t.Fatalf("ParseOFX() error = %v", err)
+	}
+	if stmt.Currency != "COP" {
+		t.Errorf("currency = %q, want COP", stmt.Currency)
+	}
+	if len(stmt.Transactions) != 2 {
+		t.Fatalf("got %d transactions, want 2", len(stmt.Transactions))
+	}
+
+	first := stmt.Transactions[0]
+	if first.Amount != money.New(-85000) || first.ExternalID != "202603050001" {
+		t.Errorf("first transaction = %+v", first)
+	}
+	if first.Description != "EXITO COLINA - COMPRA POS" {
+		t.Errorf("first description = %q", first.Description)
+	}
+	if !first.Date.Equal(time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("first date = %v", first.Date)
+	}
+
+	second := stmt.Transactions[1]
+	if second.Amount != money.FromMinor(150000050) || second.Description != "PAGO NOMINA" {
+		t.Errorf("second transaction = %+v", second)
+	}
+}
+
+func TestParseOFX_XML(t *testing.T) {
+	stmt, err := ParseOFX([]byte(xmlStatement))
+	if err != nil {
+		t.Fatalf("ParseOFX() error = %v", err)
+	}
+	if stmt.Currency != "USD" || len(stmt.Transactions) != 1 {
+		t.Fatalf("statement = %+v", stmt)
+	}
+	txn := stmt.Transactions[0]
+	if txn.Amount != money.FromMinor(-1299) || txn.Description != "NETFLIX & CO" {
+		t.Errorf("transaction = %+v", txn)
+	}
+}
+
+func TestParseOFX_Invalid(t *testing.T) {
+	if _, err := ParseOFX([]byte("not a statement")); !errors.Is(err, ErrInvalidStatement) {
+		t.Errorf("ParseOFX() error = %v, want ErrInvalidStatement", err)
+	}
+	if _, err := ParseOFX([]byte("<OFX><BANKTRANLIST></BANKTRANLIST></OFX>")); !errors.Is(err, ErrNoTransactions) {
+		t.Errorf("ParseOFX() error = %v, want ErrNoTransactions", err)
+	}
+	bad := "<OFX><STMTTRN><DTPOSTED>2026<TRNAMT>-1</STMTTRN></OFX>"
+	if _, err := ParseOFX([]byte(bad)); !errors.Is(err, ErrInvalidStatement) {
+		t.Errorf("ParseOFX() error = %v, want ErrInvalidStatement", err)
+	}
+}
+
+func TestDetectSource(t *testing.T) {
+	if got := DetectSource([]byte(sgmlStatement), "extracto.txt"); got != SourceOFX {
+		t.Errorf("DetectSource(sgml) = %s, want OFX", got)
+	}
+	if got := DetectSource([]byte("FECHA,DESCRIPCIÓN,VALOR\n"), "movimientos.QFX"); got != SourceOFX {
+		t.Errorf("DetectSource(.QFX) = %s, want OFX", got)
+	}
+	if got := DetectSource([]byte("FECHA,DESCRIPCIÓN,VALOR\n"), "movimientos.csv"); got != SourceBancolombia {
+		t.Errorf("DetectSource(csv) = %s, want BANCOLOMBIA", got)
+	}
+}
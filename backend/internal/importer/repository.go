@@ -0,0 +1,177 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// repository implements Repository using PostgreSQL
+type repository struct {
+	pool *pgxpool.Pool
+}
+
+// NewRepository creates a new import drafts repository
+func NewRepository(pool *pgxpool.Pool) Repository {
+	return &repository{pool: pool}
+}
+
+const draftColumns = `d.id, d.household_id, d.source, d.payment_method_id, pm.name, d.account_id, a.name,
+	       d.transaction_date, d.description, d.amount, d.currency, d.direction, d.external_id,
+	       d.fingerprint, d.status, d.movement_id, d.created_by, d.created_at, d.updated_at`
+
+const draftJoins = `
+	LEFT JOIN payment_methods pm ON pm.id = d.payment_method_id
+	LEFT JOIN accounts a ON a.id = d.account_id`
+
+// scanDraft scans a row selected with draftColumns
+func scanDraft(row pgx.Row) (*Draft, error) {
+	var d Draft
+	err := row.Scan(
+		&d.ID,
+		&d.HouseholdID,
+		&d.Source,
+		&d.PaymentMethodID,
+		&d.PaymentMethodName,
+		&d.AccountID,
+		&d.AccountName,
+		&d.TransactionDate,
+		&d.Description,
+		&d.Amount,
+		&d.Currency,
+		&d.Direction,
+		&d.ExternalID,
+		&d.Fingerprint,
+		&d.Status,
+		&d.MovementID,
+		&d.CreatedBy,
+		&d.CreatedAt,
+		&d.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// CreateDrafts stores drafts in a single transaction, skipping fingerprints
+// the household already has
+func (r *repository) CreateDrafts(ctx context.Context, drafts []*Draft) ([]*Draft, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var created []*Draft
+	for _, d := range drafts {
+		var id string
+		err := tx.QueryRow(ctx, `
+			INSERT INTO import_drafts (
+				household_id, source, payment_method_id, account_id, transaction_date,
+				description, amount, currency, direction, external_id, fingerprint, created_by
+			)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+			ON CONFLICT (household_id, fingerprint) DO NOTHING
+			RETURNING id
+		`, d.HouseholdID, d.Source, d.PaymentMethodID, d.AccountID, d.TransactionDate,
+			d.Description, d.Amount, d.Currency, d.Direction, d.ExternalID, d.Fingerprint, d.CreatedBy,
+		).Scan(&id)
+		if errors.Is(err, pgx.ErrNoRows) {
+			continue // Already imported
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		draft, err := scanDraft(tx.QueryRow(ctx, `
+			SELECT `+draftColumns+`
+			FROM import_drafts d`+draftJoins+`
+			WHERE d.id = $1
+		`, id))
+		if err != nil {
+			return nil, err
+		}
+		created = append(created, draft)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// GetByID retrieves a draft by ID
+func (r *repository) GetByID(ctx context.Context, id string) (*Draft, error) {
+	draft, err := scanDraft(r.pool.QueryRow(ctx, `
+		SELECT `+draftColumns+`
+		FROM import_drafts d`+draftJoins+`
+		WHERE d.id = $1
+	`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrDraftNotFound
+	}
+	return draft, err
+}
+
+// ListByHousehold lists a household's drafts, newest transactions first
+func (r *repository) ListByHousehold(ctx context.Context, householdID string, filters *ListDraftsFilters) ([]*Draft, error) {
+	status := DraftPending
+	if filters != nil && filters.Status != nil {
+		status = *filters.Status
+	}
+	conditions := []string{"d.household_id = $1", "d.status = $2"}
+	args := []interface{}{householdID, status}
+
+	if filters != nil {
+		if filters.PaymentMethodID != nil {
+			args = append(args, *filters.PaymentMethodID)
+			conditions = append(conditions, fmt.Sprintf("d.payment_method_id = $%d", len(args)))
+		}
+		if filters.AccountID != nil {
+			args = append(args, *filters.AccountID)
+			conditions = append(conditions, fmt.Sprintf("d.account_id = $%d", len(args)))
+		}
+	}
+
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+draftColumns+`
+		FROM import_drafts d`+draftJoins+`
+		WHERE `+strings.Join(conditions, " AND ")+`
+		ORDER BY d.transaction_date DESC, d.created_at DESC
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var drafts []*Draft
+	for rows.Next() {
+		draft, err := scanDraft(rows)
+		if err != nil {
+			return nil, err
+		}
+		drafts = append(drafts, draft)
+	}
+	return drafts, rows.Err()
+}
+
+// SetStatus moves a pending draft out of the review queue
+func (r *repository) SetStatus(ctx context.Context, id string, status DraftStatus, movementID *string) error {
+	result, err := r.pool.Exec(ctx, `
+		UPDATE import_drafts
+		SET status = $2, movement_id = $3, updated_at = NOW()
+		WHERE id = $1 AND status = 'PENDING'
+	`, id, status, movementID)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrDraftNotPending
+	}
+	return nil
+}
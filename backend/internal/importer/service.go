@@ -0,0 +1,413 @@
+package importer
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"strings"
+
+	"github.com/blanquicet/conti/backend/internal/accounts"
+	"github.com/blanquicet/conti/backend/internal/audit"
+	"github.com/blanquicet/conti/backend/internal/fx"
+	"github.com/blanquicet/conti/backend/internal/households"
+	"github.com/blanquicet/conti/backend/internal/movements"
+	"github.com/blanquicet/conti/backend/internal/paymentmethods"
+)
+
+// service implements Service interface
+type service struct {
+	repo              Repository
+	householdsRepo    households.HouseholdRepository
+	paymentMethodRepo paymentmethods.Repository
+	accountsRepo      accounts.Repository
+	movementsService  movements.Service
+	auditService      audit.Service
+	logger            *slog.Logger
+}
+
+// NewService creates a new statement import service
+func NewService(
+	repo Repository,
+	householdsRepo households.HouseholdRepository,
+	paymentMethodRepo paymentmethods.Repository,
+	accountsRepo accounts.Repository,
+	movementsService movements.Service,
+	auditService audit.Service,
+	logger *slog.Logger,
+) Service {
+	return &service{
+		repo:              repo,
+		householdsRepo:    householdsRepo,
+		paymentMethodRepo: paymentMethodRepo,
+		accountsRepo:      accountsRepo,
+		movementsService:  movementsService,
+		auditService:      auditService,
+		logger:            logger,
+	}
+}
+
+// DetectSource guesses the format of a statement from its content and name
+func DetectSource(data []byte, filename string) Source {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".ofx", ".qfx":
+		return SourceOFX
+	}
+	if isOFX(data) {
+		return SourceOFX
+	}
+	return SourceBancolombia
+}
+
+// ParseStatement parses a statement file in the given format
+func ParseStatement(source Source, data []byte) (*Statement, error) {
+	switch source {
+	case SourceOFX:
+		return ParseOFX(data)
+	case SourceBancolombia:
+		return ParseBancolombia(data)
+	default:
+		return nil, ErrInvalidSource
+	}
+}
+
+// fingerprint identifies a transaction of a payment method or account across
+// re-imports of overlapping statements. occurrence tells apart identical
+// transactions in the same file (two equal purchases on the same day).
+func fingerprint(targetID string, txn Transaction, occurrence int) string {
+	ref := txn.ExternalID
+	if ref == "" {
+		ref = strings.ToLower(strings.Join(strings.Fields(txn.Description), " "))
+	}
+	key := fmt.Sprintf("%s|%s|%d|%s|%d", targetID, txn.Date.Format("2006-01-02"), txn.Amount, ref, occurrence)
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// Import parses a statement and queues its transactions as drafts of the
+// given payment method or account. Transactions imported before (from an
+// overlapping statement) are skipped.
+func (s *service) Import(ctx context.Context, userID string, r io.Reader, input *ImportStatementInput) (*ImportResult, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	householdID, err := s.householdsRepo.GetUserHouseholdID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The statement must be for one of the household's cards or accounts
+	var targetID string
+	creditCard := false
+	if input.PaymentMethodID != nil {
+		pm, err := s.paymentMethodRepo.GetByID(ctx, *input.PaymentMethodID)
+		if err != nil {
+			return nil, err
+		}
+		if pm.HouseholdID != householdID {
+			return nil, ErrNotAuthorized
+		}
+		targetID = pm.ID
+		creditCard = pm.Type == paymentmethods.TypeCreditCard
+	} else {
+		account, err := s.accountsRepo.GetByID(ctx, *input.AccountID)
+		if err != nil {
+			return nil, err
+		}
+		if account.HouseholdID != householdID {
+			return nil, ErrNotAuthorized
+		}
+		targetID = account.ID
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	source := input.Source
+	if source == "" {
+		source = DetectSource(data, input.Filename)
+	}
+	stmt, err := ParseStatement(source, data)
+	if err != nil {
+		return nil, err
+	}
+
+	currency := stmt.Currency
+	if currency == "" {
+		currency = input.Currency
+	}
+	if currency == "" {
+		household, err := s.householdsRepo.GetByID(ctx, householdID)
+		if err != nil {
+			return nil, err
+		}
+		currency = household.Currency
+		if currency == "" {
+			currency = "COP"
+		}
+	}
+	if currency, err = fx.NormalizeCurrency(currency); err != nil {
+		return nil, err
+	}
+
+	// Bancolombia card extracts list charges as positive amounts; everything
+	// else uses negative for money out
+	chargesArePositive := source == SourceBancolombia && creditCard
+
+	drafts := make([]*Draft, 0, len(stmt.Transactions))
+	occurrences := make(map[string]int)
+	for _, txn := range stmt.Transactions {
+		direction := DirectionCredit
+		if (txn.Amount < 0) != chargesArePositive {
+			direction = DirectionDebit
+		}
+
+		key := fmt.Sprintf("%s|%d|%s|%s", txn.Date.Format("2006-01-02"), txn.Amount, txn.ExternalID, txn.Description)
+		occurrences[key]++
+
+		draft := &Draft{
+			HouseholdID:     householdID,
+			Source:          source,
+			PaymentMethodID: input.PaymentMethodID,
+			AccountID:       input.AccountID,
+			TransactionDate: txn.Date,
+			Description:     txn.Description,
+			Amount:          txn.Amount.Abs(),
+			Currency:        currency,
+			Direction:       direction,
+			Fingerprint:     fingerprint(targetID, txn, occurrences[key]),
+			CreatedBy:       &userID,
+		}
+		if draft.Description == "" {
+			draft.Description = string(source)
+		}
+		if txn.ExternalID != "" {
+			externalID := txn.ExternalID
+			draft.ExternalID = &externalID
+		}
+		drafts = append(drafts, draft)
+	}
+
+	created, err := s.repo.CreateDrafts(ctx, drafts)
+	if err != nil {
+		s.auditService.LogAsync(ctx, &audit.LogInput{
+			UserID:       audit.StringPtr(userID),
+			Action:       audit.ActionImportDraftsCreated,
+			ResourceType: "import_draft",
+			HouseholdID:  audit.StringPtr(householdID),
+			Success:      false,
+			ErrorMessage: audit.StringPtr(err.Error()),
+		})
+		return nil, err
+	}
+
+	s.auditService.LogAsync(ctx, &audit.LogInput{
+		UserID:       audit.StringPtr(userID),
+		Action:       audit.ActionImportDraftsCreated,
+		ResourceType: "import_draft",
+		HouseholdID:  audit.StringPtr(householdID),
+		Metadata: map[string]interface{}{
+			"source":            source,
+			"payment_method_id": input.PaymentMethodID,
+			"account_id":        input.AccountID,
+			"total":             len(drafts),
+			"created":           len(created),
+		},
+		Success: true,
+	})
+
+	return &ImportResult{
+		Source:  source,
+		Total:   len(drafts),
+		Created: len(created),
+		Skipped: len(drafts) - len(created),
+		Drafts:  created,
+	}, nil
+}
+
+// ListDrafts lists the review queue of the user's household
+func (s *service) ListDrafts(ctx context.Context, userID string, filters *ListDraftsFilters) ([]*Draft, error) {
+	householdID, err := s.householdsRepo.GetUserHouseholdID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.ListByHousehold(ctx, householdID, filters)
+}
+
+// getPendingDraft loads a draft of the user's household that is still in the queue
+func (s *service) getPendingDraft(ctx context.Context, householdID, id string) (*Draft, error) {
+	draft, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if draft.HouseholdID != householdID {
+		return nil, ErrNotAuthorized
+	}
+	if draft.Status != DraftPending {
+		return nil, ErrDraftNotPending
+	}
+	return draft, nil
+}
+
+// linkedDebitCard returns the active debit card linked to an account, if any
+func (s *service) linkedDebitCard(ctx context.Context, householdID, accountID string) (*string, error) {
+	methods, err := s.paymentMethodRepo.ListByHousehold(ctx, householdID)
+	if err != nil {
+		return nil, err
+	}
+	for _, pm := range methods {
+		if pm.IsActive && pm.Type == paymentmethods.TypeDebitCard &&
+			pm.LinkedAccountID != nil && *pm.LinkedAccountID == accountID {
+			return &pm.ID, nil
+		}
+	}
+	return nil, nil
+}
+
+// Confirm turns a draft into a movement with the type and category chosen by
+// the user. Outgoing transactions are paid by the user (unless another payer
+// is given) with the draft's card, or the debit card of the draft's account.
+// Incoming transactions can only be debt payments received by the user into
+// the draft's account.
+func (s *service) Confirm(ctx context.Context, userID, id string, input *ConfirmDraftInput) (*movements.Movement, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	householdID, err := s.householdsRepo.GetUserHouseholdID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	draft, err := s.getPendingDraft(ctx, householdID, id)
+	if err != nil {
+		return nil, err
+	}
+	if draft.Direction == DirectionCredit && input.Type != movements.TypeDebtPayment {
+		return nil, ErrCreditNeedsDebtPayment
+	}
+
+	movementInput := &movements.CreateMovementInput{
+		Type:                  input.Type,
+		Description:           draft.Description,
+		Amount:                draft.Amount,
+		CategoryID:            input.CategoryID,
+		MovementDate:          draft.TransactionDate,
+		Currency:              draft.Currency,
+		PayerUserID:           input.PayerUserID,
+		PayerContactID:        input.PayerContactID,
+		CounterpartyUserID:    input.CounterpartyUserID,
+		CounterpartyContactID: input.CounterpartyContactID,
+		PaymentMethodID:       input.PaymentMethodID,
+		ReceiverAccountID:     input.ReceiverAccountID,
+		Participants:          input.Participants,
+		EventID:               input.EventID,
+	}
+	if input.Description != nil && strings.TrimSpace(*input.Description) != "" {
+		movementInput.Description = strings.TrimSpace(*input.Description)
+	}
+
+	if draft.Direction == DirectionDebit {
+		if movementInput.PayerUserID == nil && movementInput.PayerContactID == nil {
+			movementInput.PayerUserID = &userID
+		}
+		if movementInput.PaymentMethodID == nil {
+			if draft.PaymentMethodID != nil {
+				movementInput.PaymentMethodID = draft.PaymentMethodID
+			} else if movementInput.PaymentMethodID, err = s.linkedDebitCard(ctx, householdID, *draft.AccountID); err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		if movementInput.CounterpartyUserID == nil && movementInput.CounterpartyContactID == nil {
+			movementInput.CounterpartyUserID = &userID
+		}
+		if movementInput.ReceiverAccountID == nil {
+			movementInput.ReceiverAccountID = draft.AccountID
+		}
+	}
+
+	movement, err := s.movementsService.Create(ctx, userID, movementInput)
+	if err != nil {
+		s.auditService.LogAsync(ctx, &audit.LogInput{
+			UserID:       audit.StringPtr(userID),
+			Action:       audit.ActionImportDraftConfirmed,
+			ResourceType: "import_draft",
+			ResourceID:   audit.StringPtr(id),
+			HouseholdID:  audit.StringPtr(householdID),
+			Success:      false,
+			ErrorMessage: audit.StringPtr(err.Error()),
+		})
+		return nil, err
+	}
+
+	if err := s.repo.SetStatus(ctx, id, DraftConfirmed, &movement.ID); err != nil {
+		// Reviewed by someone else in the meantime: don't keep a second
+		// movement, not even in the trash
+		if delErr := s.movementsService.Discard(ctx, userID, movement.ID); delErr != nil {
+			s.logger.Error("failed to delete movement of already reviewed draft",
+				"draft_id", id, "movement_id", movement.ID, "error", delErr)
+		}
+		return nil, err
+	}
+
+	s.auditService.LogAsync(ctx, &audit.LogInput{
+		UserID:       audit.StringPtr(userID),
+		Action:       audit.ActionImportDraftConfirmed,
+		ResourceType: "import_draft",
+		ResourceID:   audit.StringPtr(id),
+		HouseholdID:  audit.StringPtr(householdID),
+		OldValues:    audit.StructToMap(draft),
+		Metadata: map[string]interface{}{
+			"movement_id": movement.ID,
+		},
+		Success: true,
+	})
+
+	return movement, nil
+}
+
+// Discard removes a draft from the queue. It stays stored, so importing an
+// overlapping statement doesn't bring it back.
+func (s *service) Discard(ctx context.Context, userID, id string) error {
+	householdID, err := s.householdsRepo.GetUserHouseholdID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	draft, err := s.getPendingDraft(ctx, householdID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.SetStatus(ctx, id, DraftDiscarded, nil); err != nil {
+		s.auditService.LogAsync(ctx, &audit.LogInput{
+			UserID:       audit.StringPtr(userID),
+			Action:       audit.ActionImportDraftDiscarded,
+			ResourceType: "import_draft",
+			ResourceID:   audit.StringPtr(id),
+			HouseholdID:  audit.StringPtr(householdID),
+			Success:      false,
+			ErrorMessage: audit.StringPtr(err.Error()),
+		})
+		return err
+	}
+
+	s.auditService.LogAsync(ctx, &audit.LogInput{
+		UserID:       audit.StringPtr(userID),
+		Action:       audit.ActionImportDraftDiscarded,
+		ResourceType: "import_draft",
+		ResourceID:   audit.StringPtr(id),
+		HouseholdID:  audit.StringPtr(householdID),
+		OldValues:    audit.StructToMap(draft),
+		Success:      true,
+	})
+
+	return nil
+}
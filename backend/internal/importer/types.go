@@ -0,0 +1,190 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/blanquicet/conti/backend/internal/money"
+	"github.com/blanquicet/conti/backend/internal/movements"
+)
+
+// Errors for statement imports
+var (
+	ErrDraftNotFound          = errors.New("import draft not found")
+	ErrDraftNotPending        = errors.New("import draft was already reviewed")
+	ErrNotAuthorized          = errors.New("not authorized")
+	ErrInvalidSource          = errors.New("invalid import source")
+	ErrTargetRequired         = errors.New("exactly one of payment_method_id or account_id is required")
+	ErrInvalidStatement       = errors.New("invalid statement file")
+	ErrNoTransactions         = errors.New("statement has no transactions")
+	ErrMovementTypeRequired   = errors.New("movement type is required")
+	ErrCreditNeedsDebtPayment = errors.New("incoming transactions can only be confirmed as DEBT_PAYMENT")
+)
+
+// Source identifies the statement format a draft was read from
+type Source string
+
+const (
+	SourceOFX         Source = "OFX"         // OFX/QFX (SGML 1.x or XML 2.x)
+	SourceBancolombia Source = "BANCOLOMBIA" // Bancolombia CSV or Excel extract
+	SourceEmail       Source = "EMAIL"       // Bank notification email (see package mailingest)
+)
+
+// Validate checks if the source is valid
+func (s Source) Validate() error {
+	switch s {
+	case SourceOFX, SourceBancolombia, SourceEmail:
+		return nil
+	default:
+		return ErrInvalidSource
+	}
+}
+
+// Direction tells whether a transaction took money out or brought it in
+type Direction string
+
+const (
+	DirectionDebit  Direction = "DEBIT"  // Money out of the account, or a charge on the card
+	DirectionCredit Direction = "CREDIT" // Money into the account, or a payment/refund on the card
+)
+
+// DraftStatus represents where a draft is in the review queue
+type DraftStatus string
+
+const (
+	DraftPending   DraftStatus = "PENDING"   // Waiting for review
+	DraftConfirmed DraftStatus = "CONFIRMED" // Turned into a movement
+	DraftDiscarded DraftStatus = "DISCARDED" // Ignored (kept so re-imports skip it)
+)
+
+// Transaction is a statement line as read from a file
+type Transaction struct {
+	Date        time.Time
+	Description string
+	Amount      money.Amount // Signed as in the file
+	ExternalID  string       // Bank transaction ID, if the file has one
+}
+
+// Statement is the content of a parsed statement file
+type Statement struct {
+	Source       Source
+	Currency     string // Empty when the file doesn't say
+	Transactions []Transaction
+}
+
+// Draft is a statement transaction awaiting review
+type Draft struct {
+	ID          string `json:"id"`
+	HouseholdID string `json:"household_id"`
+	Source      Source `json:"source"`
+
+	// What the statement is for (exactly one)
+	PaymentMethodID   *string `json:"payment_method_id,omitempty"`
+	PaymentMethodName *string `json:"payment_method_name,omitempty"` // Populated from join
+	AccountID         *string `json:"account_id,omitempty"`
+	AccountName       *string `json:"account_name,omitempty"` // Populated from join
+
+	TransactionDate time.Time    `json:"transaction_date"`
+	Description     string       `json:"description"`
+	Amount          money.Amount `json:"amount"` // Always positive, see Direction
+	Currency        string       `json:"currency"`
+	Direction       Direction    `json:"direction"`
+	ExternalID      *string      `json:"external_id,omitempty"`
+	Fingerprint     string       `json:"-"`
+
+	Status     DraftStatus `json:"status"`
+	MovementID *string     `json:"movement_id,omitempty"` // Set once confirmed
+
+	CreatedBy *string   `json:"created_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ImportStatementInput describes an uploaded statement
+type ImportStatementInput struct {
+	Source          Source  // Detected from the file when empty
+	Filename        string  // Used to detect the source
+	PaymentMethodID *string // Card or payment method the statement is for
+	AccountID       *string // Or account the statement is for
+	Currency        string  // For files that don't state it (defaults to the household currency)
+}
+
+// Validate validates the import input
+func (i *ImportStatementInput) Validate() error {
+	if (i.PaymentMethodID == nil) == (i.AccountID == nil) {
+		return ErrTargetRequired
+	}
+	if i.Source != "" {
+		return i.Source.Validate()
+	}
+	return nil
+}
+
+// ImportResult summarizes an uploaded statement
+type ImportResult struct {
+	Source  Source   `json:"source"`
+	Total   int      `json:"total"`   // Transactions in the file
+	Created int      `json:"created"` // New drafts
+	Skipped int      `json:"skipped"` // Already imported before
+	Drafts  []*Draft `json:"drafts"`
+}
+
+// ConfirmDraftInput is what the user decides when confirming a draft. Amount,
+// date and currency come from the draft.
+type ConfirmDraftInput struct {
+	Type        movements.MovementType `json:"type"`
+	CategoryID  *string                `json:"category_id,omitempty"`
+	Description *string                `json:"description,omitempty"` // Defaults to the statement description
+
+	// Payer defaults to the user confirming the draft
+	PayerUserID    *string `json:"payer_user_id,omitempty"`
+	PayerContactID *string `json:"payer_contact_id,omitempty"`
+
+	// Defaults to the draft's payment method, or to a debit card linked to the
+	// draft's account
+	PaymentMethodID *string `json:"payment_method_id,omitempty"`
+
+	// DEBT_PAYMENT only
+	CounterpartyUserID    *string `json:"counterparty_user_id,omitempty"`
+	CounterpartyContactID *string `json:"counterparty_contact_id,omitempty"`
+	ReceiverAccountID     *string `json:"receiver_account_id,omitempty"` // Defaults to the draft's account for incoming transactions
+
+	Participants []movements.ParticipantInput `json:"participants,omitempty"`
+	EventID      *string                      `json:"event_id,omitempty"`
+}
+
+// Validate validates the confirmation input
+func (i *ConfirmDraftInput) Validate() error {
+	if i.Type == "" {
+		return ErrMovementTypeRequired
+	}
+	return i.Type.Validate()
+}
+
+// ListDraftsFilters filters the review queue
+type ListDraftsFilters struct {
+	Status          *DraftStatus // Defaults to PENDING
+	PaymentMethodID *string
+	AccountID       *string
+}
+
+// Repository defines the interface for import draft data access
+type Repository interface {
+	// CreateDrafts stores drafts in a single transaction, skipping the ones
+	// whose fingerprint was already imported. Returns the created drafts.
+	CreateDrafts(ctx context.Context, drafts []*Draft) ([]*Draft, error)
+	GetByID(ctx context.Context, id string) (*Draft, error)
+	ListByHousehold(ctx context.Context, householdID string, filters *ListDraftsFilters) ([]*Draft, error)
+	// SetStatus moves a pending draft out of the queue (ErrDraftNotPending otherwise)
+	SetStatus(ctx context.Context, id string, status DraftStatus, movementID *string) error
+}
+
+// Service defines the interface for statement import business logic
+type Service interface {
+	Import(ctx context.Context, userID string, r io.Reader, input *ImportStatementInput) (*ImportResult, error)
+	ListDrafts(ctx context.Context, userID string, filters *ListDraftsFilters) ([]*Draft, error)
+	Confirm(ctx context.Context, userID, id string, input *ConfirmDraftInput) (*movements.Movement, error)
+	Discard(ctx context.Context, userID, id string) error
+}
@@ -0,0 +1,150 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// isXLSX reports whether data is a zip archive, as .xlsx files are
+func isXLSX(data []byte) bool {
+	return bytes.HasPrefix(data, []byte("PK\x03\x04"))
+}
+
+// xlsxCell is a <c> element of a worksheet
+type xlsxCell struct {
+	Ref    string `xml:"r,attr"`
+	Type   string `xml:"t,attr"`
+	Value  string `xml:"v"`
+	Inline struct {
+		Text []string `xml:"t"`
+		Runs []struct {
+			Text string `xml:"t"`
+		} `xml:"r"`
+	} `xml:"is"`
+}
+
+// xlsxSharedString is a <si> element of the shared strings table
+type xlsxSharedString struct {
+	Text string `xml:"t"`
+	Runs []struct {
+		Text string `xml:"t"`
+	} `xml:"r"`
+}
+
+// readXLSX returns the cells of the first worksheet of an .xlsx file as rows
+// of text. It only understands what statement exports need: shared, inline
+// and plain values (dates stay as Excel serial numbers).
+func readXLSX(data []byte) ([][]string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidStatement, err)
+	}
+
+	files := make(map[string]*zip.File, len(archive.File))
+	var sheets []string
+	for _, f := range archive.File {
+		files[f.Name] = f
+		if strings.HasPrefix(f.Name, "xl/worksheets/sheet") && strings.HasSuffix(f.Name, ".xml") {
+			sheets = append(sheets, f.Name)
+		}
+	}
+	if len(sheets) == 0 {
+		return nil, fmt.Errorf("%w: workbook has no worksheets", ErrInvalidStatement)
+	}
+	sort.Strings(sheets)
+	sheet := sheets[0]
+	if _, ok := files["xl/worksheets/sheet1.xml"]; ok {
+		sheet = "xl/worksheets/sheet1.xml"
+	}
+
+	var shared []string
+	if f, ok := files["xl/sharedStrings.xml"]; ok {
+		var sst struct {
+			Items []xlsxSharedString `xml:"si"`
+		}
+		if err := decodeZipXML(f, &sst); err != nil {
+			return nil, err
+		}
+		for _, item := range sst.Items {
+			text := item.Text
+			for _, run := range item.Runs {
+				text += run.Text
+			}
+			shared = append(shared, text)
+		}
+	}
+
+	var ws struct {
+		Rows []struct {
+			Cells []xlsxCell `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	if err := decodeZipXML(files[sheet], &ws); err != nil {
+		return nil, err
+	}
+
+	rows := make([][]string, 0, len(ws.Rows))
+	for _, r := range ws.Rows {
+		var row []string
+		for i, c := range r.Cells {
+			col := i
+			if n := xlsxColumn(c.Ref); n >= 0 {
+				col = n
+			}
+			for len(row) <= col {
+				row = append(row, "")
+			}
+
+			switch c.Type {
+			case "s":
+				idx, err := strconv.Atoi(strings.TrimSpace(c.Value))
+				if err != nil || idx < 0 || idx >= len(shared) {
+					return nil, fmt.Errorf("%w: bad shared string in cell %s", ErrInvalidStatement, c.Ref)
+				}
+				row[col] = shared[idx]
+			case "inlineStr":
+				text := strings.Join(c.Inline.Text, "")
+				for _, run := range c.Inline.Runs {
+					text += run.Text
+				}
+				row[col] = text
+			default:
+				row[col] = c.Value
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// decodeZipXML decodes an XML file of the archive into v
+func decodeZipXML(f *zip.File, v interface{}) error {
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidStatement, err)
+	}
+	defer rc.Close()
+
+	if err := xml.NewDecoder(io.LimitReader(rc, 50<<20)).Decode(v); err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidStatement, f.Name, err)
+	}
+	return nil
+}
+
+// xlsxColumn returns the zero-based column of a cell reference ("C7" -> 2)
+func xlsxColumn(ref string) int {
+	col := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		col = col*26 + int(r-'A'+1)
+	}
+	return col - 1
+}
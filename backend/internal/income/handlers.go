@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/blanquicet/conti/backend/internal/auth"
+	"github.com/blanquicet/conti/backend/internal/money"
 )
 
 // Handler handles HTTP requests for income management
@@ -36,20 +37,20 @@ func NewHandler(
 // Request/Response types
 
 type CreateIncomeRequest struct {
-	MemberID    string  `json:"member_id"`
-	AccountID   string  `json:"account_id"`
-	Type        string  `json:"type"`
-	Amount      float64 `json:"amount"`
-	Description string  `json:"description"`
-	IncomeDate  string  `json:"income_date"` // YYYY-MM-DD format
+	MemberID    string       `json:"member_id"`
+	AccountID   string       `json:"account_id"`
+	Type        string       `json:"type"`
+	Amount      money.Amount `json:"amount"`
+	Description string       `json:"description"`
+	IncomeDate  string       `json:"income_date"` // YYYY-MM-DD format
 }
 
 type UpdateIncomeRequest struct {
-	AccountID   *string  `json:"account_id,omitempty"`
-	Type        *string  `json:"type,omitempty"`
-	Amount      *float64 `json:"amount,omitempty"`
-	Description *string  `json:"description,omitempty"`
-	IncomeDate  *string  `json:"income_date,omitempty"` // YYYY-MM-DD format
+	AccountID   *string       `json:"account_id,omitempty"`
+	Type        *string       `json:"type,omitempty"`
+	Amount      *money.Amount `json:"amount,omitempty"`
+	Description *string       `json:"description,omitempty"`
+	IncomeDate  *string       `json:"income_date,omitempty"` // YYYY-MM-DD format
 }
 
 type ErrorResponse struct {
@@ -315,3 +316,115 @@ func (h *Handler) HandleDelete(w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// HandleListTrash lists deleted income entries that can still be restored
+// GET /api/income/trash
+func (h *Handler) HandleListTrash(w http.ResponseWriter, r *http.Request) {
+	user, err := h.getUserFromRequest(r)
+	if err != nil {
+		h.respondError(w, errors.New("unauthorized"), http.StatusUnauthorized)
+		return
+	}
+
+	entries, err := h.service.ListTrash(r.Context(), user.ID)
+	if err != nil {
+		h.respondError(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	h.respondJSON(w, map[string]interface{}{"income_entries": entries}, http.StatusOK)
+}
+
+// HandleRestore takes an income entry out of the trash
+// POST /api/income/{id}/restore
+func (h *Handler) HandleRestore(w http.ResponseWriter, r *http.Request) {
+	user, err := h.getUserFromRequest(r)
+	if err != nil {
+		h.respondError(w, errors.New("unauthorized"), http.StatusUnauthorized)
+		return
+	}
+
+	id := r.PathValue("id")
+	if id == "" {
+		h.respondError(w, errors.New("income id is required"), http.StatusBadRequest)
+		return
+	}
+
+	income, err := h.service.Restore(r.Context(), user.ID, id)
+	if err != nil {
+		if errors.Is(err, ErrIncomeNotFound) {
+			h.respondError(w, err, http.StatusNotFound)
+			return
+		}
+		h.respondError(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	h.respondJSON(w, income, http.StatusOK)
+}
+
+// HandleHistory returns the field-level change timeline of an income entry
+// GET /api/income/{id}/history
+func (h *Handler) HandleHistory(w http.ResponseWriter, r *http.Request) {
+	user, err := h.getUserFromRequest(r)
+	if err != nil {
+		h.respondError(w, errors.New("unauthorized"), http.StatusUnauthorized)
+		return
+	}
+
+	id := r.PathValue("id")
+	if id == "" {
+		h.respondError(w, errors.New("income id is required"), http.StatusBadRequest)
+		return
+	}
+
+	history, err := h.service.History(r.Context(), user.ID, id)
+	if err != nil {
+		if errors.Is(err, ErrIncomeNotFound) {
+			h.respondError(w, err, http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, ErrNotAuthorized) {
+			h.respondError(w, err, http.StatusForbidden)
+			return
+		}
+		h.respondError(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	h.respondJSON(w, map[string]interface{}{"history": history}, http.StatusOK)
+}
+
+// HandleRevert puts an income entry back to a version of its history
+// POST /api/income/{id}/history/{versionId}/revert
+func (h *Handler) HandleRevert(w http.ResponseWriter, r *http.Request) {
+	user, err := h.getUserFromRequest(r)
+	if err != nil {
+		h.respondError(w, errors.New("unauthorized"), http.StatusUnauthorized)
+		return
+	}
+
+	id := r.PathValue("id")
+	versionID := r.PathValue("versionId")
+	if id == "" || versionID == "" {
+		h.respondError(w, errors.New("income id and version id are required"), http.StatusBadRequest)
+		return
+	}
+
+	income, err := h.service.Revert(r.Context(), user.ID, id, versionID)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrIncomeNotFound), errors.Is(err, ErrVersionNotFound):
+			h.respondError(w, err, http.StatusNotFound)
+		case errors.Is(err, ErrNotAuthorized):
+			h.respondError(w, err, http.StatusForbidden)
+		case errors.Is(err, ErrInvalidIncomeType), errors.Is(err, ErrInvalidAccountType), errors.Is(err, ErrInvalidAmount):
+			h.respondError(w, err, http.StatusBadRequest)
+		default:
+			h.respondError(w, err, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	h.respondJSON(w, income, http.StatusOK)
+}
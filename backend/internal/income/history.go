@@ -0,0 +1,114 @@
+package income
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/blanquicet/conti/backend/internal/audit"
+)
+
+// ErrVersionNotFound is returned when reverting to a version that isn't in
+// the income entry's history
+var ErrVersionNotFound = errors.New("version not found")
+
+// historyLimit caps the audit logs read for an income entry's timeline
+const historyLimit = 500
+
+// History returns the change timeline of an income entry, newest first
+func (s *service) History(ctx context.Context, userID, id string) ([]*audit.HistoryEntry, error) {
+	income, err := s.GetByID(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	logs, err := s.auditLogs(ctx, income)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := s.householdsRepo.GetMembers(ctx, income.HouseholdID)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]string, len(members))
+	for _, member := range members {
+		names[member.UserID] = member.UserName
+	}
+
+	return audit.BuildHistory(logs, historyView, names), nil
+}
+
+// Revert puts an income entry back as it was in a version of its history,
+// through Update
+func (s *service) Revert(ctx context.Context, userID, id, versionID string) (*Income, error) {
+	income, err := s.GetByID(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	logs, err := s.auditLogs(ctx, income)
+	if err != nil {
+		return nil, err
+	}
+	version := audit.FindVersion(logs, versionID)
+	if version == nil {
+		return nil, ErrVersionNotFound
+	}
+
+	input, err := revertInput(version.NewValues)
+	if err != nil {
+		return nil, err
+	}
+	return s.Update(ctx, userID, id, input)
+}
+
+// auditLogs returns the successful audit logs of an income entry, newest first
+func (s *service) auditLogs(ctx context.Context, income *Income) ([]*audit.AuditLog, error) {
+	resourceType := "income"
+	successOnly := true
+	logs, _, err := s.auditService.Query(ctx, &audit.ListFilters{
+		ResourceType: &resourceType,
+		ResourceID:   &income.ID,
+		HouseholdID:  &income.HouseholdID,
+		SuccessOnly:  &successOnly,
+		Limit:        historyLimit,
+	})
+	return logs, err
+}
+
+// revertInput builds the update that sets every editable field to the snapshot
+func revertInput(snapshot map[string]interface{}) (*UpdateIncomeInput, error) {
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, err
+	}
+	var old Income
+	if err := json.Unmarshal(data, &old); err != nil {
+		return nil, err
+	}
+
+	return &UpdateIncomeInput{
+		AccountID:   &old.AccountID,
+		Type:        &old.Type,
+		Amount:      &old.Amount,
+		Description: &old.Description,
+		IncomeDate:  &old.IncomeDate,
+	}, nil
+}
+
+// historyView is what the timeline shows of an income snapshot
+func historyView(snapshot map[string]interface{}) map[string]interface{} {
+	date := snapshot["income_date"]
+	if s, ok := date.(string); ok && len(s) >= 10 {
+		date = s[:10]
+	}
+	return map[string]interface{}{
+		"description": snapshot["description"],
+		"amount":      snapshot["amount"],
+		"type":        snapshot["type"],
+		"income_date": date,
+		"member":      snapshot["member_name"],
+		"account":     snapshot["account_name"],
+	}
+}
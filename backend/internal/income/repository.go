@@ -9,6 +9,8 @@ import (
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/blanquicet/conti/backend/internal/money"
 )
 
 // repository implements Repository using PostgreSQL
@@ -68,7 +70,7 @@ func (r *repository) GetByID(ctx context.Context, id string) (*Income, error) {
 		FROM income i
 		JOIN users u ON i.member_id = u.id
 		JOIN accounts a ON i.account_id = a.id
-		WHERE i.id = $1
+		WHERE i.id = $1 AND i.deleted_at IS NULL
 	`, id).Scan(
 		&income.ID,
 		&income.HouseholdID,
@@ -99,10 +101,12 @@ func (r *repository) ListByHousehold(ctx context.Context, householdID string, fi
 	query := `
 		SELECT i.id, i.household_id, i.member_id, i.account_id, i.type, i.amount, 
 		       i.description, i.income_date, i.created_at, i.updated_at,
-		       u.name as member_name, a.name as account_name
+		       u.name as member_name, a.name as account_name,
+		       i.deleted_at, deleted_by.name as deleted_by_name
 		FROM income i
 		JOIN users u ON i.member_id = u.id
 		JOIN accounts a ON i.account_id = a.id
+		LEFT JOIN users deleted_by ON i.deleted_by = deleted_by.id
 		WHERE i.household_id = $1
 	`
 
@@ -110,6 +114,15 @@ func (r *repository) ListByHousehold(ctx context.Context, householdID string, fi
 	args = append(args, householdID)
 	argNum := 2
 
+	// The trash is listed most recently deleted first
+	order := " ORDER BY i.income_date DESC, i.created_at DESC"
+	if filters != nil && filters.Deleted {
+		query += " AND i.deleted_at IS NOT NULL"
+		order = " ORDER BY i.deleted_at DESC, i.id DESC"
+	} else {
+		query += " AND i.deleted_at IS NULL"
+	}
+
 	// Apply filters
 	if filters != nil {
 		if filters.MemberID != nil {
@@ -139,7 +152,7 @@ func (r *repository) ListByHousehold(ctx context.Context, householdID string, fi
 		}
 	}
 
-	query += " ORDER BY i.income_date DESC, i.created_at DESC"
+	query += order
 
 	rows, err := r.pool.Query(ctx, query, args...)
 	if err != nil {
@@ -163,6 +176,8 @@ func (r *repository) ListByHousehold(ctx context.Context, householdID string, fi
 			&income.UpdatedAt,
 			&income.MemberName,
 			&income.AccountName,
+			&income.DeletedAt,
+			&income.DeletedByName,
 		)
 		if err != nil {
 			return nil, err
@@ -191,7 +206,7 @@ func (r *repository) GetTotals(ctx context.Context, householdID string, filters
 		FROM income i
 		JOIN users u ON i.member_id = u.id
 		JOIN accounts a ON i.account_id = a.id
-		WHERE i.household_id = $1
+		WHERE i.household_id = $1 AND i.deleted_at IS NULL
 	`
 
 	var args []interface{}
@@ -237,8 +252,8 @@ func (r *repository) GetTotals(ctx context.Context, householdID string, filters
 
 	totals := &IncomeTotals{
 		ByMember:  make(map[string]*MemberTotals),
-		ByAccount: make(map[string]float64),
-		ByType:    make(map[IncomeType]float64),
+		ByAccount: make(map[string]money.Amount),
+		ByType:    make(map[IncomeType]money.Amount),
 	}
 
 	for rows.Next() {
@@ -248,7 +263,7 @@ func (r *repository) GetTotals(ctx context.Context, householdID string, filters
 			memberName  string
 			accountID   string
 			accountName string
-			amount      float64
+			amount      money.Amount
 		)
 
 		err := rows.Scan(&incomeType, &memberID, &memberName, &accountID, &accountName, &amount)
@@ -375,12 +390,13 @@ func (r *repository) Update(ctx context.Context, id string, input *UpdateIncomeI
 	return enriched, nil
 }
 
-// Delete deletes an income entry
-func (r *repository) Delete(ctx context.Context, id string) error {
+// Delete moves an income entry to the trash
+func (r *repository) Delete(ctx context.Context, id, deletedBy string) error {
 	result, err := r.pool.Exec(ctx, `
-		DELETE FROM income
-		WHERE id = $1
-	`, id)
+		UPDATE income
+		SET deleted_at = NOW(), deleted_by = $2
+		WHERE id = $1 AND deleted_at IS NULL
+	`, id, deletedBy)
 
 	if err != nil {
 		return err
@@ -393,7 +409,40 @@ func (r *repository) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
-// CountByAccount counts income entries for a specific account
+// Restore takes an income entry of the household out of the trash
+func (r *repository) Restore(ctx context.Context, householdID, id string) error {
+	result, err := r.pool.Exec(ctx, `
+		UPDATE income
+		SET deleted_at = NULL, deleted_by = NULL
+		WHERE id = $1 AND household_id = $2 AND deleted_at IS NOT NULL
+	`, id, householdID)
+
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrIncomeNotFound
+	}
+
+	return nil
+}
+
+// PurgeDeleted permanently deletes the income entries moved to the trash
+// before a date
+func (r *repository) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.pool.Exec(ctx, `
+		DELETE FROM income
+		WHERE deleted_at IS NOT NULL AND deleted_at < $1
+	`, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+// CountByAccount counts income entries (including the trash, whose rows still
+// reference the account) for a specific account
 func (r *repository) CountByAccount(ctx context.Context, accountID string) (int, error) {
 	var count int
 	err := r.pool.QueryRow(ctx, `
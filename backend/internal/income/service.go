@@ -5,6 +5,7 @@ import (
 	"errors"
 	"log/slog"
 	"strings"
+	"time"
 
 	"github.com/blanquicet/conti/backend/internal/accounts"
 	"github.com/blanquicet/conti/backend/internal/audit"
@@ -263,8 +264,8 @@ func (s *service) Delete(ctx context.Context, userID, id string) error {
 		return ErrNotAuthorized
 	}
 
-	// Delete income
-	err = s.repo.Delete(ctx, id)
+	// Move to the trash
+	err = s.repo.Delete(ctx, id, userID)
 	if err != nil {
 		// Log failed deletion
 		s.auditService.LogAsync(ctx, &audit.LogInput{
@@ -292,3 +293,66 @@ func (s *service) Delete(ctx context.Context, userID, id string) error {
 
 	return nil
 }
+
+// ListTrash lists the income entries in the household trash, most recently
+// deleted first
+func (s *service) ListTrash(ctx context.Context, userID string) ([]*Income, error) {
+	householdID, err := s.householdsRepo.GetUserHouseholdID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := s.repo.ListByHousehold(ctx, householdID, &ListIncomeFilters{Deleted: true})
+	if err != nil {
+		return nil, err
+	}
+	if entries == nil {
+		entries = []*Income{}
+	}
+	return entries, nil
+}
+
+// Restore takes an income entry out of the trash
+func (s *service) Restore(ctx context.Context, userID, id string) (*Income, error) {
+	householdID, err := s.householdsRepo.GetUserHouseholdID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Restore(ctx, householdID, id); err != nil {
+		s.auditService.LogAsync(ctx, &audit.LogInput{
+			UserID:       audit.StringPtr(userID),
+			Action:       audit.ActionIncomeRestored,
+			ResourceType: "income",
+			ResourceID:   audit.StringPtr(id),
+			HouseholdID:  audit.StringPtr(householdID),
+			Success:      false,
+			ErrorMessage: audit.StringPtr(err.Error()),
+		})
+		return nil, err
+	}
+
+	restored, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.auditService.LogAsync(ctx, &audit.LogInput{
+		UserID:       audit.StringPtr(userID),
+		Action:       audit.ActionIncomeRestored,
+		ResourceType: "income",
+		ResourceID:   audit.StringPtr(id),
+		HouseholdID:  audit.StringPtr(householdID),
+		Success:      true,
+		NewValues:    audit.StructToMap(restored),
+	})
+
+	return restored, nil
+}
+
+// PurgeTrash permanently deletes the income entries moved to the trash before
+// a date, in every household
+func (s *service) PurgeTrash(ctx context.Context, before time.Time) (int, error) {
+	purged, err := s.repo.PurgeDeleted(ctx, before)
+	return int(purged), err
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 	"errors"
 	"time"
+
+	"github.com/blanquicet/conti/backend/internal/audit"
+	"github.com/blanquicet/conti/backend/internal/money"
 )
 
 // Errors for income operations
@@ -35,7 +38,7 @@ const (
 	TypeSavingsWithdrawal IncomeType = "savings_withdrawal" // Retiro de ahorros previos
 	TypePreviousBalance   IncomeType = "previous_balance"   // Sobrante del mes anterior
 	TypeDebtCollection    IncomeType = "debt_collection"    // Cobro de deuda
-	TypeAccountTransfer   IncomeType = "account_transfer"   // Transferencia entre cuentas
+	TypeAccountTransfer   IncomeType = "account_transfer"   // Transferencia entre cuentas (legacy: only one side, new ones are TRANSFER movements)
 	TypeAdjustment        IncomeType = "adjustment"         // Ajuste contable
 )
 
@@ -69,11 +72,15 @@ type Income struct {
 	AccountID   string     `json:"account_id"`
 	AccountName string     `json:"account_name"`
 	Type        IncomeType `json:"type"`
-	Amount      float64    `json:"amount"`
+	Amount      money.Amount    `json:"amount"`
 	Description string     `json:"description"`
 	IncomeDate  time.Time  `json:"income_date"`
 	CreatedAt   time.Time  `json:"created_at"`
 	UpdatedAt   time.Time  `json:"updated_at"`
+	
+	// Trash (only set on entries listed from the trash)
+	DeletedAt     *time.Time `json:"deleted_at,omitempty"`
+	DeletedByName *string    `json:"deleted_by_name,omitempty"`
 }
 
 // CreateIncomeInput represents the input for creating an income entry
@@ -81,7 +88,7 @@ type CreateIncomeInput struct {
 	MemberID    string     `json:"member_id"`
 	AccountID   string     `json:"account_id"`
 	Type        IncomeType `json:"type"`
-	Amount      float64    `json:"amount"`
+	Amount      money.Amount    `json:"amount"`
 	Description string     `json:"description"`
 	IncomeDate  time.Time  `json:"income_date"`
 }
@@ -113,7 +120,7 @@ func (i *CreateIncomeInput) Validate() error {
 type UpdateIncomeInput struct {
 	AccountID   *string     `json:"account_id,omitempty"`
 	Type        *IncomeType `json:"type,omitempty"`
-	Amount      *float64    `json:"amount,omitempty"`
+	Amount      *money.Amount    `json:"amount,omitempty"`
 	Description *string     `json:"description,omitempty"`
 	IncomeDate  *time.Time  `json:"income_date,omitempty"`
 }
@@ -141,23 +148,24 @@ type ListIncomeFilters struct {
 	Month     *string // YYYY-MM format
 	StartDate *time.Time
 	EndDate   *time.Time
+	Deleted   bool // List the household trash instead of the live entries
 }
 
 // IncomeTotals represents totals for income entries
 type IncomeTotals struct {
-	TotalAmount             float64                   `json:"total_amount"`
-	RealIncomeAmount        float64                   `json:"real_income_amount"`
-	InternalMovementsAmount float64                   `json:"internal_movements_amount"`
+	TotalAmount             money.Amount                   `json:"total_amount"`
+	RealIncomeAmount        money.Amount                   `json:"real_income_amount"`
+	InternalMovementsAmount money.Amount                   `json:"internal_movements_amount"`
 	ByMember                map[string]*MemberTotals  `json:"by_member"`
-	ByAccount               map[string]float64        `json:"by_account"`
-	ByType                  map[IncomeType]float64    `json:"by_type"`
+	ByAccount               map[string]money.Amount        `json:"by_account"`
+	ByType                  map[IncomeType]money.Amount    `json:"by_type"`
 }
 
 // MemberTotals represents totals for a specific member
 type MemberTotals struct {
-	Total              float64 `json:"total"`
-	RealIncome         float64 `json:"real_income"`
-	InternalMovements  float64 `json:"internal_movements"`
+	Total              money.Amount `json:"total"`
+	RealIncome         money.Amount `json:"real_income"`
+	InternalMovements  money.Amount `json:"internal_movements"`
 }
 
 // ListIncomeResponse represents the response for listing income
@@ -173,7 +181,9 @@ type Repository interface {
 	ListByHousehold(ctx context.Context, householdID string, filters *ListIncomeFilters) ([]*Income, error)
 	GetTotals(ctx context.Context, householdID string, filters *ListIncomeFilters) (*IncomeTotals, error)
 	Update(ctx context.Context, id string, input *UpdateIncomeInput) (*Income, error)
-	Delete(ctx context.Context, id string) error
+	Delete(ctx context.Context, id, deletedBy string) error
+	Restore(ctx context.Context, householdID, id string) error
+	PurgeDeleted(ctx context.Context, before time.Time) (int64, error)
 	CountByAccount(ctx context.Context, accountID string) (int, error)
 }
 
@@ -184,4 +194,9 @@ type Service interface {
 	ListByHousehold(ctx context.Context, userID string, filters *ListIncomeFilters) (*ListIncomeResponse, error)
 	Update(ctx context.Context, userID, id string, input *UpdateIncomeInput) (*Income, error)
 	Delete(ctx context.Context, userID, id string) error
+	ListTrash(ctx context.Context, userID string) ([]*Income, error)
+	Restore(ctx context.Context, userID, id string) (*Income, error)
+	PurgeTrash(ctx context.Context, before time.Time) (int, error)
+	History(ctx context.Context, userID, id string) ([]*audit.HistoryEntry, error)
+	Revert(ctx context.Context, userID, id, versionID string) (*Income, error)
 }
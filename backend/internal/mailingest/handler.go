@@ -0,0 +1,112 @@
+package mailingest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/blanquicet/conti/backend/internal/auth"
+	"github.com/blanquicet/conti/backend/internal/households"
+)
+
+// maxUploadSize caps the size of uploaded messages
+const maxUploadSize = 20 << 20 // 20MB
+
+// Handler handles HTTP requests for notification email ingestion
+type Handler struct {
+	service    Service
+	authSvc    *auth.Service
+	cookieName string
+	logger     *slog.Logger
+}
+
+// NewHandler creates a new notification email ingestion handler
+func NewHandler(service Service, authService *auth.Service, cookieName string, logger *slog.Logger) *Handler {
+	return &Handler{
+		service:    service,
+		authSvc:    authService,
+		cookieName: cookieName,
+		logger:     logger,
+	}
+}
+
+// getUserFromSession extracts user from session cookie
+func (h *Handler) getUserFromSession(r *http.Request) (*auth.User, error) {
+	cookie, err := r.Cookie(h.cookieName)
+	if err != nil {
+		return nil, err
+	}
+	return h.authSvc.GetUserBySession(r.Context(), cookie.Value)
+}
+
+// HandleIngest handles POST /mail-ingest/messages (multipart form).
+// Field "file" may be repeated; each one is a saved .eml message or an mbox
+// export. Responds with the outcome of every message.
+func (h *Handler) HandleIngest(w http.ResponseWriter, r *http.Request) {
+	user, err := h.getUserFromSession(r)
+	if err != nil {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadSize)
+	if err := r.ParseMultipartForm(maxUploadSize); err != nil {
+		http.Error(w, "Invalid form or files too large (max 20MB)", http.StatusBadRequest)
+		return
+	}
+
+	var messages []Message
+	for _, header := range r.MultipartForm.File["file"] {
+		file, err := header.Open()
+		if err != nil {
+			http.Error(w, "Invalid file", http.StatusBadRequest)
+			return
+		}
+		data, err := io.ReadAll(file)
+		file.Close()
+		if err != nil {
+			http.Error(w, "Invalid file", http.StatusBadRequest)
+			return
+		}
+
+		if IsMbox(data) {
+			mbox, err := ReadMbox(bytes.NewReader(data), header.Filename)
+			if err != nil {
+				h.writeError(w, "failed to read mbox", err)
+				return
+			}
+			messages = append(messages, mbox...)
+		} else {
+			messages = append(messages, Message{Source: header.Filename, Raw: data})
+		}
+	}
+
+	result, err := h.service.Ingest(r.Context(), user.ID, messages)
+	if err != nil {
+		h.writeError(w, "failed to ingest notification emails", err)
+		return
+	}
+
+	h.logger.Info("notification emails ingested", "messages", result.Messages, "created", result.Created, "user_id", user.ID)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// writeError maps service errors to HTTP status codes
+func (h *Handler) writeError(w http.ResponseWriter, msg string, err error) {
+	switch {
+	case errors.Is(err, ErrNoMessages):
+		http.Error(w, "Field 'file' is required", http.StatusBadRequest)
+	case errors.Is(err, ErrInvalidMailbox):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, households.ErrHouseholdNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		h.logger.Error(msg, "error", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,169 @@
+package mailingest
+
+import (
+	"bufio"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// IMAPConfig points at a mailbox to fetch alerts from. It is meant for a local
+// IMAP server (e.g. a Dovecot or Greenmail fed by a forwarding rule) standing
+// in for the bank mailbox.
+type IMAPConfig struct {
+	Addr     string // host:port
+	TLS      bool   // Implicit TLS (port 993)
+	Username string
+	Password string
+	Mailbox  string // Defaults to INBOX
+}
+
+// imapTimeout bounds a whole fetch session
+const imapTimeout = 2 * time.Minute
+
+// FetchIMAP returns the Bancolombia messages received in the mailbox since the
+// given day. Messages are fetched with BODY.PEEK so they stay unread.
+func FetchIMAP(cfg IMAPConfig, since time.Time) ([]Message, error) {
+	dialer := &net.Dialer{Timeout: 30 * time.Second}
+	var conn net.Conn
+	var err error
+	if cfg.TLS {
+		host, _, _ := net.SplitHostPort(cfg.Addr)
+		conn, err = tls.DialWithDialer(dialer, "tcp", cfg.Addr, &tls.Config{ServerName: host})
+	} else {
+		conn, err = dialer.Dial("tcp", cfg.Addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrIMAP, err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(imapTimeout))
+
+	c := &imapConn{r: bufio.NewReader(conn), w: conn}
+	if _, err := c.readLine(); err != nil { // Greeting
+		return nil, err
+	}
+
+	if _, err := c.command("LOGIN %s %s", imapQuote(cfg.Username), imapQuote(cfg.Password)); err != nil {
+		return nil, err
+	}
+	defer c.command("LOGOUT")
+
+	mailbox := cfg.Mailbox
+	if mailbox == "" {
+		mailbox = "INBOX"
+	}
+	if _, err := c.command("SELECT %s", imapQuote(mailbox)); err != nil {
+		return nil, err
+	}
+
+	var uids []string
+	for _, domain := range senderDomains {
+		responses, err := c.command("UID SEARCH FROM %s SINCE %s", imapQuote(domain), since.Format("2-Jan-2006"))
+		if err != nil {
+			return nil, err
+		}
+		for _, resp := range responses {
+			if fields := strings.Fields(resp.line); len(fields) > 2 && strings.EqualFold(fields[1], "SEARCH") {
+				uids = append(uids, fields[2:]...)
+			}
+		}
+	}
+
+	messages := make([]Message, 0, len(uids))
+	for _, uid := range uids {
+		responses, err := c.command("UID FETCH %s (BODY.PEEK[])", uid)
+		if err != nil {
+			return nil, err
+		}
+		for _, resp := range responses {
+			if resp.literal != nil {
+				messages = append(messages, Message{Source: "imap:" + mailbox + "/" + uid, Raw: resp.literal})
+			}
+		}
+	}
+	return messages, nil
+}
+
+// imapConn speaks just enough IMAP4rev1 for FetchIMAP
+type imapConn struct {
+	r   *bufio.Reader
+	w   io.Writer
+	tag int
+}
+
+// imapResponse is an untagged response line and the literal it carried, if any
+type imapResponse struct {
+	line    string
+	literal []byte
+}
+
+// command sends a tagged command and collects the untagged responses until
+// the tagged completion, which must be OK
+func (c *imapConn) command(format string, args ...any) ([]imapResponse, error) {
+	c.tag++
+	tag := "a" + strconv.Itoa(c.tag)
+	if _, err := fmt.Fprintf(c.w, "%s %s\r\n", tag, fmt.Sprintf(format, args...)); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrIMAP, err)
+	}
+
+	var responses []imapResponse
+	for {
+		line, err := c.readLine()
+		if err != nil {
+			return nil, err
+		}
+		if strings.HasPrefix(line, tag+" ") {
+			status := strings.TrimPrefix(line, tag+" ")
+			if !strings.HasPrefix(strings.ToUpper(status), "OK") {
+				return nil, fmt.Errorf("%w: %s", ErrIMAP, status)
+			}
+			return responses, nil
+		}
+
+		resp := imapResponse{line: line}
+		if size, ok := literalSize(line); ok {
+			resp.literal = make([]byte, size)
+			if _, err := io.ReadFull(c.r, resp.literal); err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrIMAP, err)
+			}
+			if _, err := c.readLine(); err != nil { // Rest of the response, e.g. ")"
+				return nil, err
+			}
+		}
+		responses = append(responses, resp)
+	}
+}
+
+func (c *imapConn) readLine() (string, error) {
+	line, err := c.r.ReadString('\n')
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrIMAP, err)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// literalSize returns n when a line ends with a {n} literal marker
+func literalSize(line string) (int, bool) {
+	if !strings.HasSuffix(line, "}") {
+		return 0, false
+	}
+	start := strings.LastIndexByte(line, '{')
+	if start < 0 {
+		return 0, false
+	}
+	n, err := strconv.Atoi(line[start+1 : len(line)-1])
+	if err != nil || n < 0 || n > maxMessageSize {
+		return 0, false
+	}
+	return n, true
+}
+
+// imapQuote quotes a string argument
+func imapQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
@@ -0,0 +1,115 @@
+package mailingest
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// maxMessageSize bounds a single message read from a mailbox (alerts are a few KB)
+const maxMessageSize = 10 << 20
+
+// ReadMbox splits an mbox file into messages. Lines starting with "From " are
+// separators and ">From " escapes are undone (mboxrd).
+func ReadMbox(r io.Reader, name string) ([]Message, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxMessageSize)
+
+	var messages []Message
+	var current *bytes.Buffer
+	flush := func() {
+		if current != nil && len(bytes.TrimSpace(current.Bytes())) > 0 {
+			messages = append(messages, Message{
+				Source: fmt.Sprintf("%s#%d", name, len(messages)+1),
+				Raw:    current.Bytes(),
+			})
+		}
+	}
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "From ") {
+			flush()
+			current = &bytes.Buffer{}
+			continue
+		}
+		if current == nil {
+			return nil, fmt.Errorf("%w: %s does not start with a From line", ErrInvalidMailbox, name)
+		}
+		if unquoted := strings.TrimLeft(line, ">"); len(unquoted) < len(line) && strings.HasPrefix(unquoted, "From ") {
+			line = line[1:]
+		}
+		current.WriteString(line)
+		current.WriteString("\r\n")
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidMailbox, err)
+	}
+	flush()
+	return messages, nil
+}
+
+// IsMbox reports whether data looks like an mbox file rather than a single message
+func IsMbox(data []byte) bool {
+	return bytes.HasPrefix(data, []byte("From "))
+}
+
+// ReadMaildir reads the messages of a Maildir (its cur/ and new/ folders) or,
+// when dir isn't one, of every file in dir (a folder of saved .eml files).
+// Files are returned in name order, which in a Maildir is delivery order.
+func ReadMaildir(dir string) ([]Message, error) {
+	folders := []string{filepath.Join(dir, "cur"), filepath.Join(dir, "new")}
+	if !isDir(folders[0]) && !isDir(folders[1]) {
+		folders = []string{dir}
+	}
+
+	var paths []string
+	for _, folder := range folders {
+		entries, err := os.ReadDir(folder)
+		if os.IsNotExist(err) && folder != dir {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidMailbox, err)
+		}
+		for _, entry := range entries {
+			if entry.Type().IsRegular() && !strings.HasPrefix(entry.Name(), ".") {
+				paths = append(paths, filepath.Join(folder, entry.Name()))
+			}
+		}
+	}
+	sort.Strings(paths)
+
+	messages := make([]Message, 0, len(paths))
+	for _, path := range paths {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidMailbox, err)
+		}
+		messages = append(messages, Message{Source: path, Raw: raw})
+	}
+	return messages, nil
+}
+
+// ReadPath reads messages from a Maildir, a folder of .eml files or an mbox file
+func ReadPath(path string) ([]Message, error) {
+	if isDir(path) {
+		return ReadMaildir(path)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidMailbox, err)
+	}
+	defer f.Close()
+	return ReadMbox(f, path)
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
@@ -0,0 +1,66 @@
+package mailingest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadMbox(t *testing.T) {
+	mbox := "From alertas@bancolombia Fri Feb 20 13:15:42 2026\n" +
+		string(readFixture(t, "debit_purchase.eml")) +
+		"\n" +
+		"From alertas@bancolombia Fri Feb 13 09:54:00 2026\n" +
+		"From: a@example.com\n" +
+		"Subject: quoted\n" +
+		"\n" +
+		">From the bank\n"
+
+	messages, err := ReadMbox(strings.NewReader(mbox), "alerts.mbox")
+	if err != nil {
+		t.Fatalf("ReadMbox() error = %v", err)
+	}
+	if len(messages) != 2 {
+		t.Fatalf("got %d messages, want 2", len(messages))
+	}
+	if messages[0].Source != "alerts.mbox#1" {
+		t.Errorf("source = %q", messages[0].Source)
+	}
+	if _, err := ParseMessage(messages[0].Raw); err != nil {
+		t.Errorf("ParseMessage(first) error = %v", err)
+	}
+	if !strings.Contains(string(messages[1].Raw), "\r\nFrom the bank\r\n") {
+		t.Errorf("second message = %q, want >From unescaped", messages[1].Raw)
+	}
+}
+
+func TestReadMaildir(t *testing.T) {
+	dir := t.TempDir()
+	for _, sub := range []string{"cur", "new", "tmp"} {
+		if err := os.Mkdir(filepath.Join(dir, sub), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	os.WriteFile(filepath.Join(dir, "cur", "1.host:2,S"), readFixture(t, "credit_purchase.eml"), 0o644)
+	os.WriteFile(filepath.Join(dir, "new", "2.host"), readFixture(t, "deposit.eml"), 0o644)
+	os.WriteFile(filepath.Join(dir, "tmp", "3.host"), []byte("partial"), 0o644)
+
+	messages, err := ReadMaildir(dir)
+	if err != nil {
+		t.Fatalf("ReadMaildir() error = %v", err)
+	}
+	if len(messages) != 2 {
+		t.Fatalf("got %d messages, want 2 (tmp/ skipped)", len(messages))
+	}
+}
+
+func TestReadPath_EmlFolder(t *testing.T) {
+	messages, err := ReadPath("testdata")
+	if err != nil {
+		t.Fatalf("ReadPath() error = %v", err)
+	}
+	if len(messages) != 5 {
+		t.Errorf("got %d messages, want 5", len(messages))
+	}
+}
@@ -0,0 +1,259 @@
+package mailingest
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/blanquicet/conti/backend/internal/importer"
+)
+
+// senderDomains are the domains Bancolombia sends alerts from
+var senderDomains = []string{"notificacionesbancolombia.com", "bancolombia.com.co"}
+
+// colombia is the time zone of alert dates (no DST, so a fixed zone is exact)
+var colombia = time.FixedZone("COT", -5*60*60)
+
+var (
+	// Compraste COP22.000,00 en BAJO FUEGO SAS con tu T.Cred *1936, el 20/02/2026 a las 13:15
+	purchaseRe = regexp.MustCompile(`(?i)compraste\s+(COP|USD|EUR|\$)\s*([\d.,]+)\s+en\s+(.+?)\s+con\s+tu\s+T\.?\s*(cred|deb)\w*\.?\s*\*(\d{4}),?\s+el\s+([\d/:]+)\s+a\s+las\s+([\d/:]+)`)
+
+	// Recibiste un pago por $23,378,619.00 de BRANCH OF MICRO a tu cuenta AHORROS, el 09:54 a las 13/02/2026.
+	// Recibiste una transferencia por $150.000,00 de ANA PEREZ en tu cuenta *1234, el 13/02/2026 a las 09:54
+	depositRe = regexp.MustCompile(`(?i)recibiste\s+(?:un\s+pago|una\s+transferencia)\s+(?:por|de)\s+(COP|USD|EUR|\$)?\s*([\d.,]+)\s+de\s+(.+?)\s+(?:a|en)\s+tu\s+cuenta\s*(ahorros|corriente)?\s*(?:\*(\d{4}))?,?\s+el\s+([\d/:]+)\s+a\s+las\s+([\d/:]+)`)
+
+	tagRe    = regexp.MustCompile(`(?s)<[^>]*>`)
+	scriptRe = regexp.MustCompile(`(?is)<(style|script)[^>]*>.*?</(style|script)>`)
+)
+
+// ParseMessage extracts the alert of a raw Bancolombia notification email.
+// It returns ErrNotBancolombia for other senders and ErrUnrecognizedAlert for
+// notifications that aren't card purchases or account deposits.
+func ParseMessage(raw []byte) (*Alert, error) {
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidMessage, err)
+	}
+
+	if !fromBancolombia(msg.Header.Get("From")) {
+		return nil, ErrNotBancolombia
+	}
+
+	body, err := messageText(msg.Header.Get("Content-Type"), msg.Header.Get("Content-Transfer-Encoding"), msg.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	alert, err := ParseAlertText(body)
+	if err != nil {
+		return nil, err
+	}
+	alert.MessageID = strings.Trim(strings.TrimSpace(msg.Header.Get("Message-Id")), "<>")
+	return alert, nil
+}
+
+// ParseAlertText extracts an alert from the text of a notification
+func ParseAlertText(text string) (*Alert, error) {
+	text = strings.Join(strings.Fields(text), " ")
+
+	if m := purchaseRe.FindStringSubmatch(text); m != nil {
+		alert := &Alert{
+			Kind:         AlertPurchase,
+			Currency:     alertCurrency(m[1]),
+			Counterparty: strings.TrimSpace(m[3]),
+			Last4:        m[5],
+			CardType:     CardDebit,
+		}
+		if strings.EqualFold(m[4], "cred") {
+			alert.CardType = CardCredit
+		}
+		return completeAlert(alert, m[2], m[6], m[7])
+	}
+
+	if m := depositRe.FindStringSubmatch(text); m != nil {
+		alert := &Alert{
+			Kind:         AlertDeposit,
+			Currency:     alertCurrency(m[1]),
+			Counterparty: strings.TrimSpace(m[3]),
+			AccountType:  strings.ToUpper(m[4]),
+			Last4:        m[5],
+		}
+		return completeAlert(alert, m[2], m[6], m[7])
+	}
+
+	return nil, ErrUnrecognizedAlert
+}
+
+// completeAlert parses the amount and the date of an alert. The date and
+// time may come in either order ("el 09:54 a las 13/02/2026" happens).
+func completeAlert(alert *Alert, amount, first, second string) (*Alert, error) {
+	parsed, err := importer.ParseAmount(amount)
+	if err != nil || parsed <= 0 {
+		return nil, fmt.Errorf("%w: invalid amount %q", ErrUnrecognizedAlert, amount)
+	}
+	alert.Amount = parsed
+
+	date, clock := first, second
+	if !strings.Contains(date, "/") {
+		date, clock = second, first
+	}
+	when, err := time.ParseInLocation("02/01/2006 15:04", date+" "+clock, colombia)
+	if err != nil {
+		if when, err = time.ParseInLocation("02/01/2006", date, colombia); err != nil {
+			return nil, fmt.Errorf("%w: invalid date %q", ErrUnrecognizedAlert, date)
+		}
+	}
+	alert.Date = when
+	return alert, nil
+}
+
+// alertCurrency maps the currency marker of an alert to an ISO code ("$" is pesos)
+func alertCurrency(marker string) string {
+	switch strings.ToUpper(marker) {
+	case "USD", "EUR":
+		return strings.ToUpper(marker)
+	default:
+		return "COP"
+	}
+}
+
+// fromBancolombia reports whether a From header is a Bancolombia alert sender
+func fromBancolombia(from string) bool {
+	addr, err := mail.ParseAddress(from)
+	if err != nil {
+		return false
+	}
+	address := strings.ToLower(addr.Address)
+	for _, domain := range senderDomains {
+		if strings.HasSuffix(address, "@"+domain) || strings.HasSuffix(address, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// messageText returns the readable text of a message body: the text/plain
+// part when there is one, otherwise the text/html part without markup
+func messageText(contentType, encoding string, body io.Reader) (string, error) {
+	plain, htmlText, err := collectText(contentType, encoding, body)
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(plain) != "" {
+		return plain, nil
+	}
+	return stripHTML(htmlText), nil
+}
+
+// collectText walks the MIME tree and returns the first plain and html texts
+func collectText(contentType, encoding string, body io.Reader) (plain, htmlText string, err error) {
+	if contentType == "" {
+		contentType = "text/plain; charset=us-ascii"
+	}
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", "", fmt.Errorf("%w: bad Content-Type %q", ErrInvalidMessage, contentType)
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		reader := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := reader.NextRawPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return "", "", fmt.Errorf("%w: %v", ErrInvalidMessage, err)
+			}
+			p, h, err := collectText(part.Header.Get("Content-Type"), part.Header.Get("Content-Transfer-Encoding"), part)
+			if err != nil {
+				return "", "", err
+			}
+			if plain == "" {
+				plain = p
+			}
+			if htmlText == "" {
+				htmlText = h
+			}
+		}
+		return plain, htmlText, nil
+	}
+
+	if mediaType != "text/plain" && mediaType != "text/html" {
+		return "", "", nil // Attachments, images
+	}
+
+	data, err := io.ReadAll(decodeTransfer(encoding, body))
+	if err != nil {
+		return "", "", fmt.Errorf("%w: %v", ErrInvalidMessage, err)
+	}
+	text := decodeCharset(params["charset"], data)
+	if mediaType == "text/html" {
+		return "", text, nil
+	}
+	return text, "", nil
+}
+
+// decodeTransfer undoes the Content-Transfer-Encoding of a part
+func decodeTransfer(encoding string, r io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "quoted-printable":
+		return quotedprintable.NewReader(r)
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, &newlineSkipper{r: r})
+	default:
+		return r
+	}
+}
+
+// newlineSkipper drops line breaks, which base64 bodies are wrapped with
+type newlineSkipper struct {
+	r io.Reader
+}
+
+func (n *newlineSkipper) Read(p []byte) (int, error) {
+	for {
+		count, err := n.r.Read(p)
+		kept := 0
+		for _, b := range p[:count] {
+			if b != '\r' && b != '\n' {
+				p[kept] = b
+				kept++
+			}
+		}
+		if kept > 0 || err != nil {
+			return kept, err
+		}
+	}
+}
+
+// decodeCharset converts Latin-1 text (declared or detected) to UTF-8
+func decodeCharset(charset string, data []byte) string {
+	charset = strings.ToLower(charset)
+	latin1 := charset == "iso-8859-1" || charset == "latin1" || charset == "windows-1252"
+	if !latin1 && utf8.Valid(data) {
+		return string(data)
+	}
+	runes := make([]rune, len(data))
+	for i, b := range data {
+		runes[i] = rune(b)
+	}
+	return string(runes)
+}
+
+// stripHTML turns an html body into plain text
+func stripHTML(s string) string {
+	s = scriptRe.ReplaceAllString(s, " ")
+	s = tagRe.ReplaceAllString(s, " ")
+	return html.UnescapeString(s)
+}
@@ -0,0 +1,90 @@
+package mailingest
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/blanquicet/conti/backend/internal/money"
+)
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestParseMessage_CreditPurchase(t *testing.T) {
+	alert, err := ParseMessage(readFixture(t, "credit_purchase.eml"))
+	if err != nil {
+		t.Fatalf("ParseMessage() error = %v", err)
+	}
+	if alert.Kind != AlertPurchase || alert.CardType != CardCredit || alert.Last4 != "1936" {
+		t.Errorf("alert = %+v", alert)
+	}
+	if alert.Amount != money.New(22000) || alert.Currency != "COP" || alert.Counterparty != "BAJO FUEGO SAS" {
+		t.Errorf("alert = %+v", alert)
+	}
+	if !alert.Date.Equal(time.Date(2026, 2, 20, 13, 15, 0, 0, colombia)) {
+		t.Errorf("date = %v", alert.Date)
+	}
+	if alert.MessageID != "20260220181542.1936@an.notificacionesbancolombia.com" {
+		t.Errorf("message ID = %q", alert.MessageID)
+	}
+}
+
+func TestParseMessage_DebitPurchaseLatin1(t *testing.T) {
+	alert, err := ParseMessage(readFixture(t, "debit_purchase.eml"))
+	if err != nil {
+		t.Fatalf("ParseMessage() error = %v", err)
+	}
+	if alert.CardType != CardDebit || alert.Last4 != "4821" || alert.Amount != money.New(85900) {
+		t.Errorf("alert = %+v", alert)
+	}
+	if alert.Counterparty != "DROGUERÍA COLSUBSIDIO" {
+		t.Errorf("counterparty = %q", alert.Counterparty)
+	}
+}
+
+func TestParseMessage_DepositSwappedDateTime(t *testing.T) {
+	alert, err := ParseMessage(readFixture(t, "deposit.eml"))
+	if err != nil {
+		t.Fatalf("ParseMessage() error = %v", err)
+	}
+	if alert.Kind != AlertDeposit || alert.AccountType != "AHORROS" || alert.Last4 != "" {
+		t.Errorf("alert = %+v", alert)
+	}
+	if alert.Amount != money.New(23378619) || alert.Counterparty != "BRANCH OF MICRO" {
+		t.Errorf("alert = %+v", alert)
+	}
+	if !alert.Date.Equal(time.Date(2026, 2, 13, 9, 54, 0, 0, colombia)) {
+		t.Errorf("date = %v", alert.Date)
+	}
+}
+
+func TestParseMessage_Rejected(t *testing.T) {
+	if _, err := ParseMessage(readFixture(t, "not_bancolombia.eml")); !errors.Is(err, ErrNotBancolombia) {
+		t.Errorf("ParseMessage(other sender) error = %v, want ErrNotBancolombia", err)
+	}
+	if _, err := ParseMessage(readFixture(t, "unrecognized_alert.eml")); !errors.Is(err, ErrUnrecognizedAlert) {
+		t.Errorf("ParseMessage(login alert) error = %v, want ErrUnrecognizedAlert", err)
+	}
+	if _, err := ParseMessage([]byte("not an email")); !errors.Is(err, ErrInvalidMessage) {
+		t.Errorf("ParseMessage(garbage) error = %v, want ErrInvalidMessage", err)
+	}
+}
+
+func TestParseAlertText_Transfer(t *testing.T) {
+	alert, err := ParseAlertText("Recibiste una transferencia por $150.000,00 de ANA PEREZ en tu cuenta *1234, el 13/02/2026 a las 09:54")
+	if err != nil {
+		t.Fatalf("ParseAlertText() error = %v", err)
+	}
+	if alert.Kind != AlertDeposit || alert.Last4 != "1234" || alert.Amount != money.New(150000) || alert.Counterparty != "ANA PEREZ" {
+		t.Errorf("alert = %+v", alert)
+	}
+}
@@ -0,0 +1,78 @@
+package mailingest
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// imapLookback is how far back each IMAP poll searches (duplicates are skipped)
+const imapLookback = 7 * 24 * time.Hour
+
+// Poller periodically ingests the alerts of a mailbox on behalf of a user
+type Poller struct {
+	service  Service
+	userID   string
+	path     string      // Maildir, .eml folder or mbox file
+	imap     *IMAPConfig // Or an IMAP mailbox
+	interval time.Duration
+	logger   *slog.Logger
+	stopChan chan struct{}
+}
+
+// NewPoller creates a poller that reads path when set, or the IMAP mailbox otherwise
+func NewPoller(service Service, userID, path string, imap *IMAPConfig, interval time.Duration, logger *slog.Logger) *Poller {
+	return &Poller{
+		service:  service,
+		userID:   userID,
+		path:     path,
+		imap:     imap,
+		interval: interval,
+		logger:   logger,
+		stopChan: make(chan struct{}),
+	}
+}
+
+// Start begins the polling loop
+func (p *Poller) Start(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	p.logger.Info("notification email poller started", "interval", p.interval.String())
+
+	// Run immediately on start
+	p.poll(ctx)
+
+	for {
+		select {
+		case <-ticker.C:
+			p.poll(ctx)
+		case <-p.stopChan:
+			p.logger.Info("notification email poller stopped")
+			return
+		case <-ctx.Done():
+			p.logger.Info("notification email poller context canceled")
+			return
+		}
+	}
+}
+
+// Stop stops the poller
+func (p *Poller) Stop() {
+	close(p.stopChan)
+}
+
+func (p *Poller) poll(ctx context.Context) {
+	var messages []Message
+	var err error
+	if p.path != "" {
+		messages, err = ReadPath(p.path)
+	} else {
+		messages, err = FetchIMAP(*p.imap, time.Now().Add(-imapLookback))
+	}
+	if err != nil {
+		p.logger.Error("failed to read notification emails", "error", err)
+		return
+	}
+	IngestAll(ctx, p.service, p.logger, p.userID, messages)
+}
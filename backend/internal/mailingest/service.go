@@ -0,0 +1,301 @@
+package mailingest
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+
+	"github.com/blanquicet/conti/backend/internal/accounts"
+	"github.com/blanquicet/conti/backend/internal/audit"
+	"github.com/blanquicet/conti/backend/internal/households"
+	"github.com/blanquicet/conti/backend/internal/importer"
+	"github.com/blanquicet/conti/backend/internal/paymentmethods"
+)
+
+// service implements Service interface
+type service struct {
+	importerRepo      importer.Repository
+	householdsRepo    households.HouseholdRepository
+	paymentMethodRepo paymentmethods.Repository
+	accountsRepo      accounts.Repository
+	auditService      audit.Service
+	logger            *slog.Logger
+}
+
+// NewService creates a new notification email ingestion service
+func NewService(
+	importerRepo importer.Repository,
+	householdsRepo households.HouseholdRepository,
+	paymentMethodRepo paymentmethods.Repository,
+	accountsRepo accounts.Repository,
+	auditService audit.Service,
+	logger *slog.Logger,
+) Service {
+	return &service{
+		importerRepo:      importerRepo,
+		householdsRepo:    householdsRepo,
+		paymentMethodRepo: paymentMethodRepo,
+		accountsRepo:      accountsRepo,
+		auditService:      auditService,
+		logger:            logger,
+	}
+}
+
+// Ingest parses the messages and creates a pending draft for every alert
+// whose card or account is found in the user's household. Alerts seen before
+// are reported as duplicates, so a mailbox can be ingested repeatedly.
+func (s *service) Ingest(ctx context.Context, userID string, messages []Message) (*IngestResult, error) {
+	if len(messages) == 0 {
+		return nil, ErrNoMessages
+	}
+
+	householdID, err := s.householdsRepo.GetUserHouseholdID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	methods, err := s.paymentMethodRepo.ListByHousehold(ctx, householdID)
+	if err != nil {
+		return nil, err
+	}
+	accountList, err := s.accountsRepo.ListByHousehold(ctx, householdID)
+	if err != nil {
+		return nil, err
+	}
+	m := &matcher{userID: userID, methods: methods, accounts: accountList}
+
+	result := &IngestResult{Messages: len(messages), Items: make([]IngestItem, len(messages))}
+	var drafts []*importer.Draft
+	draftItems := make(map[string]int) // Fingerprint -> item index
+	for i, msg := range messages {
+		item := IngestItem{Source: msg.Source}
+
+		alert, err := ParseMessage(msg.Raw)
+		if err != nil {
+			item.Status = ItemIgnored
+			item.Reason = err.Error()
+			result.Items[i] = item
+			continue
+		}
+		item.Alert = alert
+
+		draft := m.draft(alert)
+		if draft == nil {
+			item.Status = ItemUnmatched
+			item.Reason = "no card or account matches the alert"
+			if alert.Last4 != "" {
+				item.Reason = fmt.Sprintf("no card or account ending in %s", alert.Last4)
+			}
+			result.Items[i] = item
+			continue
+		}
+		draft.HouseholdID = householdID
+		draft.CreatedBy = &userID
+
+		// The same alert twice in one batch (e.g. mbox and Maildir copies)
+		if _, seen := draftItems[draft.Fingerprint]; seen {
+			item.Status = ItemDuplicate
+			result.Items[i] = item
+			continue
+		}
+		draftItems[draft.Fingerprint] = i
+		drafts = append(drafts, draft)
+		item.Status = ItemDuplicate // Until CreateDrafts says otherwise
+		result.Items[i] = item
+	}
+
+	if len(drafts) > 0 {
+		created, err := s.importerRepo.CreateDrafts(ctx, drafts)
+		if err != nil {
+			s.auditService.LogAsync(ctx, &audit.LogInput{
+				UserID:       audit.StringPtr(userID),
+				Action:       audit.ActionImportDraftsCreated,
+				ResourceType: "import_draft",
+				HouseholdID:  audit.StringPtr(householdID),
+				Success:      false,
+				ErrorMessage: audit.StringPtr(err.Error()),
+			})
+			return nil, err
+		}
+		for _, draft := range created {
+			if i, ok := draftItems[draft.Fingerprint]; ok {
+				id := draft.ID
+				result.Items[i].Status = ItemCreated
+				result.Items[i].DraftID = &id
+			}
+		}
+	}
+
+	for _, item := range result.Items {
+		switch item.Status {
+		case ItemCreated:
+			result.Created++
+		case ItemDuplicate:
+			result.Duplicates++
+		case ItemUnmatched:
+			result.Unmatched++
+		case ItemIgnored:
+			result.Ignored++
+		}
+	}
+
+	if result.Created > 0 {
+		s.auditService.LogAsync(ctx, &audit.LogInput{
+			UserID:       audit.StringPtr(userID),
+			Action:       audit.ActionImportDraftsCreated,
+			ResourceType: "import_draft",
+			HouseholdID:  audit.StringPtr(householdID),
+			Metadata: map[string]interface{}{
+				"source":    importer.SourceEmail,
+				"messages":  result.Messages,
+				"created":   result.Created,
+				"unmatched": result.Unmatched,
+			},
+			Success: true,
+		})
+	}
+
+	return result, nil
+}
+
+// IngestAll ingests every message and logs the outcome, for scheduled runs
+func IngestAll(ctx context.Context, svc Service, logger *slog.Logger, userID string, messages []Message) {
+	if len(messages) == 0 {
+		return
+	}
+	result, err := svc.Ingest(ctx, userID, messages)
+	if err != nil {
+		logger.Error("failed to ingest notification emails", "error", err)
+		return
+	}
+	for _, item := range result.Items {
+		if item.Status == ItemUnmatched {
+			logger.Warn("notification email did not match a card or account", "source", item.Source, "reason", item.Reason)
+		}
+	}
+	if result.Created > 0 {
+		logger.Info("ingested notification emails", "messages", result.Messages, "created", result.Created)
+	}
+}
+
+// matcher finds the card or account an alert is for
+type matcher struct {
+	userID   string
+	methods  []*paymentmethods.PaymentMethod
+	accounts []*accounts.Account
+}
+
+// draft builds the draft of an alert, or returns nil when nothing matches
+func (m *matcher) draft(alert *Alert) *importer.Draft {
+	draft := &importer.Draft{
+		Source:          importer.SourceEmail,
+		TransactionDate: alert.Date,
+		Description:     alert.Counterparty,
+		Amount:          alert.Amount,
+		Currency:        alert.Currency,
+		Direction:       importer.DirectionDebit,
+		Fingerprint:     alertFingerprint(alert),
+	}
+	if alert.MessageID != "" {
+		messageID := alert.MessageID
+		draft.ExternalID = &messageID
+	}
+
+	switch alert.Kind {
+	case AlertPurchase:
+		if pm := m.paymentMethod(alert); pm != nil {
+			draft.PaymentMethodID = &pm.ID
+			return draft
+		}
+		if alert.CardType == CardDebit {
+			if account := m.account(alert); account != nil {
+				draft.AccountID = &account.ID
+				return draft
+			}
+		}
+	case AlertDeposit:
+		draft.Direction = importer.DirectionCredit
+		if account := m.account(alert); account != nil {
+			draft.AccountID = &account.ID
+			return draft
+		}
+	}
+	return nil
+}
+
+// paymentMethod returns the active card ending in the alert's last 4 digits,
+// preferring the card type the alert names and cards of the user
+func (m *matcher) paymentMethod(alert *Alert) *paymentmethods.PaymentMethod {
+	if alert.Last4 == "" {
+		return nil
+	}
+	wantType := paymentmethods.TypeDebitCard
+	if alert.CardType == CardCredit {
+		wantType = paymentmethods.TypeCreditCard
+	}
+
+	var best *paymentmethods.PaymentMethod
+	bestScore := -1
+	for _, pm := range m.methods {
+		if !pm.IsActive || pm.Last4 == nil || *pm.Last4 != alert.Last4 {
+			continue
+		}
+		score := 0
+		if pm.Type == wantType {
+			score += 2
+		}
+		if pm.OwnerID == m.userID {
+			score++
+		}
+		if score > bestScore {
+			best, bestScore = pm, score
+		}
+	}
+	return best
+}
+
+// account returns the account ending in the alert's last 4 digits. Deposit
+// alerts often only name the account type; then the user's only account of
+// that type is used.
+func (m *matcher) account(alert *Alert) *accounts.Account {
+	if alert.Last4 != "" {
+		var best *accounts.Account
+		for _, account := range m.accounts {
+			if account.Last4 == nil || *account.Last4 != alert.Last4 {
+				continue
+			}
+			if best == nil || account.OwnerID == m.userID {
+				best = account
+			}
+		}
+		return best
+	}
+
+	wantType := accounts.TypeSavings
+	if alert.AccountType == "CORRIENTE" {
+		wantType = accounts.TypeChecking
+	}
+	var found *accounts.Account
+	for _, account := range m.accounts {
+		if account.Type != wantType || account.OwnerID != m.userID {
+			continue
+		}
+		if found != nil {
+			return nil // Ambiguous
+		}
+		found = account
+	}
+	return found
+}
+
+// alertFingerprint identifies an alert across ingestions. The Message-ID is
+// stable across mailbox copies; the alert fields are the fallback.
+func alertFingerprint(alert *Alert) string {
+	key := "email|" + alert.MessageID
+	if alert.MessageID == "" {
+		key = fmt.Sprintf("email|%s|%s|%s|%d|%s", alert.Kind, alert.Last4, alert.Date.Format("2006-01-02T15:04"), alert.Amount, alert.Counterparty)
+	}
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
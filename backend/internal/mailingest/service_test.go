@@ -0,0 +1,79 @@
+package mailingest
+
+import (
+	"testing"
+
+	"github.com/blanquicet/conti/backend/internal/accounts"
+	"github.com/blanquicet/conti/backend/internal/importer"
+	"github.com/blanquicet/conti/backend/internal/paymentmethods"
+)
+
+func strPtr(s string) *string { return &s }
+
+func testMatcher() *matcher {
+	return &matcher{
+		userID: "ana",
+		methods: []*paymentmethods.PaymentMethod{
+			{ID: "pm-luis-credit", OwnerID: "luis", Type: paymentmethods.TypeCreditCard, Last4: strPtr("1936"), IsActive: true},
+			{ID: "pm-ana-credit", OwnerID: "ana", Type: paymentmethods.TypeCreditCard, Last4: strPtr("1936"), IsActive: true},
+			{ID: "pm-ana-debit", OwnerID: "ana", Type: paymentmethods.TypeDebitCard, Last4: strPtr("1936"), IsActive: true},
+			{ID: "pm-old", OwnerID: "ana", Type: paymentmethods.TypeCreditCard, Last4: strPtr("7777"), IsActive: false},
+		},
+		accounts: []*accounts.Account{
+			{ID: "acc-ana-savings", OwnerID: "ana", Type: accounts.TypeSavings, Last4: strPtr("4821")},
+			{ID: "acc-luis-savings", OwnerID: "luis", Type: accounts.TypeSavings},
+		},
+	}
+}
+
+func TestMatcher_Purchase(t *testing.T) {
+	m := testMatcher()
+
+	draft := m.draft(&Alert{Kind: AlertPurchase, CardType: CardCredit, Last4: "1936"})
+	if draft == nil || draft.PaymentMethodID == nil || *draft.PaymentMethodID != "pm-ana-credit" {
+		t.Fatalf("credit purchase matched %+v, want pm-ana-credit", draft)
+	}
+	if draft.Direction != importer.DirectionDebit || draft.Source != importer.SourceEmail {
+		t.Errorf("draft = %+v", draft)
+	}
+
+	draft = m.draft(&Alert{Kind: AlertPurchase, CardType: CardDebit, Last4: "1936"})
+	if draft == nil || *draft.PaymentMethodID != "pm-ana-debit" {
+		t.Errorf("debit purchase matched %+v, want pm-ana-debit", draft)
+	}
+
+	// Debit card without a payment method: the account with those digits
+	draft = m.draft(&Alert{Kind: AlertPurchase, CardType: CardDebit, Last4: "4821"})
+	if draft == nil || draft.AccountID == nil || *draft.AccountID != "acc-ana-savings" {
+		t.Errorf("debit purchase matched %+v, want acc-ana-savings", draft)
+	}
+
+	if draft := m.draft(&Alert{Kind: AlertPurchase, CardType: CardCredit, Last4: "7777"}); draft != nil {
+		t.Errorf("inactive card matched %+v", draft)
+	}
+}
+
+func TestMatcher_Deposit(t *testing.T) {
+	m := testMatcher()
+
+	draft := m.draft(&Alert{Kind: AlertDeposit, AccountType: "AHORROS"})
+	if draft == nil || *draft.AccountID != "acc-ana-savings" || draft.Direction != importer.DirectionCredit {
+		t.Errorf("deposit matched %+v, want the user's only savings account", draft)
+	}
+
+	if draft := m.draft(&Alert{Kind: AlertDeposit, AccountType: "CORRIENTE"}); draft != nil {
+		t.Errorf("deposit to checking matched %+v, want no match", draft)
+	}
+}
+
+func TestAlertFingerprint(t *testing.T) {
+	a := &Alert{Kind: AlertPurchase, Last4: "1936", MessageID: "x@bancolombia"}
+	b := &Alert{Kind: AlertDeposit, Last4: "0000", MessageID: "x@bancolombia"}
+	if alertFingerprint(a) != alertFingerprint(b) {
+		t.Error("alerts with the same Message-ID should share a fingerprint")
+	}
+	a.MessageID, b.MessageID = "", ""
+	if alertFingerprint(a) == alertFingerprint(b) {
+		t.Error("different alerts without Message-ID should not share a fingerprint")
+	}
+}
@@ -0,0 +1,91 @@
+package mailingest
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/blanquicet/conti/backend/internal/money"
+)
+
+// Errors for notification email ingestion
+var (
+	ErrInvalidMessage    = errors.New("invalid email message")
+	ErrNotBancolombia    = errors.New("message is not a Bancolombia notification")
+	ErrUnrecognizedAlert = errors.New("message is not a purchase or deposit alert")
+	ErrNoMessages        = errors.New("no messages to ingest")
+	ErrInvalidMailbox    = errors.New("invalid mailbox")
+	ErrIMAP              = errors.New("IMAP error")
+)
+
+// AlertKind is the kind of transaction an alert reports
+type AlertKind string
+
+const (
+	AlertPurchase AlertKind = "PURCHASE" // "Compraste ... con tu T.Cred *1234"
+	AlertDeposit  AlertKind = "DEPOSIT"  // "Recibiste un pago por ... a tu cuenta AHORROS"
+)
+
+// CardType is the kind of card a purchase was made with
+type CardType string
+
+const (
+	CardCredit CardType = "CREDIT" // T.Cred
+	CardDebit  CardType = "DEBIT"  // T.Deb
+)
+
+// Alert is the data extracted from a Bancolombia notification. Only these
+// fields are kept; the message body is never stored.
+type Alert struct {
+	Kind         AlertKind    `json:"kind"`
+	Amount       money.Amount `json:"amount"`
+	Currency     string       `json:"currency"`
+	Counterparty string       `json:"counterparty"`           // Merchant (purchase) or sender (deposit)
+	Last4        string       `json:"last4,omitempty"`        // Card or account, when the alert has it
+	CardType     CardType     `json:"card_type,omitempty"`    // Purchases only
+	AccountType  string       `json:"account_type,omitempty"` // Deposits only (AHORROS, CORRIENTE)
+	Date         time.Time    `json:"date"`                   // Colombian time
+	MessageID    string       `json:"message_id,omitempty"`
+}
+
+// Message is a raw RFC 822 message and where it was read from
+type Message struct {
+	Source string // File path, mbox position or IMAP UID (for reporting)
+	Raw    []byte
+}
+
+// ItemStatus is the outcome of one message
+type ItemStatus string
+
+const (
+	ItemCreated   ItemStatus = "CREATED"   // A pending draft was created
+	ItemDuplicate ItemStatus = "DUPLICATE" // The alert was ingested before
+	ItemUnmatched ItemStatus = "UNMATCHED" // No card or account with its last 4 digits
+	ItemIgnored   ItemStatus = "IGNORED"   // Not a Bancolombia purchase or deposit alert
+)
+
+// IngestItem reports one message
+type IngestItem struct {
+	Source  string     `json:"source"`
+	Status  ItemStatus `json:"status"`
+	Reason  string     `json:"reason,omitempty"`
+	Alert   *Alert     `json:"alert,omitempty"`
+	DraftID *string    `json:"draft_id,omitempty"`
+}
+
+// IngestResult summarizes an ingestion run
+type IngestResult struct {
+	Messages   int          `json:"messages"`
+	Created    int          `json:"created"`
+	Duplicates int          `json:"duplicates"`
+	Unmatched  int          `json:"unmatched"`
+	Ignored    int          `json:"ignored"`
+	Items      []IngestItem `json:"items"`
+}
+
+// Service defines the interface for notification email ingestion
+type Service interface {
+	// Ingest parses messages and queues the alerts as pending drafts of the
+	// user's household (see importer)
+	Ingest(ctx context.Context, userID string, messages []Message) (*IngestResult, error)
+}
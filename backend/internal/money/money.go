@@ -0,0 +1,258 @@
+// Package money provides an exact representation for monetary amounts.
+//
+// Amounts are stored as integer minor units (centavos), matching the
+// DECIMAL(15, 2) columns in the database. This avoids the drift of float64
+// arithmetic when summing movements or splitting them between participants.
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Scale is the number of minor units in one unit of currency
+const Scale = 100
+
+// Amount is a monetary amount in minor units (1 COP = 100 minor units).
+// The zero value is zero.
+type Amount int64
+
+// ErrInvalidAmount is returned when a value cannot be parsed as an amount
+var ErrInvalidAmount = errors.New("invalid money amount")
+
+// New returns an amount of whole units (money.New(150000) is $150.000)
+func New(units int64) Amount {
+	return Amount(units * Scale)
+}
+
+// FromMinor returns an amount from minor units
+func FromMinor(minor int64) Amount {
+	return Amount(minor)
+}
+
+// FromFloat converts a float to the nearest minor unit (half away from zero).
+// Only meant for boundaries that still speak float64 (e.g. LLM tool arguments).
+func FromFloat(f float64) Amount {
+	return Amount(math.Round(f * Scale))
+}
+
+// decimalPattern is the only syntax Parse accepts. big.Rat alone would also
+// take fractions ("1/3") and exponents ("1e999999999", slow to expand).
+var decimalPattern = regexp.MustCompile(`^-?\d+(\.\d+)?$`)
+
+// maxDecimalLength caps the input of Parse, well above DECIMAL(15, 2)
+const maxDecimalLength = 40
+
+// Parse parses a plain decimal string such as "1234.5" or "-20".
+// Digits beyond the minor unit are rounded half away from zero.
+func Parse(s string) (Amount, error) {
+	s = strings.TrimSpace(s)
+	if len(s) > maxDecimalLength || !decimalPattern.MatchString(s) {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+	return fromRat(r)
+}
+
+// fromRat rounds a rational number of units to minor units
+func fromRat(r *big.Rat) (Amount, error) {
+	scaled := new(big.Rat).Mul(r, big.NewRat(Scale, 1))
+	num, den := scaled.Num(), scaled.Denom()
+
+	// Round half away from zero: (|num| * 2 + den) / (den * 2)
+	abs := new(big.Int).Abs(num)
+	abs.Mul(abs, big.NewInt(2)).Add(abs, den)
+	abs.Quo(abs, new(big.Int).Mul(den, big.NewInt(2)))
+	if num.Sign() < 0 {
+		abs.Neg(abs)
+	}
+
+	if !abs.IsInt64() {
+		return 0, fmt.Errorf("%w: out of range", ErrInvalidAmount)
+	}
+	return Amount(abs.Int64()), nil
+}
+
+// Minor returns the amount in minor units
+func (a Amount) Minor() int64 {
+	return int64(a)
+}
+
+// Float64 returns the amount in units as a float. Only use it for display or
+// ratios, never to do arithmetic that is stored back.
+func (a Amount) Float64() float64 {
+	return float64(a) / Scale
+}
+
+// Abs returns the absolute value of the amount
+func (a Amount) Abs() Amount {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
+
+// Mul multiplies the amount by a ratio (e.g. a participant percentage),
+// rounding to the nearest minor unit
+func (a Amount) Mul(ratio float64) Amount {
+	return Amount(math.Round(float64(a) * ratio))
+}
+
+// Ratio returns a / b as a float (0 if b is zero)
+func (a Amount) Ratio(b Amount) float64 {
+	if b == 0 {
+		return 0
+	}
+	return float64(a) / float64(b)
+}
+
+// String formats the amount as a plain decimal ("1234.5", "-20", "0.05")
+func (a Amount) String() string {
+	minor := int64(a)
+	sign := ""
+	if minor < 0 {
+		sign = "-"
+		minor = -minor
+	}
+
+	units := minor / Scale
+	cents := minor % Scale
+	if cents == 0 {
+		return sign + strconv.FormatInt(units, 10)
+	}
+	frac := strings.TrimRight(fmt.Sprintf("%02d", cents), "0")
+	return sign + strconv.FormatInt(units, 10) + "." + frac
+}
+
+// MarshalJSON encodes the amount as a JSON number in units, so the wire
+// format is the same as the float64 amounts it replaces
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalJSON decodes a JSON number (or a numeric string) in units
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	s = strings.Trim(s, `"`)
+
+	parsed, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
+
+// ScanNumeric implements pgtype.NumericScanner for NUMERIC/DECIMAL columns
+func (a *Amount) ScanNumeric(v pgtype.Numeric) error {
+	if !v.Valid {
+		return fmt.Errorf("cannot scan NULL into money.Amount")
+	}
+	if v.NaN || v.InfinityModifier != pgtype.Finite {
+		return fmt.Errorf("cannot scan %v into money.Amount", v)
+	}
+
+	r := new(big.Rat).SetInt(v.Int)
+	if v.Exp > 0 {
+		r.Mul(r, new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(v.Exp)), nil)))
+	} else if v.Exp < 0 {
+		r.Quo(r, new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(-v.Exp)), nil)))
+	}
+
+	parsed, err := fromRat(r)
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
+
+// ScanFloat64 implements pgtype.Float64Scanner for computed float columns
+func (a *Amount) ScanFloat64(v pgtype.Float8) error {
+	if !v.Valid {
+		return fmt.Errorf("cannot scan NULL into money.Amount")
+	}
+	*a = FromFloat(v.Float64)
+	return nil
+}
+
+// NumericValue implements pgtype.NumericValuer
+func (a Amount) NumericValue() (pgtype.Numeric, error) {
+	return pgtype.Numeric{Int: big.NewInt(int64(a)), Exp: -2, Valid: true}, nil
+}
+
+// Sum adds up the given amounts
+func Sum(amounts ...Amount) Amount {
+	var total Amount
+	for _, a := range amounts {
+		total += a
+	}
+	return total
+}
+
+// Allocate splits the amount in parts proportional to the given weights, so
+// that the parts always add up to exactly the amount. Each part is first
+// rounded down; the leftover minor units go one by one to the parts with the
+// largest remainders (ties go to the earlier part), so the result is
+// deterministic. Weights don't need to add up to 1. Non-positive weights get
+// nothing; if no weight is positive, everything is zero.
+func (a Amount) Allocate(weights []float64) []Amount {
+	parts := make([]Amount, len(weights))
+
+	totalWeight := 0.0
+	for _, w := range weights {
+		if w > 0 {
+			totalWeight += w
+		}
+	}
+	if totalWeight == 0 || len(weights) == 0 {
+		return parts
+	}
+
+	total := a.Abs()
+	type remainder struct {
+		index int
+		frac  float64
+	}
+	remainders := make([]remainder, 0, len(weights))
+	allocated := Amount(0)
+	for i, w := range weights {
+		if w <= 0 {
+			continue
+		}
+		exact := float64(total) * w / totalWeight
+		floor := math.Floor(exact)
+		parts[i] = Amount(floor)
+		allocated += parts[i]
+		remainders = append(remainders, remainder{index: i, frac: exact - floor})
+	}
+
+	sort.SliceStable(remainders, func(i, j int) bool {
+		return remainders[i].frac > remainders[j].frac
+	})
+	for i := 0; allocated < total; i++ {
+		parts[remainders[i%len(remainders)].index]++
+		allocated++
+	}
+
+	if a < 0 {
+		for i := range parts {
+			parts[i] = -parts[i]
+		}
+	}
+	return parts
+}
@@ -0,0 +1,142 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Amount
+	}{
+		{"0", 0},
+		{"150000", 15000000},
+		{"1234.5", 123450},
+		{"1234.05", 123405},
+		{"-20", -2000},
+		{"0.285", 29}, // float64 would round this down
+		{"33333.333333", 3333333},
+	}
+
+	for _, tt := range tests {
+		got, err := Parse(tt.input)
+		if err != nil {
+			t.Fatalf("Parse(%q) error: %v", tt.input, err)
+		}
+		if got != tt.want {
+			t.Errorf("Parse(%q) = %d, want %d", tt.input, got, tt.want)
+		}
+	}
+
+	for _, input := range []string{"abc", "", "1e3", "1/3", "1e999999999", "+5", ".5", "5.", "1.2.3", strings.Repeat("9", 41)} {
+		if _, err := Parse(input); !errors.Is(err, ErrInvalidAmount) {
+			t.Errorf("Parse(%q) error = %v, want ErrInvalidAmount", input, err)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		amount Amount
+		want   string
+	}{
+		{0, "0"},
+		{New(150000), "150000"},
+		{123450, "1234.5"},
+		{123405, "1234.05"},
+		{-2000, "-20"},
+		{-5, "-0.05"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.amount.String(); got != tt.want {
+			t.Errorf("Amount(%d).String() = %q, want %q", tt.amount, got, tt.want)
+		}
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	var v struct {
+		Amount  Amount  `json:"amount"`
+		Pointer *Amount `json:"pointer,omitempty"`
+	}
+	if err := json.Unmarshal([]byte(`{"amount": 45000.75, "pointer": 10}`), &v); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if v.Amount != 4500075 || v.Pointer == nil || *v.Pointer != 1000 {
+		t.Fatalf("unexpected decode: %+v", v)
+	}
+
+	out, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if string(out) != `{"amount":45000.75,"pointer":10}` {
+		t.Errorf("marshal = %s", out)
+	}
+}
+
+func TestScanNumeric(t *testing.T) {
+	tests := []struct {
+		numeric pgtype.Numeric
+		want    Amount
+	}{
+		{pgtype.Numeric{Int: big.NewInt(12345), Exp: -2, Valid: true}, 12345},
+		{pgtype.Numeric{Int: big.NewInt(15), Exp: 4, Valid: true}, New(150000)},
+		{pgtype.Numeric{Int: big.NewInt(1234567), Exp: -4, Valid: true}, 12346},
+	}
+
+	for _, tt := range tests {
+		var a Amount
+		if err := a.ScanNumeric(tt.numeric); err != nil {
+			t.Fatalf("ScanNumeric error: %v", err)
+		}
+		if a != tt.want {
+			t.Errorf("ScanNumeric(%v) = %d, want %d", tt.numeric, a, tt.want)
+		}
+	}
+
+	var a Amount
+	if err := a.ScanNumeric(pgtype.Numeric{}); err == nil {
+		t.Error("expected error scanning NULL")
+	}
+}
+
+func TestAllocate(t *testing.T) {
+	tests := []struct {
+		name    string
+		amount  Amount
+		weights []float64
+		want    []Amount
+	}{
+		{"even split", New(100), []float64{0.5, 0.5}, []Amount{New(50), New(50)}},
+		{"thirds give the extra cent to the first", 10000, []float64{1, 1, 1}, []Amount{3334, 3333, 3333}},
+		{"largest remainder wins", 1000, []float64{0.335, 0.33, 0.335}, []Amount{335, 330, 335}},
+		{"negative amount", -10000, []float64{1, 1, 1}, []Amount{-3334, -3333, -3333}},
+		{"zero weights get nothing", 500, []float64{0, 1}, []Amount{0, 500}},
+		{"no weights", 500, nil, []Amount{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.amount.Allocate(tt.weights)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("got %v, want %v", got, tt.want)
+				}
+			}
+			if len(tt.weights) > 0 && Sum(got...) != tt.amount {
+				t.Errorf("parts sum to %d, want %d", Sum(got...), tt.amount)
+			}
+		})
+	}
+}
@@ -0,0 +1,321 @@
+package movements
+
+import (
+	"sort"
+
+	"github.com/blanquicet/conti/backend/internal/households"
+	"github.com/blanquicet/conti/backend/internal/money"
+)
+
+// settledThreshold is the amount under which a net debt is considered settled
+// (amounts under $1 COP are rounding noise from legacy percentage splits).
+const settledThreshold money.Amount = 1 * money.Scale
+
+// DebtLedger accumulates who-owes-whom amounts from SPLIT and DEBT_PAYMENT
+// movements and nets each pair of people into a single DebtBalance.
+// It is shared by the household debt consolidation and event summaries.
+// Amounts are in the household currency.
+type DebtLedger struct {
+	currency string
+	amounts  map[string]map[string]money.Amount         // debtorID -> creditorID -> amount
+	names    map[string]string                          // ID -> display name
+	details  map[string]map[string][]DebtMovementDetail // debtorID -> creditorID -> movements
+	linked   map[string]string                          // contactID -> linked userID
+}
+
+// NewDebtLedger creates an empty debt ledger in the given (household) currency
+func NewDebtLedger(currency string) *DebtLedger {
+	return &DebtLedger{
+		currency: currency,
+		amounts:  make(map[string]map[string]money.Amount),
+		names:    make(map[string]string),
+		details:  make(map[string]map[string][]DebtMovementDetail),
+		linked:   make(map[string]string),
+	}
+}
+
+// LinkContacts records debts with contacts linked to a user under the user's
+// ID, so they net with the movements where that user appears directly
+func (l *DebtLedger) LinkContacts(contacts []*households.Contact) {
+	for contactID, userID := range linkedContactUsers(contacts) {
+		l.linked[contactID] = userID
+	}
+}
+
+// PersonID returns the ID a person is recorded under: the user ID, or the
+// contact ID translated to its linked user
+func (l *DebtLedger) PersonID(userID, contactID *string) string {
+	if userID != nil {
+		return *userID
+	}
+	if contactID != nil {
+		if linked, ok := l.linked[*contactID]; ok {
+			return linked
+		}
+		return *contactID
+	}
+	return ""
+}
+
+// SetName sets the display name for a person (user or contact ID)
+func (l *DebtLedger) SetName(id, name string) {
+	l.names[id] = name
+}
+
+// HasName reports whether the person appears in the ledger
+func (l *DebtLedger) HasName(id string) bool {
+	_, ok := l.names[id]
+	return ok
+}
+
+// Add records that debtorID owes creditorID the given amount.
+// Payments are recorded as negative amounts, which reduce the debt.
+func (l *DebtLedger) Add(debtorID, creditorID string, amount money.Amount, detail DebtMovementDetail) {
+	if l.amounts[debtorID] == nil {
+		l.amounts[debtorID] = make(map[string]money.Amount)
+	}
+	l.amounts[debtorID][creditorID] += amount
+
+	if l.details[debtorID] == nil {
+		l.details[debtorID] = make(map[string][]DebtMovementDetail)
+	}
+	l.details[debtorID][creditorID] = append(l.details[debtorID][creditorID], detail)
+}
+
+// AddMovement records a SPLIT or DEBT_PAYMENT movement by user and contact ID
+// (linked contacts translated, see LinkContacts). Other movement types don't create debts and are ignored, and so
+// are debt payments still awaiting the receiver's confirmation.
+func (l *DebtLedger) AddMovement(m *Movement) {
+	payerID := l.PersonID(m.PayerUserID, m.PayerContactID)
+	if payerID == "" {
+		return
+	}
+
+	switch m.Type {
+	case TypeSplit:
+		l.SetName(payerID, m.PayerName)
+		shares := m.HouseholdShares()
+		originals := m.ParticipantShares()
+		for i, p := range m.Participants {
+			participantID := l.PersonID(p.ParticipantUserID, p.ParticipantContactID)
+			// Skip if participant is the payer (they don't owe themselves)
+			if participantID == "" || participantID == payerID {
+				continue
+			}
+			l.SetName(participantID, p.ParticipantName)
+
+			share := shares[i]
+			l.Add(participantID, payerID, share, withOriginal(m, originals[i], DebtMovementDetail{
+				MovementID:   m.ID,
+				Description:  m.Description,
+				Amount:       share,
+				MovementDate: m.MovementDate.Format("2006-01-02T15:04:05Z07:00"),
+				Type:         string(TypeSplit),
+				PayerID:      payerID,
+				PayerName:    m.PayerName,
+			}))
+		}
+
+	case TypeDebtPayment:
+		counterpartyID := l.PersonID(m.CounterpartyUserID, m.CounterpartyContactID)
+		if counterpartyID == "" || m.AwaitingConfirmation() {
+			return
+		}
+		l.SetName(payerID, m.PayerName)
+		if m.CounterpartyName != nil {
+			l.SetName(counterpartyID, *m.CounterpartyName)
+		}
+
+		l.Add(payerID, counterpartyID, -m.HouseholdAmount(), withOriginal(m, -m.Amount, DebtMovementDetail{
+			MovementID:   m.ID,
+			Description:  m.Description,
+			Amount:       -m.HouseholdAmount(),
+			MovementDate: m.MovementDate.Format("2006-01-02T15:04:05Z07:00"),
+			Type:         string(TypeDebtPayment),
+			PayerID:      payerID,
+			PayerName:    m.PayerName,
+		}))
+	}
+}
+
+// Balances nets opposite directions for each pair of people and returns the
+// resulting balances. Pairs that net to zero are kept when they have
+// movements (the debt was settled in the period), and dropped otherwise.
+func (l *DebtLedger) Balances() []DebtBalance {
+	var balances []DebtBalance
+	processed := make(map[string]bool) // Track processed pairs to avoid duplicates
+
+	for debtorID, creditors := range l.amounts {
+		for creditorID, amount := range creditors {
+			pairKey := debtorID + "|" + creditorID
+			reversePairKey := creditorID + "|" + debtorID
+
+			if processed[pairKey] || processed[reversePairKey] {
+				continue
+			}
+
+			// Net out reverse debt if exists
+			reverseAmount := money.Amount(0)
+			if l.amounts[creditorID] != nil {
+				reverseAmount = l.amounts[creditorID][debtorID]
+			}
+
+			netAmount := amount - reverseAmount
+
+			// Combine movements from both directions
+			movements := l.details[debtorID][creditorID]
+			if l.details[creditorID] != nil {
+				movements = append(movements, l.details[creditorID][debtorID]...)
+			}
+
+			// Check if any movement in this pair is cross-household
+			hasCrossHousehold := false
+			for _, md := range movements {
+				if md.IsCrossHousehold {
+					hasCrossHousehold = true
+					break
+				}
+			}
+
+			if netAmount > settledThreshold {
+				balances = append(balances, DebtBalance{
+					DebtorID:         debtorID,
+					DebtorName:       l.names[debtorID],
+					CreditorID:       creditorID,
+					CreditorName:     l.names[creditorID],
+					Amount:           netAmount,
+					Currency:         l.currency,
+					IsCrossHousehold: hasCrossHousehold,
+					Movements:        movements,
+				})
+				processed[pairKey] = true
+			} else if netAmount < -settledThreshold {
+				// Reverse direction
+				balances = append(balances, DebtBalance{
+					DebtorID:         creditorID,
+					DebtorName:       l.names[creditorID],
+					CreditorID:       debtorID,
+					CreditorName:     l.names[debtorID],
+					Amount:           -netAmount,
+					Currency:         l.currency,
+					IsCrossHousehold: hasCrossHousehold,
+					Movements:        movements,
+				})
+				processed[reversePairKey] = true
+			} else if len(movements) > 0 {
+				// Balance is zero but there are movements - show it
+				balances = append(balances, DebtBalance{
+					DebtorID:         debtorID,
+					DebtorName:       l.names[debtorID],
+					CreditorID:       creditorID,
+					CreditorName:     l.names[creditorID],
+					Amount:           0,
+					Currency:         l.currency,
+					IsCrossHousehold: hasCrossHousehold,
+					Movements:        movements,
+				})
+				processed[pairKey] = true
+				processed[reversePairKey] = true
+			} else {
+				// Balanced out with no movements - don't show
+				processed[pairKey] = true
+				processed[reversePairKey] = true
+			}
+		}
+	}
+
+	return balances
+}
+
+// withOriginal keeps the amount in the movement's own currency on the detail
+// of a movement that was converted to the household currency
+func withOriginal(m *Movement, original money.Amount, detail DebtMovementDetail) DebtMovementDetail {
+	if m.ExchangeRate != nil {
+		detail.OriginalAmount = &original
+		detail.OriginalCurrency = m.Currency
+	}
+	return detail
+}
+
+// linkedContactUsers maps each contact linked to a user account to that user
+func linkedContactUsers(contacts []*households.Contact) map[string]string {
+	linked := make(map[string]string)
+	for _, c := range contacts {
+		if c.LinkedUserID != nil {
+			linked[c.ID] = *c.LinkedUserID
+		}
+	}
+	return linked
+}
+
+// SettlementTransfer is a single payment that settles part of the debts
+type SettlementTransfer struct {
+	FromID   string
+	FromName string
+	ToID     string
+	ToName   string
+	Amount   money.Amount
+}
+
+// SimplifyDebts computes a minimal set of transfers that settles all the given
+// balances. Each person's net position is computed first (what they are owed
+// minus what they owe), then the largest debtor pays the largest creditor until
+// everyone is even. This removes chains like A→B→C (A pays C directly) and
+// needs at most n-1 transfers for n people. Results are deterministic.
+func SimplifyDebts(balances []DebtBalance) []SettlementTransfer {
+	net := make(map[string]money.Amount)
+	names := make(map[string]string)
+	for _, b := range balances {
+		net[b.DebtorID] -= b.Amount
+		net[b.CreditorID] += b.Amount
+		names[b.DebtorID] = b.DebtorName
+		names[b.CreditorID] = b.CreditorName
+	}
+
+	type position struct {
+		id     string
+		amount money.Amount
+	}
+	var debtors, creditors []position
+	for id, amount := range net {
+		if amount < -settledThreshold {
+			debtors = append(debtors, position{id, -amount})
+		} else if amount > settledThreshold {
+			creditors = append(creditors, position{id, amount})
+		}
+	}
+	byAmountDesc := func(p []position) func(i, j int) bool {
+		return func(i, j int) bool {
+			if p[i].amount != p[j].amount {
+				return p[i].amount > p[j].amount
+			}
+			return p[i].id < p[j].id
+		}
+	}
+	sort.Slice(debtors, byAmountDesc(debtors))
+	sort.Slice(creditors, byAmountDesc(creditors))
+
+	transfers := make([]SettlementTransfer, 0)
+	i, j := 0, 0
+	for i < len(debtors) && j < len(creditors) {
+		amount := min(debtors[i].amount, creditors[j].amount)
+		transfers = append(transfers, SettlementTransfer{
+			FromID:   debtors[i].id,
+			FromName: names[debtors[i].id],
+			ToID:     creditors[j].id,
+			ToName:   names[creditors[j].id],
+			Amount:   amount,
+		})
+
+		debtors[i].amount -= amount
+		creditors[j].amount -= amount
+		if debtors[i].amount <= settledThreshold {
+			i++
+		}
+		if creditors[j].amount <= settledThreshold {
+			j++
+		}
+	}
+
+	return transfers
+}
@@ -0,0 +1,290 @@
+package movements
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blanquicet/conti/backend/internal/households"
+	"github.com/blanquicet/conti/backend/internal/money"
+)
+
+func TestSimplifyDebts(t *testing.T) {
+	tests := []struct {
+		name      string
+		balances  []DebtBalance
+		wantCount int
+	}{
+		{
+			name:      "no balances",
+			balances:  nil,
+			wantCount: 0,
+		},
+		{
+			name: "single debt stays as is",
+			balances: []DebtBalance{
+				{DebtorID: "a", CreditorID: "b", Amount: money.New(50000)},
+			},
+			wantCount: 1,
+		},
+		{
+			name: "chain A->B->C collapses to A->C",
+			balances: []DebtBalance{
+				{DebtorID: "a", CreditorID: "b", Amount: money.New(100000)},
+				{DebtorID: "b", CreditorID: "c", Amount: money.New(100000)},
+			},
+			wantCount: 1,
+		},
+		{
+			name: "cycle cancels out",
+			balances: []DebtBalance{
+				{DebtorID: "a", CreditorID: "b", Amount: money.New(30000)},
+				{DebtorID: "b", CreditorID: "c", Amount: money.New(30000)},
+				{DebtorID: "c", CreditorID: "a", Amount: money.New(30000)},
+			},
+			wantCount: 0,
+		},
+		{
+			name: "four people need at most three transfers",
+			balances: []DebtBalance{
+				{DebtorID: "a", CreditorID: "b", Amount: money.New(40000)},
+				{DebtorID: "a", CreditorID: "c", Amount: money.New(10000)},
+				{DebtorID: "b", CreditorID: "c", Amount: money.New(20000)},
+				{DebtorID: "d", CreditorID: "c", Amount: money.New(15000)},
+				{DebtorID: "d", CreditorID: "b", Amount: money.New(5000)},
+			},
+			wantCount: 3,
+		},
+		{
+			name: "amounts under the settled threshold are ignored",
+			balances: []DebtBalance{
+				{DebtorID: "a", CreditorID: "b", Amount: money.FromMinor(50)},
+			},
+			wantCount: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transfers := SimplifyDebts(tt.balances)
+			if len(transfers) != tt.wantCount {
+				t.Fatalf("got %d transfers, want %d: %+v", len(transfers), tt.wantCount, transfers)
+			}
+
+			// Applying the transfers must leave everyone even
+			net := make(map[string]money.Amount)
+			for _, b := range tt.balances {
+				net[b.DebtorID] -= b.Amount
+				net[b.CreditorID] += b.Amount
+			}
+			for _, tr := range transfers {
+				if tr.Amount <= 0 {
+					t.Errorf("transfer with non-positive amount: %+v", tr)
+				}
+				net[tr.FromID] += tr.Amount
+				net[tr.ToID] -= tr.Amount
+			}
+			for id, amount := range net {
+				if amount.Abs() > settledThreshold {
+					t.Errorf("%s is left with %v after settlement", id, amount)
+				}
+			}
+		})
+	}
+}
+
+func TestSimplifyDebts_Deterministic(t *testing.T) {
+	balances := []DebtBalance{
+		{DebtorID: "a", CreditorID: "c", Amount: money.New(10000)},
+		{DebtorID: "b", CreditorID: "c", Amount: money.New(10000)},
+		{DebtorID: "b", CreditorID: "d", Amount: money.New(10000)},
+	}
+
+	first := SimplifyDebts(balances)
+	for i := 0; i < 20; i++ {
+		again := SimplifyDebts(balances)
+		if len(again) != len(first) {
+			t.Fatalf("run %d: got %d transfers, want %d", i, len(again), len(first))
+		}
+		for j := range first {
+			if again[j] != first[j] {
+				t.Fatalf("run %d: transfer %d = %+v, want %+v", i, j, again[j], first[j])
+			}
+		}
+	}
+}
+
+func TestDebtLedger_PaymentAwaitingConfirmation(t *testing.T) {
+	date := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	ana, luis := "ana", "luis"
+	split := &Movement{
+		ID:           "m1",
+		Type:         TypeSplit,
+		Amount:       money.New(100000),
+		MovementDate: date,
+		PayerUserID:  &ana,
+		PayerName:    "Ana",
+		Participants: []Participant{
+			{ParticipantUserID: &ana, ParticipantName: "Ana", Percentage: 0.5},
+			{ParticipantContactID: &luis, ParticipantName: "Luis", Percentage: 0.5},
+		},
+	}
+	payment := func(status *ConfirmationStatus) *Movement {
+		return &Movement{
+			ID:                 "m2",
+			Type:               TypeDebtPayment,
+			Amount:             money.New(50000),
+			MovementDate:       date,
+			PayerContactID:     &luis,
+			PayerName:          "Luis",
+			CounterpartyUserID: &ana,
+			ConfirmationStatus: status,
+		}
+	}
+	statusPtr := func(s ConfirmationStatus) *ConfirmationStatus { return &s }
+
+	tests := []struct {
+		name       string
+		status     *ConfirmationStatus
+		wantAmount money.Amount
+	}{
+		{"no confirmation needed", nil, 0},
+		{"pending is not netted", statusPtr(ConfirmationPending), money.New(50000)},
+		{"disputed is not netted", statusPtr(ConfirmationDisputed), money.New(50000)},
+		{"confirmed is netted", statusPtr(ConfirmationConfirmed), 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ledger := NewDebtLedger("COP")
+			ledger.AddMovement(split)
+			ledger.AddMovement(payment(tt.status))
+
+			balances := ledger.Balances()
+			if len(balances) != 1 {
+				t.Fatalf("got %d balances, want 1", len(balances))
+			}
+			if balances[0].Amount != tt.wantAmount {
+				t.Errorf("amount = %v, want %v", balances[0].Amount, tt.wantAmount)
+			}
+		})
+	}
+}
+
+func TestDebtLedger_LinkedContact(t *testing.T) {
+	date := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	ana, luis, luisContact := "ana", "luis", "c-luis"
+	// Ana paid for both, and Luis paid her back as a member of the other
+	// household: the contact linked to him and his user net together
+	split := &Movement{
+		ID:           "m1",
+		Type:         TypeSplit,
+		Amount:       money.New(100000),
+		MovementDate: date,
+		PayerUserID:  &ana,
+		PayerName:    "Ana",
+		Participants: []Participant{
+			{ParticipantUserID: &ana, ParticipantName: "Ana", Percentage: 0.5},
+			{ParticipantContactID: &luisContact, ParticipantName: "Luis", Percentage: 0.5},
+		},
+	}
+	payment := &Movement{
+		ID:                 "m2",
+		Type:               TypeDebtPayment,
+		Amount:             money.New(20000),
+		MovementDate:       date,
+		PayerUserID:        &luis,
+		PayerName:          "Luis",
+		CounterpartyUserID: &ana,
+	}
+
+	ledger := NewDebtLedger("COP")
+	ledger.LinkContacts([]*households.Contact{{ID: luisContact, Name: "Luis", LinkedUserID: &luis}})
+	ledger.AddMovement(split)
+	ledger.AddMovement(payment)
+
+	balances := ledger.Balances()
+	if len(balances) != 1 {
+		t.Fatalf("got %d balances, want 1", len(balances))
+	}
+	if b := balances[0]; b.DebtorID != luis || b.CreditorID != ana || b.Amount != money.New(30000) {
+		t.Errorf("balance = %s owes %s %v, want luis owes ana 30000", b.DebtorID, b.CreditorID, b.Amount)
+	}
+}
+
+func TestDebtLedger_ConvertedMovement(t *testing.T) {
+	ana, luis := "ana", "luis"
+	rate := 4000.0
+	split := &Movement{
+		ID:           "m1",
+		Type:         TypeSplit,
+		Amount:       money.New(101),
+		Currency:     "USD",
+		ExchangeRate: &rate,
+		BaseAmount:   money.New(404000),
+		MovementDate: time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC),
+		PayerUserID:  &ana,
+		PayerName:    "Ana",
+		Participants: []Participant{
+			{ParticipantUserID: &ana, ParticipantName: "Ana", Percentage: 0.5},
+			{ParticipantContactID: &luis, ParticipantName: "Luis", Percentage: 0.5},
+		},
+	}
+
+	ledger := NewDebtLedger("COP")
+	ledger.AddMovement(split)
+
+	balances := ledger.Balances()
+	if len(balances) != 1 {
+		t.Fatalf("got %d balances, want 1", len(balances))
+	}
+	b := balances[0]
+	if b.Amount != money.New(202000) || b.Currency != "COP" {
+		t.Errorf("balance = %v %s, want 202000 COP", b.Amount, b.Currency)
+	}
+
+	detail := b.Movements[0]
+	if detail.OriginalAmount == nil || *detail.OriginalAmount != money.FromMinor(5050) || detail.OriginalCurrency != "USD" {
+		t.Errorf("detail original = %v %q, want 50.5 USD", detail.OriginalAmount, detail.OriginalCurrency)
+	}
+}
+
+func TestDebtLedger_Refund(t *testing.T) {
+	date := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	ana, luis, original := "ana", "luis", "m1"
+	participants := []Participant{
+		{ParticipantUserID: &ana, ParticipantName: "Ana", Percentage: 0.5},
+		{ParticipantContactID: &luis, ParticipantName: "Luis", Percentage: 0.5},
+	}
+	split := &Movement{
+		ID:           original,
+		Type:         TypeSplit,
+		Amount:       money.New(100000),
+		MovementDate: date,
+		PayerUserID:  &ana,
+		PayerName:    "Ana",
+		Participants: participants,
+	}
+	refund := &Movement{
+		ID:           "m2",
+		Type:         TypeSplit,
+		Amount:       money.New(40000),
+		MovementDate: date.AddDate(0, 0, 5),
+		PayerUserID:  &ana,
+		PayerName:    "Ana",
+		Participants: participants,
+		RefundOfID:   &original,
+	}
+
+	ledger := NewDebtLedger("COP")
+	ledger.AddMovement(split)
+	ledger.AddMovement(refund)
+
+	balances := ledger.Balances()
+	if len(balances) != 1 {
+		t.Fatalf("got %d balances, want 1", len(balances))
+	}
+	// Luis owed half of 100000 and gets back half of the 40000 refund
+	if balances[0].Amount != money.New(30000) {
+		t.Errorf("amount = %v, want 30000", balances[0].Amount)
+	}
+}
@@ -3,13 +3,18 @@ package movements
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/blanquicet/conti/backend/internal/auth"
 	"github.com/blanquicet/conti/backend/internal/categorygroups"
+	"github.com/blanquicet/conti/backend/internal/fx"
 	"github.com/blanquicet/conti/backend/internal/households"
+	"github.com/blanquicet/conti/backend/internal/money"
 	"github.com/blanquicet/conti/backend/internal/paymentmethods"
 )
 
@@ -79,10 +84,22 @@ func (h *Handler) HandleCreate(w http.ResponseWriter, r *http.Request) {
 		switch err {
 		case ErrNotAuthorized:
 			http.Error(w, "Not authorized", http.StatusForbidden)
+		case ErrEventNotFound:
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case ErrEventClosed:
+			http.Error(w, err.Error(), http.StatusConflict)
 		case ErrInvalidMovementType, ErrInvalidAmount, ErrPayerRequired,
 			ErrCounterpartyRequired, ErrCounterpartyNotAllowed,
 			ErrParticipantsRequired, ErrParticipantsNotAllowed,
-			ErrInvalidPercentageSum, ErrCategoryRequired, ErrPaymentMethodRequired:
+			ErrInvalidPercentageSum, ErrInvalidParticipantAmounts,
+			ErrCategoryRequired, ErrPaymentMethodRequired,
+			ErrInvalidExchangeRate, ErrExchangeRateNotFound, fx.ErrInvalidCurrency,
+			ErrInvalidTag, ErrTooManyTags,
+			ErrTransferAccountsRequired, ErrSameTransferAccount, ErrSourceAccountNotAllowed,
+			ErrPaymentMethodNotAllowed, ErrTransferPayerNotMember, ErrInvalidTransferAccount,
+			ErrRefundExceedsOriginal, ErrRefundCurrency,
+			ErrInvalidInstallments, ErrInvalidInstallmentRate, ErrInstallmentsRequired,
+			ErrInstallmentsNotAllowed, ErrInstallmentsNotCreditCard:
 			http.Error(w, err.Error(), http.StatusBadRequest)
 		default:
 			http.Error(w, "Internal server error", http.StatusInternalServerError)
@@ -118,17 +135,10 @@ func (h *Handler) HandleList(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Parse query parameters for filters
-	filters := &ListMovementsFilters{}
-	
-	if typeStr := r.URL.Query().Get("type"); typeStr != "" {
-		movType := MovementType(typeStr)
-		filters.Type = &movType
-	}
-	if month := r.URL.Query().Get("month"); month != "" {
-		filters.Month = &month
-	}
-	if memberID := r.URL.Query().Get("member_id"); memberID != "" {
-		filters.MemberID = &memberID
+	filters, err := ParseListFilters(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
 
 	// Get movements
@@ -140,6 +150,11 @@ func (h *Handler) HandleList(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, err.Error(), http.StatusNotFound)
 			return
 		}
+		if errors.Is(err, ErrInvalidSort) || errors.Is(err, ErrInvalidLimit) ||
+			errors.Is(err, ErrInvalidCursor) || errors.Is(err, ErrInvalidAmountRange) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
@@ -150,6 +165,92 @@ func (h *Handler) HandleList(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// ParseListFilters reads the filters, sort and page of GET /movements (also
+// used by the export):
+//
+//	type, month, start_date, end_date (YYYY-MM-DD), member_id, event_id, tag,
+//	q (description words), amount_min, amount_max, category_id,
+//	category_group_id, payment_method_id, contact_id, template_id,
+//	from_template (true/false), account_id,
+//	sort (date, amount, created), order (asc, desc), limit, cursor
+func ParseListFilters(r *http.Request) (*ListMovementsFilters, error) {
+	query := r.URL.Query()
+	filters := &ListMovementsFilters{}
+	optional := func(name string) *string {
+		if value := strings.TrimSpace(query.Get(name)); value != "" {
+			return &value
+		}
+		return nil
+	}
+
+	if typeStr := query.Get("type"); typeStr != "" {
+		movType := MovementType(typeStr)
+		filters.Type = &movType
+	}
+	filters.Month = optional("month")
+	filters.MemberID = optional("member_id")
+	filters.EventID = optional("event_id")
+	filters.Tag = optional("tag")
+	filters.Search = optional("q")
+	filters.CategoryID = optional("category_id")
+	filters.CategoryGroupID = optional("category_group_id")
+	filters.PaymentMethodID = optional("payment_method_id")
+	filters.ContactID = optional("contact_id")
+	filters.TemplateID = optional("template_id")
+	filters.AccountID = optional("account_id")
+
+	for name, target := range map[string]**time.Time{"start_date": &filters.StartDate, "end_date": &filters.EndDate} {
+		if value := query.Get(name); value != "" {
+			date, err := time.Parse("2006-01-02", value)
+			if err != nil {
+				return nil, errors.New(name + " must be YYYY-MM-DD")
+			}
+			*target = &date
+		}
+	}
+	for name, target := range map[string]**money.Amount{"amount_min": &filters.AmountMin, "amount_max": &filters.AmountMax} {
+		if value := query.Get(name); value != "" {
+			amount, err := money.Parse(value)
+			if err != nil {
+				return nil, errors.New(name + " must be an amount")
+			}
+			*target = &amount
+		}
+	}
+	if value := query.Get("from_template"); value != "" {
+		fromTemplate, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, errors.New("from_template must be true or false")
+		}
+		filters.FromTemplate = &fromTemplate
+	}
+
+	filters.Sort = SortField(query.Get("sort"))
+	switch query.Get("order") {
+	case "", "desc":
+	case "asc":
+		filters.Ascending = true
+	default:
+		return nil, errors.New("order must be asc or desc")
+	}
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			return nil, ErrInvalidLimit
+		}
+		filters.Limit = limit
+	}
+	if value := query.Get("cursor"); value != "" {
+		cursor, err := DecodeCursor(value)
+		if err != nil {
+			return nil, err
+		}
+		filters.Cursor = cursor
+	}
+
+	return filters, nil
+}
+
 // HandleGetByID retrieves a single movement by ID
 // GET /movements/{id}
 func (h *Handler) HandleGetByID(w http.ResponseWriter, r *http.Request) {
@@ -240,7 +341,18 @@ func (h *Handler) HandleUpdate(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, "Movement not found", http.StatusNotFound)
 		case ErrNotAuthorized:
 			http.Error(w, "Not authorized", http.StatusForbidden)
-		case ErrInvalidAmount:
+		case ErrEventNotFound:
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case ErrEventClosed:
+			http.Error(w, err.Error(), http.StatusConflict)
+		case ErrInvalidAmount, ErrInvalidParticipantAmounts,
+			ErrInvalidExchangeRate, ErrExchangeRateNotFound, fx.ErrInvalidCurrency,
+			ErrInvalidTag, ErrTooManyTags,
+			ErrTransferAccountsRequired, ErrSameTransferAccount, ErrSourceAccountNotAllowed,
+			ErrPaymentMethodNotAllowed, ErrTransferPayerNotMember, ErrInvalidTransferAccount,
+			ErrRefundExceedsOriginal, ErrRefundCurrency,
+			ErrInvalidInstallments, ErrInvalidInstallmentRate, ErrInstallmentsRequired,
+			ErrInstallmentsNotAllowed, ErrInstallmentsNotCreditCard:
 			http.Error(w, err.Error(), http.StatusBadRequest)
 		default:
 			http.Error(w, "Internal server error", http.StatusInternalServerError)
@@ -292,6 +404,338 @@ func (h *Handler) HandleGetDebtConsolidation(w http.ResponseWriter, r *http.Requ
 	}
 }
 
+// HandleGetSettlementPlan proposes the minimal set of payments that settles all debts
+// GET /movements/debts/settlements?month=YYYY-MM
+func (h *Handler) HandleGetSettlementPlan(w http.ResponseWriter, r *http.Request) {
+	// Get user from session
+	cookie, err := r.Cookie(h.cookieName)
+	if err != nil {
+		h.logger.Error("no session cookie", "error", err)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	user, err := h.authSvc.GetUserBySession(r.Context(), cookie.Value)
+	if err != nil {
+		h.logger.Error("failed to get user by session", "error", err)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// Parse optional month filter
+	var month *string
+	if monthStr := r.URL.Query().Get("month"); monthStr != "" {
+		month = &monthStr
+	}
+
+	plan, err := h.service.GetSettlementPlan(r.Context(), user.ID, month)
+	if err != nil {
+		h.logger.Error("failed to get settlement plan", "error", err, "user_id", user.ID)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(plan); err != nil {
+		h.logger.Error("failed to encode response", "error", err)
+	}
+}
+
+// HandleSettle creates the chosen settlement payments in a single transaction
+// POST /movements/debts/settle
+func (h *Handler) HandleSettle(w http.ResponseWriter, r *http.Request) {
+	// Get user from session
+	cookie, err := r.Cookie(h.cookieName)
+	if err != nil {
+		h.logger.Error("no session cookie", "error", err)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	user, err := h.authSvc.GetUserBySession(r.Context(), cookie.Value)
+	if err != nil {
+		h.logger.Error("failed to get user by session", "error", err)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req SettleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Error("failed to decode request", "error", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	inputs := make([]*CreateMovementInput, 0, len(req.Movements))
+	for _, m := range req.Movements {
+		input, err := m.ToInput()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		inputs = append(inputs, input)
+	}
+
+	created, err := h.service.SettleDebts(r.Context(), user.ID, inputs)
+	if err != nil {
+		h.logger.Error("failed to settle debts", "error", err, "user_id", user.ID)
+
+		switch err {
+		case ErrNotAuthorized:
+			http.Error(w, "Not authorized", http.StatusForbidden)
+		case ErrNoSettlements, ErrSettlementNotDebtPayment,
+			ErrInvalidAmount, ErrPayerRequired, ErrCounterpartyRequired,
+			ErrParticipantsNotAllowed, ErrExchangeRateNotFound:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	h.logger.Info("debts settled", "count", len(created), "user_id", user.ID)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{
+		"movements": created,
+	}); err != nil {
+		h.logger.Error("failed to encode response", "error", err)
+	}
+}
+
+// maxImportSize caps the size of an uploaded movements CSV
+const maxImportSize = 5 << 20 // 5MB
+
+// HandleImport handles POST /movements/import (multipart form).
+// Fields: "file" (the CSV), "mapping" (ImportMapping as JSON),
+// "dry_run" (defaults to true; send "false" to import),
+// "include_duplicates" and "skip_rows" (comma-separated row numbers).
+func (h *Handler) HandleImport(w http.ResponseWriter, r *http.Request) {
+	// Get user from session
+	cookie, err := r.Cookie(h.cookieName)
+	if err != nil {
+		h.logger.Error("no session cookie", "error", err)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	user, err := h.authSvc.GetUserBySession(r.Context(), cookie.Value)
+	if err != nil {
+		h.logger.Error("failed to get user by session", "error", err)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+	if err := r.ParseMultipartForm(maxImportSize); err != nil {
+		http.Error(w, "Invalid form or file too large (max 5MB)", http.StatusBadRequest)
+		return
+	}
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "Field 'file' is required", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	var mapping ImportMapping
+	if err := json.Unmarshal([]byte(r.FormValue("mapping")), &mapping); err != nil {
+		http.Error(w, "Field 'mapping' must be valid JSON", http.StatusBadRequest)
+		return
+	}
+
+	opts := &ImportOptions{DryRun: true}
+	if v := r.FormValue("dry_run"); v != "" {
+		if opts.DryRun, err = strconv.ParseBool(v); err != nil {
+			http.Error(w, "Invalid dry_run value", http.StatusBadRequest)
+			return
+		}
+	}
+	if v := r.FormValue("include_duplicates"); v != "" {
+		if opts.IncludeDuplicates, err = strconv.ParseBool(v); err != nil {
+			http.Error(w, "Invalid include_duplicates value", http.StatusBadRequest)
+			return
+		}
+	}
+	if v := r.FormValue("skip_rows"); v != "" {
+		for _, s := range strings.Split(v, ",") {
+			row, err := strconv.Atoi(strings.TrimSpace(s))
+			if err != nil {
+				http.Error(w, "Invalid skip_rows value", http.StatusBadRequest)
+				return
+			}
+			opts.SkipRows = append(opts.SkipRows, row)
+		}
+	}
+
+	result, err := h.service.Import(r.Context(), user.ID, file, &mapping, opts)
+	if err != nil {
+		h.logger.Error("failed to import movements", "error", err, "user_id", user.ID)
+
+		switch {
+		case errors.Is(err, ErrInvalidImportMapping), errors.Is(err, ErrInvalidImportFile),
+			errors.Is(err, ErrImportRowFailed):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	if !opts.DryRun {
+		h.logger.Info("movements imported", "count", result.Imported, "user_id", user.ID)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		h.logger.Error("failed to encode response", "error", err)
+	}
+}
+
+// HandleListPendingConfirmations handles GET /movements/confirmations
+// Lists debt payments other households recorded as paid to us that await our response.
+func (h *Handler) HandleListPendingConfirmations(w http.ResponseWriter, r *http.Request) {
+	// Get user from session
+	cookie, err := r.Cookie(h.cookieName)
+	if err != nil {
+		h.logger.Error("no session cookie", "error", err)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	user, err := h.authSvc.GetUserBySession(r.Context(), cookie.Value)
+	if err != nil {
+		h.logger.Error("failed to get user by session", "error", err)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	pending, err := h.service.ListPendingConfirmations(r.Context(), user.ID)
+	if err != nil {
+		h.logger.Error("failed to list pending confirmations", "error", err, "user_id", user.ID)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{
+		"movements": pending,
+	}); err != nil {
+		h.logger.Error("failed to encode response", "error", err)
+	}
+}
+
+// HandleListTags handles GET /movements/tags (the household's tags, for
+// suggestions and the tag filter)
+func (h *Handler) HandleListTags(w http.ResponseWriter, r *http.Request) {
+	// Get user from session
+	cookie, err := r.Cookie(h.cookieName)
+	if err != nil {
+		h.logger.Error("no session cookie", "error", err)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	user, err := h.authSvc.GetUserBySession(r.Context(), cookie.Value)
+	if err != nil {
+		h.logger.Error("failed to get user by session", "error", err)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	tags, err := h.service.ListTags(r.Context(), user.ID)
+	if err != nil {
+		h.logger.Error("failed to list tags", "error", err, "user_id", user.ID)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{
+		"tags": tags,
+	}); err != nil {
+		h.logger.Error("failed to encode response", "error", err)
+	}
+}
+
+// DisputeRequest is the request body for disputing a debt payment
+type DisputeRequest struct {
+	Reason *string `json:"reason,omitempty"`
+}
+
+// HandleConfirm handles POST /movements/{id}/confirm
+func (h *Handler) HandleConfirm(w http.ResponseWriter, r *http.Request) {
+	h.handleConfirmationResponse(w, r, ConfirmationConfirmed)
+}
+
+// HandleDispute handles POST /movements/{id}/dispute
+func (h *Handler) HandleDispute(w http.ResponseWriter, r *http.Request) {
+	h.handleConfirmationResponse(w, r, ConfirmationDisputed)
+}
+
+// handleConfirmationResponse records the receiver's response to a debt payment
+func (h *Handler) handleConfirmationResponse(w http.ResponseWriter, r *http.Request, status ConfirmationStatus) {
+	// Get user from session
+	cookie, err := r.Cookie(h.cookieName)
+	if err != nil {
+		h.logger.Error("no session cookie", "error", err)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	user, err := h.authSvc.GetUserBySession(r.Context(), cookie.Value)
+	if err != nil {
+		h.logger.Error("failed to get user by session", "error", err)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// Get movement ID from path
+	id := r.PathValue("id")
+	if id == "" {
+		http.Error(w, "Movement ID is required", http.StatusBadRequest)
+		return
+	}
+
+	// Dispute reason is optional; an empty body is fine
+	var req DisputeRequest
+	if status == ConfirmationDisputed && r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.logger.Error("failed to decode request", "error", err)
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	movement, err := h.service.RespondToConfirmation(r.Context(), user.ID, id, status, req.Reason)
+	if err != nil {
+		h.logger.Error("failed to respond to confirmation", "error", err, "movement_id", id, "user_id", user.ID)
+
+		switch err {
+		case ErrMovementNotFound:
+			http.Error(w, "Movement not found", http.StatusNotFound)
+		case ErrNotAuthorized:
+			http.Error(w, "Not authorized", http.StatusForbidden)
+		case ErrNotAwaitingConfirmation:
+			http.Error(w, err.Error(), http.StatusConflict)
+		case ErrInvalidConfirmationStatus:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	h.logger.Info("debt payment confirmation recorded", "movement_id", id, "status", status, "user_id", user.ID)
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(movement); err != nil {
+		h.logger.Error("failed to encode response", "error", err)
+	}
+}
+
 // HandleDelete deletes a movement
 // DELETE /movements/{id}
 func (h *Handler) HandleDelete(w http.ResponseWriter, r *http.Request) {
@@ -326,6 +770,8 @@ func (h *Handler) HandleDelete(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, "Movement not found", http.StatusNotFound)
 		case ErrNotAuthorized:
 			http.Error(w, "Not authorized", http.StatusForbidden)
+		case ErrHasRefunds:
+			http.Error(w, err.Error(), http.StatusConflict)
 		default:
 			http.Error(w, "Internal server error", http.StatusInternalServerError)
 		}
@@ -335,17 +781,284 @@ func (h *Handler) HandleDelete(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// HandleListTrash handles GET /movements/trash (deleted movements that can
+// still be restored)
+func (h *Handler) HandleListTrash(w http.ResponseWriter, r *http.Request) {
+	// Get user from session
+	cookie, err := r.Cookie(h.cookieName)
+	if err != nil {
+		h.logger.Error("no session cookie", "error", err)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	user, err := h.authSvc.GetUserBySession(r.Context(), cookie.Value)
+	if err != nil {
+		h.logger.Error("failed to get user by session", "error", err)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	movements, err := h.service.ListTrash(r.Context(), user.ID)
+	if err != nil {
+		h.logger.Error("failed to list trash", "error", err, "user_id", user.ID)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{
+		"movements": movements,
+	}); err != nil {
+		h.logger.Error("failed to encode response", "error", err)
+	}
+}
+
+// HandleRestore handles POST /movements/{id}/restore
+func (h *Handler) HandleRestore(w http.ResponseWriter, r *http.Request) {
+	// Get user from session
+	cookie, err := r.Cookie(h.cookieName)
+	if err != nil {
+		h.logger.Error("no session cookie", "error", err)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	user, err := h.authSvc.GetUserBySession(r.Context(), cookie.Value)
+	if err != nil {
+		h.logger.Error("failed to get user by session", "error", err)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// Get movement ID from path
+	id := r.PathValue("id")
+	if id == "" {
+		http.Error(w, "Movement ID is required", http.StatusBadRequest)
+		return
+	}
+
+	movement, err := h.service.Restore(r.Context(), user.ID, id)
+	if err != nil {
+		h.logger.Error("failed to restore movement", "error", err, "movement_id", id, "user_id", user.ID)
+		
+		switch err {
+		case ErrMovementNotFound:
+			http.Error(w, "Movement not found in trash", http.StatusNotFound)
+		case ErrRefundOriginalDeleted:
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(movement); err != nil {
+		h.logger.Error("failed to encode response", "error", err)
+	}
+}
+
+// RefundRequest is the request body for refunding a movement
+type RefundRequest struct {
+	Amount       *money.Amount `json:"amount,omitempty"` // Defaults to what is left to refund
+	MovementDate string        `json:"movement_date"`    // YYYY-MM-DD format
+	Description  *string       `json:"description,omitempty"`
+}
+
+// HandleRefund handles POST /movements/{id}/refund
+func (h *Handler) HandleRefund(w http.ResponseWriter, r *http.Request) {
+	// Get user from session
+	cookie, err := r.Cookie(h.cookieName)
+	if err != nil {
+		h.logger.Error("no session cookie", "error", err)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	user, err := h.authSvc.GetUserBySession(r.Context(), cookie.Value)
+	if err != nil {
+		h.logger.Error("failed to get user by session", "error", err)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// Get movement ID from path
+	id := r.PathValue("id")
+	if id == "" {
+		http.Error(w, "Movement ID is required", http.StatusBadRequest)
+		return
+	}
+
+	var req RefundRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Error("failed to decode request", "error", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	movementDate, err := time.Parse("2006-01-02", req.MovementDate)
+	if err != nil {
+		http.Error(w, "Invalid movement_date, use YYYY-MM-DD", http.StatusBadRequest)
+		return
+	}
+
+	refund, err := h.service.Refund(r.Context(), user.ID, id, &CreateRefundInput{
+		Amount:       req.Amount,
+		MovementDate: movementDate,
+		Description:  req.Description,
+	})
+	if err != nil {
+		h.logger.Error("failed to refund movement", "error", err, "movement_id", id, "user_id", user.ID)
+
+		switch err {
+		case ErrMovementNotFound:
+			http.Error(w, "Movement not found", http.StatusNotFound)
+		case ErrNotAuthorized:
+			http.Error(w, "Not authorized", http.StatusForbidden)
+		case ErrEventClosed:
+			http.Error(w, err.Error(), http.StatusConflict)
+		case ErrNotRefundable, ErrRefundExceedsOriginal, ErrInvalidAmount,
+			ErrCategoryRequired, ErrPaymentMethodRequired, ErrInvalidParticipantAmounts,
+			ErrExchangeRateNotFound:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	h.logger.Info("movement refunded", "movement_id", id, "refund_id", refund.ID, "user_id", user.ID)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(refund); err != nil {
+		h.logger.Error("failed to encode response", "error", err)
+	}
+}
+
+// HandleHistory handles GET /movements/{id}/history (field-level changes,
+// newest first)
+func (h *Handler) HandleHistory(w http.ResponseWriter, r *http.Request) {
+	// Get user from session
+	cookie, err := r.Cookie(h.cookieName)
+	if err != nil {
+		h.logger.Error("no session cookie", "error", err)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	user, err := h.authSvc.GetUserBySession(r.Context(), cookie.Value)
+	if err != nil {
+		h.logger.Error("failed to get user by session", "error", err)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// Get movement ID from path
+	id := r.PathValue("id")
+	if id == "" {
+		http.Error(w, "Movement ID is required", http.StatusBadRequest)
+		return
+	}
+
+	history, err := h.service.History(r.Context(), user.ID, id)
+	if err != nil {
+		h.logger.Error("failed to get movement history", "error", err, "movement_id", id, "user_id", user.ID)
+		
+		switch err {
+		case ErrMovementNotFound:
+			http.Error(w, "Movement not found", http.StatusNotFound)
+		case ErrNotAuthorized:
+			http.Error(w, "Not authorized", http.StatusForbidden)
+		default:
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{
+		"history": history,
+	}); err != nil {
+		h.logger.Error("failed to encode response", "error", err)
+	}
+}
+
+// HandleRevert handles POST /movements/{id}/history/{versionId}/revert
+func (h *Handler) HandleRevert(w http.ResponseWriter, r *http.Request) {
+	// Get user from session
+	cookie, err := r.Cookie(h.cookieName)
+	if err != nil {
+		h.logger.Error("no session cookie", "error", err)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	user, err := h.authSvc.GetUserBySession(r.Context(), cookie.Value)
+	if err != nil {
+		h.logger.Error("failed to get user by session", "error", err)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id := r.PathValue("id")
+	versionID := r.PathValue("versionId")
+	if id == "" || versionID == "" {
+		http.Error(w, "Movement ID and version ID are required", http.StatusBadRequest)
+		return
+	}
+
+	movement, err := h.service.Revert(r.Context(), user.ID, id, versionID)
+	if err != nil {
+		h.logger.Error("failed to revert movement", "error", err, "movement_id", id, "version_id", versionID, "user_id", user.ID)
+		
+		switch err {
+		case ErrMovementNotFound:
+			http.Error(w, "Movement not found", http.StatusNotFound)
+		case ErrVersionNotFound:
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case ErrNotAuthorized:
+			http.Error(w, "Not authorized", http.StatusForbidden)
+		case ErrEventNotFound:
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case ErrEventClosed:
+			http.Error(w, err.Error(), http.StatusConflict)
+		case ErrInvalidAmount, ErrInvalidParticipantAmounts,
+			ErrInvalidExchangeRate, ErrExchangeRateNotFound, fx.ErrInvalidCurrency,
+			ErrInvalidTag, ErrTooManyTags,
+			ErrTransferAccountsRequired, ErrSameTransferAccount, ErrSourceAccountNotAllowed,
+			ErrPaymentMethodNotAllowed, ErrTransferPayerNotMember, ErrInvalidTransferAccount,
+			ErrRefundExceedsOriginal, ErrRefundCurrency,
+			ErrInvalidInstallments, ErrInvalidInstallmentRate, ErrInstallmentsRequired,
+			ErrInstallmentsNotAllowed, ErrInstallmentsNotCreditCard:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(movement); err != nil {
+		h.logger.Error("failed to encode response", "error", err)
+	}
+}
+
 // Request/Response types
 
 // CreateMovementRequest represents the HTTP request for creating a movement
 type CreateMovementRequest struct {
 	Type         string                      `json:"type"`
 	Description  string                      `json:"description"`
-	Amount       float64                     `json:"amount"`
+	Amount       money.Amount                `json:"amount"`
 	Category     *string                     `json:"category,omitempty"`     // Legacy: category name
 	CategoryID   *string                     `json:"category_id,omitempty"`  // New: category ID (UUID)
 	MovementDate string                      `json:"movement_date"` // YYYY-MM-DD format
 	
+	// Currency of the amount (defaults to the household currency) and optional rate to convert it
+	Currency     string   `json:"currency,omitempty"`
+	ExchangeRate *float64 `json:"exchange_rate,omitempty"`
+	
 	PayerUserID    *string `json:"payer_user_id,omitempty"`
 	PayerContactID *string `json:"payer_contact_id,omitempty"`
 	
@@ -354,18 +1067,32 @@ type CreateMovementRequest struct {
 	
 	PaymentMethodID   *string                  `json:"payment_method_id,omitempty"`
 	ReceiverAccountID *string                  `json:"receiver_account_id,omitempty"`
+	SourceAccountID   *string                  `json:"source_account_id,omitempty"` // Only for TRANSFER
 	Participants      []ParticipantRequestItem `json:"participants,omitempty"`
 	
 	// Template reference (when movement is created from a recurring template)
 	GeneratedFromTemplateID *string `json:"generated_from_template_id,omitempty"`
+	
+	// Event reference (trip, dinner, etc.)
+	EventID *string `json:"event_id,omitempty"`
+	
+	// Installments for credit card purchases
+	Installments           *int     `json:"installments,omitempty"`
+	InstallmentRate        *float64 `json:"installment_rate,omitempty"`
+	InstallmentsFirstCycle *string  `json:"installments_first_cycle,omitempty"` // YYYY-MM-DD format
+}
+
+// SettleRequest represents the HTTP request for creating settlement payments
+type SettleRequest struct {
+	Movements []CreateMovementRequest `json:"movements"`
 }
 
 // ParticipantRequestItem represents a participant in the HTTP request
 type ParticipantRequestItem struct {
-	ParticipantUserID    *string  `json:"participant_user_id,omitempty"`
-	ParticipantContactID *string  `json:"participant_contact_id,omitempty"`
-	Percentage           float64  `json:"percentage"`
-	Amount               *float64 `json:"amount,omitempty"`
+	ParticipantUserID    *string       `json:"participant_user_id,omitempty"`
+	ParticipantContactID *string       `json:"participant_contact_id,omitempty"`
+	Percentage           float64       `json:"percentage"`
+	Amount               *money.Amount `json:"amount,omitempty"`
 }
 
 // ToInput converts CreateMovementRequest to CreateMovementInput
@@ -383,13 +1110,27 @@ func (r *CreateMovementRequest) ToInput() (*CreateMovementInput, error) {
 		Category:                r.Category,
 		CategoryID:              r.CategoryID,
 		MovementDate:            movementDate,
+		Currency:                r.Currency,
+		ExchangeRate:            r.ExchangeRate,
 		PayerUserID:             r.PayerUserID,
 		PayerContactID:          r.PayerContactID,
 		CounterpartyUserID:      r.CounterpartyUserID,
 		CounterpartyContactID:   r.CounterpartyContactID,
 		PaymentMethodID:         r.PaymentMethodID,
 		ReceiverAccountID:       r.ReceiverAccountID,
+		SourceAccountID:         r.SourceAccountID,
 		GeneratedFromTemplateID: r.GeneratedFromTemplateID,
+		EventID:                 r.EventID,
+		Installments:            r.Installments,
+		InstallmentRate:         r.InstallmentRate,
+	}
+
+	if r.InstallmentsFirstCycle != nil {
+		firstCycle, err := time.Parse("2006-01-02", *r.InstallmentsFirstCycle)
+		if err != nil {
+			return nil, err
+		}
+		input.InstallmentsFirstCycle = &firstCycle
 	}
 
 	// Convert participants
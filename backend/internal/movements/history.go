@@ -0,0 +1,200 @@
+package movements
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/blanquicet/conti/backend/internal/audit"
+)
+
+// ErrVersionNotFound is returned when reverting to a version that isn't in
+// the movement's history
+var ErrVersionNotFound = errors.New("version not found")
+
+// historyLimit caps the audit logs read for a movement's timeline
+const historyLimit = 500
+
+// History returns the change timeline of a movement, newest first
+func (s *service) History(ctx context.Context, userID, id string) ([]*audit.HistoryEntry, error) {
+	movement, err := s.GetByID(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	logs, err := s.auditLogs(ctx, movement)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := s.householdsRepo.GetMembers(ctx, movement.HouseholdID)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]string, len(members))
+	for _, member := range members {
+		names[member.UserID] = member.UserName
+	}
+
+	return audit.BuildHistory(logs, historyView, names), nil
+}
+
+// Revert puts a movement back as it was in a version of its history. The
+// snapshot goes through Update, so it is validated and audited like any edit.
+func (s *service) Revert(ctx context.Context, userID, id, versionID string) (*Movement, error) {
+	movement, err := s.GetByID(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	logs, err := s.auditLogs(ctx, movement)
+	if err != nil {
+		return nil, err
+	}
+	version := audit.FindVersion(logs, versionID)
+	if version == nil {
+		return nil, ErrVersionNotFound
+	}
+
+	input, err := revertInput(movement, version.NewValues)
+	if err != nil {
+		return nil, err
+	}
+	return s.Update(ctx, userID, id, input)
+}
+
+// auditLogs returns the successful audit logs of a movement, newest first
+func (s *service) auditLogs(ctx context.Context, movement *Movement) ([]*audit.AuditLog, error) {
+	resourceType := "movement"
+	successOnly := true
+	logs, _, err := s.auditService.Query(ctx, &audit.ListFilters{
+		ResourceType: &resourceType,
+		ResourceID:   &movement.ID,
+		HouseholdID:  &movement.HouseholdID,
+		SuccessOnly:  &successOnly,
+		Limit:        historyLimit,
+	})
+	return logs, err
+}
+
+// revertInput builds the update that turns the current movement into the
+// snapshot. Fields that can't be cleared through Update (category, payment
+// method) keep their current value when the snapshot has none.
+func revertInput(current *Movement, snapshot map[string]interface{}) (*UpdateMovementInput, error) {
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, err
+	}
+	var old Movement
+	if err := json.Unmarshal(data, &old); err != nil {
+		return nil, err
+	}
+
+	input := &UpdateMovementInput{
+		Description:             &old.Description,
+		Amount:                  &old.Amount,
+		MovementDate:            &old.MovementDate,
+		CategoryID:              old.CategoryID,
+		PaymentMethodID:         old.PaymentMethodID,
+		ReceiverAccountID:       old.ReceiverAccountID,
+		ExchangeRate:            old.ExchangeRate,
+		PayerUserID:             old.PayerUserID,
+		PayerContactID:          old.PayerContactID,
+		GeneratedFromTemplateID: old.GeneratedFromTemplateID,
+	}
+	if old.Currency != "" {
+		input.Currency = &old.Currency
+	}
+	if current.Type == TypeTransfer {
+		input.SourceAccountID = old.SourceAccountID
+	}
+	if old.Installments != nil || current.HasInstallments() {
+		// A version without installments was a single payment
+		single := 1
+		input.Installments = &single
+		if old.Installments != nil {
+			input.Installments = old.Installments
+		}
+		input.InstallmentRate = old.InstallmentRate
+		input.InstallmentsFirstCycle = old.InstallmentsFirstCycle
+	}
+	if current.Type == TypeDebtPayment {
+		input.CounterpartyUserID = old.CounterpartyUserID
+		input.CounterpartyContactID = old.CounterpartyContactID
+	}
+
+	// An empty event ID detaches the movement, an empty list clears the tags
+	eventID := ""
+	if old.EventID != nil {
+		eventID = *old.EventID
+	}
+	input.EventID = &eventID
+	tags := old.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	input.Tags = &tags
+
+	if current.Type == TypeSplit && len(old.Participants) > 0 {
+		participants := make([]ParticipantInput, len(old.Participants))
+		for i, p := range old.Participants {
+			participants[i] = ParticipantInput{
+				ParticipantUserID:    p.ParticipantUserID,
+				ParticipantContactID: p.ParticipantContactID,
+				Percentage:           p.Percentage,
+				Amount:               p.Amount,
+			}
+		}
+		input.Participants = &participants
+	}
+
+	return input, nil
+}
+
+// historyView is what the timeline shows of a movement snapshot: names
+// instead of IDs, and participants as name, percentage and amount
+func historyView(snapshot map[string]interface{}) map[string]interface{} {
+	view := map[string]interface{}{
+		"description":         snapshot["description"],
+		"amount":              snapshot["amount"],
+		"currency":            snapshot["currency"],
+		"exchange_rate":       snapshot["exchange_rate"],
+		"movement_date":       dateOnly(snapshot["movement_date"]),
+		"category":            snapshot["category_name"],
+		"payer":               snapshot["payer_name"],
+		"counterparty":        snapshot["counterparty_name"],
+		"payment_method":      snapshot["payment_method_name"],
+		"receiver_account":    snapshot["receiver_account_name"],
+		"source_account":      snapshot["source_account_name"],
+		"event":               snapshot["event_name"],
+		"tags":                snapshot["tags"],
+		"installments":        snapshot["installments"],
+		"installment_rate":    snapshot["installment_rate"],
+		"confirmation_status": snapshot["confirmation_status"],
+	}
+
+	if list, ok := snapshot["participants"].([]interface{}); ok && len(list) > 0 {
+		participants := make([]interface{}, 0, len(list))
+		for _, item := range list {
+			p, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			participants = append(participants, map[string]interface{}{
+				"name":       p["participant_name"],
+				"percentage": p["percentage"],
+				"amount":     p["amount"],
+			})
+		}
+		view["participants"] = participants
+	}
+	return view
+}
+
+// dateOnly trims the time of a JSON timestamp ("2026-03-15T00:00:00Z")
+func dateOnly(v interface{}) interface{} {
+	if s, ok := v.(string); ok && len(s) >= 10 {
+		return s[:10]
+	}
+	return v
+}
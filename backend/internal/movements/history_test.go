@@ -0,0 +1,64 @@
+package movements
+
+import (
+	"testing"
+
+	"github.com/blanquicet/conti/backend/internal/audit"
+	"github.com/blanquicet/conti/backend/internal/money"
+)
+
+func TestRevertInput(t *testing.T) {
+	ana, luis := "user-ana", "contact-luis"
+	half := money.New(25000)
+	snapshot := audit.StructToMap(&Movement{
+		Type:        TypeSplit,
+		Description: "Cena",
+		Amount:      money.New(50000),
+		Currency:    "COP",
+		PayerUserID: &ana,
+		Participants: []Participant{
+			{ParticipantUserID: &ana, Percentage: 0.5, Amount: &half},
+			{ParticipantContactID: &luis, Percentage: 0.5, Amount: &half},
+		},
+	})
+
+	input, err := revertInput(&Movement{Type: TypeSplit}, snapshot)
+	if err != nil {
+		t.Fatalf("revertInput() error = %v", err)
+	}
+	if *input.Description != "Cena" || *input.Amount != money.New(50000) || *input.Currency != "COP" {
+		t.Errorf("input = %+v", input)
+	}
+	if input.EventID == nil || *input.EventID != "" {
+		t.Error("a snapshot without event should detach the movement")
+	}
+	if input.Tags == nil || len(*input.Tags) != 0 {
+		t.Error("a snapshot without tags should clear them")
+	}
+	if input.Participants == nil || len(*input.Participants) != 2 || *(*input.Participants)[1].Amount != half {
+		t.Errorf("participants = %+v", input.Participants)
+	}
+	if input.CounterpartyUserID != nil || input.CounterpartyContactID != nil {
+		t.Error("counterparty only applies to debt payments")
+	}
+}
+
+func TestHistoryView(t *testing.T) {
+	category := "Mercado"
+	view := historyView(audit.StructToMap(&Movement{
+		Description:  "Éxito",
+		CategoryName: &category,
+		Participants: []Participant{{ParticipantName: "Ana", Percentage: 1}},
+	}))
+
+	if view["category"] != "Mercado" || view["description"] != "Éxito" {
+		t.Errorf("view = %v", view)
+	}
+	if view["movement_date"] != "0001-01-01" {
+		t.Errorf("movement_date = %v, want the date only", view["movement_date"])
+	}
+	participants, ok := view["participants"].([]interface{})
+	if !ok || len(participants) != 1 || participants[0].(map[string]interface{})["name"] != "Ana" {
+		t.Errorf("participants = %v", view["participants"])
+	}
+}
@@ -0,0 +1,637 @@
+package movements
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/blanquicet/conti/backend/internal/money"
+)
+
+// Errors for movement imports
+var (
+	ErrInvalidImportMapping = errors.New("invalid import mapping")
+	ErrInvalidImportFile    = errors.New("invalid import file")
+	ErrImportRowFailed      = errors.New("import row failed")
+)
+
+// ImportColumns maps movement fields to CSV header names. Date, Description
+// and Amount are required; the rest are optional.
+type ImportColumns struct {
+	Date          string `json:"date"`
+	Description   string `json:"description"`
+	Amount        string `json:"amount"`
+	Type          string `json:"type,omitempty"`
+	Category      string `json:"category,omitempty"`       // Category name
+	PaymentMethod string `json:"payment_method,omitempty"` // Payment method name
+	Payer         string `json:"payer,omitempty"`          // Member or contact name (defaults to the importing user)
+	Counterparty  string `json:"counterparty,omitempty"`   // Member or contact name (DEBT_PAYMENT)
+	Participants  string `json:"participants,omitempty"`   // "Ana; Luis" (equal split) or "Ana:60; Luis:40" (SPLIT)
+	Currency      string `json:"currency,omitempty"`
+}
+
+// ImportMapping describes how to read a CSV of movements
+type ImportMapping struct {
+	Columns          ImportColumns           `json:"columns"`
+	DateFormat       string                  `json:"date_format,omitempty"`       // YYYY-MM-DD (default), DD/MM/YYYY, MM/DD/YYYY, DD-MM-YYYY or a Go layout
+	DecimalSeparator string                  `json:"decimal_separator,omitempty"` // "." (default) or ","
+	Delimiter        string                  `json:"delimiter,omitempty"`         // "," (default), ";" or "\t"
+	TypeValues       map[string]MovementType `json:"type_values,omitempty"`       // CSV value -> type, e.g. "Compartido": "SPLIT"
+	DefaultType      MovementType            `json:"default_type,omitempty"`      // Used when the type column is missing or empty
+}
+
+// dateLayouts translates the date formats users know into Go layouts
+var dateLayouts = map[string]string{
+	"YYYY-MM-DD": "2006-01-02",
+	"DD/MM/YYYY": "02/01/2006",
+	"MM/DD/YYYY": "01/02/2006",
+	"DD-MM-YYYY": "02-01-2006",
+	"YYYY/MM/DD": "2006/01/02",
+}
+
+// Validate validates the mapping and fills in its defaults
+func (m *ImportMapping) Validate() error {
+	if m.Columns.Date == "" || m.Columns.Description == "" || m.Columns.Amount == "" {
+		return fmt.Errorf("%w: date, description and amount columns are required", ErrInvalidImportMapping)
+	}
+	if m.Columns.Type == "" && m.DefaultType == "" {
+		return fmt.Errorf("%w: a type column or default_type is required", ErrInvalidImportMapping)
+	}
+	if m.DefaultType != "" {
+		if err := m.DefaultType.Validate(); err != nil {
+			return fmt.Errorf("%w: default_type: %v", ErrInvalidImportMapping, err)
+		}
+	}
+	for value, t := range m.TypeValues {
+		if err := t.Validate(); err != nil {
+			return fmt.Errorf("%w: type_values[%q]: %v", ErrInvalidImportMapping, value, err)
+		}
+	}
+
+	if m.DateFormat == "" {
+		m.DateFormat = "YYYY-MM-DD"
+	}
+	switch m.DecimalSeparator {
+	case "":
+		m.DecimalSeparator = "."
+	case ".", ",":
+	default:
+		return fmt.Errorf("%w: decimal_separator must be \".\" or \",\"", ErrInvalidImportMapping)
+	}
+	switch m.Delimiter {
+	case "":
+		m.Delimiter = ","
+	case ",", ";", "\t":
+	default:
+		return fmt.Errorf("%w: delimiter must be \",\", \";\" or a tab", ErrInvalidImportMapping)
+	}
+	return nil
+}
+
+// dateLayout returns the Go layout for the mapping's date format
+func (m *ImportMapping) dateLayout() string {
+	if layout, ok := dateLayouts[strings.ToUpper(m.DateFormat)]; ok {
+		return layout
+	}
+	return m.DateFormat
+}
+
+// ImportOptions controls what an import does with the parsed rows
+type ImportOptions struct {
+	DryRun            bool  // Only report what would be imported
+	IncludeDuplicates bool  // Also import rows flagged as probable duplicates
+	SkipRows          []int // Row numbers (as reported by the dry run) to leave out
+}
+
+// ImportRowStatus is the outcome of one CSV row
+type ImportRowStatus string
+
+const (
+	ImportRowValid     ImportRowStatus = "VALID"     // Would be imported
+	ImportRowInvalid   ImportRowStatus = "INVALID"   // Has errors, never imported
+	ImportRowDuplicate ImportRowStatus = "DUPLICATE" // Probably already recorded
+	ImportRowSkipped   ImportRowStatus = "SKIPPED"   // Left out by the user
+	ImportRowImported  ImportRowStatus = "IMPORTED"  // Created (commit only)
+)
+
+// ImportRowResult reports one CSV row
+type ImportRowResult struct {
+	Row         int                  `json:"row"` // Line number in the file (the header is line 1)
+	Status      ImportRowStatus      `json:"status"`
+	Errors      []string             `json:"errors,omitempty"`
+	DuplicateOf string               `json:"duplicate_of,omitempty"` // Existing movement ID, or "row N" for a repeated row
+	Movement    *CreateMovementInput `json:"movement,omitempty"`     // The resolved movement
+	MovementID  *string              `json:"movement_id,omitempty"`  // Set once imported
+}
+
+// ImportResult summarizes an import (dry run or commit)
+type ImportResult struct {
+	DryRun     bool              `json:"dry_run"`
+	TotalRows  int               `json:"total_rows"`
+	Valid      int               `json:"valid"`
+	Invalid    int               `json:"invalid"`
+	Duplicates int               `json:"duplicates"`
+	Skipped    int               `json:"skipped"`
+	Imported   int               `json:"imported"`
+	Rows       []ImportRowResult `json:"rows"`
+}
+
+// importParticipant is a participant as written in the CSV
+type importParticipant struct {
+	name       string
+	percentage float64 // 0 when the row splits equally
+}
+
+// importRow is a parsed CSV row whose names are not resolved yet
+type importRow struct {
+	line         int
+	input        *CreateMovementInput
+	category     string
+	payer        string
+	counterparty string
+	method       string
+	participants []importParticipant
+	errors       []string
+}
+
+// parseImportCSV reads the rows of a movements CSV according to the mapping.
+// Values that can't be parsed become row errors; only a malformed file or a
+// header missing a mapped column fails the whole import.
+func parseImportCSV(r io.Reader, mapping *ImportMapping) ([]*importRow, error) {
+	reader := csv.NewReader(r)
+	reader.Comma = rune(mapping.Delimiter[0])
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%w: file is empty", ErrInvalidImportFile)
+		}
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImportFile, err)
+	}
+
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		index[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	column := func(name string) (int, error) {
+		if name == "" {
+			return -1, nil
+		}
+		i, ok := index[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return -1, fmt.Errorf("%w: column %q not found in header", ErrInvalidImportFile, name)
+		}
+		return i, nil
+	}
+
+	cols := mapping.Columns
+	names := []string{cols.Date, cols.Description, cols.Amount, cols.Type, cols.Category,
+		cols.PaymentMethod, cols.Payer, cols.Counterparty, cols.Participants, cols.Currency}
+	idx := make([]int, len(names))
+	for i, name := range names {
+		if idx[i], err = column(name); err != nil {
+			return nil, err
+		}
+	}
+	dateCol, descCol, amountCol, typeCol, categoryCol := idx[0], idx[1], idx[2], idx[3], idx[4]
+	methodCol, payerCol, counterpartyCol, participantsCol, currencyCol := idx[5], idx[6], idx[7], idx[8], idx[9]
+
+	layout := mapping.dateLayout()
+	var rows []*importRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidImportFile, err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		field := func(i int) string {
+			if i < 0 || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		// Skip blank lines (e.g. trailing separators from spreadsheet exports)
+		blank := true
+		for _, v := range record {
+			if strings.TrimSpace(v) != "" {
+				blank = false
+				break
+			}
+		}
+		if blank {
+			continue
+		}
+
+		row := &importRow{
+			line:         line,
+			input:        &CreateMovementInput{Description: field(descCol), Currency: field(currencyCol)},
+			category:     field(categoryCol),
+			payer:        field(payerCol),
+			counterparty: field(counterpartyCol),
+			method:       field(methodCol),
+		}
+
+		if date, err := time.Parse(layout, field(dateCol)); err != nil {
+			row.errors = append(row.errors, fmt.Sprintf("invalid date %q, expected %s", field(dateCol), mapping.DateFormat))
+		} else {
+			row.input.MovementDate = date
+		}
+
+		if amount, err := parseImportAmount(field(amountCol), mapping.DecimalSeparator); err != nil {
+			row.errors = append(row.errors, fmt.Sprintf("invalid amount %q", field(amountCol)))
+		} else {
+			row.input.Amount = amount
+		}
+
+		row.input.Type = mapping.DefaultType
+		if value := field(typeCol); value != "" {
+			if t, ok := mapping.TypeValues[value]; ok {
+				row.input.Type = t
+			} else {
+				row.input.Type = MovementType(strings.ToUpper(value))
+			}
+		}
+
+		if value := field(participantsCol); value != "" {
+			participants, err := parseImportParticipants(value)
+			if err != nil {
+				row.errors = append(row.errors, err.Error())
+			}
+			row.participants = participants
+		}
+
+		rows = append(rows, row)
+	}
+
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w: no rows found", ErrInvalidImportFile)
+	}
+	return rows, nil
+}
+
+// parseImportAmount parses amounts as spreadsheets export them:
+// "$ 1.234,56" with a "," separator, "1,234.56" with ".".
+func parseImportAmount(value, decimalSeparator string) (money.Amount, error) {
+	value = strings.NewReplacer("$", "", " ", "", "\u00a0", "").Replace(value)
+	if decimalSeparator == "," {
+		value = strings.ReplaceAll(value, ".", "")
+		value = strings.ReplaceAll(value, ",", ".")
+	} else {
+		value = strings.ReplaceAll(value, ",", "")
+	}
+	return money.Parse(value)
+}
+
+// parseImportParticipants parses "Ana; Luis" (equal split) or "Ana:60; Luis:40"
+// (percentages, which must then be given for everyone)
+func parseImportParticipants(value string) ([]importParticipant, error) {
+	var participants []importParticipant
+	withPercentage := 0
+	for _, part := range strings.Split(value, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		p := importParticipant{name: part}
+		if name, pct, ok := strings.Cut(part, ":"); ok {
+			percentage, err := strconv.ParseFloat(strings.TrimSpace(pct), 64)
+			if err != nil || percentage <= 0 || percentage > 100 {
+				return nil, fmt.Errorf("invalid participant percentage %q", part)
+			}
+			p.name = strings.TrimSpace(name)
+			p.percentage = percentage / 100
+			withPercentage++
+		}
+		participants = append(participants, p)
+	}
+	if withPercentage > 0 && withPercentage != len(participants) {
+		return nil, errors.New("participant percentages must be given for everyone or no one")
+	}
+	if withPercentage == 0 {
+		for i := range participants {
+			participants[i].percentage = 1 / float64(len(participants))
+		}
+	}
+	return participants, nil
+}
+
+// importResolver turns names written in the CSV into household IDs
+type importResolver struct {
+	householdID string
+	repo        Repository
+	members     map[string]string // lower-case name -> user ID
+	contacts    map[string]string // lower-case name -> contact ID
+	methods     map[string]string // lower-case name -> payment method ID
+	categories  map[string]*string
+}
+
+// newImportResolver loads the household's members, contacts and payment methods
+func (s *service) newImportResolver(ctx context.Context, householdID string) (*importResolver, error) {
+	resolver := &importResolver{
+		householdID: householdID,
+		repo:        s.repo,
+		members:     make(map[string]string),
+		contacts:    make(map[string]string),
+		methods:     make(map[string]string),
+		categories:  make(map[string]*string),
+	}
+
+	members, err := s.householdsRepo.GetMembers(ctx, householdID)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range members {
+		resolver.members[strings.ToLower(m.UserName)] = m.UserID
+	}
+
+	contacts, err := s.householdsRepo.ListContacts(ctx, householdID)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range contacts {
+		if c.IsActive {
+			resolver.contacts[strings.ToLower(c.Name)] = c.ID
+		}
+	}
+
+	methods, err := s.paymentMethodRepo.ListByHousehold(ctx, householdID)
+	if err != nil {
+		return nil, err
+	}
+	for _, pm := range methods {
+		if pm.IsActive {
+			resolver.methods[strings.ToLower(pm.Name)] = pm.ID
+		}
+	}
+
+	return resolver, nil
+}
+
+// person resolves a member or contact name (members win on a tie)
+func (r *importResolver) person(name string) (userID, contactID *string, ok bool) {
+	key := strings.ToLower(strings.TrimSpace(name))
+	if id, found := r.members[key]; found {
+		return &id, nil, true
+	}
+	if id, found := r.contacts[key]; found {
+		return nil, &id, true
+	}
+	return nil, nil, false
+}
+
+// category resolves a category name, caching lookups
+func (r *importResolver) category(ctx context.Context, name string) *string {
+	if id, cached := r.categories[name]; cached {
+		return id
+	}
+	var result *string
+	if id, err := r.repo.GetCategoryIDByName(ctx, r.householdID, name); err == nil {
+		result = &id
+	}
+	r.categories[name] = result
+	return result
+}
+
+// resolve fills in the IDs of a parsed row, recording names that don't match
+func (r *importResolver) resolve(ctx context.Context, row *importRow, userID string) {
+	input := row.input
+
+	if row.payer == "" {
+		input.PayerUserID = &userID
+	} else if u, c, ok := r.person(row.payer); ok {
+		input.PayerUserID, input.PayerContactID = u, c
+	} else {
+		row.errors = append(row.errors, fmt.Sprintf("unknown payer %q", row.payer))
+	}
+
+	if row.counterparty != "" {
+		if u, c, ok := r.person(row.counterparty); ok {
+			input.CounterpartyUserID, input.CounterpartyContactID = u, c
+		} else {
+			row.errors = append(row.errors, fmt.Sprintf("unknown counterparty %q", row.counterparty))
+		}
+	}
+
+	if row.method != "" {
+		if id, ok := r.methods[strings.ToLower(row.method)]; ok {
+			input.PaymentMethodID = &id
+		} else {
+			row.errors = append(row.errors, fmt.Sprintf("unknown payment method %q", row.method))
+		}
+	}
+
+	if row.category != "" {
+		if id := r.category(ctx, row.category); id != nil {
+			input.CategoryID = id
+		} else {
+			row.errors = append(row.errors, fmt.Sprintf("unknown category %q", row.category))
+		}
+	}
+
+	for _, p := range row.participants {
+		u, c, ok := r.person(p.name)
+		if !ok {
+			row.errors = append(row.errors, fmt.Sprintf("unknown participant %q", p.name))
+			continue
+		}
+		input.Participants = append(input.Participants, ParticipantInput{
+			ParticipantUserID:    u,
+			ParticipantContactID: c,
+			Percentage:           p.percentage,
+		})
+	}
+}
+
+// importKey identifies rows that look like the same movement
+func importKey(date time.Time, amount money.Amount, currency, description string) string {
+	return fmt.Sprintf("%s|%d|%s|%s", date.Format("2006-01-02"), amount, currency,
+		strings.ToLower(strings.Join(strings.Fields(description), " ")))
+}
+
+// findDuplicate returns the movement (or earlier row) that a row probably
+// repeats: same day, amount and currency, and the same description or
+// payment method
+func findDuplicate(input *CreateMovementInput, existing []*Movement, seen map[string]int) string {
+	key := importKey(input.MovementDate, input.Amount, input.Currency, input.Description)
+	if line, ok := seen[key]; ok {
+		return fmt.Sprintf("row %d", line)
+	}
+	for _, m := range existing {
+		if !m.MovementDate.Equal(input.MovementDate) || m.Amount != input.Amount || m.Currency != input.Currency {
+			continue
+		}
+		sameDescription := strings.EqualFold(strings.TrimSpace(m.Description), strings.TrimSpace(input.Description))
+		sameMethod := input.PaymentMethodID != nil && m.PaymentMethodID != nil && *input.PaymentMethodID == *m.PaymentMethodID
+		if sameDescription || sameMethod {
+			return m.ID
+		}
+	}
+	return ""
+}
+
+// Import validates the rows of a movements CSV and, unless it is a dry run,
+// creates the accepted ones in a single transaction: if any of them fails,
+// nothing is imported. Rows with errors are never imported, and rows flagged
+// as probable duplicates only when opts.IncludeDuplicates is set.
+func (s *service) Import(ctx context.Context, userID string, r io.Reader, mapping *ImportMapping, opts *ImportOptions) (*ImportResult, error) {
+	if err := mapping.Validate(); err != nil {
+		return nil, err
+	}
+
+	householdID, err := s.householdsRepo.GetUserHouseholdID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	baseCurrency, err := s.householdCurrency(ctx, householdID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := parseImportCSV(r, mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	resolver, err := s.newImportResolver(ctx, householdID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Existing movements in the file's date range, to flag duplicates
+	var first, last time.Time
+	for _, row := range rows {
+		date := row.input.MovementDate
+		if date.IsZero() {
+			continue
+		}
+		if first.IsZero() || date.Before(first) {
+			first = date
+		}
+		if date.After(last) {
+			last = date
+		}
+	}
+	var existing []*Movement
+	if !first.IsZero() {
+		existing, err = s.repo.ListByHousehold(ctx, householdID, &ListMovementsFilters{
+			StartDate: &first,
+			EndDate:   &last,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	skip := make(map[int]bool, len(opts.SkipRows))
+	for _, line := range opts.SkipRows {
+		skip[line] = true
+	}
+
+	result := &ImportResult{
+		DryRun:    opts.DryRun,
+		TotalRows: len(rows),
+		Rows:      make([]ImportRowResult, len(rows)),
+	}
+	seen := make(map[string]int)
+	var accepted []int
+	for i, row := range rows {
+		resolver.resolve(ctx, row, userID)
+		// Rules run here (and not again in Create) so the preview shows what
+		// they fill in
+		if len(row.errors) == 0 {
+			if err := s.applyRules(ctx, householdID, row.input); err != nil {
+				return nil, err
+			}
+		}
+		if err := row.input.Validate(); err != nil {
+			row.errors = append(row.errors, err.Error())
+		}
+		if row.input.Currency == "" {
+			row.input.Currency = baseCurrency
+		}
+
+		res := ImportRowResult{Row: row.line, Movement: row.input}
+		switch {
+		case len(row.errors) > 0:
+			res.Status = ImportRowInvalid
+			res.Errors = row.errors
+		default:
+			res.DuplicateOf = findDuplicate(row.input, existing, seen)
+			if res.DuplicateOf != "" {
+				res.Status = ImportRowDuplicate
+			} else {
+				res.Status = ImportRowValid
+			}
+			seen[importKey(row.input.MovementDate, row.input.Amount, row.input.Currency, row.input.Description)] = row.line
+		}
+
+		if res.Status != ImportRowInvalid && skip[row.line] {
+			res.Status = ImportRowSkipped
+		}
+		if res.Status == ImportRowValid || (res.Status == ImportRowDuplicate && opts.IncludeDuplicates) {
+			accepted = append(accepted, i)
+		}
+		result.Rows[i] = res
+	}
+
+	if opts.DryRun || len(accepted) == 0 {
+		result.tally()
+		return result, nil
+	}
+
+	// Create through Create (same checks and auditing as a single movement),
+	// logging the audit entries once the transaction commits
+	created := make(map[int]*Movement, len(accepted))
+	err = s.withTx(ctx, func(txCtx context.Context) error {
+		for _, i := range accepted {
+			movement, err := s.Create(txCtx, userID, rows[i].input)
+			if err != nil {
+				return fmt.Errorf("%w: row %d: %w", ErrImportRowFailed, rows[i].line, err)
+			}
+			created[i] = movement
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.Warn("movement import rolled back", "user_id", userID, "rows", len(accepted), "error", err)
+		return nil, err
+	}
+
+	for i, movement := range created {
+		result.Rows[i].Status = ImportRowImported
+		result.Rows[i].MovementID = &movement.ID
+	}
+	result.tally()
+	return result, nil
+}
+
+// tally counts every row once, by its final status
+func (r *ImportResult) tally() {
+	r.Valid, r.Invalid, r.Duplicates, r.Skipped, r.Imported = 0, 0, 0, 0, 0
+	for _, row := range r.Rows {
+		switch row.Status {
+		case ImportRowValid:
+			r.Valid++
+		case ImportRowInvalid:
+			r.Invalid++
+		case ImportRowDuplicate:
+			r.Duplicates++
+		case ImportRowSkipped:
+			r.Skipped++
+		case ImportRowImported:
+			r.Imported++
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package movements
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blanquicet/conti/backend/internal/money"
+)
+
+func TestParseImportCSV(t *testing.T) {
+	mapping := &ImportMapping{
+		Columns: ImportColumns{
+			Date:         "Fecha",
+			Description:  "Descripción",
+			Amount:       "Valor",
+			Type:         "Tipo",
+			Category:     "Categoría",
+			Participants: "Participantes",
+		},
+		DateFormat:       "DD/MM/YYYY",
+		DecimalSeparator: ",",
+		Delimiter:        ";",
+		TypeValues:       map[string]MovementType{"Compartido": TypeSplit},
+		DefaultType:      TypeHousehold,
+	}
+	if err := mapping.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	input := "Fecha;Descripción;Valor;Tipo;Categoría;Participantes\n" +
+		"05/03/2026;Mercado;$ 1.234,50;;Mercado;\n" +
+		"\n" +
+		"06/03/2026;Cena;80.000;Compartido;;\"Ana:60; Luis:40\"\n" +
+		"2026-03-07;Taxi;abc;;;\n"
+
+	rows, err := parseImportCSV(strings.NewReader(input), mapping)
+	if err != nil {
+		t.Fatalf("parseImportCSV() error = %v", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("got %d rows, want 3 (blank lines skipped)", len(rows))
+	}
+
+	first := rows[0]
+	if first.line != 2 || len(first.errors) != 0 {
+		t.Errorf("first row line = %d, errors = %v", first.line, first.errors)
+	}
+	if first.input.Amount != money.FromMinor(123450) {
+		t.Errorf("first amount = %v, want 1234.50", first.input.Amount)
+	}
+	if first.input.Type != TypeHousehold || first.category != "Mercado" {
+		t.Errorf("first type = %s, category = %q", first.input.Type, first.category)
+	}
+	if !first.input.MovementDate.Equal(time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("first date = %v", first.input.MovementDate)
+	}
+
+	second := rows[1]
+	if second.line != 4 || second.input.Type != TypeSplit {
+		t.Errorf("second row line = %d, type = %s", second.line, second.input.Type)
+	}
+	if len(second.participants) != 2 || second.participants[0].percentage != 0.6 {
+		t.Errorf("second participants = %+v", second.participants)
+	}
+
+	third := rows[2]
+	if len(third.errors) != 2 {
+		t.Errorf("third row errors = %v, want bad date and bad amount", third.errors)
+	}
+}
+
+func TestParseImportCSV_MissingColumn(t *testing.T) {
+	mapping := &ImportMapping{
+		Columns:     ImportColumns{Date: "date", Description: "description", Amount: "total"},
+		DefaultType: TypeHousehold,
+	}
+	if err := mapping.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	_, err := parseImportCSV(strings.NewReader("date,description,amount\n2026-03-01,Arriendo,100\n"), mapping)
+	if !errors.Is(err, ErrInvalidImportFile) {
+		t.Fatalf("parseImportCSV() error = %v, want ErrInvalidImportFile", err)
+	}
+}
+
+func TestImportMapping_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		mapping ImportMapping
+	}{
+		{"missing amount column", ImportMapping{Columns: ImportColumns{Date: "d", Description: "desc"}, DefaultType: TypeHousehold}},
+		{"no type", ImportMapping{Columns: ImportColumns{Date: "d", Description: "desc", Amount: "a"}}},
+		{"bad default type", ImportMapping{Columns: ImportColumns{Date: "d", Description: "desc", Amount: "a"}, DefaultType: "GIFT"}},
+		{"bad separator", ImportMapping{Columns: ImportColumns{Date: "d", Description: "desc", Amount: "a"}, DefaultType: TypeHousehold, DecimalSeparator: "'"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.mapping.Validate(); !errors.Is(err, ErrInvalidImportMapping) {
+				t.Errorf("Validate() error = %v, want ErrInvalidImportMapping", err)
+			}
+		})
+	}
+}
+
+func TestParseImportParticipants(t *testing.T) {
+	equal, err := parseImportParticipants("Ana; Luis; ;Sofía")
+	if err != nil {
+		t.Fatalf("parseImportParticipants() error = %v", err)
+	}
+	if len(equal) != 3 || equal[2].name != "Sofía" || equal[0].percentage != 1.0/3 {
+		t.Errorf("equal split = %+v", equal)
+	}
+
+	if _, err := parseImportParticipants("Ana:60; Luis"); err == nil {
+		t.Error("expected an error when only some participants have percentages")
+	}
+	if _, err := parseImportParticipants("Ana:abc"); err == nil {
+		t.Error("expected an error for an invalid percentage")
+	}
+}
+
+func TestFindDuplicate(t *testing.T) {
+	date := time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC)
+	methodID := "pm-1"
+	existing := []*Movement{
+		{ID: "mov-1", MovementDate: date, Amount: money.New(50000), Currency: "COP", Description: "Mercado Éxito"},
+		{ID: "mov-2", MovementDate: date, Amount: money.New(12000), Currency: "COP", Description: "Uber", PaymentMethodID: &methodID},
+	}
+
+	tests := []struct {
+		name  string
+		input *CreateMovementInput
+		want  string
+	}{
+		{
+			"same description, different case",
+			&CreateMovementInput{MovementDate: date, Amount: money.New(50000), Currency: "COP", Description: "mercado éxito "},
+			"mov-1",
+		},
+		{
+			"same payment method",
+			&CreateMovementInput{MovementDate: date, Amount: money.New(12000), Currency: "COP", Description: "UBER *TRIP", PaymentMethodID: &methodID},
+			"mov-2",
+		},
+		{
+			"different amount",
+			&CreateMovementInput{MovementDate: date, Amount: money.New(50001), Currency: "COP", Description: "Mercado Éxito"},
+			"",
+		},
+		{
+			"repeated row in the file",
+			&CreateMovementInput{MovementDate: date, Amount: money.New(9000), Currency: "COP", Description: "Panadería"},
+			"row 3",
+		},
+	}
+
+	seen := map[string]int{
+		importKey(date, money.New(9000), "COP", "panadería"): 3,
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := findDuplicate(tt.input, existing, seen); got != tt.want {
+				t.Errorf("findDuplicate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestImportResult_Tally(t *testing.T) {
+	result := &ImportResult{Rows: []ImportRowResult{
+		{Row: 2, Status: ImportRowImported},
+		{Row: 3, Status: ImportRowInvalid},
+		{Row: 4, Status: ImportRowSkipped}, // A duplicate the user left out
+		{Row: 5, Status: ImportRowDuplicate},
+		{Row: 6, Status: ImportRowValid},
+		{Row: 7, Status: ImportRowImported},
+	}}
+
+	result.tally()
+	if result.Imported != 2 || result.Invalid != 1 || result.Skipped != 1 || result.Duplicates != 1 || result.Valid != 1 {
+		t.Errorf("tally() = valid %d, invalid %d, duplicates %d, skipped %d, imported %d",
+			result.Valid, result.Invalid, result.Duplicates, result.Skipped, result.Imported)
+	}
+	if total := result.Valid + result.Invalid + result.Duplicates + result.Skipped + result.Imported; total != len(result.Rows) {
+		t.Errorf("counted %d rows, want %d", total, len(result.Rows))
+	}
+}
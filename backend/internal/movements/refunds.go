@@ -0,0 +1,170 @@
+package movements
+
+import (
+	"context"
+
+	"github.com/blanquicet/conti/backend/internal/audit"
+	"github.com/blanquicet/conti/backend/internal/money"
+)
+
+// Refund records a refund of part or all of a HOUSEHOLD or SPLIT movement.
+// The refund is a movement of the same type, category, payment method, payer,
+// participants, event and tags, so it takes its share off every total the
+// original counts in (budgets, card cycles, debts). The original stays locked
+// while the refund is created, so concurrent refunds can't exceed it.
+func (s *service) Refund(ctx context.Context, userID, id string, input *CreateRefundInput) (*Movement, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	original, err := s.GetByID(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if original.IsRefund() || (original.Type != TypeHousehold && original.Type != TypeSplit) {
+		return nil, ErrNotRefundable
+	}
+
+	var movement *Movement
+	err = s.withTx(ctx, func(txCtx context.Context) error {
+		refunded, err := s.repo.LockRefunded(txCtx, original.ID)
+		if err != nil {
+			return err
+		}
+		amount, err := refundAmount(original.Amount, refunded, input.Amount)
+		if err != nil {
+			return err
+		}
+
+		refund := refundInput(original, input, amount)
+		if err := refund.Validate(); err != nil {
+			return err
+		}
+
+		if refund.EventID != nil {
+			if err := s.validateEvent(txCtx, original.HouseholdID, *refund.EventID); err != nil {
+				return err
+			}
+		}
+
+		// Refunded at the rate the original was converted with
+		currency, baseAmount, rate, err := s.convert(txCtx, original.HouseholdID, refund.Amount, refund.Currency, refund.ExchangeRate, refund.MovementDate)
+		if err != nil {
+			return err
+		}
+		refund.Currency = currency
+		refund.BaseAmount = baseAmount
+		refund.ExchangeRate = rate
+
+		if refund.Type == TypeSplit {
+			if err := AllocateParticipants(refund.Amount, refund.Participants); err != nil {
+				return err
+			}
+		}
+
+		movement, err = s.repo.Create(txCtx, refund, original.HouseholdID)
+		if err != nil {
+			s.auditService.LogAsync(ctx, &audit.LogInput{
+				UserID:       audit.StringPtr(userID),
+				Action:       audit.ActionMovementCreated,
+				ResourceType: "movement",
+				HouseholdID:  audit.StringPtr(original.HouseholdID),
+				Metadata:     map[string]interface{}{"refund_of_id": original.ID},
+				Success:      false,
+				ErrorMessage: audit.StringPtr(err.Error()),
+			})
+			return err
+		}
+
+		s.logAudit(txCtx, &audit.LogInput{
+			UserID:       audit.StringPtr(userID),
+			Action:       audit.ActionMovementCreated,
+			ResourceType: "movement",
+			ResourceID:   audit.StringPtr(movement.ID),
+			HouseholdID:  audit.StringPtr(original.HouseholdID),
+			NewValues:    audit.StructToMap(movement),
+			Metadata:     map[string]interface{}{"refund_of_id": original.ID},
+			Success:      true,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return movement, nil
+}
+
+// refundAmount is what a refund takes off an original of which refunded was
+// already refunded: the requested amount, or everything left by default
+func refundAmount(original, refunded money.Amount, requested *money.Amount) (money.Amount, error) {
+	remaining := original - refunded
+	amount := remaining
+	if requested != nil {
+		amount = *requested
+	}
+	if amount <= 0 || amount > remaining {
+		return 0, ErrRefundExceedsOriginal
+	}
+	return amount, nil
+}
+
+// refundInput builds the movement that refunds amount of the original.
+// Participants keep their percentages and are allocated the refund amount.
+func refundInput(original *Movement, input *CreateRefundInput, amount money.Amount) *CreateMovementInput {
+	description := "Reembolso: " + original.Description
+	if input.Description != nil {
+		description = *input.Description
+	}
+
+	refund := &CreateMovementInput{
+		Type:            original.Type,
+		Description:     description,
+		Amount:          amount,
+		CategoryID:      original.CategoryID,
+		MovementDate:    input.MovementDate,
+		Currency:        original.Currency,
+		ExchangeRate:    original.ExchangeRate,
+		PayerUserID:     original.PayerUserID,
+		PayerContactID:  original.PayerContactID,
+		PaymentMethodID: original.PaymentMethodID,
+		EventID:         original.EventID,
+		Tags:            original.Tags,
+		RefundOfID:      &original.ID,
+	}
+	for _, p := range original.Participants {
+		refund.Participants = append(refund.Participants, ParticipantInput{
+			ParticipantUserID:    p.ParticipantUserID,
+			ParticipantContactID: p.ParticipantContactID,
+			Percentage:           p.Percentage,
+		})
+	}
+	return refund
+}
+
+// checkRefundUpdate keeps refunds within their original when an update
+// changes the amount or currency of either side
+func (s *service) checkRefundUpdate(ctx context.Context, existing *Movement, input *UpdateMovementInput) error {
+	if input.Amount == nil && input.Currency == nil {
+		return nil
+	}
+	if input.Currency != nil && *input.Currency != existing.Currency && (existing.IsRefund() || existing.RefundedAmount > 0) {
+		return ErrRefundCurrency
+	}
+	if input.Amount == nil {
+		return nil
+	}
+
+	if existing.IsRefund() {
+		original, err := s.repo.GetByID(ctx, *existing.RefundOfID)
+		if err != nil {
+			return err
+		}
+		if original.RefundedAmount-existing.Amount+*input.Amount > original.Amount {
+			return ErrRefundExceedsOriginal
+		}
+	} else if *input.Amount < existing.RefundedAmount {
+		return ErrRefundExceedsOriginal
+	}
+	return nil
+}
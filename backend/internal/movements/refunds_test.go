@@ -0,0 +1,127 @@
+package movements
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blanquicet/conti/backend/internal/money"
+)
+
+func TestRefundInput(t *testing.T) {
+	ana, luis, category, card := "ana", "luis", "restaurantes", "visa"
+	original := &Movement{
+		ID:              "m1",
+		Type:            TypeSplit,
+		Description:     "Cena",
+		Amount:          money.New(90000),
+		Currency:        "COP",
+		CategoryID:      &category,
+		PayerUserID:     &ana,
+		PaymentMethodID: &card,
+		Tags:            []string{"viaje"},
+		Participants: []Participant{
+			{ParticipantUserID: &ana, Percentage: 0.5, Amount: amountPtr(money.New(45000))},
+			{ParticipantContactID: &luis, Percentage: 0.5, Amount: amountPtr(money.New(45000))},
+		},
+	}
+	date := time.Date(2026, 4, 2, 0, 0, 0, 0, time.UTC)
+
+	refund := refundInput(original, &CreateRefundInput{MovementDate: date}, money.New(30000))
+	if err := refund.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if refund.RefundOfID == nil || *refund.RefundOfID != "m1" {
+		t.Errorf("RefundOfID = %v, want m1", refund.RefundOfID)
+	}
+	if refund.Description != "Reembolso: Cena" || refund.Type != TypeSplit || *refund.PaymentMethodID != card {
+		t.Errorf("refund = %q %s %s, want copied from the original", refund.Description, refund.Type, *refund.PaymentMethodID)
+	}
+
+	// Participants keep their percentages and share the refund amount
+	if err := AllocateParticipants(refund.Amount, refund.Participants); err != nil {
+		t.Fatalf("AllocateParticipants() error = %v", err)
+	}
+	for _, p := range refund.Participants {
+		if *p.Amount != money.New(15000) {
+			t.Errorf("participant amount = %v, want 15000", *p.Amount)
+		}
+	}
+}
+
+func TestMovement_RefundIsNegative(t *testing.T) {
+	original := "m1"
+	refund := &Movement{
+		Type:       TypeSplit,
+		Amount:     money.New(30000),
+		RefundOfID: &original,
+		Participants: []Participant{
+			{Percentage: 0.5, Amount: amountPtr(money.New(15000))},
+			{Percentage: 0.5, Amount: amountPtr(money.New(15000))},
+		},
+	}
+
+	if got := refund.NetHouseholdAmount(); got != -money.New(30000) {
+		t.Errorf("NetHouseholdAmount() = %v, want -30000", got)
+	}
+	for _, share := range refund.HouseholdShares() {
+		if share != -money.New(15000) {
+			t.Errorf("share = %v, want -15000", share)
+		}
+	}
+	// The stored participant amounts stay positive
+	if *refund.Participants[0].Amount != money.New(15000) {
+		t.Errorf("participant amount = %v, want 15000", *refund.Participants[0].Amount)
+	}
+}
+
+func TestCreateRefundInput_Validate(t *testing.T) {
+	zero := money.Amount(0)
+	if err := (&CreateRefundInput{MovementDate: time.Now(), Amount: &zero}).Validate(); err != ErrInvalidAmount {
+		t.Errorf("zero amount error = %v, want ErrInvalidAmount", err)
+	}
+	if err := (&CreateRefundInput{}).Validate(); err == nil {
+		t.Error("missing date: want error")
+	}
+	if err := (&CreateRefundInput{MovementDate: time.Now()}).Validate(); err != nil {
+		t.Errorf("full refund error = %v, want nil", err)
+	}
+}
+
+func TestRefundAmount(t *testing.T) {
+	original := money.New(90000)
+	amount := func(v int64) *money.Amount { a := money.New(v); return &a }
+
+	tests := []struct {
+		name      string
+		refunded  money.Amount
+		requested *money.Amount
+		want      money.Amount
+		wantErr   bool
+	}{
+		{"everything by default", 0, nil, money.New(90000), false},
+		{"what is left by default", money.New(30000), nil, money.New(60000), false},
+		{"part of what is left", money.New(30000), amount(20000), money.New(20000), false},
+		{"exactly what is left", money.New(30000), amount(60000), money.New(60000), false},
+		{"more than what is left", money.New(30000), amount(60001), 0, true},
+		// A refund committed while waiting for the lock leaves nothing
+		{"fully refunded", money.New(90000), nil, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := refundAmount(original, tt.refunded, tt.requested)
+			if tt.wantErr {
+				if err != ErrRefundExceedsOriginal {
+					t.Errorf("refundAmount() error = %v, want ErrRefundExceedsOriginal", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("refundAmount() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("refundAmount() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -5,9 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/blanquicet/conti/backend/internal/money"
 )
 
 // repository implements Repository using PostgreSQL
@@ -20,10 +24,46 @@ func NewRepository(pool *pgxpool.Pool) Repository {
 	return &repository{pool: pool}
 }
 
+// txKey is the context key for a transaction started by WithTx
+type txKey struct{}
+
+// dbtx is the subset of pgx shared by the pool and a transaction
+type dbtx interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// db returns the transaction carried by ctx (see WithTx), or the pool
+func (r *repository) db(ctx context.Context) dbtx {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx
+	}
+	return r.pool
+}
+
+// WithTx runs fn inside a single transaction. Repository calls made with the
+// context passed to fn join that transaction (nested Begin calls become
+// savepoints), so either all of them are committed or none is.
+func (r *repository) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	tx, err := r.db(ctx).Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
 // Create creates a new movement (and participants if SPLIT type)
 func (r *repository) Create(ctx context.Context, input *CreateMovementInput, householdID string) (*Movement, error) {
 	// Start transaction
-	tx, err := r.pool.Begin(ctx)
+	tx, err := r.db(ctx).Begin(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -34,24 +74,30 @@ func (r *repository) Create(ctx context.Context, input *CreateMovementInput, hou
 	err = tx.QueryRow(ctx, `
 		INSERT INTO movements (
 			household_id, type, description, amount, category_id, movement_date, currency,
+			exchange_rate, base_amount,
 			payer_user_id, payer_contact_id,
 			counterparty_user_id, counterparty_contact_id,
-			payment_method_id, receiver_account_id,
-			generated_from_template_id
+			payment_method_id, receiver_account_id, source_account_id,
+			generated_from_template_id, event_id, confirmation_status, refund_of_id,
+			installments, installment_rate, installments_first_cycle
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20,
+		        $21, $22, $23)
 		RETURNING id, household_id, type, description, amount, category_id, movement_date,
-		          currency, payer_user_id, payer_contact_id,
+		          currency, exchange_rate::float8, base_amount, payer_user_id, payer_contact_id,
 		          counterparty_user_id, counterparty_contact_id,
-		          payment_method_id, receiver_account_id, 
-		          generated_from_template_id, created_at, updated_at
+		          payment_method_id, receiver_account_id, source_account_id,
+		          generated_from_template_id, event_id, refund_of_id,
+		          installments, installment_rate::float8, installments_first_cycle, created_at, updated_at
 	`,
 		householdID, input.Type, input.Description, input.Amount, input.CategoryID,
-		input.MovementDate, "COP", // Currency defaults to COP
+		input.MovementDate, input.Currency,
+		input.ExchangeRate, input.BaseAmount,
 		input.PayerUserID, input.PayerContactID,
 		input.CounterpartyUserID, input.CounterpartyContactID,
-		input.PaymentMethodID, input.ReceiverAccountID,
-		input.GeneratedFromTemplateID,
+		input.PaymentMethodID, input.ReceiverAccountID, input.SourceAccountID,
+		input.GeneratedFromTemplateID, input.EventID, input.ConfirmationStatus, input.RefundOfID,
+		input.Installments, input.InstallmentRate, input.InstallmentsFirstCycle,
 	).Scan(
 		&movement.ID,
 		&movement.HouseholdID,
@@ -61,13 +107,21 @@ func (r *repository) Create(ctx context.Context, input *CreateMovementInput, hou
 		&movement.CategoryID,
 		&movement.MovementDate,
 		&movement.Currency,
+		&movement.ExchangeRate,
+		&movement.BaseAmount,
 		&movement.PayerUserID,
 		&movement.PayerContactID,
 		&movement.CounterpartyUserID,
 		&movement.CounterpartyContactID,
 		&movement.PaymentMethodID,
 		&movement.ReceiverAccountID,
+		&movement.SourceAccountID,
 		&movement.GeneratedFromTemplateID,
+		&movement.EventID,
+		&movement.RefundOfID,
+		&movement.Installments,
+		&movement.InstallmentRate,
+		&movement.InstallmentsFirstCycle,
 		&movement.CreatedAt,
 		&movement.UpdatedAt,
 	)
@@ -90,6 +144,13 @@ func (r *repository) Create(ctx context.Context, input *CreateMovementInput, hou
 		}
 	}
 
+	// Tag the movement
+	if len(input.Tags) > 0 {
+		if err := setTags(ctx, tx, movement.ID, input.Tags); err != nil {
+			return nil, err
+		}
+	}
+
 	// Commit transaction
 	if err := tx.Commit(ctx); err != nil {
 		return nil, err
@@ -112,11 +173,16 @@ func (r *repository) GetByID(ctx context.Context, id string) (*Movement, error)
 	query := `
 		SELECT 
 			m.id, m.household_id, m.type, m.description, m.amount,
-			m.movement_date, m.currency,
+			m.movement_date, m.currency, m.exchange_rate::float8, m.base_amount,
 			m.payer_user_id, m.payer_contact_id,
 			m.counterparty_user_id, m.counterparty_contact_id,
-			m.payment_method_id, m.receiver_account_id,
-			m.generated_from_template_id,
+			m.payment_method_id, m.receiver_account_id, m.source_account_id,
+			m.generated_from_template_id, m.event_id,
+			m.confirmation_status, m.confirmation_responded_by,
+			m.confirmation_responded_at, m.dispute_reason,
+			m.refund_of_id, (SELECT COALESCE(SUM(r.amount), 0) FROM movements r
+			                WHERE r.refund_of_id = m.id AND r.deleted_at IS NULL) as refunded_amount,
+			m.installments, m.installment_rate::float8, m.installments_first_cycle,
 			m.created_at, m.updated_at,
 			-- Payer name (user or contact)
 			COALESCE(payer_user.name, payer_contact.name) as payer_name,
@@ -124,14 +190,16 @@ func (r *repository) GetByID(ctx context.Context, id string) (*Movement, error)
 			COALESCE(counterparty_user.name, counterparty_contact.name) as counterparty_name,
 			-- Payment method name (if exists)
 			pm.name as payment_method_name,
-			-- Receiver account name (if exists)
+			-- Receiver and source account names (if exist)
 			ra.name as receiver_account_name,
+			sa.name as source_account_name,
 			-- Category info via JOIN
 			c.id as category_id,
 			c.name as category_name,
 			cg.id as category_group_id,
 			cg.name as category_group_name,
-			cg.icon as category_group_icon
+			cg.icon as category_group_icon,
+			ev.name as event_name
 		FROM movements m
 		LEFT JOIN users payer_user ON m.payer_user_id = payer_user.id
 		LEFT JOIN contacts payer_contact ON m.payer_contact_id = payer_contact.id
@@ -139,12 +207,14 @@ func (r *repository) GetByID(ctx context.Context, id string) (*Movement, error)
 		LEFT JOIN contacts counterparty_contact ON m.counterparty_contact_id = counterparty_contact.id
 		LEFT JOIN payment_methods pm ON m.payment_method_id = pm.id
 		LEFT JOIN accounts ra ON m.receiver_account_id = ra.id
+		LEFT JOIN accounts sa ON m.source_account_id = sa.id
 		LEFT JOIN categories c ON m.category_id = c.id
 		LEFT JOIN category_groups cg ON c.category_group_id = cg.id
-		WHERE m.id = $1
+		LEFT JOIN events ev ON m.event_id = ev.id
+		WHERE m.id = $1 AND m.deleted_at IS NULL
 	`
 
-	err := r.pool.QueryRow(ctx, query, id).Scan(
+	err := r.db(ctx).QueryRow(ctx, query, id).Scan(
 		&movement.ID,
 		&movement.HouseholdID,
 		&movement.Type,
@@ -152,24 +222,39 @@ func (r *repository) GetByID(ctx context.Context, id string) (*Movement, error)
 		&movement.Amount,
 		&movement.MovementDate,
 		&movement.Currency,
+		&movement.ExchangeRate,
+		&movement.BaseAmount,
 		&movement.PayerUserID,
 		&movement.PayerContactID,
 		&movement.CounterpartyUserID,
 		&movement.CounterpartyContactID,
 		&movement.PaymentMethodID,
 		&movement.ReceiverAccountID,
+		&movement.SourceAccountID,
 		&movement.GeneratedFromTemplateID,
+		&movement.EventID,
+		&movement.ConfirmationStatus,
+		&movement.ConfirmationRespondedBy,
+		&movement.ConfirmationRespondedAt,
+		&movement.DisputeReason,
+		&movement.RefundOfID,
+		&movement.RefundedAmount,
+		&movement.Installments,
+		&movement.InstallmentRate,
+		&movement.InstallmentsFirstCycle,
 		&movement.CreatedAt,
 		&movement.UpdatedAt,
 		&movement.PayerName,
 		&movement.CounterpartyName,
 		&movement.PaymentMethodName,
 		&movement.ReceiverAccountName,
+		&movement.SourceAccountName,
 		&movement.CategoryID,
 		&movement.CategoryName,
 		&movement.CategoryGroupID,
 		&movement.CategoryGroupName,
 		&movement.CategoryGroupIcon,
+		&movement.EventName,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -188,6 +273,10 @@ func (r *repository) GetByID(ctx context.Context, id string) (*Movement, error)
 		movement.Participants = participants
 	}
 
+	if err := r.loadTags(ctx, []*Movement{&movement}); err != nil {
+		return nil, err
+	}
+
 	return &movement, nil
 }
 
@@ -206,7 +295,7 @@ func (r *repository) getParticipants(ctx context.Context, movementID string) ([]
 		ORDER BY mp.created_at ASC
 	`
 
-	rows, err := r.pool.Query(ctx, query, movementID)
+	rows, err := r.db(ctx).Query(ctx, query, movementID)
 	if err != nil {
 		return nil, err
 	}
@@ -241,7 +330,7 @@ func (r *repository) getParticipants(ctx context.Context, movementID string) ([]
 // GetCategoryIDByName looks up a category ID by name within a household
 func (r *repository) GetCategoryIDByName(ctx context.Context, householdID string, categoryName string) (string, error) {
 	var categoryID string
-	err := r.pool.QueryRow(ctx, `
+	err := r.db(ctx).QueryRow(ctx, `
 		SELECT id FROM categories
 		WHERE household_id = $1 AND name = $2 AND is_active = true
 		LIMIT 1
@@ -254,26 +343,52 @@ func (r *repository) GetCategoryIDByName(ctx context.Context, householdID string
 	return categoryID, nil
 }
 
+// GetEventStatus returns the household and status of an event.
+// Used to validate event assignment without importing the events package.
+func (r *repository) GetEventStatus(ctx context.Context, eventID string) (string, string, error) {
+	var householdID, status string
+	err := r.db(ctx).QueryRow(ctx, `
+		SELECT household_id, status FROM events WHERE id = $1
+	`, eventID).Scan(&householdID, &status)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", "", ErrEventNotFound
+		}
+		return "", "", err
+	}
+
+	return householdID, status, nil
+}
+
 // ListByHousehold retrieves all movements for a household with optional filters
 func (r *repository) ListByHousehold(ctx context.Context, householdID string, filters *ListMovementsFilters) ([]*Movement, error) {
 	query := `
 		SELECT 
 			m.id, m.household_id, m.type, m.description, m.amount,
-			m.movement_date, m.currency,
+			m.movement_date, m.currency, m.exchange_rate::float8, m.base_amount,
 			m.payer_user_id, m.payer_contact_id,
 			m.counterparty_user_id, m.counterparty_contact_id,
-			m.payment_method_id, m.receiver_account_id,
-			m.generated_from_template_id,
+			m.payment_method_id, m.receiver_account_id, m.source_account_id,
+			m.generated_from_template_id, m.event_id,
+			m.confirmation_status, m.confirmation_responded_by,
+			m.confirmation_responded_at, m.dispute_reason,
+			m.refund_of_id, (SELECT COALESCE(SUM(r.amount), 0) FROM movements r
+			                WHERE r.refund_of_id = m.id AND r.deleted_at IS NULL) as refunded_amount,
+			m.installments, m.installment_rate::float8, m.installments_first_cycle,
 			m.created_at, m.updated_at,
 			COALESCE(payer_user.name, payer_contact.name) as payer_name,
 			COALESCE(counterparty_user.name, counterparty_contact.name) as counterparty_name,
 			pm.name as payment_method_name,
 			ra.name as receiver_account_name,
+			sa.name as source_account_name,
 			c.id as category_id,
 			c.name as category_name,
 			cg.id as category_group_id,
 			cg.name as category_group_name,
-			cg.icon as category_group_icon
+			cg.icon as category_group_icon,
+			ev.name as event_name,
+			m.deleted_at,
+			deleted_by.name as deleted_by_name
 		FROM movements m
 		LEFT JOIN users payer_user ON m.payer_user_id = payer_user.id
 		LEFT JOIN contacts payer_contact ON m.payer_contact_id = payer_contact.id
@@ -281,47 +396,29 @@ func (r *repository) ListByHousehold(ctx context.Context, householdID string, fi
 		LEFT JOIN contacts counterparty_contact ON m.counterparty_contact_id = counterparty_contact.id
 		LEFT JOIN payment_methods pm ON m.payment_method_id = pm.id
 		LEFT JOIN accounts ra ON m.receiver_account_id = ra.id
+		LEFT JOIN accounts sa ON m.source_account_id = sa.id
 		LEFT JOIN categories c ON m.category_id = c.id
 		LEFT JOIN category_groups cg ON c.category_group_id = cg.id
+		LEFT JOIN events ev ON m.event_id = ev.id
+		LEFT JOIN users deleted_by ON m.deleted_by = deleted_by.id
 		WHERE m.household_id = $1
 	`
 
-	var args []interface{}
-	args = append(args, householdID)
-	argNum := 2
-
-	// Apply filters
-	if filters != nil {
-		if filters.Type != nil {
-			query += fmt.Sprintf(" AND m.type = $%d", argNum)
-			args = append(args, *filters.Type)
-			argNum++
-		}
-		if filters.Month != nil {
-			query += fmt.Sprintf(" AND TO_CHAR(m.movement_date, 'YYYY-MM') = $%d", argNum)
-			args = append(args, *filters.Month)
-			argNum++
-		}
-		if filters.StartDate != nil {
-			query += fmt.Sprintf(" AND m.movement_date >= $%d", argNum)
-			args = append(args, *filters.StartDate)
-			argNum++
-		}
-		if filters.EndDate != nil {
-			query += fmt.Sprintf(" AND m.movement_date <= $%d", argNum)
-			args = append(args, *filters.EndDate)
-			argNum++
-		}
-		if filters.MemberID != nil {
-			query += fmt.Sprintf(" AND m.payer_user_id = $%d", argNum)
-			args = append(args, *filters.MemberID)
-			argNum++
-		}
+	args := []interface{}{householdID}
+	clause, args := filterClause(filters, args)
+	query += clause
+	if filters != nil && filters.Cursor != nil {
+		clause, args = cursorClause(filters.Cursor, args)
+		query += clause
 	}
 
-	query += " ORDER BY m.movement_date DESC, m.created_at DESC"
+	query += orderClause(filters)
+	if filters != nil && filters.Limit > 0 {
+		args = append(args, filters.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
 
-	rows, err := r.pool.Query(ctx, query, args...)
+	rows, err := r.db(ctx).Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -338,24 +435,41 @@ func (r *repository) ListByHousehold(ctx context.Context, householdID string, fi
 			&m.Amount,
 			&m.MovementDate,
 			&m.Currency,
+			&m.ExchangeRate,
+			&m.BaseAmount,
 			&m.PayerUserID,
 			&m.PayerContactID,
 			&m.CounterpartyUserID,
 			&m.CounterpartyContactID,
 			&m.PaymentMethodID,
 			&m.ReceiverAccountID,
+			&m.SourceAccountID,
 			&m.GeneratedFromTemplateID,
+			&m.EventID,
+			&m.ConfirmationStatus,
+			&m.ConfirmationRespondedBy,
+			&m.ConfirmationRespondedAt,
+			&m.DisputeReason,
+			&m.RefundOfID,
+			&m.RefundedAmount,
+			&m.Installments,
+			&m.InstallmentRate,
+			&m.InstallmentsFirstCycle,
 			&m.CreatedAt,
 			&m.UpdatedAt,
 			&m.PayerName,
 			&m.CounterpartyName,
 			&m.PaymentMethodName,
 			&m.ReceiverAccountName,
+			&m.SourceAccountName,
 			&m.CategoryID,
 			&m.CategoryName,
 			&m.CategoryGroupID,
 			&m.CategoryGroupName,
 			&m.CategoryGroupIcon,
+			&m.EventName,
+			&m.DeletedAt,
+			&m.DeletedByName,
 		)
 		if err != nil {
 			return nil, err
@@ -378,6 +492,10 @@ func (r *repository) ListByHousehold(ctx context.Context, householdID string, fi
 		return nil, err
 	}
 
+	if err := r.loadTags(ctx, movements); err != nil {
+		return nil, err
+	}
+
 	return movements, nil
 }
 
@@ -391,11 +509,13 @@ func (r *repository) ListMovementsByContactIDs(ctx context.Context, contactIDs [
 	query := `
 		SELECT 
 			m.id, m.household_id, m.type, m.description, m.amount,
-			m.movement_date, m.currency,
+			m.movement_date, m.currency, m.exchange_rate::float8, m.base_amount,
 			m.payer_user_id, m.payer_contact_id,
 			m.counterparty_user_id, m.counterparty_contact_id,
 			m.payment_method_id, m.receiver_account_id,
-			m.generated_from_template_id,
+			m.generated_from_template_id, m.event_id,
+			m.confirmation_status, m.confirmation_responded_by,
+			m.confirmation_responded_at, m.dispute_reason,
 			m.created_at, m.updated_at,
 			COALESCE(payer_user.name, payer_contact.name) as payer_name,
 			COALESCE(counterparty_user.name, counterparty_contact.name) as counterparty_name,
@@ -405,7 +525,8 @@ func (r *repository) ListMovementsByContactIDs(ctx context.Context, contactIDs [
 			c.name as category_name,
 			cg.id as category_group_id,
 			cg.name as category_group_name,
-			cg.icon as category_group_icon
+			cg.icon as category_group_icon,
+			ev.name as event_name
 		FROM movements m
 		LEFT JOIN users payer_user ON m.payer_user_id = payer_user.id
 		LEFT JOIN contacts payer_contact ON m.payer_contact_id = payer_contact.id
@@ -415,7 +536,9 @@ func (r *repository) ListMovementsByContactIDs(ctx context.Context, contactIDs [
 		LEFT JOIN accounts ra ON m.receiver_account_id = ra.id
 		LEFT JOIN categories c ON m.category_id = c.id
 		LEFT JOIN category_groups cg ON c.category_group_id = cg.id
+		LEFT JOIN events ev ON m.event_id = ev.id
 		WHERE m.type IN ('SPLIT', 'DEBT_PAYMENT')
+		  AND m.deleted_at IS NULL
 		  AND (
 			m.payer_contact_id = ANY($1)
 			OR m.counterparty_contact_id = ANY($1)
@@ -439,7 +562,7 @@ func (r *repository) ListMovementsByContactIDs(ctx context.Context, contactIDs [
 
 	query += " ORDER BY m.movement_date DESC, m.created_at DESC"
 
-	rows, err := r.pool.Query(ctx, query, args...)
+	rows, err := r.db(ctx).Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -456,6 +579,8 @@ func (r *repository) ListMovementsByContactIDs(ctx context.Context, contactIDs [
 			&m.Amount,
 			&m.MovementDate,
 			&m.Currency,
+			&m.ExchangeRate,
+			&m.BaseAmount,
 			&m.PayerUserID,
 			&m.PayerContactID,
 			&m.CounterpartyUserID,
@@ -463,6 +588,11 @@ func (r *repository) ListMovementsByContactIDs(ctx context.Context, contactIDs [
 			&m.PaymentMethodID,
 			&m.ReceiverAccountID,
 			&m.GeneratedFromTemplateID,
+			&m.EventID,
+			&m.ConfirmationStatus,
+			&m.ConfirmationRespondedBy,
+			&m.ConfirmationRespondedAt,
+			&m.DisputeReason,
 			&m.CreatedAt,
 			&m.UpdatedAt,
 			&m.PayerName,
@@ -474,6 +604,7 @@ func (r *repository) ListMovementsByContactIDs(ctx context.Context, contactIDs [
 			&m.CategoryGroupID,
 			&m.CategoryGroupName,
 			&m.CategoryGroupIcon,
+			&m.EventName,
 		)
 		if err != nil {
 			return nil, err
@@ -499,57 +630,35 @@ func (r *repository) ListMovementsByContactIDs(ctx context.Context, contactIDs [
 
 // GetTotals calculates totals for movements
 func (r *repository) GetTotals(ctx context.Context, householdID string, filters *ListMovementsFilters) (*MovementTotals, error) {
-	// Build WHERE clause
-	whereClause := "WHERE m.household_id = $1"
-	var args []interface{}
-	args = append(args, householdID)
-	argNum := 2
-
-	if filters != nil {
-		if filters.Type != nil {
-			whereClause += fmt.Sprintf(" AND m.type = $%d", argNum)
-			args = append(args, *filters.Type)
-			argNum++
-		}
-		if filters.Month != nil {
-			whereClause += fmt.Sprintf(" AND TO_CHAR(m.movement_date, 'YYYY-MM') = $%d", argNum)
-			args = append(args, *filters.Month)
-			argNum++
-		}
-		if filters.StartDate != nil {
-			whereClause += fmt.Sprintf(" AND m.movement_date >= $%d", argNum)
-			args = append(args, *filters.StartDate)
-			argNum++
-		}
-		if filters.EndDate != nil {
-			whereClause += fmt.Sprintf(" AND m.movement_date <= $%d", argNum)
-			args = append(args, *filters.EndDate)
-			argNum++
-		}
-		if filters.MemberID != nil {
-			whereClause += fmt.Sprintf(" AND m.payer_user_id = $%d", argNum)
-			args = append(args, *filters.MemberID)
-			argNum++
-		}
+	// Build WHERE clause (pages don't apply, totals cover every page)
+	clause, args := filterClause(filters, []interface{}{householdID})
+	whereClause := "WHERE m.household_id = $1" + clause
+
+	// Transfers move money between accounts, they aren't spending: they only
+	// show up in the totals by type (and when listing transfers on purpose)
+	spendingClause := whereClause
+	if filters == nil || filters.Type == nil || *filters.Type != TypeTransfer {
+		spendingClause += " AND m.type <> 'TRANSFER'"
 	}
 
 	totals := &MovementTotals{
-		ByType:          make(map[MovementType]float64),
-		ByCategory:      make(map[string]float64),
-		ByPaymentMethod: make(map[string]float64),
+		ByType:          make(map[MovementType]money.Amount),
+		ByCategory:      make(map[string]money.Amount),
+		ByPaymentMethod: make(map[string]money.Amount),
+		ByTag:           make(map[string]money.Amount),
 	}
 
-	// Get total amount
-	err := r.pool.QueryRow(ctx, fmt.Sprintf(`
-		SELECT COALESCE(SUM(amount), 0) FROM movements m %s
-	`, whereClause), args...).Scan(&totals.TotalAmount)
+	// Get total amount (totals are in the household currency)
+	err := r.db(ctx).QueryRow(ctx, fmt.Sprintf(`
+		SELECT COALESCE(SUM(signed_base_amount), 0) FROM movements m %s
+	`, spendingClause), args...).Scan(&totals.TotalAmount)
 	if err != nil {
 		return nil, err
 	}
 
 	// Get totals by type
-	rows, err := r.pool.Query(ctx, fmt.Sprintf(`
-		SELECT type, SUM(amount) FROM movements m %s GROUP BY type
+	rows, err := r.db(ctx).Query(ctx, fmt.Sprintf(`
+		SELECT type, SUM(signed_base_amount) FROM movements m %s GROUP BY type
 	`, whereClause), args...)
 	if err != nil {
 		return nil, err
@@ -558,7 +667,7 @@ func (r *repository) GetTotals(ctx context.Context, householdID string, filters
 
 	for rows.Next() {
 		var movType MovementType
-		var sum float64
+		var sum money.Amount
 		if err := rows.Scan(&movType, &sum); err != nil {
 			return nil, err
 		}
@@ -567,13 +676,13 @@ func (r *repository) GetTotals(ctx context.Context, householdID string, filters
 	rows.Close()
 
 	// Get totals by category
-	rows, err = r.pool.Query(ctx, fmt.Sprintf(`
-		SELECT c.name, SUM(m.amount) 
+	rows, err = r.db(ctx).Query(ctx, fmt.Sprintf(`
+		SELECT c.name, SUM(m.signed_base_amount) 
 		FROM movements m 
 		LEFT JOIN categories c ON m.category_id = c.id
 		%s AND m.category_id IS NOT NULL 
 		GROUP BY c.name
-	`, whereClause), args...)
+	`, spendingClause), args...)
 	if err != nil {
 		return nil, err
 	}
@@ -581,7 +690,7 @@ func (r *repository) GetTotals(ctx context.Context, householdID string, filters
 
 	for rows.Next() {
 		var category string
-		var sum float64
+		var sum money.Amount
 		if err := rows.Scan(&category, &sum); err != nil {
 			return nil, err
 		}
@@ -590,8 +699,8 @@ func (r *repository) GetTotals(ctx context.Context, householdID string, filters
 	rows.Close()
 
 	// Get totals by payment method
-	rows, err = r.pool.Query(ctx, fmt.Sprintf(`
-		SELECT pm.name, SUM(m.amount) 
+	rows, err = r.db(ctx).Query(ctx, fmt.Sprintf(`
+		SELECT pm.name, SUM(m.signed_base_amount) 
 		FROM movements m 
 		JOIN payment_methods pm ON m.payment_method_id = pm.id
 		%s AND m.payment_method_id IS NOT NULL
@@ -604,20 +713,149 @@ func (r *repository) GetTotals(ctx context.Context, householdID string, filters
 
 	for rows.Next() {
 		var pmName string
-		var sum float64
+		var sum money.Amount
 		if err := rows.Scan(&pmName, &sum); err != nil {
 			return nil, err
 		}
 		totals.ByPaymentMethod[pmName] = sum
 	}
+	rows.Close()
+
+	// Get totals by tag
+	rows, err = r.db(ctx).Query(ctx, fmt.Sprintf(`
+		SELECT t.name, SUM(m.signed_base_amount)
+		FROM movements m
+		JOIN movement_tags mt ON mt.movement_id = m.id
+		JOIN tags t ON t.id = mt.tag_id
+		%s
+		GROUP BY t.name
+	`, spendingClause), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
-	return totals, nil
+	for rows.Next() {
+		var tag string
+		var sum money.Amount
+		if err := rows.Scan(&tag, &sum); err != nil {
+			return nil, err
+		}
+		totals.ByTag[tag] = sum
+	}
+
+	return totals, rows.Err()
+}
+
+// tagFilter returns the condition for movements tagged with the name in
+// argument argNum
+func tagFilter(argNum int) string {
+	return fmt.Sprintf(` AND EXISTS (
+		SELECT 1 FROM movement_tags mt JOIN tags t ON t.id = mt.tag_id
+		WHERE mt.movement_id = m.id AND LOWER(t.name) = LOWER($%d)
+	)`, argNum)
+}
+
+// setTags replaces the tags of a movement, creating the household tags that
+// don't exist yet (names compare case-insensitively, the existing spelling
+// is kept)
+func setTags(ctx context.Context, db dbtx, movementID string, tags []string) error {
+	if _, err := db.Exec(ctx, "DELETE FROM movement_tags WHERE movement_id = $1", movementID); err != nil {
+		return err
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+
+	_, err := db.Exec(ctx, `
+		INSERT INTO tags (household_id, name)
+		SELECT m.household_id, t.name
+		FROM movements m, UNNEST($2::text[]) AS t(name)
+		WHERE m.id = $1
+		ON CONFLICT (household_id, (LOWER(name))) DO NOTHING
+	`, movementID, tags)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(ctx, `
+		INSERT INTO movement_tags (movement_id, tag_id)
+		SELECT m.id, t.id
+		FROM movements m
+		JOIN tags t ON t.household_id = m.household_id
+		WHERE m.id = $1 AND LOWER(t.name) IN (SELECT LOWER(n) FROM UNNEST($2::text[]) AS n)
+		ON CONFLICT DO NOTHING
+	`, movementID, tags)
+	return err
+}
+
+// loadTags fills in the tags of the movements with a single query
+func (r *repository) loadTags(ctx context.Context, movements []*Movement) error {
+	if len(movements) == 0 {
+		return nil
+	}
+	byID := make(map[string]*Movement, len(movements))
+	ids := make([]string, 0, len(movements))
+	for _, m := range movements {
+		byID[m.ID] = m
+		ids = append(ids, m.ID)
+	}
+
+	rows, err := r.db(ctx).Query(ctx, `
+		SELECT mt.movement_id, t.name
+		FROM movement_tags mt
+		JOIN tags t ON t.id = mt.tag_id
+		WHERE mt.movement_id = ANY($1)
+		ORDER BY LOWER(t.name)
+	`, ids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var movementID, name string
+		if err := rows.Scan(&movementID, &name); err != nil {
+			return err
+		}
+		if m := byID[movementID]; m != nil {
+			m.Tags = append(m.Tags, name)
+		}
+	}
+	return rows.Err()
+}
+
+// ListTags lists the tags of a household with how many movements use them
+func (r *repository) ListTags(ctx context.Context, householdID string) ([]*Tag, error) {
+	rows, err := r.db(ctx).Query(ctx, `
+		SELECT t.id, t.name, COUNT(m.id)
+		FROM tags t
+		LEFT JOIN movement_tags mt ON mt.tag_id = t.id
+		LEFT JOIN movements m ON m.id = mt.movement_id AND m.deleted_at IS NULL
+		WHERE t.household_id = $1
+		GROUP BY t.id, t.name
+		ORDER BY LOWER(t.name)
+	`, householdID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := make([]*Tag, 0)
+	for rows.Next() {
+		var tag Tag
+		if err := rows.Scan(&tag.ID, &tag.Name, &tag.MovementCount); err != nil {
+			return nil, err
+		}
+		tags = append(tags, &tag)
+	}
+	return tags, rows.Err()
 }
 
 // Update updates a movement
 func (r *repository) Update(ctx context.Context, id string, input *UpdateMovementInput) (*Movement, error) {
 	// Start a transaction for updating movement and participants
-	tx, err := r.pool.Begin(ctx)
+	tx, err := r.db(ctx).Begin(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -658,6 +896,37 @@ func (r *repository) Update(ctx context.Context, id string, input *UpdateMovemen
 		args = append(args, *input.ReceiverAccountID)
 		argNum++
 	}
+	if input.SourceAccountID != nil {
+		setClauses = append(setClauses, fmt.Sprintf("source_account_id = $%d", argNum))
+		args = append(args, *input.SourceAccountID)
+		argNum++
+	}
+	if input.Installments != nil {
+		setClauses = append(setClauses, fmt.Sprintf("installments = $%d", argNum))
+		args = append(args, *input.Installments)
+		argNum++
+	}
+	if input.InstallmentRate != nil {
+		setClauses = append(setClauses, fmt.Sprintf("installment_rate = $%d", argNum))
+		args = append(args, *input.InstallmentRate)
+		argNum++
+	}
+	if input.InstallmentsFirstCycle != nil {
+		setClauses = append(setClauses, fmt.Sprintf("installments_first_cycle = $%d", argNum))
+		args = append(args, *input.InstallmentsFirstCycle)
+		argNum++
+	}
+	if input.Currency != nil {
+		setClauses = append(setClauses, fmt.Sprintf("currency = $%d", argNum))
+		args = append(args, *input.Currency)
+		argNum++
+	}
+	// Base amount and exchange rate always change together (nil rate clears it)
+	if input.BaseAmount != nil {
+		setClauses = append(setClauses, fmt.Sprintf("base_amount = $%d, exchange_rate = $%d", argNum, argNum+1))
+		args = append(args, *input.BaseAmount, input.ExchangeRate)
+		argNum += 2
+	}
 	
 	// When updating payer, clear the other payer field (user vs contact are mutually exclusive)
 	if input.PayerUserID != nil {
@@ -698,6 +967,17 @@ func (r *repository) Update(ctx context.Context, id string, input *UpdateMovemen
 		argNum++
 	}
 
+	// Event ID (empty string detaches the movement from its event)
+	if input.EventID != nil {
+		if *input.EventID == "" {
+			setClauses = append(setClauses, "event_id = NULL")
+		} else {
+			setClauses = append(setClauses, fmt.Sprintf("event_id = $%d", argNum))
+			args = append(args, *input.EventID)
+			argNum++
+		}
+	}
+
 	if len(setClauses) > 0 {
 		// Always update updated_at
 		setClauses = append(setClauses, fmt.Sprintf("updated_at = $%d", argNum))
@@ -746,6 +1026,13 @@ func (r *repository) Update(ctx context.Context, id string, input *UpdateMovemen
 		}
 	}
 
+	// Replace tags if provided
+	if input.Tags != nil {
+		if err := setTags(ctx, tx, id, *input.Tags); err != nil {
+			return nil, err
+		}
+	}
+
 	// Commit transaction
 	if err = tx.Commit(ctx); err != nil {
 		return nil, err
@@ -754,9 +1041,24 @@ func (r *repository) Update(ctx context.Context, id string, input *UpdateMovemen
 	return r.GetByID(ctx, id)
 }
 
-// Delete deletes a movement
-func (r *repository) Delete(ctx context.Context, id string) error {
-	result, err := r.pool.Exec(ctx, "DELETE FROM movements WHERE id = $1", id)
+// SetConfirmationStatus records the receiver's response to a debt payment.
+// A nil status clears the confirmation (payment no longer needs one).
+func (r *repository) SetConfirmationStatus(ctx context.Context, id string, status *ConfirmationStatus, respondedBy *string, disputeReason *string) error {
+	var respondedAt *time.Time
+	if respondedBy != nil {
+		now := time.Now()
+		respondedAt = &now
+	}
+
+	result, err := r.db(ctx).Exec(ctx, `
+		UPDATE movements
+		SET confirmation_status = $1,
+		    confirmation_responded_by = $2,
+		    confirmation_responded_at = $3,
+		    dispute_reason = $4,
+		    updated_at = NOW()
+		WHERE id = $5
+	`, status, respondedBy, respondedAt, disputeReason, id)
 	if err != nil {
 		return err
 	}
@@ -767,3 +1069,120 @@ func (r *repository) Delete(ctx context.Context, id string) error {
 
 	return nil
 }
+
+// Delete moves a movement to the trash. Its participants, tags, attachments
+// and template link are kept for a restore.
+func (r *repository) Delete(ctx context.Context, id, deletedBy string) error {
+	result, err := r.db(ctx).Exec(ctx, `
+		UPDATE movements SET deleted_at = NOW(), deleted_by = $2
+		WHERE id = $1 AND deleted_at IS NULL
+	`, id, deletedBy)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrMovementNotFound
+	}
+
+	return nil
+}
+
+// Restore takes a movement of the household out of the trash
+func (r *repository) Restore(ctx context.Context, householdID, id string) error {
+	// A refund can't come back without the movement it refunds
+	var originalDeleted bool
+	err := r.db(ctx).QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM movements m JOIN movements o ON o.id = m.refund_of_id
+			WHERE m.id = $1 AND o.deleted_at IS NOT NULL
+		)
+	`, id).Scan(&originalDeleted)
+	if err != nil {
+		return err
+	}
+	if originalDeleted {
+		return ErrRefundOriginalDeleted
+	}
+
+	result, err := r.db(ctx).Exec(ctx, `
+		UPDATE movements SET deleted_at = NULL, deleted_by = NULL
+		WHERE id = $1 AND household_id = $2 AND deleted_at IS NOT NULL
+	`, id, householdID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrMovementNotFound
+	}
+
+	return nil
+}
+
+// Discard permanently deletes a movement without going through the trash
+// (participants, tags and attachment rows go by cascade)
+func (r *repository) Discard(ctx context.Context, id string) error {
+	result, err := r.db(ctx).Exec(ctx, `DELETE FROM movements WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrMovementNotFound
+	}
+
+	return nil
+}
+
+// LockRefunded locks a movement until the transaction in ctx ends (see
+// WithTx) and returns how much of it has been refunded. The total is read
+// after the lock, so it includes refunds committed while waiting for it.
+func (r *repository) LockRefunded(ctx context.Context, id string) (money.Amount, error) {
+	var locked string
+	err := r.db(ctx).QueryRow(ctx, `
+		SELECT id FROM movements WHERE id = $1 AND deleted_at IS NULL FOR UPDATE
+	`, id).Scan(&locked)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, ErrMovementNotFound
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	var refunded money.Amount
+	err = r.db(ctx).QueryRow(ctx, `
+		SELECT COALESCE(SUM(amount), 0) FROM movements
+		WHERE refund_of_id = $1 AND deleted_at IS NULL
+	`, id).Scan(&refunded)
+	return refunded, err
+}
+
+// PurgeDeleted permanently deletes the movements moved to the trash before
+// a date (participants, tags and attachment rows go by cascade). A refunded
+// movement waits until its refunds go too.
+func (r *repository) PurgeDeleted(ctx context.Context, before time.Time) ([]PurgedMovement, error) {
+	rows, err := r.db(ctx).Query(ctx, `
+		DELETE FROM movements
+		WHERE deleted_at IS NOT NULL AND deleted_at < $1
+		  AND NOT EXISTS (
+			SELECT 1 FROM movements r
+			WHERE r.refund_of_id = movements.id AND (r.deleted_at IS NULL OR r.deleted_at >= $1)
+		  )
+		RETURNING id, household_id
+	`, before)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var purged []PurgedMovement
+	for rows.Next() {
+		var p PurgedMovement
+		if err := rows.Scan(&p.ID, &p.HouseholdID); err != nil {
+			return nil, err
+		}
+		purged = append(purged, p)
+	}
+	return purged, rows.Err()
+}
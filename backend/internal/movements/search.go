@@ -0,0 +1,265 @@
+package movements
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/blanquicet/conti/backend/internal/money"
+)
+
+// Errors for movement search
+var (
+	ErrInvalidSort        = errors.New("invalid sort, use date, amount or created")
+	ErrInvalidLimit       = errors.New("limit must be between 1 and 500")
+	ErrInvalidCursor      = errors.New("invalid cursor")
+	ErrInvalidAmountRange = errors.New("amount_min must be less than or equal to amount_max")
+)
+
+// SortField is what movement lists are ordered by
+type SortField string
+
+const (
+	SortByDate    SortField = "date"    // Movement date, then when it was recorded (default)
+	SortByAmount  SortField = "amount"  // Amount in the household currency
+	SortByCreated SortField = "created" // When it was recorded
+)
+
+const (
+	MaxPageSize    = 500
+	maxSearchWords = 10
+)
+
+// Cursor is the position of the last movement of a page. It is handed to
+// clients as an opaque string (see EncodeCursor) and only valid with the sort
+// it was created for.
+type Cursor struct {
+	Sort         SortField    `json:"s"`
+	Ascending    bool         `json:"a,omitempty"`
+	MovementDate time.Time    `json:"d"`
+	BaseAmount   money.Amount `json:"m"`
+	CreatedAt    time.Time    `json:"c"`
+	ID           string       `json:"i"`
+}
+
+// EncodeCursor returns the cursor of the page that starts after m
+func EncodeCursor(m *Movement, sort SortField, ascending bool) string {
+	data, _ := json.Marshal(&Cursor{
+		Sort:         sort,
+		Ascending:    ascending,
+		MovementDate: m.MovementDate,
+		BaseAmount:   m.BaseAmount,
+		CreatedAt:    m.CreatedAt,
+		ID:           m.ID,
+	})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor parses a cursor returned by EncodeCursor
+func DecodeCursor(s string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var cursor Cursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == "" {
+		return nil, ErrInvalidCursor
+	}
+	return &cursor, nil
+}
+
+// Validate checks the filters and fills in the default sort
+func (f *ListMovementsFilters) Validate() error {
+	if f.Sort == "" {
+		f.Sort = SortByDate
+	}
+	switch f.Sort {
+	case SortByDate, SortByAmount, SortByCreated:
+	default:
+		return ErrInvalidSort
+	}
+	if f.Limit < 0 || f.Limit > MaxPageSize {
+		return ErrInvalidLimit
+	}
+	// A cursor only makes sense for the order it came from
+	if f.Cursor != nil && (f.Cursor.Sort != f.Sort || f.Cursor.Ascending != f.Ascending) {
+		return ErrInvalidCursor
+	}
+	if f.AmountMin != nil && f.AmountMax != nil && *f.AmountMin > *f.AmountMax {
+		return ErrInvalidAmountRange
+	}
+	return nil
+}
+
+// sortColumns returns the columns movements are ordered by for a sort,
+// always ending in m.id so the order (and every cursor) is stable
+func sortColumns(sort SortField) []string {
+	switch sort {
+	case SortByAmount:
+		return []string{"m.base_amount", "m.id"}
+	case SortByCreated:
+		return []string{"m.created_at", "m.id"}
+	default:
+		return []string{"m.movement_date", "m.created_at", "m.id"}
+	}
+}
+
+// orderClause returns the ORDER BY clause for the filters' sort. The trash
+// is always listed most recently deleted first.
+func orderClause(filters *ListMovementsFilters) string {
+	if filters != nil && filters.Deleted {
+		return " ORDER BY m.deleted_at DESC, m.id DESC"
+	}
+	sort, direction := SortByDate, "DESC"
+	if filters != nil {
+		if filters.Sort != "" {
+			sort = filters.Sort
+		}
+		if filters.Ascending {
+			direction = "ASC"
+		}
+	}
+	columns := sortColumns(sort)
+	for i := range columns {
+		columns[i] += " " + direction
+	}
+	return " ORDER BY " + strings.Join(columns, ", ")
+}
+
+// cursorClause returns the condition for the movements after the cursor
+// (a row comparison, which the pagination indexes serve)
+func cursorClause(cursor *Cursor, args []interface{}) (string, []interface{}) {
+	var values []string
+	for _, column := range sortColumns(cursor.Sort) {
+		switch column {
+		case "m.movement_date":
+			args = append(args, cursor.MovementDate)
+			values = append(values, fmt.Sprintf("$%d::date", len(args)))
+		case "m.base_amount":
+			args = append(args, cursor.BaseAmount)
+			values = append(values, fmt.Sprintf("$%d::numeric", len(args)))
+		case "m.created_at":
+			args = append(args, cursor.CreatedAt)
+			values = append(values, fmt.Sprintf("$%d::timestamptz", len(args)))
+		case "m.id":
+			args = append(args, cursor.ID)
+			values = append(values, fmt.Sprintf("$%d::uuid", len(args)))
+		}
+	}
+
+	operator := "<"
+	if cursor.Ascending {
+		operator = ">"
+	}
+	return fmt.Sprintf(" AND (%s) %s (%s)",
+		strings.Join(sortColumns(cursor.Sort), ", "), operator, strings.Join(values, ", ")), args
+}
+
+// filterClause appends the conditions for the filters (movements aliased m)
+// to a WHERE clause whose arguments are args. Movements in the trash are
+// left out unless the filters ask for them.
+func filterClause(filters *ListMovementsFilters, args []interface{}) (string, []interface{}) {
+	if filters == nil {
+		return " AND m.deleted_at IS NULL", args
+	}
+
+	var clause strings.Builder
+	if filters.Deleted {
+		clause.WriteString(" AND m.deleted_at IS NOT NULL")
+	} else {
+		clause.WriteString(" AND m.deleted_at IS NULL")
+	}
+	add := func(format string, value interface{}) {
+		args = append(args, value)
+		clause.WriteString(fmt.Sprintf(format, len(args)))
+	}
+
+	if filters.Type != nil {
+		add(" AND m.type = $%d", *filters.Type)
+	}
+	if filters.Month != nil {
+		add(" AND TO_CHAR(m.movement_date, 'YYYY-MM') = $%d", *filters.Month)
+	}
+	if filters.StartDate != nil {
+		add(" AND m.movement_date >= $%d", *filters.StartDate)
+	}
+	if filters.EndDate != nil {
+		add(" AND m.movement_date <= $%d", *filters.EndDate)
+	}
+	if filters.MemberID != nil {
+		add(" AND m.payer_user_id = $%d", *filters.MemberID)
+	}
+	if filters.EventID != nil {
+		add(" AND m.event_id = $%d", *filters.EventID)
+	}
+	if filters.Tag != nil {
+		args = append(args, *filters.Tag)
+		clause.WriteString(tagFilter(len(args)))
+	}
+	if filters.Search != nil {
+		for _, word := range searchWords(*filters.Search) {
+			add(" AND m.description ILIKE $%d", "%"+escapeLike(word)+"%")
+		}
+	}
+	if filters.AmountMin != nil {
+		add(" AND m.base_amount >= $%d", *filters.AmountMin)
+	}
+	if filters.AmountMax != nil {
+		add(" AND m.base_amount <= $%d", *filters.AmountMax)
+	}
+	if filters.CategoryID != nil {
+		add(" AND m.category_id = $%d", *filters.CategoryID)
+	}
+	if filters.CategoryGroupID != nil {
+		add(" AND m.category_id IN (SELECT id FROM categories WHERE category_group_id = $%d)", *filters.CategoryGroupID)
+	}
+	if filters.PaymentMethodID != nil {
+		add(" AND m.payment_method_id = $%d", *filters.PaymentMethodID)
+	}
+	if filters.ContactID != nil {
+		args = append(args, *filters.ContactID)
+		n := len(args)
+		clause.WriteString(fmt.Sprintf(` AND (m.payer_contact_id = $%d OR m.counterparty_contact_id = $%d OR EXISTS (
+			SELECT 1 FROM movement_participants mp WHERE mp.movement_id = m.id AND mp.participant_contact_id = $%d
+		))`, n, n, n))
+	}
+	if filters.TemplateID != nil {
+		add(" AND m.generated_from_template_id = $%d", *filters.TemplateID)
+	}
+	if filters.FromTemplate != nil {
+		if *filters.FromTemplate {
+			clause.WriteString(" AND m.generated_from_template_id IS NOT NULL")
+		} else {
+			clause.WriteString(" AND m.generated_from_template_id IS NULL")
+		}
+	}
+	if filters.AccountID != nil {
+		// Same rule as account balances: received in the account, transferred
+		// out of it, or paid with a payment method that draws from it
+		args = append(args, *filters.AccountID)
+		n := len(args)
+		clause.WriteString(fmt.Sprintf(` AND (m.receiver_account_id = $%d OR m.source_account_id = $%d OR EXISTS (
+			SELECT 1 FROM payment_methods fpm
+			WHERE fpm.id = m.payment_method_id AND COALESCE(fpm.linked_account_id, fpm.account_id) = $%d
+		))`, n, n, n))
+	}
+
+	return clause.String(), args
+}
+
+// searchWords splits a search into the words every description must contain
+func searchWords(search string) []string {
+	words := strings.Fields(search)
+	if len(words) > maxSearchWords {
+		words = words[:maxSearchWords]
+	}
+	return words
+}
+
+// escapeLike escapes the LIKE wildcards of user input
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
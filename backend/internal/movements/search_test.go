@@ -0,0 +1,133 @@
+package movements
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blanquicet/conti/backend/internal/money"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	m := &Movement{
+		ID:           "7b7e5f7e-8a61-4f0e-9a57-2f0b3c1d9e10",
+		MovementDate: time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC),
+		BaseAmount:   money.FromMinor(12345678),
+		CreatedAt:    time.Date(2026, 3, 10, 14, 5, 6, 123456000, time.UTC),
+	}
+
+	cursor, err := DecodeCursor(EncodeCursor(m, SortByAmount, true))
+	if err != nil {
+		t.Fatalf("DecodeCursor() error = %v", err)
+	}
+	if cursor.Sort != SortByAmount || !cursor.Ascending || cursor.ID != m.ID ||
+		cursor.BaseAmount != m.BaseAmount || !cursor.CreatedAt.Equal(m.CreatedAt) ||
+		!cursor.MovementDate.Equal(m.MovementDate) {
+		t.Errorf("cursor = %+v, want the movement's sort keys", cursor)
+	}
+
+	for _, bad := range []string{"not base64!", "e30"} { // e30 is {}
+		if _, err := DecodeCursor(bad); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("DecodeCursor(%q) error = %v, want ErrInvalidCursor", bad, err)
+		}
+	}
+}
+
+func TestListMovementsFiltersValidate(t *testing.T) {
+	filters := &ListMovementsFilters{}
+	if err := filters.Validate(); err != nil || filters.Sort != SortByDate {
+		t.Errorf("Validate() = %v, sort %q; want nil and the date sort", err, filters.Sort)
+	}
+
+	cursor := &Cursor{Sort: SortByDate, ID: "x"}
+	tests := []struct {
+		name    string
+		filters ListMovementsFilters
+		wantErr error
+	}{
+		{"unknown sort", ListMovementsFilters{Sort: "payer"}, ErrInvalidSort},
+		{"limit too large", ListMovementsFilters{Limit: MaxPageSize + 1}, ErrInvalidLimit},
+		{"cursor from another sort", ListMovementsFilters{Sort: SortByAmount, Cursor: cursor}, ErrInvalidCursor},
+		{"cursor from another order", ListMovementsFilters{Ascending: true, Cursor: cursor}, ErrInvalidCursor},
+		{
+			"inverted amount range",
+			ListMovementsFilters{AmountMin: amountPtr(money.New(10)), AmountMax: amountPtr(money.New(5))},
+			ErrInvalidAmountRange,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.filters.Validate(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFilterClause(t *testing.T) {
+	search := "uber  50%_off"
+	contact := "contact-1"
+	fromTemplate := false
+	clause, args := filterClause(&ListMovementsFilters{
+		Search:       &search,
+		ContactID:    &contact,
+		FromTemplate: &fromTemplate,
+	}, []interface{}{"household-1"})
+
+	// Placeholders continue after the household argument
+	if !strings.Contains(clause, "m.description ILIKE $2") || !strings.Contains(clause, "m.description ILIKE $3") {
+		t.Errorf("clause = %s, want one ILIKE per word", clause)
+	}
+	if strings.Count(clause, "$4") != 3 {
+		t.Errorf("clause = %s, want the contact argument used three times", clause)
+	}
+	if !strings.Contains(clause, "generated_from_template_id IS NULL") {
+		t.Errorf("clause = %s, want the hand-entered filter", clause)
+	}
+	if len(args) != 4 || args[2] != `%50\%\_off%` {
+		t.Errorf("args = %q, want escaped LIKE wildcards", args)
+	}
+}
+
+func TestFilterClause_Trash(t *testing.T) {
+	tests := []struct {
+		name    string
+		filters *ListMovementsFilters
+		want    string
+	}{
+		{"no filters", nil, "m.deleted_at IS NULL"},
+		{"live", &ListMovementsFilters{}, "m.deleted_at IS NULL"},
+		{"trash", &ListMovementsFilters{Deleted: true}, "m.deleted_at IS NOT NULL"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clause, _ := filterClause(tt.filters, nil)
+			if !strings.HasPrefix(clause, " AND "+tt.want) {
+				t.Errorf("clause = %s, want %s", clause, tt.want)
+			}
+		})
+	}
+
+	if got := orderClause(&ListMovementsFilters{Deleted: true, Sort: SortByAmount}); got != " ORDER BY m.deleted_at DESC, m.id DESC" {
+		t.Errorf("trash orderClause() = %q", got)
+	}
+}
+
+func TestCursorClause(t *testing.T) {
+	clause, args := cursorClause(&Cursor{Sort: SortByDate, ID: "x"}, []interface{}{"household-1"})
+	want := " AND (m.movement_date, m.created_at, m.id) < ($2::date, $3::timestamptz, $4::uuid)"
+	if clause != want || len(args) != 4 {
+		t.Errorf("cursorClause() = %q with %d args, want %q with 4", clause, len(args), want)
+	}
+
+	clause, _ = cursorClause(&Cursor{Sort: SortByAmount, Ascending: true, ID: "x"}, nil)
+	if clause != " AND (m.base_amount, m.id) > ($1::numeric, $2::uuid)" {
+		t.Errorf("ascending amount cursorClause() = %q", clause)
+	}
+	if got := orderClause(&ListMovementsFilters{Sort: SortByAmount, Ascending: true}); got != " ORDER BY m.base_amount ASC, m.id ASC" {
+		t.Errorf("orderClause() = %q", got)
+	}
+}
+
+func amountPtr(a money.Amount) *money.Amount { return &a }
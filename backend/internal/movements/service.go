@@ -4,10 +4,13 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	"time"
 
 	"github.com/blanquicet/conti/backend/internal/accounts"
 	"github.com/blanquicet/conti/backend/internal/audit"
+	"github.com/blanquicet/conti/backend/internal/fx"
 	"github.com/blanquicet/conti/backend/internal/households"
+	"github.com/blanquicet/conti/backend/internal/money"
 	"github.com/blanquicet/conti/backend/internal/paymentmethods"
 )
 
@@ -17,6 +20,9 @@ type service struct {
 	householdsRepo    households.HouseholdRepository
 	paymentMethodRepo paymentmethods.Repository
 	accountsRepo      accounts.Repository
+	fxRepo            fx.Repository
+	ruleApplier       RuleApplier       // Optional
+	attachments       AttachmentCleaner // Optional
 	auditService      audit.Service
 	logger            *slog.Logger
 }
@@ -27,6 +33,9 @@ func NewService(
 	householdsRepo households.HouseholdRepository,
 	paymentMethodRepo paymentmethods.Repository,
 	accountsRepo accounts.Repository,
+	fxRepo fx.Repository,
+	ruleApplier RuleApplier,
+	attachments AttachmentCleaner,
 	auditService audit.Service,
 	logger *slog.Logger,
 ) Service {
@@ -35,6 +44,9 @@ func NewService(
 		householdsRepo:    householdsRepo,
 		paymentMethodRepo: paymentMethodRepo,
 		accountsRepo:      accountsRepo,
+		fxRepo:            fxRepo,
+		ruleApplier:       ruleApplier,
+		attachments:       attachments,
 		auditService:      auditService,
 		logger:            logger,
 	}
@@ -42,17 +54,22 @@ func NewService(
 
 // Create creates a new movement
 func (s *service) Create(ctx context.Context, userID string, input *CreateMovementInput) (*Movement, error) {
-	// Validate input
-	if err := input.Validate(); err != nil {
-		return nil, err
-	}
-
 	// Get user's household
 	householdID, err := s.householdsRepo.GetUserHouseholdID(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
 
+	// Let the household's rules fill in what was left empty
+	if err := s.applyRules(ctx, householdID, input); err != nil {
+		return nil, err
+	}
+
+	// Validate input
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
 	// Verify payer belongs to household (if user) or is a contact of household
 	if input.PayerUserID != nil {
 		isMember, err := s.householdsRepo.IsUserMember(ctx, householdID, *input.PayerUserID)
@@ -77,6 +94,7 @@ func (s *service) Create(ctx context.Context, userID string, input *CreateMoveme
 	}
 
 	// Verify payment method belongs to household (if provided)
+	var paymentMethod *paymentmethods.PaymentMethod
 	if input.PaymentMethodID != nil {
 		pm, err := s.paymentMethodRepo.GetByID(ctx, *input.PaymentMethodID)
 		if err != nil {
@@ -85,6 +103,17 @@ func (s *service) Create(ctx context.Context, userID string, input *CreateMoveme
 		if pm.HouseholdID != householdID {
 			return nil, ErrNotAuthorized
 		}
+		paymentMethod = pm
+	}
+
+	// Only credit card purchases can be deferred over installments
+	if input.Installments != nil && *input.Installments > 1 {
+		if paymentMethod == nil || paymentMethod.Type != paymentmethods.TypeCreditCard {
+			return nil, ErrInstallmentsNotCreditCard
+		}
+		if input.InstallmentsFirstCycle == nil {
+			input.InstallmentsFirstCycle = &input.MovementDate
+		}
 	}
 
 	// Verify receiver account for DEBT_PAYMENT with household member receiver
@@ -146,6 +175,51 @@ func (s *service) Create(ctx context.Context, userID string, input *CreateMoveme
 		}
 	}
 
+	// Verify both accounts of a TRANSFER
+	if input.Type == TypeTransfer {
+		for _, accountID := range []string{*input.SourceAccountID, *input.ReceiverAccountID} {
+			if err := s.validateTransferAccount(ctx, householdID, accountID); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Verify event belongs to household and is still open (if provided)
+	if input.EventID != nil {
+		if err := s.validateEvent(ctx, householdID, *input.EventID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Convert to the household currency, keeping the original amount and currency
+	currency, baseAmount, rate, err := s.convert(ctx, householdID, input.Amount, input.Currency, input.ExchangeRate, input.MovementDate)
+	if err != nil {
+		return nil, err
+	}
+	input.Currency = currency
+	input.BaseAmount = baseAmount
+	input.ExchangeRate = rate
+
+	// Split the amount between participants exactly (no centavo lost or invented)
+	if input.Type == TypeSplit {
+		if err := AllocateParticipants(input.Amount, input.Participants); err != nil {
+			return nil, err
+		}
+	}
+
+	// Debt payments to a linked contact wait for the receiver's confirmation
+	input.ConfirmationStatus = nil
+	if input.Type == TypeDebtPayment && input.CounterpartyContactID != nil {
+		needsConfirmation, err := s.requiresConfirmation(ctx, *input.CounterpartyContactID)
+		if err != nil {
+			return nil, err
+		}
+		if needsConfirmation {
+			pending := ConfirmationPending
+			input.ConfirmationStatus = &pending
+		}
+	}
+
 	// Resolve category ID from category name if needed
 	if input.Category != nil && *input.Category != "" && input.CategoryID == nil {
 		// Look up category by name in household
@@ -173,8 +247,8 @@ func (s *service) Create(ctx context.Context, userID string, input *CreateMoveme
 		return nil, err
 	}
 
-	// Log successful creation
-	s.auditService.LogAsync(ctx, &audit.LogInput{
+	// Log successful creation (after commit when inside withTx)
+	s.logAudit(ctx, &audit.LogInput{
 		UserID:       audit.StringPtr(userID),
 		Action:       audit.ActionMovementCreated,
 		ResourceType: "movement",
@@ -187,6 +261,204 @@ func (s *service) Create(ctx context.Context, userID string, input *CreateMoveme
 	return movement, nil
 }
 
+// applyRules fills in a new movement from the household's rules, once.
+// Movements generated from recurring templates are created exactly as the
+// template says.
+func (s *service) applyRules(ctx context.Context, householdID string, input *CreateMovementInput) error {
+	if s.ruleApplier == nil || input.GeneratedFromTemplateID != nil || input.rulesApplied {
+		return nil
+	}
+	if err := s.ruleApplier.Apply(ctx, householdID, input); err != nil {
+		return err
+	}
+	input.rulesApplied = true
+	return nil
+}
+
+// pendingAuditKey is the context key for the audit entries held back by withTx
+type pendingAuditKey struct{}
+
+// pendingAudit collects audit entries of a transaction that hasn't committed yet
+type pendingAudit struct {
+	entries []*audit.LogInput
+}
+
+// logAudit logs a successful change, or holds it back until the transaction
+// started by withTx commits
+func (s *service) logAudit(ctx context.Context, input *audit.LogInput) {
+	if pending, ok := ctx.Value(pendingAuditKey{}).(*pendingAudit); ok {
+		pending.entries = append(pending.entries, input)
+		return
+	}
+	s.auditService.LogAsync(ctx, input)
+}
+
+// withTx runs fn in a single transaction (see Repository.WithTx). The audit
+// entries of the changes made by fn are only logged once it commits, so a
+// rolled back change never shows up as successful in the audit log.
+func (s *service) withTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	pending := &pendingAudit{}
+	if err := s.repo.WithTx(context.WithValue(ctx, pendingAuditKey{}, pending), fn); err != nil {
+		return err
+	}
+	for _, entry := range pending.entries {
+		s.auditService.LogAsync(ctx, entry)
+	}
+	return nil
+}
+
+// validateEvent checks that an event belongs to the household and accepts movements
+func (s *service) validateEvent(ctx context.Context, householdID, eventID string) error {
+	eventHouseholdID, status, err := s.repo.GetEventStatus(ctx, eventID)
+	if err != nil {
+		return err
+	}
+	if eventHouseholdID != householdID {
+		return ErrNotAuthorized
+	}
+	if status == "closed" {
+		return ErrEventClosed
+	}
+	return nil
+}
+
+// checkInstallmentsUpdate checks installments being set on a movement, or the
+// payment method of a purchase in installments being changed
+func (s *service) checkInstallmentsUpdate(ctx context.Context, existing *Movement, input *UpdateMovementInput) error {
+	installments := existing.Installments
+	if input.Installments != nil {
+		installments = input.Installments
+	}
+	if installments == nil {
+		if input.InstallmentRate != nil || input.InstallmentsFirstCycle != nil {
+			return ErrInstallmentsRequired
+		}
+		return nil
+	}
+	if *installments <= 1 || (input.Installments == nil && input.PaymentMethodID == nil) {
+		return nil
+	}
+
+	if (existing.Type != TypeHousehold && existing.Type != TypeSplit) || existing.IsRefund() {
+		return ErrInstallmentsNotAllowed
+	}
+	paymentMethodID := existing.PaymentMethodID
+	if input.PaymentMethodID != nil {
+		paymentMethodID = input.PaymentMethodID
+	}
+	if paymentMethodID == nil {
+		return ErrInstallmentsNotCreditCard
+	}
+	pm, err := s.paymentMethodRepo.GetByID(ctx, *paymentMethodID)
+	if err != nil {
+		return err
+	}
+	if pm.Type != paymentmethods.TypeCreditCard {
+		return ErrInstallmentsNotCreditCard
+	}
+
+	// Newly deferred purchases start in the cycle of their date
+	if existing.InstallmentsFirstCycle == nil && input.InstallmentsFirstCycle == nil {
+		date := existing.MovementDate
+		if input.MovementDate != nil {
+			date = *input.MovementDate
+		}
+		input.InstallmentsFirstCycle = &date
+	}
+	return nil
+}
+
+// validateTransferAccount checks that an account belongs to the household and
+// holds money of its own (savings or cash), so it can be either side of a transfer
+func (s *service) validateTransferAccount(ctx context.Context, householdID, accountID string) error {
+	account, err := s.accountsRepo.GetByID(ctx, accountID)
+	if err != nil {
+		if errors.Is(err, accounts.ErrAccountNotFound) {
+			return errors.New("transfer account not found")
+		}
+		return err
+	}
+	if account.HouseholdID != householdID {
+		return ErrNotAuthorized
+	}
+	if !account.Type.CanReceiveIncome() {
+		return ErrInvalidTransferAccount
+	}
+	return nil
+}
+
+// householdCurrency returns the currency the household keeps its totals in
+func (s *service) householdCurrency(ctx context.Context, householdID string) (string, error) {
+	household, err := s.householdsRepo.GetByID(ctx, householdID)
+	if err != nil {
+		return "", err
+	}
+	if household.Currency == "" {
+		return "COP", nil
+	}
+	return household.Currency, nil
+}
+
+// convert resolves the currency of an amount (empty means the household
+// currency) and converts the amount to the household currency. A nil rate is
+// returned when no conversion is needed. Otherwise the given rate is used, or
+// the latest household FX rate on or before date.
+func (s *service) convert(ctx context.Context, householdID string, amount money.Amount, currency string, rate *float64, date time.Time) (string, money.Amount, *float64, error) {
+	base, err := s.householdCurrency(ctx, householdID)
+	if err != nil {
+		return "", 0, nil, err
+	}
+	if currency == "" || currency == base {
+		return base, amount, nil, nil
+	}
+
+	if rate == nil {
+		found, err := s.fxRepo.FindRate(ctx, householdID, currency, base, date)
+		if errors.Is(err, fx.ErrRateNotFound) {
+			return "", 0, nil, ErrExchangeRateNotFound
+		}
+		if err != nil {
+			return "", 0, nil, err
+		}
+		rate = &found
+	}
+	return currency, fx.Convert(amount, *rate), rate, nil
+}
+
+// rebase re-expresses a movement of another household in this household's
+// currency. Its stored base amount may be in a different currency, so it is
+// converted again from the original amount with this household's rates.
+func (s *service) rebase(ctx context.Context, householdID, currency string, m *Movement) (*Movement, error) {
+	rebased := *m
+	if m.Currency == currency {
+		rebased.ExchangeRate = nil
+		rebased.BaseAmount = m.Amount
+		return &rebased, nil
+	}
+
+	rate, err := s.fxRepo.FindRate(ctx, householdID, m.Currency, currency, m.MovementDate)
+	if err != nil {
+		return nil, err
+	}
+	rebased.ExchangeRate = &rate
+	rebased.BaseAmount = fx.Convert(m.Amount, rate)
+	return &rebased, nil
+}
+
+// requiresConfirmation reports whether a debt payment to the given contact must
+// be confirmed by the receiver: the contact is linked to a user (who can
+// respond from their own household) and the link was accepted.
+func (s *service) requiresConfirmation(ctx context.Context, contactID string) (bool, error) {
+	contact, err := s.householdsRepo.GetContact(ctx, contactID)
+	if err != nil {
+		if errors.Is(err, households.ErrContactNotFound) {
+			return false, nil // FK constraint will reject the movement
+		}
+		return false, err
+	}
+	return contact.LinkedUserID != nil && contact.IsActive && contact.LinkStatus == "ACCEPTED", nil
+}
+
 // GetByID retrieves a movement by ID
 func (s *service) GetByID(ctx context.Context, userID, id string) (*Movement, error) {
 	// Get movement
@@ -216,11 +488,27 @@ func (s *service) ListByHousehold(ctx context.Context, userID string, filters *L
 		return nil, err
 	}
 
-	// Get movements
-	movements, err := s.repo.ListByHousehold(ctx, householdID, filters)
+	if filters == nil {
+		filters = &ListMovementsFilters{}
+	}
+	if err := filters.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Get movements (one extra row tells whether there is a next page)
+	page := *filters
+	if page.Limit > 0 {
+		page.Limit++
+	}
+	movements, err := s.repo.ListByHousehold(ctx, householdID, &page)
 	if err != nil {
 		return nil, err
 	}
+	var nextCursor string
+	if filters.Limit > 0 && len(movements) > filters.Limit {
+		movements = movements[:filters.Limit]
+		nextCursor = EncodeCursor(movements[len(movements)-1], filters.Sort, filters.Ascending)
+	}
 
 	// Get totals
 	totals, err := s.repo.GetTotals(ctx, householdID, filters)
@@ -229,8 +517,9 @@ func (s *service) ListByHousehold(ctx context.Context, userID string, filters *L
 	}
 
 	return &ListMovementsResponse{
-		Movements: movements,
-		Totals:    totals,
+		Movements:  movements,
+		Totals:     totals,
+		NextCursor: nextCursor,
 	}, nil
 }
 
@@ -254,170 +543,45 @@ func (s *service) GetDebtConsolidation(ctx context.Context, userID string, month
 		return nil, err
 	}
 
-	// Calculate balances: map[debtorID][creditorID] = amount
-	balanceMap := make(map[string]map[string]float64)
-	balanceNames := make(map[string]string) // ID -> Name mapping
-	// Track movements contributing to each debt: map[debtorID][creditorID] = []movements
-	movementDetails := make(map[string]map[string][]DebtMovementDetail)
+	// Accumulate who owes whom (in the household currency), with the movements
+	// contributing to each debt
+	currency, err := s.householdCurrency(ctx, householdID)
+	if err != nil {
+		return nil, err
+	}
+	ledger := NewDebtLedger(currency)
+
+	// Debt payments awaiting the receiver's confirmation are reported apart
+	var pendingPayments []DebtMovementDetail
 
-	// Build contact-to-user translation map for linked contacts in this household
-	// This ensures that debts involving linked contacts use their real user ID,
-	// so they can net correctly with cross-household movements.
-	localContactToUserID := make(map[string]string) // contactID → linked userID
-	localLinkedUserNames := make(map[string]string)  // linked userID → contact name
+	// Debts with contacts linked to a user are recorded under the user's ID,
+	// so they net correctly with cross-household movements
 	contacts, err := s.householdsRepo.ListContacts(ctx, householdID)
 	if err != nil {
 		s.logger.Warn("failed to list contacts for ID translation", "error", err)
-	} else {
-		for _, c := range contacts {
-			if c.LinkedUserID != nil {
-				localContactToUserID[c.ID] = *c.LinkedUserID
-				localLinkedUserNames[*c.LinkedUserID] = c.Name
-			}
-		}
-	}
-
-	// translateLocalContact translates a contact ID to linked user ID if available
-	translateLocalContact := func(contactID string) (string, bool) {
-		if uid, ok := localContactToUserID[contactID]; ok {
-			return uid, true
-		}
-		return contactID, false
 	}
+	ledger.LinkContacts(contacts)
 
 	for _, m := range movements {
-		currency := m.Currency
-		if currency == "" {
-			currency = "COP"
-		}
-
-		// Handle SPLIT movements: participants owe the payer
-		if m.Type == TypeSplit && len(m.Participants) > 0 {
-			payerID := ""
-			payerName := m.PayerName
-			
-			if m.PayerUserID != nil {
-				payerID = *m.PayerUserID
-			} else if m.PayerContactID != nil {
-				if translated, ok := translateLocalContact(*m.PayerContactID); ok {
-					payerID = translated
-				} else {
-					payerID = *m.PayerContactID
-				}
-			}
-			
-			if payerID != "" {
-				balanceNames[payerID] = payerName
-				
-				for _, p := range m.Participants {
-					participantID := ""
-					participantName := p.ParticipantName
-					
-					if p.ParticipantUserID != nil {
-						participantID = *p.ParticipantUserID
-					} else if p.ParticipantContactID != nil {
-						if translated, ok := translateLocalContact(*p.ParticipantContactID); ok {
-							participantID = translated
-						} else {
-							participantID = *p.ParticipantContactID
-						}
-					}
-					
-					// Skip if participant is the payer (they don't owe themselves)
-					if participantID == "" || participantID == payerID {
-						continue
-					}
-					
-					balanceNames[participantID] = participantName
-					
-					// Participant owes payer their share
-					share := m.Amount * p.Percentage
-					
-					if balanceMap[participantID] == nil {
-						balanceMap[participantID] = make(map[string]float64)
-					}
-					balanceMap[participantID][payerID] += share
-					
-					// Track movement detail
-					if movementDetails[participantID] == nil {
-						movementDetails[participantID] = make(map[string][]DebtMovementDetail)
-					}
-					movementDetails[participantID][payerID] = append(
-						movementDetails[participantID][payerID],
-						DebtMovementDetail{
-							MovementID:   m.ID,
-							Description:  m.Description,
-							Amount:       share,
-							MovementDate: m.MovementDate.Format("2006-01-02T15:04:05Z07:00"),
-							Type:         string(TypeSplit),
-							PayerID:      payerID,
-							PayerName:    payerName,
-						},
-					)
-				}
-			}
-		}
-
-		// Handle DEBT_PAYMENT movements: counterparty owes payer (or vice versa)
-		if m.Type == TypeDebtPayment {
-			payerID := ""
-			payerName := m.PayerName
-			counterpartyID := ""
-			counterpartyName := ""
-			
-			if m.PayerUserID != nil {
-				payerID = *m.PayerUserID
-			} else if m.PayerContactID != nil {
-				if translated, ok := translateLocalContact(*m.PayerContactID); ok {
-					payerID = translated
-				} else {
-					payerID = *m.PayerContactID
-				}
-			}
-			
-			if m.CounterpartyUserID != nil {
-				counterpartyID = *m.CounterpartyUserID
-			} else if m.CounterpartyContactID != nil {
-				if translated, ok := translateLocalContact(*m.CounterpartyContactID); ok {
-					counterpartyID = translated
-				} else {
-					counterpartyID = *m.CounterpartyContactID
-				}
-			}
-			
-			if m.CounterpartyName != nil {
-				counterpartyName = *m.CounterpartyName
-			}
-			
+		if m.Type == TypeDebtPayment && m.AwaitingConfirmation() {
+			payerID := ledger.PersonID(m.PayerUserID, m.PayerContactID)
+			counterpartyID := ledger.PersonID(m.CounterpartyUserID, m.CounterpartyContactID)
 			if payerID != "" && counterpartyID != "" {
-				balanceNames[payerID] = payerName
-				balanceNames[counterpartyID] = counterpartyName
-				
-				// Debt payment: payer pays counterparty
-				// This REDUCES what payer owes counterparty
-				if balanceMap[payerID] == nil {
-					balanceMap[payerID] = make(map[string]float64)
-				}
-				balanceMap[payerID][counterpartyID] -= m.Amount
-				
-				// Track movement detail (negative amount for payment)
-				if movementDetails[payerID] == nil {
-					movementDetails[payerID] = make(map[string][]DebtMovementDetail)
-				}
-				movementDetails[payerID][counterpartyID] = append(
-					movementDetails[payerID][counterpartyID],
-					DebtMovementDetail{
-						MovementID:   m.ID,
-						Description:  m.Description,
-						Amount:       -m.Amount, // Negative because it reduces debt
-						MovementDate: m.MovementDate.Format("2006-01-02T15:04:05Z07:00"),
-						Type:         string(TypeDebtPayment),
-						PayerID:      payerID,      // Who made the payment
-						PayerName:    payerName,    // Name of who made the payment
-					},
-				)
+				// Not netted until the receiver confirms it
+				pendingPayments = append(pendingPayments, withOriginal(m, m.Amount, DebtMovementDetail{
+					MovementID:         m.ID,
+					Description:        m.Description,
+					Amount:             m.HouseholdAmount(),
+					MovementDate:       m.MovementDate.Format("2006-01-02T15:04:05Z07:00"),
+					Type:               string(TypeDebtPayment),
+					PayerID:            payerID,
+					PayerName:          m.PayerName,
+					ConfirmationStatus: string(*m.ConfirmationStatus),
+				}))
 			}
+			continue
 		}
+		ledger.AddMovement(m)
 	}
 
 	// Get household members (needed for cross-household name resolution and summary)
@@ -453,6 +617,13 @@ func (s *service) GetDebtConsolidation(ctx context.Context, userID string, month
 			// Process cross-household movements using the same balance logic,
 			// but translate the user's contact_id to their real user_id
 			for _, m := range crossMovements {
+				// Express the movement in this household's currency
+				m, err := s.rebase(ctx, householdID, currency, m)
+				if err != nil {
+					s.logger.Warn("skipping cross-household movement without exchange rate", "error", err)
+					continue
+				}
+
 				// Determine which household this movement belongs to
 				sourceHouseholdName := ""
 				for _, lc := range linkedContacts {
@@ -509,9 +680,11 @@ func (s *service) GetDebtConsolidation(ctx context.Context, userID string, month
 					}
 
 					if payerID != "" {
-						balanceNames[payerID] = payerName
+						ledger.SetName(payerID, payerName)
 
-						for _, p := range m.Participants {
+						shares := m.HouseholdShares()
+						originals := m.ParticipantShares()
+						for i, p := range m.Participants {
 							participantID := ""
 							participantName := p.ParticipantName
 
@@ -530,32 +703,20 @@ func (s *service) GetDebtConsolidation(ctx context.Context, userID string, month
 								continue
 							}
 
-							balanceNames[participantID] = participantName
-
-							share := m.Amount * p.Percentage
-
-							if balanceMap[participantID] == nil {
-								balanceMap[participantID] = make(map[string]float64)
-							}
-							balanceMap[participantID][payerID] += share
+							ledger.SetName(participantID, participantName)
 
-							if movementDetails[participantID] == nil {
-								movementDetails[participantID] = make(map[string][]DebtMovementDetail)
-							}
-							movementDetails[participantID][payerID] = append(
-								movementDetails[participantID][payerID],
-								DebtMovementDetail{
-									MovementID:          m.ID,
-									Description:         m.Description,
-									Amount:              share,
-									MovementDate:        m.MovementDate.Format("2006-01-02T15:04:05Z07:00"),
-									Type:                string(TypeSplit),
-									PayerID:             payerID,
-									PayerName:           payerName,
-									IsCrossHousehold:    true,
-									SourceHouseholdName: sourceHouseholdName,
-								},
-							)
+							share := shares[i]
+							ledger.Add(participantID, payerID, share, withOriginal(m, originals[i], DebtMovementDetail{
+								MovementID:          m.ID,
+								Description:         m.Description,
+								Amount:              share,
+								MovementDate:        m.MovementDate.Format("2006-01-02T15:04:05Z07:00"),
+								Type:                string(TypeSplit),
+								PayerID:             payerID,
+								PayerName:           payerName,
+								IsCrossHousehold:    true,
+								SourceHouseholdName: sourceHouseholdName,
+							}))
 						}
 					}
 				}
@@ -592,32 +753,35 @@ func (s *service) GetDebtConsolidation(ctx context.Context, userID string, month
 						counterpartyName = *m.CounterpartyName
 					}
 
-					if payerID != "" && counterpartyID != "" {
-						balanceNames[payerID] = payerName
-						balanceNames[counterpartyID] = counterpartyName
-
-						if balanceMap[payerID] == nil {
-							balanceMap[payerID] = make(map[string]float64)
-						}
-						balanceMap[payerID][counterpartyID] -= m.Amount
-
-						if movementDetails[payerID] == nil {
-							movementDetails[payerID] = make(map[string][]DebtMovementDetail)
-						}
-						movementDetails[payerID][counterpartyID] = append(
-							movementDetails[payerID][counterpartyID],
-							DebtMovementDetail{
-								MovementID:          m.ID,
-								Description:         m.Description,
-								Amount:              -m.Amount,
-								MovementDate:        m.MovementDate.Format("2006-01-02T15:04:05Z07:00"),
-								Type:                string(TypeDebtPayment),
-								PayerID:             payerID,
-								PayerName:           payerName,
-								IsCrossHousehold:    true,
-								SourceHouseholdName: sourceHouseholdName,
-							},
-						)
+					if payerID != "" && counterpartyID != "" && m.AwaitingConfirmation() {
+						// Paid to us from another household, waiting for our confirmation
+						pendingPayments = append(pendingPayments, withOriginal(m, m.Amount, DebtMovementDetail{
+							MovementID:          m.ID,
+							Description:         m.Description,
+							Amount:              m.HouseholdAmount(),
+							MovementDate:        m.MovementDate.Format("2006-01-02T15:04:05Z07:00"),
+							Type:                string(TypeDebtPayment),
+							PayerID:             payerID,
+							PayerName:           payerName,
+							IsCrossHousehold:    true,
+							SourceHouseholdName: sourceHouseholdName,
+							ConfirmationStatus:  string(*m.ConfirmationStatus),
+						}))
+					} else if payerID != "" && counterpartyID != "" {
+						ledger.SetName(payerID, payerName)
+						ledger.SetName(counterpartyID, counterpartyName)
+
+						ledger.Add(payerID, counterpartyID, -m.HouseholdAmount(), withOriginal(m, -m.Amount, DebtMovementDetail{
+							MovementID:          m.ID,
+							Description:         m.Description,
+							Amount:              -m.HouseholdAmount(),
+							MovementDate:        m.MovementDate.Format("2006-01-02T15:04:05Z07:00"),
+							Type:                string(TypeDebtPayment),
+							PayerID:             payerID,
+							PayerName:           payerName,
+							IsCrossHousehold:    true,
+							SourceHouseholdName: sourceHouseholdName,
+						}))
 					}
 				}
 			}
@@ -629,115 +793,19 @@ func (s *service) GetDebtConsolidation(ctx context.Context, userID string, month
 	// sees people by the names they chose locally.
 	if members != nil {
 		for _, member := range members {
-			if _, exists := balanceNames[member.UserID]; exists {
-				balanceNames[member.UserID] = member.UserName
+			if ledger.HasName(member.UserID) {
+				ledger.SetName(member.UserID, member.UserName)
 			}
 		}
 	}
-	for cID, linkedUID := range localContactToUserID {
-		// Find the contact name from the contacts list
-		for _, c := range contacts {
-			if c.ID == cID && c.LinkedUserID != nil {
-				balanceNames[linkedUID] = c.Name
-				break
-			}
+	for _, c := range contacts {
+		if c.LinkedUserID != nil {
+			ledger.SetName(*c.LinkedUserID, c.Name)
 		}
 	}
 
-	// Convert balance map to list of DebtBalance, netting out negative amounts
-	var balances []DebtBalance
-	processed := make(map[string]bool) // Track processed pairs to avoid duplicates
-
-	for debtorID, creditors := range balanceMap {
-		for creditorID, amount := range creditors {
-			pairKey := debtorID + "|" + creditorID
-			reversePairKey := creditorID + "|" + debtorID
-			
-			if processed[pairKey] || processed[reversePairKey] {
-				continue
-			}
-			
-			// Net out reverse debt if exists
-			reverseAmount := 0.0
-			if balanceMap[creditorID] != nil {
-				reverseAmount = balanceMap[creditorID][debtorID]
-			}
-			
-			netAmount := amount - reverseAmount
-			
-			// Combine movements from both directions
-			movements := movementDetails[debtorID][creditorID]
-			if movementDetails[creditorID] != nil {
-				movements = append(movements, movementDetails[creditorID][debtorID]...)
-			}
-
-			// Check if any movement in this pair is cross-household
-			hasCrossHousehold := false
-			for _, md := range movements {
-				if md.IsCrossHousehold {
-					hasCrossHousehold = true
-					break
-				}
-			}
-			
-			// Include balance if:
-			// 1. Net amount is positive (debtor owes creditor)
-			// 2. Net amount is negative (creditor owes debtor - reverse)
-			// 3. Net amount is zero BUT there are movements (debt was settled this month)
-			if netAmount > 1.0 { // Amounts under $1 COP are considered settled
-				balances = append(balances, DebtBalance{
-					DebtorID:         debtorID,
-					DebtorName:       balanceNames[debtorID],
-					CreditorID:       creditorID,
-					CreditorName:     balanceNames[creditorID],
-					Amount:           netAmount,
-					Currency:         "COP", // TODO: handle multi-currency
-					IsCrossHousehold: hasCrossHousehold,
-					Movements:        movements,
-				})
-				processed[pairKey] = true
-			} else if netAmount < -1.0 {
-				// Reverse direction
-				balances = append(balances, DebtBalance{
-					DebtorID:         creditorID,
-					DebtorName:       balanceNames[creditorID],
-					CreditorID:       debtorID,
-					CreditorName:     balanceNames[debtorID],
-					Amount:           -netAmount,
-					Currency:         "COP",
-					IsCrossHousehold: hasCrossHousehold,
-					Movements:        movements,
-				})
-				processed[reversePairKey] = true
-			} else if len(movements) > 0 {
-				// Balance is zero but there are movements - show it
-				// Pick the direction with more debt-increasing movements
-				debtIncreasing := 0.0
-				for _, m := range movementDetails[debtorID][creditorID] {
-					if m.Amount > 0 {
-						debtIncreasing += m.Amount
-					}
-				}
-				
-				balances = append(balances, DebtBalance{
-					DebtorID:         debtorID,
-					DebtorName:       balanceNames[debtorID],
-					CreditorID:       creditorID,
-					CreditorName:     balanceNames[creditorID],
-					Amount:           0,
-					Currency:         "COP",
-					IsCrossHousehold: hasCrossHousehold,
-					Movements:        movements,
-				})
-				processed[pairKey] = true
-				processed[reversePairKey] = true
-			} else {
-				// Balanced out with no movements - don't show
-				processed[pairKey] = true
-				processed[reversePairKey] = true
-			}
-		}
-	}
+	// Net each pair of people into a single balance
+	balances := ledger.Balances()
 
 	// Calculate summary for household members
 	// Use the members fetched earlier to identify internal vs external debts
@@ -750,8 +818,8 @@ func (s *service) GetDebtConsolidation(ctx context.Context, userID string, month
 			memberIDs[member.UserID] = true
 		}
 
-		theyOweUs := 0.0
-		weOwe := 0.0
+		theyOweUs := money.Amount(0)
+		weOwe := money.Amount(0)
 
 		for _, balance := range balances {
 			debtorIsMember := memberIDs[balance.DebtorID]
@@ -775,12 +843,272 @@ func (s *service) GetDebtConsolidation(ctx context.Context, userID string, month
 	}
 
 	return &DebtConsolidationResponse{
-		Balances: balances,
-		Month:    month,
-		Summary:  summary,
+		Balances:        balances,
+		Month:           month,
+		Summary:         summary,
+		PendingPayments: pendingPayments,
 	}, nil
 }
 
+// GetSettlementPlan proposes the minimal set of DEBT_PAYMENT movements that
+// settles the household's debts ("Saldar"). Nothing is created here; the chosen
+// drafts are submitted to SettleDebts.
+func (s *service) GetSettlementPlan(ctx context.Context, userID string, month *string) (*SettlementPlan, error) {
+	householdID, err := s.householdsRepo.GetUserHouseholdID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	consolidation, err := s.GetDebtConsolidation(ctx, userID, month)
+	if err != nil {
+		return nil, err
+	}
+	currency, err := s.householdCurrency(ctx, householdID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Drafts must reference people as this household knows them:
+	// members by user ID, everyone else by local contact ID
+	memberIDs := make(map[string]bool)
+	members, err := s.householdsRepo.GetMembers(ctx, householdID)
+	if err != nil {
+		return nil, err
+	}
+	for _, member := range members {
+		memberIDs[member.UserID] = true
+	}
+
+	contactIDs := make(map[string]bool)
+	linkedUserToContact := make(map[string]string) // linked userID → local contactID
+	contacts, err := s.householdsRepo.ListContacts(ctx, householdID)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range contacts {
+		contactIDs[c.ID] = true
+		if c.LinkedUserID != nil {
+			linkedUserToContact[*c.LinkedUserID] = c.ID
+		}
+	}
+
+	// resolve returns (userID, contactID) for a consolidation person ID
+	resolve := func(id string) (*string, *string, bool) {
+		if memberIDs[id] {
+			return &id, nil, true
+		}
+		if contactIDs[id] {
+			return nil, &id, true
+		}
+		if contactID, ok := linkedUserToContact[id]; ok {
+			return nil, &contactID, true
+		}
+		return nil, nil, false
+	}
+
+	plan := &SettlementPlan{
+		Month:  month,
+		Drafts: make([]SettlementDraft, 0),
+	}
+
+	var open []DebtBalance
+	for _, b := range consolidation.Balances {
+		if b.Amount <= settledThreshold {
+			continue
+		}
+		plan.CurrentTransfers++
+		_, _, debtorOK := resolve(b.DebtorID)
+		_, _, creditorOK := resolve(b.CreditorID)
+		if !debtorOK || !creditorOK {
+			plan.Excluded = append(plan.Excluded, b)
+			continue
+		}
+		open = append(open, b)
+	}
+
+	for _, t := range SimplifyDebts(open) {
+		payerUserID, payerContactID, _ := resolve(t.FromID)
+		counterpartyUserID, counterpartyContactID, _ := resolve(t.ToID)
+		plan.Drafts = append(plan.Drafts, SettlementDraft{
+			Type:                    TypeDebtPayment,
+			Description:             "Saldar deuda con " + t.ToName,
+			Amount:                  t.Amount,
+			Currency:                currency,
+			PayerUserID:             payerUserID,
+			PayerContactID:          payerContactID,
+			PayerName:               t.FromName,
+			CounterpartyUserID:      counterpartyUserID,
+			CounterpartyContactID:   counterpartyContactID,
+			CounterpartyName:        t.ToName,
+			RequiresReceiverAccount: counterpartyUserID != nil,
+		})
+	}
+
+	return plan, nil
+}
+
+// SettleDebts creates the chosen settlement DEBT_PAYMENT movements atomically:
+// every movement goes through Create (same validation and auditing), and if any
+// of them fails none is persisted. Audit entries are logged after the commit.
+func (s *service) SettleDebts(ctx context.Context, userID string, inputs []*CreateMovementInput) ([]*Movement, error) {
+	if len(inputs) == 0 {
+		return nil, ErrNoSettlements
+	}
+	for _, input := range inputs {
+		if input.Type != TypeDebtPayment {
+			return nil, ErrSettlementNotDebtPayment
+		}
+	}
+
+	var created []*Movement
+	err := s.withTx(ctx, func(txCtx context.Context) error {
+		created = make([]*Movement, 0, len(inputs))
+		for _, input := range inputs {
+			movement, err := s.Create(txCtx, userID, input)
+			if err != nil {
+				return err
+			}
+			created = append(created, movement)
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.Warn("settlement rolled back", "user_id", userID, "count", len(inputs), "error", err)
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// ListPendingConfirmations lists debt payments recorded by other households to
+// one of this household's members that are waiting for a response
+// (pending or disputed)
+func (s *service) ListPendingConfirmations(ctx context.Context, userID string) ([]*Movement, error) {
+	householdID, err := s.householdsRepo.GetUserHouseholdID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Contacts in other households that represent members of this household
+	linkedContacts, err := s.householdsRepo.FindLinkedContactsByHousehold(ctx, householdID)
+	if err != nil {
+		return nil, err
+	}
+
+	contactIDs := make([]string, len(linkedContacts))
+	isOurContact := make(map[string]bool)
+	for i, lc := range linkedContacts {
+		contactIDs[i] = lc.ContactID
+		isOurContact[lc.ContactID] = true
+	}
+
+	candidates, err := s.repo.ListMovementsByContactIDs(ctx, contactIDs, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	pending := make([]*Movement, 0)
+	for _, m := range candidates {
+		if m.Type != TypeDebtPayment || !m.AwaitingConfirmation() {
+			continue
+		}
+		// Only payments made TO us; payments we made are confirmed by the other side
+		if m.CounterpartyContactID == nil || !isOurContact[*m.CounterpartyContactID] {
+			continue
+		}
+		pending = append(pending, m)
+	}
+
+	return pending, nil
+}
+
+// RespondToConfirmation confirms or disputes a debt payment that another
+// household recorded as paid to one of this household's members. Only the
+// receiving household can respond. A disputed payment can still be confirmed
+// later (e.g. the transfer showed up), but a confirmed one is final.
+func (s *service) RespondToConfirmation(ctx context.Context, userID, id string, status ConfirmationStatus, disputeReason *string) (*Movement, error) {
+	if status != ConfirmationConfirmed && status != ConfirmationDisputed {
+		return nil, ErrInvalidConfirmationStatus
+	}
+
+	householdID, err := s.householdsRepo.GetUserHouseholdID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// The receiver must be a contact (in the payer's household) linked to one of our members
+	if existing.CounterpartyContactID == nil {
+		return nil, ErrNotAuthorized
+	}
+	linkedContacts, err := s.householdsRepo.FindLinkedContactsByHousehold(ctx, householdID)
+	if err != nil {
+		return nil, err
+	}
+	isReceiver := false
+	for _, lc := range linkedContacts {
+		if lc.ContactID == *existing.CounterpartyContactID {
+			isReceiver = true
+			break
+		}
+	}
+	if !isReceiver {
+		return nil, ErrNotAuthorized
+	}
+
+	if !existing.AwaitingConfirmation() {
+		return nil, ErrNotAwaitingConfirmation
+	}
+	if status == ConfirmationDisputed && *existing.ConfirmationStatus == ConfirmationDisputed {
+		return nil, ErrNotAwaitingConfirmation
+	}
+
+	action := audit.ActionMovementConfirmed
+	if status == ConfirmationDisputed {
+		action = audit.ActionMovementDisputed
+	} else {
+		disputeReason = nil
+	}
+
+	if err := s.repo.SetConfirmationStatus(ctx, id, &status, &userID, disputeReason); err != nil {
+		s.auditService.LogAsync(ctx, &audit.LogInput{
+			UserID:       audit.StringPtr(userID),
+			Action:       action,
+			ResourceType: "movement",
+			ResourceID:   audit.StringPtr(id),
+			HouseholdID:  audit.StringPtr(householdID),
+			Success:      false,
+			ErrorMessage: audit.StringPtr(err.Error()),
+		})
+		return nil, err
+	}
+
+	updated, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.auditService.LogAsync(ctx, &audit.LogInput{
+		UserID:       audit.StringPtr(userID),
+		Action:       action,
+		ResourceType: "movement",
+		ResourceID:   audit.StringPtr(id),
+		HouseholdID:  audit.StringPtr(householdID),
+		OldValues:    audit.StructToMap(existing),
+		NewValues:    audit.StructToMap(updated),
+		Metadata: map[string]interface{}{
+			"payer_household_id": existing.HouseholdID,
+		},
+		Success: true,
+	})
+
+	return updated, nil
+}
+
 // Update updates a movement
 func (s *service) Update(ctx context.Context, userID, id string, input *UpdateMovementInput) (*Movement, error) {
 	// Validate input
@@ -875,6 +1203,13 @@ func (s *service) Update(ctx context.Context, userID, id string, input *UpdateMo
 		}
 	}
 
+	// Validate event if being updated (empty string detaches the movement)
+	if input.EventID != nil && *input.EventID != "" {
+		if err := s.validateEvent(ctx, householdID, *input.EventID); err != nil {
+			return nil, err
+		}
+	}
+
 	// Note: CategoryID is now expected to be a UUID, not a category name
 	// The legacy category name resolution has been removed
 
@@ -932,6 +1267,106 @@ func (s *service) Update(ctx context.Context, userID, id string, input *UpdateMo
 		if input.Participants != nil && len(*input.Participants) == 0 {
 			return nil, errors.New("participants are required for split movements")
 		}
+		
+	case TypeTransfer:
+		// TRANSFER stays between two different household accounts, paid by a member
+		if input.PaymentMethodID != nil {
+			return nil, ErrPaymentMethodNotAllowed
+		}
+		if input.PayerContactID != nil {
+			return nil, ErrTransferPayerNotMember
+		}
+		sourceAccountID := existing.SourceAccountID
+		if input.SourceAccountID != nil {
+			sourceAccountID = input.SourceAccountID
+		}
+		receiverAccountID := existing.ReceiverAccountID
+		if input.ReceiverAccountID != nil {
+			receiverAccountID = input.ReceiverAccountID
+		}
+		if sourceAccountID == nil || receiverAccountID == nil {
+			return nil, ErrTransferAccountsRequired
+		}
+		if *sourceAccountID == *receiverAccountID {
+			return nil, ErrSameTransferAccount
+		}
+		for _, accountID := range []*string{input.SourceAccountID, input.ReceiverAccountID} {
+			if accountID == nil {
+				continue
+			}
+			if err := s.validateTransferAccount(ctx, householdID, *accountID); err != nil {
+				return nil, err
+			}
+		}
+	}
+	
+	// Only transfers leave from an account directly
+	if existing.Type != TypeTransfer && input.SourceAccountID != nil {
+		return nil, ErrSourceAccountNotAllowed
+	}
+	
+	// Refunds can't add up to more than what they refund
+	if err := s.checkRefundUpdate(ctx, existing, input); err != nil {
+		return nil, err
+	}
+
+	// Installments stay on credit card purchases
+	if err := s.checkInstallmentsUpdate(ctx, existing, input); err != nil {
+		return nil, err
+	}
+
+	// Recompute the amount in the household currency when any of its inputs change
+	if input.Amount != nil || input.Currency != nil || input.ExchangeRate != nil || input.MovementDate != nil {
+		amount := existing.Amount
+		if input.Amount != nil {
+			amount = *input.Amount
+		}
+		currency := existing.Currency
+		if input.Currency != nil {
+			currency = *input.Currency
+		}
+		date := existing.MovementDate
+		if input.MovementDate != nil {
+			date = *input.MovementDate
+		}
+		rate := input.ExchangeRate
+		if rate == nil && currency == existing.Currency && date.Equal(existing.MovementDate) {
+			// Same currency and day: keep the rate the movement was recorded with
+			rate = existing.ExchangeRate
+		}
+
+		currency, baseAmount, rate, err := s.convert(ctx, householdID, amount, currency, rate, date)
+		if err != nil {
+			return nil, err
+		}
+		input.Currency = &currency
+		input.BaseAmount = &baseAmount
+		input.ExchangeRate = rate
+	}
+
+	// Keep participant amounts exact when the amount or the participants change
+	if existing.Type == TypeSplit && (input.Amount != nil || input.Participants != nil) {
+		total := existing.Amount
+		if input.Amount != nil {
+			total = *input.Amount
+		}
+		participants := input.Participants
+		if participants == nil {
+			// Re-split the new amount with the current percentages
+			current := make([]ParticipantInput, len(existing.Participants))
+			for i, p := range existing.Participants {
+				current[i] = ParticipantInput{
+					ParticipantUserID:    p.ParticipantUserID,
+					ParticipantContactID: p.ParticipantContactID,
+					Percentage:           p.Percentage,
+				}
+			}
+			participants = &current
+		}
+		if err := AllocateParticipants(total, *participants); err != nil {
+			return nil, err
+		}
+		input.Participants = participants
 	}
 
 	// Update movement
@@ -951,6 +1386,32 @@ func (s *service) Update(ctx context.Context, userID, id string, input *UpdateMo
 		return nil, err
 	}
 
+	// A debt payment that changed amount, date or receiver must be confirmed again
+	paymentChanged := input.Amount != nil || input.MovementDate != nil || input.Currency != nil ||
+		input.CounterpartyUserID != nil || input.CounterpartyContactID != nil
+	if updated.Type == TypeDebtPayment && paymentChanged {
+		var status *ConfirmationStatus
+		if updated.CounterpartyContactID != nil {
+			needsConfirmation, err := s.requiresConfirmation(ctx, *updated.CounterpartyContactID)
+			if err != nil {
+				return nil, err
+			}
+			if needsConfirmation {
+				pending := ConfirmationPending
+				status = &pending
+			}
+		}
+		if status != nil || updated.ConfirmationStatus != nil {
+			if err := s.repo.SetConfirmationStatus(ctx, id, status, nil, nil); err != nil {
+				return nil, err
+			}
+			updated.ConfirmationStatus = status
+			updated.ConfirmationRespondedBy = nil
+			updated.ConfirmationRespondedAt = nil
+			updated.DisputeReason = nil
+		}
+	}
+
 	// Log successful update
 	s.auditService.LogAsync(ctx, &audit.LogInput{
 		UserID:       audit.StringPtr(userID),
@@ -984,8 +1445,13 @@ func (s *service) Delete(ctx context.Context, userID, id string) error {
 		return ErrNotAuthorized
 	}
 
-	// Delete movement
-	if err := s.repo.Delete(ctx, id); err != nil {
+	// Refunds point at the movement they refund, so they go first
+	if existing.RefundedAmount > 0 {
+		return ErrHasRefunds
+	}
+
+	// Move to the trash
+	if err := s.repo.Delete(ctx, id, userID); err != nil {
 		// Log failed deletion attempt
 		s.auditService.LogAsync(ctx, &audit.LogInput{
 			UserID:       audit.StringPtr(userID),
@@ -1013,3 +1479,113 @@ func (s *service) Delete(ctx context.Context, userID, id string) error {
 
 	return nil
 }
+
+// ListTrash lists the movements in the household trash, most recently deleted first
+func (s *service) ListTrash(ctx context.Context, userID string) ([]*Movement, error) {
+	householdID, err := s.householdsRepo.GetUserHouseholdID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.ListByHousehold(ctx, householdID, &ListMovementsFilters{Deleted: true})
+}
+
+// Restore takes a movement out of the trash with its participants, tags,
+// attachments and template link
+func (s *service) Restore(ctx context.Context, userID, id string) (*Movement, error) {
+	householdID, err := s.householdsRepo.GetUserHouseholdID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Restore(ctx, householdID, id); err != nil {
+		s.auditService.LogAsync(ctx, &audit.LogInput{
+			UserID:       audit.StringPtr(userID),
+			Action:       audit.ActionMovementRestored,
+			ResourceType: "movement",
+			ResourceID:   audit.StringPtr(id),
+			HouseholdID:  audit.StringPtr(householdID),
+			Success:      false,
+			ErrorMessage: audit.StringPtr(err.Error()),
+		})
+		return nil, err
+	}
+
+	movement, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.auditService.LogAsync(ctx, &audit.LogInput{
+		UserID:       audit.StringPtr(userID),
+		Action:       audit.ActionMovementRestored,
+		ResourceType: "movement",
+		ResourceID:   audit.StringPtr(id),
+		HouseholdID:  audit.StringPtr(householdID),
+		NewValues:    audit.StructToMap(movement),
+		Success:      true,
+	})
+
+	return movement, nil
+}
+
+// PurgeTrash permanently deletes the movements moved to the trash before a
+// date, in every household, and removes their attachment files
+func (s *service) PurgeTrash(ctx context.Context, before time.Time) (int, error) {
+	purged, err := s.repo.PurgeDeleted(ctx, before)
+	if err != nil {
+		return 0, err
+	}
+
+	// The rows are gone either way; leftover files are only logged
+	if s.attachments != nil {
+		for _, p := range purged {
+			if err := s.attachments.DeleteMovementFiles(ctx, p.HouseholdID, p.ID); err != nil {
+				s.logger.Error("failed to delete movement attachments", "movement_id", p.ID, "error", err)
+			}
+		}
+	}
+
+	return len(purged), nil
+}
+
+// Discard permanently removes a movement that was just created, so a failed
+// operation doesn't leave it behind in the trash
+func (s *service) Discard(ctx context.Context, userID, id string) error {
+	existing, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	householdID, err := s.householdsRepo.GetUserHouseholdID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if existing.HouseholdID != householdID {
+		return ErrNotAuthorized
+	}
+
+	if err := s.repo.Discard(ctx, id); err != nil {
+		return err
+	}
+
+	s.auditService.LogAsync(ctx, &audit.LogInput{
+		UserID:       audit.StringPtr(userID),
+		Action:       audit.ActionMovementDeleted,
+		ResourceType: "movement",
+		ResourceID:   audit.StringPtr(id),
+		HouseholdID:  audit.StringPtr(householdID),
+		OldValues:    audit.StructToMap(existing),
+		Success:      true,
+	})
+
+	return nil
+}
+
+// ListTags lists the tags of the user's household
+func (s *service) ListTags(ctx context.Context, userID string) ([]*Tag, error) {
+	householdID, err := s.householdsRepo.GetUserHouseholdID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.ListTags(ctx, householdID)
+}
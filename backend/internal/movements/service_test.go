@@ -0,0 +1,106 @@
+package movements
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/blanquicet/conti/backend/internal/audit"
+)
+
+// txRepository runs WithTx without a database; commitErr fails the commit
+type txRepository struct {
+	Repository
+	commitErr error
+}
+
+func (r *txRepository) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if err := fn(ctx); err != nil {
+		return err
+	}
+	return r.commitErr
+}
+
+// recordingAudit keeps the entries logged through LogAsync
+type recordingAudit struct {
+	audit.Service
+	entries []*audit.LogInput
+}
+
+func (a *recordingAudit) LogAsync(ctx context.Context, input *audit.LogInput) {
+	a.entries = append(a.entries, input)
+}
+
+func TestWithTxLogsAuditAfterCommit(t *testing.T) {
+	changed := &audit.LogInput{Action: audit.ActionMovementCreated, Success: true}
+
+	tests := []struct {
+		name      string
+		fnErr     error
+		commitErr error
+		wantErr   bool
+		wantLogs  int
+	}{
+		{"committed", nil, nil, false, 2},
+		{"rolled back", errors.New("row failed"), nil, true, 0},
+		{"commit failed", nil, errors.New("connection lost"), true, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auditService := &recordingAudit{}
+			s := &service{repo: &txRepository{commitErr: tt.commitErr}, auditService: auditService}
+
+			err := s.withTx(context.Background(), func(ctx context.Context) error {
+				s.logAudit(ctx, changed)
+				s.logAudit(ctx, changed)
+				if len(auditService.entries) != 0 {
+					t.Errorf("logged %d entries before commit", len(auditService.entries))
+				}
+				return tt.fnErr
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("withTx() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(auditService.entries) != tt.wantLogs {
+				t.Errorf("logged %d entries, want %d", len(auditService.entries), tt.wantLogs)
+			}
+		})
+	}
+}
+
+func TestLogAuditOutsideTx(t *testing.T) {
+	auditService := &recordingAudit{}
+	s := &service{auditService: auditService}
+
+	s.logAudit(context.Background(), &audit.LogInput{Action: audit.ActionMovementCreated})
+	if len(auditService.entries) != 1 {
+		t.Errorf("logged %d entries, want 1", len(auditService.entries))
+	}
+}
+
+// countingRules counts how many times the rules ran
+type countingRules struct {
+	calls int
+}
+
+func (r *countingRules) Apply(ctx context.Context, householdID string, input *CreateMovementInput) error {
+	r.calls++
+	return nil
+}
+
+func TestApplyRulesOnce(t *testing.T) {
+	rules := &countingRules{}
+	s := &service{ruleApplier: rules}
+	input := &CreateMovementInput{Type: TypeHousehold}
+
+	// The import preview and Create both apply rules to the same input
+	for range 2 {
+		if err := s.applyRules(context.Background(), "h1", input); err != nil {
+			t.Fatalf("applyRules() error = %v", err)
+		}
+	}
+	if rules.calls != 1 {
+		t.Errorf("rules applied %d times, want 1", rules.calls)
+	}
+}
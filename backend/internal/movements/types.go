@@ -3,7 +3,14 @@ package movements
 import (
 	"context"
 	"errors"
+	"io"
+	"strings"
 	"time"
+	"unicode/utf8"
+
+	"github.com/blanquicet/conti/backend/internal/audit"
+	"github.com/blanquicet/conti/backend/internal/fx"
+	"github.com/blanquicet/conti/backend/internal/money"
 )
 
 // Errors for movement operations
@@ -18,8 +25,41 @@ var (
 	ErrParticipantsRequired   = errors.New("participants are required for SPLIT movements")
 	ErrParticipantsNotAllowed = errors.New("participants not allowed for this movement type")
 	ErrInvalidPercentageSum   = errors.New("participant percentages must sum to 100%")
+	ErrInvalidParticipantAmounts = errors.New("participant amounts must add up to the movement amount")
+	ErrInvalidExchangeRate    = errors.New("exchange_rate must be greater than zero")
+	ErrExchangeRateNotFound   = errors.New("no exchange rate to the household currency on or before the movement date")
 	ErrCategoryRequired       = errors.New("category is required for this movement type")
 	ErrPaymentMethodRequired  = errors.New("payment method is required")
+	ErrEventNotFound          = errors.New("event not found")
+	ErrEventClosed            = errors.New("event is closed")
+	ErrNoSettlements          = errors.New("at least one settlement movement is required")
+	ErrSettlementNotDebtPayment = errors.New("settlement movements must be of type DEBT_PAYMENT")
+	ErrNotAwaitingConfirmation  = errors.New("movement is not awaiting confirmation")
+	ErrInvalidConfirmationStatus = errors.New("invalid confirmation status")
+	ErrInvalidTag             = errors.New("tags must be 1 to 50 characters")
+	ErrTooManyTags            = errors.New("a movement can have at most 20 tags")
+	ErrTransferAccountsRequired = errors.New("source and destination accounts are required for TRANSFER")
+	ErrSameTransferAccount      = errors.New("source and destination accounts must be different")
+	ErrSourceAccountNotAllowed  = errors.New("source account is only allowed for TRANSFER")
+	ErrPaymentMethodNotAllowed  = errors.New("payment method not allowed for TRANSFER")
+	ErrTransferPayerNotMember   = errors.New("the payer of a TRANSFER must be a household member")
+	ErrInvalidTransferAccount   = errors.New("transfer accounts must be of type savings or cash")
+	ErrNotRefundable            = errors.New("only HOUSEHOLD and SPLIT movements can be refunded")
+	ErrRefundExceedsOriginal    = errors.New("refunds cannot add up to more than the original movement")
+	ErrRefundCurrency           = errors.New("refunds keep the currency of the original movement")
+	ErrHasRefunds               = errors.New("movement has refunds, delete them first")
+	ErrRefundOriginalDeleted    = errors.New("the refunded movement is in the trash, restore it first")
+	ErrInvalidInstallments      = errors.New("installments must be between 1 and 48")
+	ErrInvalidInstallmentRate   = errors.New("installment_rate must be between 0 and 1")
+	ErrInstallmentsRequired     = errors.New("installment_rate and installments_first_cycle require installments")
+	ErrInstallmentsNotAllowed   = errors.New("installments are only allowed for HOUSEHOLD and SPLIT purchases")
+	ErrInstallmentsNotCreditCard = errors.New("installments are only allowed on credit card purchases")
+)
+
+const (
+	maxTagLength       = 50
+	maxTagsPerMovement = 20
+	maxInstallments    = 48
 )
 
 // MovementType represents the type of movement
@@ -29,28 +69,44 @@ const (
 	TypeHousehold   MovementType = "HOUSEHOLD"    // Household expense
 	TypeSplit       MovementType = "SPLIT"        // Shared/split expense with participants
 	TypeDebtPayment MovementType = "DEBT_PAYMENT" // Debt payment/settlement
+	TypeTransfer    MovementType = "TRANSFER"     // Money moved between two household accounts (not spending)
 )
 
 // Validate checks if the movement type is valid
 func (t MovementType) Validate() error {
 	switch t {
-	case TypeHousehold, TypeSplit, TypeDebtPayment:
+	case TypeHousehold, TypeSplit, TypeDebtPayment, TypeTransfer:
 		return nil
 	default:
 		return ErrInvalidMovementType
 	}
 }
 
+// ConfirmationStatus represents the receiver's acknowledgement of a DEBT_PAYMENT
+// made to a linked contact (a contact with their own account in another household)
+type ConfirmationStatus string
+
+const (
+	ConfirmationPending   ConfirmationStatus = "PENDING"   // Waiting for the receiver
+	ConfirmationConfirmed ConfirmationStatus = "CONFIRMED" // Receiver confirmed, debt is cleared
+	ConfirmationDisputed  ConfirmationStatus = "DISPUTED"  // Receiver says it wasn't received
+)
+
 // Movement represents a financial movement/expense
 type Movement struct {
 	ID            string       `json:"id"`
 	HouseholdID   string       `json:"household_id"`
 	Type          MovementType `json:"type"`
 	Description   string       `json:"description"`
-	Amount        float64      `json:"amount"`
+	Amount        money.Amount `json:"amount"`
 	MovementDate  time.Time    `json:"movement_date"`
 	Currency      string       `json:"currency"`
 	
+	// Conversion to the household currency (ExchangeRate is nil when Currency
+	// already is the household currency, and then BaseAmount equals Amount)
+	ExchangeRate *float64     `json:"exchange_rate,omitempty"`
+	BaseAmount   money.Amount `json:"base_amount"`
+	
 	// Category info (from JOIN with categories and category_groups)
 	CategoryID        *string `json:"category_id,omitempty"`
 	CategoryName      *string `json:"category_name,omitempty"`
@@ -73,20 +129,135 @@ type Movement struct {
 	PaymentMethodName *string `json:"payment_method_name,omitempty"` // Populated from join
 	
 	// Receiver account (only for DEBT_PAYMENT when counterparty is a household member)
-	// This represents where the income is received
+	// This represents where the income is received. It is also the destination of a TRANSFER.
 	ReceiverAccountID   *string `json:"receiver_account_id,omitempty"`
 	ReceiverAccountName *string `json:"receiver_account_name,omitempty"` // Populated from join
 	
+	// Source account (only for TRANSFER, where the money leaves from)
+	SourceAccountID   *string `json:"source_account_id,omitempty"`
+	SourceAccountName *string `json:"source_account_name,omitempty"` // Populated from join
+	
 	// Participants (only for SPLIT)
 	Participants []Participant `json:"participants,omitempty"`
 	
 	// Recurring template reference (if auto-generated)
 	GeneratedFromTemplateID *string `json:"generated_from_template_id,omitempty"`
 	
+	// Event this movement belongs to (trip, dinner, etc.)
+	EventID   *string `json:"event_id,omitempty"`
+	EventName *string `json:"event_name,omitempty"` // Populated from join
+	
+	// Household tags ("reembolsable", "Navidad 2026"), sorted by name
+	Tags []string `json:"tags,omitempty"`
+	
+	// Receiver confirmation (only for DEBT_PAYMENT to a linked contact, nil otherwise)
+	ConfirmationStatus      *ConfirmationStatus `json:"confirmation_status,omitempty"`
+	ConfirmationRespondedBy *string             `json:"confirmation_responded_by,omitempty"`
+	ConfirmationRespondedAt *time.Time          `json:"confirmation_responded_at,omitempty"`
+	DisputeReason           *string             `json:"dispute_reason,omitempty"`
+	
+	// Trash (only set on movements listed from the trash)
+	DeletedAt     *time.Time `json:"deleted_at,omitempty"`
+	DeletedByName *string    `json:"deleted_by_name,omitempty"`
+	
+	// Refunds: RefundOfID is set on a refund, RefundedAmount on the original
+	// (what its refunds add up to, in its own currency)
+	RefundOfID     *string      `json:"refund_of_id,omitempty"`
+	RefundedAmount money.Amount `json:"refunded_amount,omitempty"`
+	
+	// Installments (compras a cuotas): a credit card purchase charged over
+	// Installments billing cycles, starting in the cycle of
+	// InstallmentsFirstCycle, with InstallmentRate monthly interest
+	Installments           *int       `json:"installments,omitempty"`
+	InstallmentRate        *float64   `json:"installment_rate,omitempty"`
+	InstallmentsFirstCycle *time.Time `json:"installments_first_cycle,omitempty"`
+	
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// HasInstallments reports whether the purchase is spread over several card cycles
+func (m *Movement) HasInstallments() bool {
+	return m.Installments != nil && *m.Installments > 1
+}
+
+// IsRefund reports whether the movement gives back part of another one
+func (m *Movement) IsRefund() bool {
+	return m.RefundOfID != nil
+}
+
+// NetHouseholdAmount returns HouseholdAmount with the sign it has in
+// spending totals: refunds give money back, so they are negative
+func (m *Movement) NetHouseholdAmount() money.Amount {
+	if m.IsRefund() {
+		return -m.HouseholdAmount()
+	}
+	return m.HouseholdAmount()
+}
+
+// AwaitingConfirmation reports whether the movement is a debt payment that the
+// receiver hasn't confirmed yet (pending or disputed). Such payments don't
+// reduce debts until they are confirmed.
+func (m *Movement) AwaitingConfirmation() bool {
+	return m.ConfirmationStatus != nil && *m.ConfirmationStatus != ConfirmationConfirmed
+}
+
+// HouseholdAmount returns the amount in the household currency
+func (m *Movement) HouseholdAmount() money.Amount {
+	if m.ExchangeRate == nil {
+		return m.Amount
+	}
+	return m.BaseAmount
+}
+
+// HouseholdShares returns ParticipantShares converted to the household
+// currency. The converted amount is allocated with the same proportions, so
+// the shares still add up to exactly HouseholdAmount (negated for refunds).
+func (m *Movement) HouseholdShares() []money.Amount {
+	shares := m.participantShares()
+	if m.ExchangeRate != nil {
+		weights := make([]float64, len(shares))
+		for i, share := range shares {
+			weights[i] = float64(share)
+		}
+		shares = m.BaseAmount.Allocate(weights)
+	}
+	return m.signed(shares)
+}
+
+// ParticipantShares returns what each participant owes of the movement, in the
+// same order as Participants. Stored amounts are used when every participant
+// has one; legacy rows without amounts are allocated from their percentages.
+// A refund gives back part of what was owed, so its shares are negative.
+func (m *Movement) ParticipantShares() []money.Amount {
+	return m.signed(m.participantShares())
+}
+
+// signed negates shares for refunds
+func (m *Movement) signed(shares []money.Amount) []money.Amount {
+	if m.IsRefund() {
+		for i := range shares {
+			shares[i] = -shares[i]
+		}
+	}
+	return shares
+}
+
+func (m *Movement) participantShares() []money.Amount {
+	shares := make([]money.Amount, len(m.Participants))
+	weights := make([]float64, len(m.Participants))
+	for i, p := range m.Participants {
+		if p.Amount == nil {
+			for j, p := range m.Participants {
+				weights[j] = p.Percentage
+			}
+			return m.Amount.Allocate(weights)
+		}
+		shares[i] = *p.Amount
+	}
+	return shares
+}
+
 // Participant represents a participant in a shared expense
 type Participant struct {
 	ID                   string    `json:"id"`
@@ -94,8 +265,8 @@ type Participant struct {
 	ParticipantUserID    *string   `json:"participant_user_id,omitempty"`
 	ParticipantContactID *string   `json:"participant_contact_id,omitempty"`
 	ParticipantName      string    `json:"participant_name"` // Populated from join
-	Percentage           float64   `json:"percentage"` // 0.0 to 1.0
-	Amount               *float64  `json:"amount,omitempty"` // Exact amount (optional, source of truth when set)
+	Percentage           float64       `json:"percentage"` // 0.0 to 1.0
+	Amount               *money.Amount `json:"amount,omitempty"` // Exact amount (source of truth when set, NULL on legacy rows)
 	CreatedAt            time.Time `json:"created_at"`
 }
 
@@ -103,11 +274,17 @@ type Participant struct {
 type CreateMovementInput struct {
 	Type         MovementType `json:"type"`
 	Description  string       `json:"description"`
-	Amount       float64      `json:"amount"`
+	Amount       money.Amount `json:"amount"`
 	Category     *string      `json:"category,omitempty"`     // Legacy: category name as string
 	CategoryID   *string      `json:"category_id,omitempty"`  // New: category ID (FK to categories table)
 	MovementDate time.Time    `json:"movement_date"`
 	
+	// Currency of Amount (defaults to the household currency). For other
+	// currencies ExchangeRate is used, or the household's FX rates when omitted.
+	Currency     string       `json:"currency,omitempty"`
+	ExchangeRate *float64     `json:"exchange_rate,omitempty"`
+	BaseAmount   money.Amount `json:"-"` // Set by the service
+	
 	// Payer (exactly one required)
 	PayerUserID    *string `json:"payer_user_id,omitempty"`
 	PayerContactID *string `json:"payer_contact_id,omitempty"`
@@ -120,22 +297,119 @@ type CreateMovementInput struct {
 	PaymentMethodID *string `json:"payment_method_id,omitempty"`
 	
 	// Receiver account (optional for DEBT_PAYMENT when counterparty is a household member)
-	// Income is received in this account
+	// Income is received in this account. Required for TRANSFER (the destination).
 	ReceiverAccountID *string `json:"receiver_account_id,omitempty"`
 	
+	// Source account (required only for TRANSFER)
+	SourceAccountID *string `json:"source_account_id,omitempty"`
+	
 	// Participants (required only for SPLIT)
 	Participants []ParticipantInput `json:"participants,omitempty"`
 	
 	// Generated from template (set when movement is created from a recurring template)
 	GeneratedFromTemplateID *string `json:"generated_from_template_id,omitempty"`
+	
+	// Event (optional, must be an open event of the household)
+	EventID *string `json:"event_id,omitempty"`
+	
+	// Tags by name; tags the household doesn't have yet are created
+	Tags []string `json:"tags,omitempty"`
+	
+	// Set by the service for DEBT_PAYMENT to a linked contact (never from client input)
+	ConfirmationStatus *ConfirmationStatus `json:"-"`
+	
+	// Installments (optional, only for credit card purchases). The first
+	// cycle defaults to the cycle of the movement date.
+	Installments           *int       `json:"installments,omitempty"`
+	InstallmentRate        *float64   `json:"installment_rate,omitempty"`
+	InstallmentsFirstCycle *time.Time `json:"installments_first_cycle,omitempty"`
+	
+	// Set by the service when creating a refund (never from client input)
+	RefundOfID *string `json:"-"`
+
+	// Set once the household's rules have filled in the movement
+	rulesApplied bool
+}
+
+// CreateRefundInput represents input for refunding a movement. The refund
+// copies everything else from the original movement.
+type CreateRefundInput struct {
+	Amount       *money.Amount `json:"amount,omitempty"` // In the original currency, defaults to what is left to refund
+	MovementDate time.Time     `json:"movement_date"`    // When the refund posts (the card cycle it falls in)
+	Description  *string       `json:"description,omitempty"`
+}
+
+// Validate validates the refund input
+func (i *CreateRefundInput) Validate() error {
+	if i.Amount != nil && *i.Amount <= 0 {
+		return ErrInvalidAmount
+	}
+	if i.MovementDate.IsZero() {
+		return errors.New("movement_date is required")
+	}
+	if i.Description != nil && strings.TrimSpace(*i.Description) == "" {
+		return errors.New("description cannot be empty")
+	}
+	return nil
 }
 
 // ParticipantInput represents input for a participant
 type ParticipantInput struct {
 	ParticipantUserID    *string  `json:"participant_user_id,omitempty"`
 	ParticipantContactID *string  `json:"participant_contact_id,omitempty"`
-	Percentage           float64  `json:"percentage"` // 0.0 to 1.0
-	Amount               *float64 `json:"amount,omitempty"` // Exact amount (optional, takes precedence over percentage)
+	Percentage           float64       `json:"percentage"` // 0.0 to 1.0
+	Amount               *money.Amount `json:"amount,omitempty"` // Exact amount (optional, takes precedence over percentage)
+}
+
+// AllocateParticipants fills in the exact amount of every participant so that
+// they add up to total. Participants that already have an amount keep it, and
+// the rest of the total is allocated among the others by percentage (largest
+// remainder, so no centavo is lost or invented). Percentages are then
+// recomputed from the amounts.
+func AllocateParticipants(total money.Amount, participants []ParticipantInput) error {
+	remaining := total
+	var weights []float64
+	var pending []int
+	for i, p := range participants {
+		if p.Amount != nil {
+			remaining -= *p.Amount
+			continue
+		}
+		weights = append(weights, p.Percentage)
+		pending = append(pending, i)
+	}
+
+	if len(pending) > 0 {
+		if remaining <= 0 {
+			return ErrInvalidParticipantAmounts
+		}
+		for k, amount := range remaining.Allocate(weights) {
+			if amount <= 0 {
+				return ErrInvalidParticipantAmounts
+			}
+			a := amount
+			participants[pending[k]].Amount = &a
+		}
+	} else if remaining != 0 {
+		return ErrInvalidParticipantAmounts
+	}
+
+	for i := range participants {
+		participants[i].Percentage = participants[i].Amount.Ratio(total)
+	}
+	return nil
+}
+
+// validateParticipantAmounts checks that exact participant amounts add up to total
+func validateParticipantAmounts(total money.Amount, participants []ParticipantInput) error {
+	sum := money.Amount(0)
+	for _, p := range participants {
+		sum += *p.Amount
+	}
+	if sum != total {
+		return ErrInvalidParticipantAmounts
+	}
+	return nil
 }
 
 // Validate validates the create movement input
@@ -155,11 +429,30 @@ func (i *CreateMovementInput) Validate() error {
 		return ErrInvalidAmount
 	}
 	
+	// Validate tags
+	tags, err := NormalizeTags(i.Tags)
+	if err != nil {
+		return err
+	}
+	i.Tags = tags
+	
 	// Validate movement date
 	if i.MovementDate.IsZero() {
 		return errors.New("movement_date is required")
 	}
 	
+	// Validate currency (empty means the household currency)
+	if i.Currency != "" {
+		currency, err := fx.NormalizeCurrency(i.Currency)
+		if err != nil {
+			return err
+		}
+		i.Currency = currency
+	}
+	if i.ExchangeRate != nil && *i.ExchangeRate <= 0 {
+		return ErrInvalidExchangeRate
+	}
+	
 	// Validate payer (exactly one)
 	hasPayerUser := i.PayerUserID != nil && *i.PayerUserID != ""
 	hasPayerContact := i.PayerContactID != nil && *i.PayerContactID != ""
@@ -170,6 +463,22 @@ func (i *CreateMovementInput) Validate() error {
 		return errors.New("cannot specify both payer_user_id and payer_contact_id")
 	}
 	
+	// Only transfers leave from an account directly
+	if i.Type != TypeTransfer && i.SourceAccountID != nil {
+		return ErrSourceAccountNotAllowed
+	}
+	
+	// Validate installments (purchases only; a refund is credited at once)
+	if i.Installments == nil && (i.InstallmentRate != nil || i.InstallmentsFirstCycle != nil) {
+		return ErrInstallmentsRequired
+	}
+	if err := validateInstallments(i.Installments, i.InstallmentRate); err != nil {
+		return err
+	}
+	if i.Installments != nil && ((i.Type != TypeHousehold && i.Type != TypeSplit) || i.RefundOfID != nil) {
+		return ErrInstallmentsNotAllowed
+	}
+	
 	// Type-specific validations
 	switch i.Type {
 	case TypeHousehold:
@@ -197,6 +506,7 @@ func (i *CreateMovementInput) Validate() error {
 		}
 		// Validate participants
 		totalPercentage := 0.0
+		allAmounts := true
 		for _, p := range i.Participants {
 			// Exactly one participant identifier
 			hasUser := p.ParticipantUserID != nil && *p.ParticipantUserID != ""
@@ -212,9 +522,20 @@ func (i *CreateMovementInput) Validate() error {
 				return errors.New("participant percentage must be between 0 and 1")
 			}
 			totalPercentage += p.Percentage
+			if p.Amount == nil {
+				allAmounts = false
+			} else if *p.Amount <= 0 {
+				return ErrInvalidAmount
+			}
 		}
-		// Check percentage sum (allow small floating point error)
-		if totalPercentage < 0.9999 || totalPercentage > 1.0001 {
+		if allAmounts {
+			// Exact amounts are the source of truth: they must add up to the cent
+			if err := validateParticipantAmounts(i.Amount, i.Participants); err != nil {
+				return err
+			}
+		} else if totalPercentage < 0.9999 || totalPercentage > 1.0001 {
+			// Percentages like 1/3 can't be represented exactly; the service
+			// turns them into exact amounts with AllocateParticipants
 			return ErrInvalidPercentageSum
 		}
 		// No counterparty allowed
@@ -247,21 +568,63 @@ func (i *CreateMovementInput) Validate() error {
 		if len(i.Participants) > 0 {
 			return ErrParticipantsNotAllowed
 		}
+		
+	case TypeTransfer:
+		// Moved by a household member between two of the household's accounts
+		if !hasPayerUser {
+			return ErrTransferPayerNotMember
+		}
+		hasSource := i.SourceAccountID != nil && *i.SourceAccountID != ""
+		hasReceiver := i.ReceiverAccountID != nil && *i.ReceiverAccountID != ""
+		if !hasSource || !hasReceiver {
+			return ErrTransferAccountsRequired
+		}
+		if *i.SourceAccountID == *i.ReceiverAccountID {
+			return ErrSameTransferAccount
+		}
+		// The accounts are the payment: no payment method, counterparty or participants
+		if i.PaymentMethodID != nil {
+			return ErrPaymentMethodNotAllowed
+		}
+		if i.CounterpartyUserID != nil || i.CounterpartyContactID != nil {
+			return ErrCounterpartyNotAllowed
+		}
+		if len(i.Participants) > 0 {
+			return ErrParticipantsNotAllowed
+		}
+		// Category is optional (it is never counted as spending)
 	}
 	
 	return nil
 }
 
+// validateInstallments checks the installment count and monthly rate
+func validateInstallments(installments *int, rate *float64) error {
+	if installments != nil && (*installments < 1 || *installments > maxInstallments) {
+		return ErrInvalidInstallments
+	}
+	if rate != nil && (*rate < 0 || *rate >= 1) {
+		return ErrInvalidInstallmentRate
+	}
+	return nil
+}
+
 // UpdateMovementInput represents input for updating a movement
 type UpdateMovementInput struct {
 	Description     *string             `json:"description,omitempty"`
-	Amount          *float64            `json:"amount,omitempty"`
+	Amount          *money.Amount       `json:"amount,omitempty"`
 	CategoryID      *string             `json:"category_id,omitempty"`
 	MovementDate    *time.Time          `json:"movement_date,omitempty"`
 	PaymentMethodID *string             `json:"payment_method_id,omitempty"`
 	ReceiverAccountID *string           `json:"receiver_account_id,omitempty"`
+	SourceAccountID   *string           `json:"source_account_id,omitempty"` // Only for TRANSFER
 	Participants    *[]ParticipantInput `json:"participants,omitempty"`
 	
+	// Currency and conversion (the base amount is recomputed by the service)
+	Currency     *string       `json:"currency,omitempty"`
+	ExchangeRate *float64      `json:"exchange_rate,omitempty"`
+	BaseAmount   *money.Amount `json:"-"`
+	
 	// Payer (can be updated)
 	PayerUserID    *string `json:"payer_user_id,omitempty"`
 	PayerContactID *string `json:"payer_contact_id,omitempty"`
@@ -273,6 +636,17 @@ type UpdateMovementInput struct {
 	// Generated from template (can be updated when linking movement to a template)
 	GeneratedFromTemplateID *string `json:"generated_from_template_id,omitempty"`
 	
+	// Event (can be updated to move a movement into an event)
+	EventID *string `json:"event_id,omitempty"`
+	
+	// Tags replace the movement's tags (an empty list removes them all)
+	Tags *[]string `json:"tags,omitempty"`
+	
+	// Installments (1 turns the purchase back into a single payment)
+	Installments           *int       `json:"installments,omitempty"`
+	InstallmentRate        *float64   `json:"installment_rate,omitempty"`
+	InstallmentsFirstCycle *time.Time `json:"installments_first_cycle,omitempty"`
+	
 	// Note: Cannot update type after creation
 }
 
@@ -284,6 +658,29 @@ func (i *UpdateMovementInput) Validate() error {
 	if i.Description != nil && *i.Description == "" {
 		return errors.New("description cannot be empty")
 	}
+	if i.Currency != nil {
+		currency, err := fx.NormalizeCurrency(*i.Currency)
+		if err != nil {
+			return err
+		}
+		i.Currency = &currency
+	}
+	if i.ExchangeRate != nil && *i.ExchangeRate <= 0 {
+		return ErrInvalidExchangeRate
+	}
+	if i.Tags != nil {
+		tags, err := NormalizeTags(*i.Tags)
+		if err != nil {
+			return err
+		}
+		i.Tags = &tags
+	}
+	if i.SourceAccountID != nil && i.ReceiverAccountID != nil && *i.SourceAccountID == *i.ReceiverAccountID {
+		return ErrSameTransferAccount
+	}
+	if err := validateInstallments(i.Installments, i.InstallmentRate); err != nil {
+		return err
+	}
 	
 	// Validate payer != counterparty if both are being updated
 	// Check user IDs
@@ -309,27 +706,84 @@ type ListMovementsFilters struct {
 	StartDate *time.Time
 	EndDate   *time.Time
 	MemberID  *string // Filter by payer (user only)
+	EventID   *string // Filter by event
+	Tag       *string // Filter by tag name (case-insensitive)
+	
+	// Search matches descriptions containing every word (case-insensitive)
+	Search          *string
+	AmountMin       *money.Amount // In the household currency, inclusive
+	AmountMax       *money.Amount // In the household currency, inclusive
+	CategoryID      *string
+	CategoryGroupID *string
+	PaymentMethodID *string
+	ContactID       *string // Contact as payer, counterparty or participant
+	TemplateID      *string // Generated from this recurring template
+	FromTemplate    *bool   // Generated from any template (true) or entered by hand (false)
+	AccountID       *string // Paid from the account (through a payment method) or received in it
+	
+	// Order and pages (see search.go). Without a limit every movement is returned.
+	Sort      SortField // Defaults to date
+	Ascending bool      // Oldest or smallest first (newest or largest by default)
+	Limit     int
+	Cursor    *Cursor // Position after the previous page
+	
+	// Deleted lists the household trash instead of the live movements
+	Deleted bool
 }
 
 // MovementTotals represents totals for movements
 type MovementTotals struct {
-	TotalAmount        float64                   `json:"total_amount"`
-	ByType             map[MovementType]float64  `json:"by_type"`
-	ByCategory         map[string]float64        `json:"by_category"`
-	ByPaymentMethod    map[string]float64        `json:"by_payment_method"`
+	TotalAmount        money.Amount                  `json:"total_amount"`
+	ByType             map[MovementType]money.Amount `json:"by_type"`
+	ByCategory         map[string]money.Amount       `json:"by_category"`
+	ByPaymentMethod    map[string]money.Amount       `json:"by_payment_method"`
+	ByTag              map[string]money.Amount       `json:"by_tag"` // A movement counts once for each of its tags
+}
+
+// Tag is a household tag with the number of movements that use it
+type Tag struct {
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	MovementCount int    `json:"movement_count"`
+}
+
+// NormalizeTags trims tags, collapses inner spaces and drops duplicates that
+// differ only in case (the first spelling wins)
+func NormalizeTags(tags []string) ([]string, error) {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.Join(strings.Fields(tag), " ")
+		if tag == "" || utf8.RuneCountInString(tag) > maxTagLength {
+			return nil, ErrInvalidTag
+		}
+		key := strings.ToLower(tag)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		normalized = append(normalized, tag)
+	}
+	if len(normalized) > maxTagsPerMovement {
+		return nil, ErrTooManyTags
+	}
+	return normalized, nil
 }
 
 // DebtMovementDetail represents a single movement contributing to a debt
 type DebtMovementDetail struct {
 	MovementID          string  `json:"movement_id"`
 	Description         string  `json:"description"`
-	Amount              float64 `json:"amount"`      // Amount contributed to this debt (positive) or payment (negative)
+	Amount              money.Amount `json:"amount"` // Amount contributed to this debt (positive) or payment (negative)
 	MovementDate        string  `json:"movement_date"`
 	Type                string  `json:"type"` // "SPLIT" or "DEBT_PAYMENT"
+	OriginalAmount      *money.Amount `json:"original_amount,omitempty"`   // Share in the movement's own currency, when it was converted
+	OriginalCurrency    string        `json:"original_currency,omitempty"` // Currency of OriginalAmount
 	PayerID             string  `json:"payer_id,omitempty"` // ID of who paid (for SPLIT movements)
 	PayerName           string  `json:"payer_name,omitempty"` // Name of who paid (for SPLIT movements)
 	IsCrossHousehold    bool    `json:"is_cross_household,omitempty"`
 	SourceHouseholdName string  `json:"source_household_name,omitempty"`
+	ConfirmationStatus  string  `json:"confirmation_status,omitempty"` // Only for payments awaiting confirmation
 }
 
 // DebtBalance represents who owes whom and how much
@@ -338,7 +792,7 @@ type DebtBalance struct {
 	DebtorName       string  `json:"debtor_name"` // Name of person who owes
 	CreditorID       string  `json:"creditor_id"` // ID of person who is owed
 	CreditorName     string  `json:"creditor_name"` // Name of person who is owed
-	Amount           float64 `json:"amount"`      // Amount owed
+	Amount           money.Amount `json:"amount"` // Amount owed
 	Currency         string  `json:"currency"`
 	IsCrossHousehold bool    `json:"is_cross_household,omitempty"` // True if any movement is from another household
 	Movements        []DebtMovementDetail `json:"movements,omitempty"` // Breakdown of movements contributing to this debt
@@ -349,18 +803,53 @@ type DebtConsolidationResponse struct {
 	Balances   []DebtBalance       `json:"balances"`             // List of who owes whom
 	Month      *string             `json:"month,omitempty"`      // Optional month filter
 	Summary    *DebtSummary        `json:"summary,omitempty"`    // Summary for household members
+	
+	// Debt payments awaiting confirmation from the receiver. They are not netted
+	// into Balances until confirmed. Cross-household entries await our confirmation.
+	PendingPayments []DebtMovementDetail `json:"pending_payments,omitempty"`
 }
 
 // DebtSummary represents totals for household members
 type DebtSummary struct {
-	TheyOweUs float64 `json:"they_owe_us"` // What external contacts owe to household members
-	WeOwe     float64 `json:"we_owe"`      // What household members owe to external contacts
+	TheyOweUs money.Amount `json:"they_owe_us"` // What external contacts owe to household members
+	WeOwe     money.Amount `json:"we_owe"`      // What household members owe to external contacts
+}
+
+// SettlementDraft is a proposed DEBT_PAYMENT that settles part of the debts.
+// The user picks which drafts to create (and completes payment method,
+// receiver account and date) before submitting them to SettleDebts.
+type SettlementDraft struct {
+	Type                  MovementType `json:"type"` // Always DEBT_PAYMENT
+	Description           string       `json:"description"`
+	Amount                money.Amount `json:"amount"`
+	Currency              string       `json:"currency"`
+	PayerUserID           *string      `json:"payer_user_id,omitempty"`
+	PayerContactID        *string      `json:"payer_contact_id,omitempty"`
+	PayerName             string       `json:"payer_name"`
+	CounterpartyUserID    *string      `json:"counterparty_user_id,omitempty"`
+	CounterpartyContactID *string      `json:"counterparty_contact_id,omitempty"`
+	CounterpartyName      string       `json:"counterparty_name"`
+	
+	// True when the counterparty is a household member, so receiver_account_id is required
+	RequiresReceiverAccount bool `json:"requires_receiver_account"`
+}
+
+// SettlementPlan is the minimal set of transfers that settles the household's debts
+type SettlementPlan struct {
+	Month            *string           `json:"month,omitempty"`
+	Drafts           []SettlementDraft `json:"drafts"`
+	CurrentTransfers int               `json:"current_transfers"` // Pairwise balances before simplification
+	
+	// Balances involving people that can't be referenced from this household
+	// (e.g. members of another household with no local contact). Not simplified.
+	Excluded []DebtBalance `json:"excluded,omitempty"`
 }
 
 // ListMovementsResponse represents the response for listing movements
 type ListMovementsResponse struct {
-	Movements []*Movement     `json:"movements"`
-	Totals    *MovementTotals `json:"totals"`
+	Movements  []*Movement     `json:"movements"`
+	Totals     *MovementTotals `json:"totals"` // Over every page
+	NextCursor string          `json:"next_cursor,omitempty"` // Empty on the last page
 }
 
 // Repository defines the interface for movement data access
@@ -368,11 +857,38 @@ type Repository interface {
 	Create(ctx context.Context, input *CreateMovementInput, householdID string) (*Movement, error)
 	GetByID(ctx context.Context, id string) (*Movement, error)
 	GetCategoryIDByName(ctx context.Context, householdID string, categoryName string) (string, error)
+	GetEventStatus(ctx context.Context, eventID string) (householdID string, status string, err error)
 	ListByHousehold(ctx context.Context, householdID string, filters *ListMovementsFilters) ([]*Movement, error)
 	ListMovementsByContactIDs(ctx context.Context, contactIDs []string, month *string) ([]*Movement, error)
 	GetTotals(ctx context.Context, householdID string, filters *ListMovementsFilters) (*MovementTotals, error)
 	Update(ctx context.Context, id string, input *UpdateMovementInput) (*Movement, error)
-	Delete(ctx context.Context, id string) error
+	Delete(ctx context.Context, id, deletedBy string) error
+	Restore(ctx context.Context, householdID, id string) error
+	PurgeDeleted(ctx context.Context, before time.Time) ([]PurgedMovement, error)
+	Discard(ctx context.Context, id string) error
+	LockRefunded(ctx context.Context, id string) (money.Amount, error)
+	WithTx(ctx context.Context, fn func(ctx context.Context) error) error
+	SetConfirmationStatus(ctx context.Context, id string, status *ConfirmationStatus, respondedBy *string, disputeReason *string) error
+	ListTags(ctx context.Context, householdID string) ([]*Tag, error)
+}
+
+// RuleApplier fills in the fields a new movement leaves empty (category,
+// type, participants, event) from the household's rules. Implemented by
+// package rules.
+type RuleApplier interface {
+	Apply(ctx context.Context, householdID string, input *CreateMovementInput) error
+}
+
+// PurgedMovement identifies a movement removed from the trash for good
+type PurgedMovement struct {
+	ID          string
+	HouseholdID string
+}
+
+// AttachmentCleaner removes the stored files of a purged movement (the
+// attachment rows go with it by cascade). Implemented by package attachments.
+type AttachmentCleaner interface {
+	DeleteMovementFiles(ctx context.Context, householdID, movementID string) error
 }
 
 // Service defines the interface for movement business logic
@@ -381,6 +897,30 @@ type Service interface {
 	GetByID(ctx context.Context, userID, id string) (*Movement, error)
 	ListByHousehold(ctx context.Context, userID string, filters *ListMovementsFilters) (*ListMovementsResponse, error)
 	GetDebtConsolidation(ctx context.Context, userID string, month *string) (*DebtConsolidationResponse, error)
+	GetSettlementPlan(ctx context.Context, userID string, month *string) (*SettlementPlan, error)
+	SettleDebts(ctx context.Context, userID string, inputs []*CreateMovementInput) ([]*Movement, error)
+	Import(ctx context.Context, userID string, r io.Reader, mapping *ImportMapping, opts *ImportOptions) (*ImportResult, error)
+	ListPendingConfirmations(ctx context.Context, userID string) ([]*Movement, error)
+	RespondToConfirmation(ctx context.Context, userID, id string, status ConfirmationStatus, disputeReason *string) (*Movement, error)
 	Update(ctx context.Context, userID, id string, input *UpdateMovementInput) (*Movement, error)
 	Delete(ctx context.Context, userID, id string) error
+	ListTags(ctx context.Context, userID string) ([]*Tag, error)
+	
+	// Refund records a refund of part or all of a movement
+	Refund(ctx context.Context, userID, id string, input *CreateRefundInput) (*Movement, error)
+	
+	// Trash: Delete moves a movement there, Restore brings it back and
+	// PurgeTrash removes what was deleted before a date
+	ListTrash(ctx context.Context, userID string) ([]*Movement, error)
+	Restore(ctx context.Context, userID, id string) (*Movement, error)
+	PurgeTrash(ctx context.Context, before time.Time) (int, error)
+	
+	// Discard permanently removes a movement that was just created, when the
+	// operation it was created for can't be completed
+	Discard(ctx context.Context, userID, id string) error
+	
+	// History is the change timeline built from audit logs; Revert applies
+	// one of its versions
+	History(ctx context.Context, userID, id string) ([]*audit.HistoryEntry, error)
+	Revert(ctx context.Context, userID, id, versionID string) (*Movement, error)
 }
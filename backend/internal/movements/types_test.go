@@ -0,0 +1,212 @@
+package movements
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blanquicet/conti/backend/internal/money"
+)
+
+func TestAllocateParticipants(t *testing.T) {
+	amountPtr := func(a money.Amount) *money.Amount { return &a }
+
+	tests := []struct {
+		name         string
+		total        money.Amount
+		participants []ParticipantInput
+		want         []money.Amount
+		wantErr      error
+	}{
+		{
+			name:  "thirds add up to the total",
+			total: money.New(100),
+			participants: []ParticipantInput{
+				{Percentage: 1.0 / 3},
+				{Percentage: 1.0 / 3},
+				{Percentage: 1.0 / 3},
+			},
+			want: []money.Amount{3334, 3333, 3333},
+		},
+		{
+			name:  "explicit amounts are kept and the rest is split",
+			total: money.New(100000),
+			participants: []ParticipantInput{
+				{Amount: amountPtr(money.New(40000))},
+				{Percentage: 0.3},
+				{Percentage: 0.3},
+			},
+			want: []money.Amount{money.New(40000), money.New(30000), money.New(30000)},
+		},
+		{
+			name:  "explicit amounts must match the total",
+			total: money.New(100000),
+			participants: []ParticipantInput{
+				{Amount: amountPtr(money.New(40000))},
+				{Amount: amountPtr(money.New(50000))},
+			},
+			wantErr: ErrInvalidParticipantAmounts,
+		},
+		{
+			name:  "explicit amounts cannot exceed the total",
+			total: money.New(100),
+			participants: []ParticipantInput{
+				{Amount: amountPtr(money.New(100))},
+				{Percentage: 0.5},
+			},
+			wantErr: ErrInvalidParticipantAmounts,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := AllocateParticipants(tt.total, tt.participants)
+			if err != tt.wantErr {
+				t.Fatalf("AllocateParticipants() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			var sum money.Amount
+			for i, p := range tt.participants {
+				if *p.Amount != tt.want[i] {
+					t.Errorf("participant %d amount = %v, want %v", i, *p.Amount, tt.want[i])
+				}
+				sum += *p.Amount
+			}
+			if sum != tt.total {
+				t.Errorf("amounts sum to %v, want %v", sum, tt.total)
+			}
+		})
+	}
+}
+
+func TestNormalizeTags(t *testing.T) {
+	got, err := NormalizeTags([]string{"  Navidad   2026 ", "reembolsable", "REEMBOLSABLE", "navidad 2026"})
+	if err != nil {
+		t.Fatalf("NormalizeTags() error = %v", err)
+	}
+	want := []string{"Navidad 2026", "reembolsable"}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("NormalizeTags() = %q, want %q", got, want)
+	}
+
+	if _, err := NormalizeTags([]string{"   "}); err != ErrInvalidTag {
+		t.Errorf("blank tag error = %v, want ErrInvalidTag", err)
+	}
+	if _, err := NormalizeTags([]string{strings.Repeat("ñ", 51)}); err != ErrInvalidTag {
+		t.Errorf("long tag error = %v, want ErrInvalidTag", err)
+	}
+
+	many := make([]string, 21)
+	for i := range many {
+		many[i] = fmt.Sprintf("tag %d", i)
+	}
+	if _, err := NormalizeTags(many); err != ErrTooManyTags {
+		t.Errorf("21 tags error = %v, want ErrTooManyTags", err)
+	}
+}
+
+func TestCreateMovementInput_ValidateTransfer(t *testing.T) {
+	str := func(s string) *string { return &s }
+	transfer := func(change func(*CreateMovementInput)) *CreateMovementInput {
+		input := &CreateMovementInput{
+			Type:              TypeTransfer,
+			Description:       "Ahorro del mes",
+			Amount:            500000,
+			MovementDate:      time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+			PayerUserID:       str("ana"),
+			SourceAccountID:   str("savings"),
+			ReceiverAccountID: str("cash"),
+		}
+		if change != nil {
+			change(input)
+		}
+		return input
+	}
+
+	tests := []struct {
+		name    string
+		input   *CreateMovementInput
+		wantErr error
+	}{
+		{"valid", transfer(nil), nil},
+		{"missing source", transfer(func(i *CreateMovementInput) { i.SourceAccountID = nil }), ErrTransferAccountsRequired},
+		{"missing destination", transfer(func(i *CreateMovementInput) { i.ReceiverAccountID = nil }), ErrTransferAccountsRequired},
+		{"same account", transfer(func(i *CreateMovementInput) { i.ReceiverAccountID = str("savings") }), ErrSameTransferAccount},
+		{"payment method", transfer(func(i *CreateMovementInput) { i.PaymentMethodID = str("debit") }), ErrPaymentMethodNotAllowed},
+		{"contact payer", transfer(func(i *CreateMovementInput) {
+			i.PayerUserID = nil
+			i.PayerContactID = str("maria")
+		}), ErrTransferPayerNotMember},
+		{"counterparty", transfer(func(i *CreateMovementInput) { i.CounterpartyUserID = str("luis") }), ErrCounterpartyNotAllowed},
+		{"source on household expense", transfer(func(i *CreateMovementInput) {
+			i.Type = TypeHousehold
+			i.CategoryID = str("mercado")
+			i.PaymentMethodID = str("debit")
+			i.ReceiverAccountID = nil
+		}), ErrSourceAccountNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.input.Validate(); err != tt.wantErr {
+				t.Errorf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCreateMovementInput_ValidateInstallments(t *testing.T) {
+	str := func(s string) *string { return &s }
+	count := func(n int) *int { return &n }
+	rate := func(r float64) *float64 { return &r }
+	purchase := func(change func(*CreateMovementInput)) *CreateMovementInput {
+		input := &CreateMovementInput{
+			Type:            TypeHousehold,
+			Description:     "Nevera",
+			Amount:          3600000,
+			CategoryID:      str("hogar"),
+			MovementDate:    time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+			PayerUserID:     str("ana"),
+			PaymentMethodID: str("visa"),
+			Installments:    count(12),
+			InstallmentRate: rate(0.0189),
+		}
+		if change != nil {
+			change(input)
+		}
+		return input
+	}
+
+	tests := []struct {
+		name    string
+		input   *CreateMovementInput
+		wantErr error
+	}{
+		{"valid", purchase(nil), nil},
+		{"single payment", purchase(func(i *CreateMovementInput) {
+			i.Installments = nil
+			i.InstallmentRate = nil
+		}), nil},
+		{"zero installments", purchase(func(i *CreateMovementInput) { i.Installments = count(0) }), ErrInvalidInstallments},
+		{"too many installments", purchase(func(i *CreateMovementInput) { i.Installments = count(49) }), ErrInvalidInstallments},
+		{"negative rate", purchase(func(i *CreateMovementInput) { i.InstallmentRate = rate(-0.01) }), ErrInvalidInstallmentRate},
+		{"rate without installments", purchase(func(i *CreateMovementInput) { i.Installments = nil }), ErrInstallmentsRequired},
+		{"debt payment", purchase(func(i *CreateMovementInput) {
+			i.Type = TypeDebtPayment
+			i.CounterpartyContactID = str("maria")
+		}), ErrInstallmentsNotAllowed},
+		{"refund", purchase(func(i *CreateMovementInput) { i.RefundOfID = str("original") }), ErrInstallmentsNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.input.Validate(); err != tt.wantErr {
+				t.Errorf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
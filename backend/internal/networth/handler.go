@@ -0,0 +1,97 @@
+package networth
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/blanquicet/conti/backend/internal/auth"
+)
+
+// Handler handles HTTP requests for the net worth report
+type Handler struct {
+	service    Service
+	authSvc    *auth.Service
+	cookieName string
+	logger     *slog.Logger
+}
+
+// NewHandler creates a new net worth handler
+func NewHandler(service Service, authService *auth.Service, cookieName string, logger *slog.Logger) *Handler {
+	return &Handler{
+		service:    service,
+		authSvc:    authService,
+		cookieName: cookieName,
+		logger:     logger,
+	}
+}
+
+// getUserFromSession extracts user from session cookie
+func (h *Handler) getUserFromSession(r *http.Request) (*auth.User, error) {
+	cookie, err := r.Cookie(h.cookieName)
+	if err != nil {
+		return nil, err
+	}
+	return h.authSvc.GetUserBySession(r.Context(), cookie.Value)
+}
+
+// HandleGetReport handles GET /net-worth: assets (account balances and what
+// contacts owe) and liabilities (card debt and what we owe contacts), in
+// total and per member
+func (h *Handler) HandleGetReport(w http.ResponseWriter, r *http.Request) {
+	user, err := h.getUserFromSession(r)
+	if err != nil {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	report, err := h.service.GetReport(r.Context(), user.ID)
+	if err != nil {
+		h.writeError(w, "failed to get net worth", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// HandleListSnapshots handles GET /net-worth/history?months=12: the monthly
+// snapshots of the last months (12 by default), oldest first
+func (h *Handler) HandleListSnapshots(w http.ResponseWriter, r *http.Request) {
+	user, err := h.getUserFromSession(r)
+	if err != nil {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	months := 12
+	if monthsStr := r.URL.Query().Get("months"); monthsStr != "" {
+		months, err = strconv.Atoi(monthsStr)
+		if err != nil {
+			http.Error(w, ErrInvalidMonths.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	snapshots, err := h.service.ListSnapshots(r.Context(), user.ID, months)
+	if err != nil {
+		h.writeError(w, "failed to list net worth snapshots", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(snapshots)
+}
+
+// writeError maps service errors to HTTP responses
+func (h *Handler) writeError(w http.ResponseWriter, msg string, err error) {
+	switch {
+	case errors.Is(err, ErrInvalidMonths):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		h.logger.Error(msg, "error", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	}
+}
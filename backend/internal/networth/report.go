@@ -0,0 +1,108 @@
+package networth
+
+import (
+	"time"
+
+	"github.com/blanquicet/conti/backend/internal/accounts"
+	"github.com/blanquicet/conti/backend/internal/creditcards"
+	"github.com/blanquicet/conti/backend/internal/households"
+	"github.com/blanquicet/conti/backend/internal/movements"
+)
+
+// buildReport adds up the accounts, cards and debts with contacts of a
+// household, in total and per member. Debts between members cancel out in
+// the household and are left out.
+func buildReport(date time.Time, members []*households.HouseholdMember, accountList []*accounts.Account, cards []*creditcards.CardSummary, debts []movements.DebtBalance) *Report {
+	report := &Report{
+		Date:     date,
+		Members:  make([]*MemberNetWorth, 0, len(members)),
+		Accounts: make([]*Item, 0, len(accountList)),
+		Cards:    make([]*Item, 0, len(cards)),
+		Debts:    make([]*Item, 0),
+	}
+
+	byMember := make(map[string]*MemberNetWorth)
+	member := func(id, name string) *MemberNetWorth {
+		m, ok := byMember[id]
+		if !ok {
+			m = &MemberNetWorth{MemberID: id, MemberName: name}
+			byMember[id] = m
+			report.Members = append(report.Members, m)
+		}
+		return m
+	}
+	for _, m := range members {
+		member(m.UserID, m.UserName)
+	}
+
+	for _, account := range accountList {
+		if account.CurrentBalance == nil {
+			continue
+		}
+		m := member(account.OwnerID, account.OwnerName)
+		report.Accounts = append(report.Accounts, &Item{
+			ID:         account.ID,
+			Name:       account.Name,
+			MemberID:   m.MemberID,
+			MemberName: m.MemberName,
+			Amount:     *account.CurrentBalance,
+		})
+		m.AccountBalances += *account.CurrentBalance
+		report.AccountBalances += *account.CurrentBalance
+	}
+
+	for _, card := range cards {
+		m := member(card.OwnerID, card.OwnerName)
+		report.Cards = append(report.Cards, &Item{
+			ID:         card.ID,
+			Name:       card.Name,
+			MemberID:   m.MemberID,
+			MemberName: m.MemberName,
+			Amount:     card.NetDebt,
+		})
+		m.CardDebt += card.NetDebt
+		report.CardDebt += card.NetDebt
+	}
+
+	isMember := make(map[string]bool, len(members))
+	for _, m := range members {
+		isMember[m.UserID] = true
+	}
+	for _, debt := range debts {
+		switch {
+		case isMember[debt.CreditorID] && !isMember[debt.DebtorID]:
+			m := byMember[debt.CreditorID]
+			report.Debts = append(report.Debts, &Item{
+				ID:         debt.DebtorID,
+				Name:       debt.DebtorName,
+				MemberID:   m.MemberID,
+				MemberName: m.MemberName,
+				Amount:     debt.Amount,
+			})
+			m.OwedByContacts += debt.Amount
+			report.OwedByContacts += debt.Amount
+		case isMember[debt.DebtorID] && !isMember[debt.CreditorID]:
+			m := byMember[debt.DebtorID]
+			report.Debts = append(report.Debts, &Item{
+				ID:         debt.CreditorID,
+				Name:       debt.CreditorName,
+				MemberID:   m.MemberID,
+				MemberName: m.MemberName,
+				Amount:     -debt.Amount,
+			})
+			m.OwedToContacts += debt.Amount
+			report.OwedToContacts += debt.Amount
+		}
+	}
+
+	report.total()
+	for _, m := range report.Members {
+		m.total()
+	}
+	return report
+}
+
+// monthOf returns the first day of the month of t
+func monthOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,78 @@
+package networth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blanquicet/conti/backend/internal/accounts"
+	"github.com/blanquicet/conti/backend/internal/creditcards"
+	"github.com/blanquicet/conti/backend/internal/households"
+	"github.com/blanquicet/conti/backend/internal/money"
+	"github.com/blanquicet/conti/backend/internal/movements"
+)
+
+func amount(v int64) *money.Amount {
+	a := money.New(v)
+	return &a
+}
+
+func TestBuildReport(t *testing.T) {
+	members := []*households.HouseholdMember{
+		{UserID: "ana", UserName: "Ana"},
+		{UserID: "luis", UserName: "Luis"},
+	}
+	accountList := []*accounts.Account{
+		{ID: "a1", Name: "Ahorros", OwnerID: "ana", OwnerName: "Ana", CurrentBalance: amount(1000)},
+		{ID: "a2", Name: "Efectivo", OwnerID: "luis", OwnerName: "Luis", CurrentBalance: amount(200)},
+	}
+	cards := []*creditcards.CardSummary{
+		{ID: "c1", Name: "Visa", OwnerID: "ana", OwnerName: "Ana", NetDebt: money.New(300)},
+	}
+	debts := []movements.DebtBalance{
+		{DebtorID: "pedro", DebtorName: "Pedro", CreditorID: "ana", CreditorName: "Ana", Amount: money.New(150)},
+		{DebtorID: "luis", DebtorName: "Luis", CreditorID: "maria", CreditorName: "María", Amount: money.New(80)},
+		// Between members: cancels out in the household
+		{DebtorID: "luis", DebtorName: "Luis", CreditorID: "ana", CreditorName: "Ana", Amount: money.New(500)},
+	}
+
+	report := buildReport(time.Date(2026, time.April, 1, 0, 0, 0, 0, time.UTC), members, accountList, cards, debts)
+
+	want := Breakdown{
+		AccountBalances: money.New(1200),
+		OwedByContacts:  money.New(150),
+		CardDebt:        money.New(300),
+		OwedToContacts:  money.New(80),
+		Assets:          money.New(1350),
+		Liabilities:     money.New(380),
+		NetWorth:        money.New(970),
+	}
+	if report.Breakdown != want {
+		t.Errorf("Breakdown = %+v, want %+v", report.Breakdown, want)
+	}
+	if len(report.Debts) != 2 || report.Debts[1].Amount != money.New(-80) {
+		t.Errorf("Debts = %+v, want Pedro's and María's (negative)", report.Debts)
+	}
+
+	if len(report.Members) != 2 {
+		t.Fatalf("Members = %d, want 2", len(report.Members))
+	}
+	ana, luis := report.Members[0], report.Members[1]
+	if ana.NetWorth != money.New(850) || luis.NetWorth != money.New(120) {
+		t.Errorf("member net worth = %v, %v, want 850, 120", ana.NetWorth, luis.NetWorth)
+	}
+	if ana.NetWorth+luis.NetWorth != report.NetWorth {
+		t.Error("member net worths don't add up to the household's")
+	}
+}
+
+func TestBuildReport_FormerMember(t *testing.T) {
+	accountList := []*accounts.Account{
+		{ID: "a1", Name: "Ahorros", OwnerID: "gone", OwnerName: "Former", CurrentBalance: amount(400)},
+	}
+
+	report := buildReport(time.Now(), nil, accountList, nil, nil)
+
+	if len(report.Members) != 1 || report.Members[0].MemberName != "Former" || report.Members[0].NetWorth != money.New(400) {
+		t.Errorf("Members = %+v, want the account owner", report.Members)
+	}
+}
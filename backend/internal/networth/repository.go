@@ -0,0 +1,119 @@
+package networth
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// repository implements Repository using PostgreSQL
+type repository struct {
+	pool *pgxpool.Pool
+}
+
+// NewRepository creates a new net worth repository
+func NewRepository(pool *pgxpool.Pool) Repository {
+	return &repository{pool: pool}
+}
+
+// ListHouseholds returns every household with members, each with its oldest member
+func (r *repository) ListHouseholds(ctx context.Context) ([]*Household, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT DISTINCT ON (household_id) household_id, user_id
+		FROM household_members
+		ORDER BY household_id, joined_at
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*Household
+	for rows.Next() {
+		var h Household
+		if err := rows.Scan(&h.ID, &h.UserID); err != nil {
+			return nil, err
+		}
+		result = append(result, &h)
+	}
+
+	return result, rows.Err()
+}
+
+// SaveSnapshot creates or replaces the snapshot of a month
+func (r *repository) SaveSnapshot(ctx context.Context, householdID string, snapshot *Snapshot) error {
+	members, err := json.Marshal(snapshot.Members)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.pool.Exec(ctx, `
+		INSERT INTO net_worth_snapshots (
+			household_id, month, account_balances, owed_by_contacts, card_debt,
+			owed_to_contacts, net_worth, members
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (household_id, month) DO UPDATE SET
+			account_balances = EXCLUDED.account_balances,
+			owed_by_contacts = EXCLUDED.owed_by_contacts,
+			card_debt = EXCLUDED.card_debt,
+			owed_to_contacts = EXCLUDED.owed_to_contacts,
+			net_worth = EXCLUDED.net_worth,
+			members = EXCLUDED.members,
+			updated_at = NOW()
+	`,
+		householdID,
+		snapshot.Month,
+		snapshot.AccountBalances,
+		snapshot.OwedByContacts,
+		snapshot.CardDebt,
+		snapshot.OwedToContacts,
+		snapshot.NetWorth,
+		members,
+	)
+	return err
+}
+
+// ListSnapshots returns the snapshots from a month on, oldest first
+func (r *repository) ListSnapshots(ctx context.Context, householdID string, from time.Time) ([]*Snapshot, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT month, account_balances, owed_by_contacts, card_debt, owed_to_contacts,
+		       members, updated_at
+		FROM net_worth_snapshots
+		WHERE household_id = $1 AND month >= $2
+		ORDER BY month
+	`, householdID, from)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	snapshots := make([]*Snapshot, 0)
+	for rows.Next() {
+		var s Snapshot
+		var members []byte
+		if err := rows.Scan(
+			&s.Month,
+			&s.AccountBalances,
+			&s.OwedByContacts,
+			&s.CardDebt,
+			&s.OwedToContacts,
+			&members,
+			&s.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(members, &s.Members); err != nil {
+			return nil, err
+		}
+		s.total()
+		for _, m := range s.Members {
+			m.total()
+		}
+		snapshots = append(snapshots, &s)
+	}
+
+	return snapshots, rows.Err()
+}
@@ -0,0 +1,123 @@
+package networth
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/blanquicet/conti/backend/internal/accounts"
+	"github.com/blanquicet/conti/backend/internal/creditcards"
+	"github.com/blanquicet/conti/backend/internal/households"
+	"github.com/blanquicet/conti/backend/internal/movements"
+)
+
+// service implements Service interface
+type service struct {
+	repo               Repository
+	householdsRepo     households.HouseholdRepository
+	accountsRepo       accounts.Repository
+	creditCardsService creditcards.Service
+	movementsService   movements.Service
+	logger             *slog.Logger
+}
+
+// NewService creates a new net worth service
+func NewService(
+	repo Repository,
+	householdsRepo households.HouseholdRepository,
+	accountsRepo accounts.Repository,
+	creditCardsService creditcards.Service,
+	movementsService movements.Service,
+	logger *slog.Logger,
+) Service {
+	return &service{
+		repo:               repo,
+		householdsRepo:     householdsRepo,
+		accountsRepo:       accountsRepo,
+		creditCardsService: creditCardsService,
+		movementsService:   movementsService,
+		logger:             logger,
+	}
+}
+
+// GetReport returns the current net worth of the user's household
+func (s *service) GetReport(ctx context.Context, userID string) (*Report, error) {
+	householdID, err := s.householdsRepo.GetUserHouseholdID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get household: %w", err)
+	}
+
+	return s.report(ctx, householdID, userID, time.Now())
+}
+
+// ListSnapshots returns the snapshots of the last months, current one included
+func (s *service) ListSnapshots(ctx context.Context, userID string, months int) ([]*Snapshot, error) {
+	if months < 1 || months > maxMonths {
+		return nil, ErrInvalidMonths
+	}
+
+	householdID, err := s.householdsRepo.GetUserHouseholdID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get household: %w", err)
+	}
+
+	from := monthOf(time.Now()).AddDate(0, 1-months, 0)
+	return s.repo.ListSnapshots(ctx, householdID, from)
+}
+
+// RecordSnapshots saves the snapshot of the month of now for every household.
+// A household failing doesn't stop the others.
+func (s *service) RecordSnapshots(ctx context.Context, now time.Time) (int, error) {
+	list, err := s.repo.ListHouseholds(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("list households: %w", err)
+	}
+
+	recorded := 0
+	for _, household := range list {
+		report, err := s.report(ctx, household.ID, household.UserID, now)
+		if err != nil {
+			s.logger.Error("failed to compute net worth", "household_id", household.ID, "error", err)
+			continue
+		}
+
+		snapshot := &Snapshot{
+			Month:     monthOf(now),
+			Breakdown: report.Breakdown,
+			Members:   report.Members,
+		}
+		if err := s.repo.SaveSnapshot(ctx, household.ID, snapshot); err != nil {
+			s.logger.Error("failed to save net worth snapshot", "household_id", household.ID, "error", err)
+			continue
+		}
+		recorded++
+	}
+
+	return recorded, nil
+}
+
+// report computes the net worth of a household on behalf of one of its members
+func (s *service) report(ctx context.Context, householdID, userID string, now time.Time) (*Report, error) {
+	members, err := s.householdsRepo.GetMembers(ctx, householdID)
+	if err != nil {
+		return nil, fmt.Errorf("get members: %w", err)
+	}
+
+	accountList, err := s.accountsRepo.ListByHousehold(ctx, householdID)
+	if err != nil {
+		return nil, fmt.Errorf("list accounts: %w", err)
+	}
+
+	summary, err := s.creditCardsService.GetSummary(ctx, userID, now, nil)
+	if err != nil {
+		return nil, fmt.Errorf("get credit cards summary: %w", err)
+	}
+
+	debts, err := s.movementsService.GetDebtConsolidation(ctx, userID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("get debt consolidation: %w", err)
+	}
+
+	return buildReport(now, members, accountList, summary.Cards, debts.Balances), nil
+}
@@ -0,0 +1,63 @@
+package networth
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Snapshotter periodically refreshes the net worth snapshot of the current
+// month, so each month keeps the last one taken in it
+type Snapshotter struct {
+	service  Service
+	logger   *slog.Logger
+	stopChan chan struct{}
+}
+
+// NewSnapshotter creates a new net worth snapshotter
+func NewSnapshotter(service Service, logger *slog.Logger) *Snapshotter {
+	return &Snapshotter{
+		service:  service,
+		logger:   logger,
+		stopChan: make(chan struct{}),
+	}
+}
+
+// Start begins the snapshot loop (runs every 6 hours)
+func (s *Snapshotter) Start(ctx context.Context) {
+	ticker := time.NewTicker(6 * time.Hour)
+	defer ticker.Stop()
+
+	s.logger.Info("net worth snapshotter started (runs every 6 hours)")
+
+	// Run immediately on start
+	s.record(ctx, time.Now())
+
+	for {
+		select {
+		case <-ticker.C:
+			s.record(ctx, time.Now())
+		case <-s.stopChan:
+			s.logger.Info("net worth snapshotter stopped")
+			return
+		case <-ctx.Done():
+			s.logger.Info("net worth snapshotter context canceled")
+			return
+		}
+	}
+}
+
+// Stop stops the snapshotter
+func (s *Snapshotter) Stop() {
+	close(s.stopChan)
+}
+
+// record saves the snapshots of the month of now
+func (s *Snapshotter) record(ctx context.Context, now time.Time) {
+	count, err := s.service.RecordSnapshots(ctx, now)
+	if err != nil {
+		s.logger.Error("failed to record net worth snapshots", "error", err)
+		return
+	}
+	s.logger.Info("recorded net worth snapshots", "count", count)
+}
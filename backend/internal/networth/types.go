@@ -0,0 +1,98 @@
+package networth
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/blanquicet/conti/backend/internal/money"
+)
+
+// Errors for net worth operations
+var (
+	ErrInvalidMonths = errors.New("months must be between 1 and 120")
+)
+
+// maxMonths bounds the net worth history
+const maxMonths = 120
+
+// Breakdown is what makes up a net worth, in the household currency
+type Breakdown struct {
+	AccountBalances money.Amount `json:"account_balances"`
+	OwedByContacts  money.Amount `json:"owed_by_contacts"`
+	CardDebt        money.Amount `json:"card_debt"` // Net debt of the cards in the current billing cycle
+	OwedToContacts  money.Amount `json:"owed_to_contacts"`
+
+	// Calculated fields
+	Assets      money.Amount `json:"assets"`      // Account balances + owed by contacts
+	Liabilities money.Amount `json:"liabilities"` // Card debt + owed to contacts
+	NetWorth    money.Amount `json:"net_worth"`   // Assets - liabilities
+}
+
+// total fills the calculated fields
+func (b *Breakdown) total() {
+	b.Assets = b.AccountBalances + b.OwedByContacts
+	b.Liabilities = b.CardDebt + b.OwedToContacts
+	b.NetWorth = b.Assets - b.Liabilities
+}
+
+// MemberNetWorth is the net worth of a household member: their accounts and
+// cards, and the debts between them and contacts
+type MemberNetWorth struct {
+	MemberID   string `json:"member_id"`
+	MemberName string `json:"member_name"`
+	Breakdown
+}
+
+// Item is an account, card or contact debt in a net worth report
+type Item struct {
+	ID         string       `json:"id"` // Of the account, card or contact
+	Name       string       `json:"name"`
+	MemberID   string       `json:"member_id"`
+	MemberName string       `json:"member_name"`
+	Amount     money.Amount `json:"amount"` // Debts: positive when the contact owes the member
+}
+
+// Report is the current net worth of a household
+type Report struct {
+	Date time.Time `json:"date"`
+	Breakdown
+	Members  []*MemberNetWorth `json:"members"`
+	Accounts []*Item           `json:"accounts"`
+	Cards    []*Item           `json:"cards"`
+	Debts    []*Item           `json:"debts"`
+}
+
+// Snapshot is the net worth of a household in a month
+type Snapshot struct {
+	Month time.Time `json:"month"` // First day of the month
+	Breakdown
+	Members   []*MemberNetWorth `json:"members"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+// Household is a household and one of its members, to compute its net worth
+// on their behalf
+type Household struct {
+	ID     string
+	UserID string
+}
+
+// Repository defines the interface for net worth snapshots
+type Repository interface {
+	// ListHouseholds returns every household with members
+	ListHouseholds(ctx context.Context) ([]*Household, error)
+	// SaveSnapshot creates or replaces the snapshot of a month
+	SaveSnapshot(ctx context.Context, householdID string, snapshot *Snapshot) error
+	// ListSnapshots returns the snapshots from a month on, oldest first
+	ListSnapshots(ctx context.Context, householdID string, from time.Time) ([]*Snapshot, error)
+}
+
+// Service defines the interface for net worth business logic
+type Service interface {
+	GetReport(ctx context.Context, userID string) (*Report, error)
+	// ListSnapshots returns the snapshots of the last months, current one included
+	ListSnapshots(ctx context.Context, userID string, months int) ([]*Snapshot, error)
+	// RecordSnapshots saves the snapshot of the month of now for every household
+	RecordSnapshots(ctx context.Context, now time.Time) (int, error)
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/blanquicet/conti/backend/internal/auth"
 	"github.com/blanquicet/conti/backend/internal/households"
+	"github.com/blanquicet/conti/backend/internal/money"
 )
 
 // Handler handles HTTP requests for payment method management
@@ -49,6 +50,9 @@ type CreatePaymentMethodRequest struct {
 	Notes                 *string           `json:"notes,omitempty"`
 	LinkedAccountID       *string           `json:"linked_account_id,omitempty"`
 	CutoffDay             *int              `json:"cutoff_day,omitempty"`
+	CreditLimit              *money.Amount `json:"credit_limit,omitempty"`
+	PaymentDueDay            *int          `json:"payment_due_day,omitempty"`
+	MinimumPaymentPercentage *float64      `json:"minimum_payment_percentage,omitempty"`
 }
 
 type UpdatePaymentMethodRequest struct {
@@ -60,6 +64,9 @@ type UpdatePaymentMethodRequest struct {
 	IsActive              *bool   `json:"is_active,omitempty"`
 	LinkedAccountID       *string `json:"linked_account_id,omitempty"`
 	CutoffDay             *int    `json:"cutoff_day,omitempty"`
+	CreditLimit              *money.Amount `json:"credit_limit,omitempty"`
+	PaymentDueDay            *int          `json:"payment_due_day,omitempty"`
+	MinimumPaymentPercentage *float64      `json:"minimum_payment_percentage,omitempty"`
 }
 
 type ErrorResponse struct {
@@ -141,6 +148,9 @@ Institution:           req.Institution,
 Notes:                 req.Notes,
 LinkedAccountID:       req.LinkedAccountID,
 CutoffDay:             req.CutoffDay,
+CreditLimit:              req.CreditLimit,
+PaymentDueDay:            req.PaymentDueDay,
+MinimumPaymentPercentage: req.MinimumPaymentPercentage,
 }
 
 pm, err := h.service.Create(r.Context(), input)
@@ -257,6 +267,9 @@ input := &UpdateInput{
 		IsActive:              req.IsActive,
 		LinkedAccountID:       req.LinkedAccountID,
 		CutoffDay:             req.CutoffDay,
+		CreditLimit:              req.CreditLimit,
+		PaymentDueDay:            req.PaymentDueDay,
+		MinimumPaymentPercentage: req.MinimumPaymentPercentage,
 		OwnerID:               user.ID,
 	}
 
@@ -26,14 +26,17 @@ func (r *repository) Create(ctx context.Context, pm *PaymentMethod) (*PaymentMet
 	err := r.pool.QueryRow(ctx, `
 		INSERT INTO payment_methods (
 			household_id, owner_id, name, type, is_shared_with_household,
-			last4, institution, notes, is_active, cutoff_day, linked_account_id
+			last4, institution, notes, is_active, cutoff_day, linked_account_id,
+			credit_limit, payment_due_day, minimum_payment_percentage
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
 		RETURNING id, household_id, owner_id, name, type, is_shared_with_household,
 		          last4, institution, notes, is_active, created_at, updated_at,
-		          cutoff_day, linked_account_id
+		          cutoff_day, linked_account_id,
+		          credit_limit, payment_due_day, minimum_payment_percentage::float8
 	`, pm.HouseholdID, pm.OwnerID, pm.Name, pm.Type, pm.IsSharedWithHousehold,
-	   pm.Last4, pm.Institution, pm.Notes, pm.IsActive, pm.CutoffDay, pm.LinkedAccountID).Scan(
+	   pm.Last4, pm.Institution, pm.Notes, pm.IsActive, pm.CutoffDay, pm.LinkedAccountID,
+	   pm.CreditLimit, pm.PaymentDueDay, pm.MinimumPaymentPercentage).Scan(
 		&result.ID,
 		&result.HouseholdID,
 		&result.OwnerID,
@@ -48,6 +51,9 @@ func (r *repository) Create(ctx context.Context, pm *PaymentMethod) (*PaymentMet
 		&result.UpdatedAt,
 		&result.CutoffDay,
 		&result.LinkedAccountID,
+		&result.CreditLimit,
+		&result.PaymentDueDay,
+		&result.MinimumPaymentPercentage,
 	)
 
 	if err != nil {
@@ -70,7 +76,8 @@ err := r.pool.QueryRow(ctx, `
 SELECT pm.id, pm.household_id, pm.owner_id, pm.name, pm.type,
        pm.is_shared_with_household, pm.last4, pm.institution, pm.notes,
        pm.is_active, pm.created_at, pm.updated_at, u.name as owner_name,
-       pm.cutoff_day, pm.linked_account_id, a.name as linked_account_name
+       pm.cutoff_day, pm.linked_account_id, a.name as linked_account_name,
+       pm.credit_limit, pm.payment_due_day, pm.minimum_payment_percentage::float8
 FROM payment_methods pm
 JOIN users u ON pm.owner_id = u.id
 LEFT JOIN accounts a ON pm.linked_account_id = a.id
@@ -92,6 +99,9 @@ WHERE pm.id = $1
 &pm.CutoffDay,
 &pm.LinkedAccountID,
 &pm.LinkedAccountName,
+&pm.CreditLimit,
+&pm.PaymentDueDay,
+&pm.MinimumPaymentPercentage,
 )
 
 if err != nil {
@@ -112,13 +122,16 @@ func (r *repository) Update(ctx context.Context, pm *PaymentMethod) (*PaymentMet
 		UPDATE payment_methods
 		SET name = $1, is_shared_with_household = $2, last4 = $3,
 		    institution = $4, notes = $5, is_active = $6, updated_at = NOW(),
-		    cutoff_day = $7, linked_account_id = $8
-		WHERE id = $9
+		    cutoff_day = $7, linked_account_id = $8,
+		    credit_limit = $9, payment_due_day = $10, minimum_payment_percentage = $11
+		WHERE id = $12
 		RETURNING id, household_id, owner_id, name, type, is_shared_with_household,
 		          last4, institution, notes, is_active, created_at, updated_at,
-		          cutoff_day, linked_account_id
+		          cutoff_day, linked_account_id,
+		          credit_limit, payment_due_day, minimum_payment_percentage::float8
 	`, pm.Name, pm.IsSharedWithHousehold, pm.Last4, pm.Institution, pm.Notes, pm.IsActive,
-	   pm.CutoffDay, pm.LinkedAccountID, pm.ID).Scan(
+	   pm.CutoffDay, pm.LinkedAccountID,
+	   pm.CreditLimit, pm.PaymentDueDay, pm.MinimumPaymentPercentage, pm.ID).Scan(
 		&result.ID,
 		&result.HouseholdID,
 		&result.OwnerID,
@@ -133,6 +146,9 @@ func (r *repository) Update(ctx context.Context, pm *PaymentMethod) (*PaymentMet
 		&result.UpdatedAt,
 		&result.CutoffDay,
 		&result.LinkedAccountID,
+		&result.CreditLimit,
+		&result.PaymentDueDay,
+		&result.MinimumPaymentPercentage,
 	)
 
 	if err != nil {
@@ -175,7 +191,8 @@ rows, err := r.pool.Query(ctx, `
 SELECT pm.id, pm.household_id, pm.owner_id, pm.name, pm.type,
        pm.is_shared_with_household, pm.last4, pm.institution, pm.notes,
        pm.is_active, pm.created_at, pm.updated_at, u.name as owner_name,
-       pm.cutoff_day, pm.linked_account_id, a.name as linked_account_name
+       pm.cutoff_day, pm.linked_account_id, a.name as linked_account_name,
+       pm.credit_limit, pm.payment_due_day, pm.minimum_payment_percentage::float8
 FROM payment_methods pm
 JOIN users u ON pm.owner_id = u.id
 LEFT JOIN accounts a ON pm.linked_account_id = a.id
@@ -208,6 +225,9 @@ err := rows.Scan(
 &pm.CutoffDay,
 &pm.LinkedAccountID,
 &pm.LinkedAccountName,
+&pm.CreditLimit,
+&pm.PaymentDueDay,
+&pm.MinimumPaymentPercentage,
 )
 if err != nil {
 return nil, err
@@ -228,7 +248,8 @@ var pm PaymentMethod
 err := r.pool.QueryRow(ctx, `
 SELECT id, household_id, owner_id, name, type, is_shared_with_household,
        last4, institution, notes, is_active, created_at, updated_at,
-       cutoff_day, linked_account_id
+       cutoff_day, linked_account_id,
+       credit_limit, payment_due_day, minimum_payment_percentage::float8
 FROM payment_methods
 WHERE household_id = $1 AND name = $2 AND is_active = true
 `, householdID, name).Scan(
@@ -246,6 +267,9 @@ WHERE household_id = $1 AND name = $2 AND is_active = true
 &pm.UpdatedAt,
 &pm.CutoffDay,
 &pm.LinkedAccountID,
+&pm.CreditLimit,
+&pm.PaymentDueDay,
+&pm.MinimumPaymentPercentage,
 )
 
 if err != nil {
@@ -5,6 +5,7 @@ import (
 "errors"
 "strings"
 	"github.com/blanquicet/conti/backend/internal/audit"
+	"github.com/blanquicet/conti/backend/internal/money"
 )
 
 // Service handles payment method business logic
@@ -34,6 +35,9 @@ Institution           *string
 Notes                 *string
 LinkedAccountID       *string
 CutoffDay             *int
+CreditLimit              *money.Amount
+PaymentDueDay            *int
+MinimumPaymentPercentage *float64
 }
 
 // Validate validates the input
@@ -93,6 +97,12 @@ func (s *Service) Create(ctx context.Context, input *CreateInput) (*PaymentMetho
 		IsActive:              isActive,
 		LinkedAccountID:       input.LinkedAccountID,
 		CutoffDay:             input.CutoffDay,
+		CreditLimit:              input.CreditLimit,
+		PaymentDueDay:            input.PaymentDueDay,
+		MinimumPaymentPercentage: input.MinimumPaymentPercentage,
+	}
+	if err := pm.validateCreditCardFields(); err != nil {
+		return nil, err
 	}
 
 	created, err := s.repo.Create(ctx, pm)
@@ -132,6 +142,9 @@ type UpdateInput struct {
 	IsActive              *bool
 	LinkedAccountID       *string
 	CutoffDay             *int
+	CreditLimit              *money.Amount
+	PaymentDueDay            *int
+	MinimumPaymentPercentage *float64
 	OwnerID               string // for authorization
 }
 
@@ -212,6 +225,18 @@ if input.Institution != nil {
 	if input.CutoffDay != nil {
 		existing.CutoffDay = input.CutoffDay
 	}
+	if input.CreditLimit != nil {
+		existing.CreditLimit = input.CreditLimit
+	}
+	if input.PaymentDueDay != nil {
+		existing.PaymentDueDay = input.PaymentDueDay
+	}
+	if input.MinimumPaymentPercentage != nil {
+		existing.MinimumPaymentPercentage = input.MinimumPaymentPercentage
+	}
+	if err := existing.validateCreditCardFields(); err != nil {
+		return nil, err
+	}
 
 	updated, err := s.repo.Update(ctx, existing)
 if err != nil {
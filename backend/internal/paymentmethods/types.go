@@ -4,6 +4,8 @@ import (
 "context"
 "errors"
 "time"
+
+"github.com/blanquicet/conti/backend/internal/money"
 )
 
 // Errors for payment method operations
@@ -16,6 +18,10 @@ ErrCutoffDayOnlyForCreditCards = errors.New("cutoff_day is only applicable for c
 ErrLinkedAccountRequired       = errors.New("linked_account_id is required for debit cards")
 ErrLinkedAccountMustBeSavings  = errors.New("linked account must be a savings account")
 ErrInvalidCutoffDay            = errors.New("cutoff_day must be between 1 and 31")
+ErrCreditFieldsOnlyForCreditCards = errors.New("credit_limit, payment_due_day and minimum_payment_percentage are only applicable for credit cards")
+ErrInvalidCreditLimit          = errors.New("credit_limit must be positive")
+ErrInvalidPaymentDueDay        = errors.New("payment_due_day must be between 1 and 31")
+ErrInvalidMinimumPayment       = errors.New("minimum_payment_percentage must be greater than 0 and at most 1")
 )
 
 // PaymentMethodType represents the type of payment method
@@ -56,6 +62,12 @@ UpdatedAt              time.Time         `json:"updated_at"`
 // Credit card specific: billing cycle cut-off day (1-31, NULL = last day of month)
 CutoffDay              *int              `json:"cutoff_day,omitempty"`
 
+// Credit card specific: limit (household currency), payment due day (the first
+// one after the cut-off) and minimum payment as a fraction of the statement (0.0 to 1.0)
+CreditLimit              *money.Amount `json:"credit_limit,omitempty"`
+PaymentDueDay            *int          `json:"payment_due_day,omitempty"`
+MinimumPaymentPercentage *float64      `json:"minimum_payment_percentage,omitempty"`
+
 // Debit card specific: linked savings account for balance tracking
 LinkedAccountID        *string           `json:"linked_account_id,omitempty"`
 
@@ -81,6 +93,16 @@ return errors.New("last4 must be exactly 4 characters")
 if p.Institution != nil && len(*p.Institution) > 100 {
 return errors.New("institution must be 100 characters or less")
 }
+if err := p.validateCreditCardFields(); err != nil {
+return err
+}
+// Note: linked_account_id validation (required for debit cards, must be savings)
+// is done in the service layer where we can check the account type
+return nil
+}
+
+// validateCreditCardFields validates the fields only credit cards have
+func (p *PaymentMethod) validateCreditCardFields() error {
 // Validate cutoff_day: only for credit cards, must be 1-31
 if p.CutoffDay != nil {
 if p.Type != TypeCreditCard {
@@ -90,8 +112,21 @@ if *p.CutoffDay < 1 || *p.CutoffDay > 31 {
 return ErrInvalidCutoffDay
 }
 }
-// Note: linked_account_id validation (required for debit cards, must be savings)
-// is done in the service layer where we can check the account type
+// Validate credit limit, due day and minimum payment: only for credit cards
+if p.CreditLimit != nil || p.PaymentDueDay != nil || p.MinimumPaymentPercentage != nil {
+if p.Type != TypeCreditCard {
+return ErrCreditFieldsOnlyForCreditCards
+}
+if p.CreditLimit != nil && *p.CreditLimit <= 0 {
+return ErrInvalidCreditLimit
+}
+if p.PaymentDueDay != nil && (*p.PaymentDueDay < 1 || *p.PaymentDueDay > 31) {
+return ErrInvalidPaymentDueDay
+}
+if p.MinimumPaymentPercentage != nil && (*p.MinimumPaymentPercentage <= 0 || *p.MinimumPaymentPercentage > 1) {
+return ErrInvalidMinimumPayment
+}
+}
 return nil
 }
 
@@ -98,6 +98,7 @@ func (g *Generator) GenerateMovement(ctx context.Context, template *RecurringMov
 		Type:                   *template.MovementType,
 		Description:            template.Name, // Use template name as description
 		Amount:                 template.Amount,
+		Currency:               template.Currency,
 		CategoryID:             template.CategoryID,
 		MovementDate:           time.Now(), // Use current date
 		GeneratedFromTemplateID: &templateID, // Mark as auto-generated
@@ -114,6 +114,25 @@ func TestCalculateNextScheduledDateEdgeCases(t *testing.T) {
 		}
 	})
 
+	t.Run("Day 31 in February is clamped to the 28th", func(t *testing.T) {
+		from := time.Date(2026, 2, 1, 10, 0, 0, 0, time.UTC)
+		pattern := RecurrenceMonthly
+		dayOfMonth := 31
+
+		nextDate := calculateNextScheduledDate(from, &pattern, &dayOfMonth, nil)
+
+		// Used to overflow into March 3
+		if nextDate.Year() != 2026 || nextDate.Month() != time.February || nextDate.Day() != 28 {
+			t.Errorf("Expected Feb 28, 2026, got %v", nextDate)
+		}
+
+		// Next one after Feb 28 should be March 31
+		nextDate2 := calculateNextScheduledDate(nextDate, &pattern, &dayOfMonth, nil)
+		if nextDate2.Year() != 2026 || nextDate2.Month() != time.March || nextDate2.Day() != 31 {
+			t.Errorf("Expected March 31, 2026, got %v", nextDate2)
+		}
+	})
+
 	t.Run("Day of year 1 (January 1)", func(t *testing.T) {
 		lastGenerated := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
 		pattern := RecurrenceYearly
@@ -138,3 +157,65 @@ func TestCalculateNextScheduledDateEdgeCases(t *testing.T) {
 		}
 	})
 }
+
+func TestDayInMonth(t *testing.T) {
+	tests := []struct {
+		name  string
+		year  int
+		month time.Month
+		day   int
+		want  time.Time
+	}{
+		{"day that exists", 2026, time.March, 15, time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)},
+		{"day 31 in February", 2026, time.February, 31, time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC)},
+		{"day 30 in a leap February", 2024, time.February, 30, time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"day 31 in April", 2026, time.April, 31, time.Date(2026, 4, 30, 0, 0, 0, 0, time.UTC)},
+		{"month past December", 2025, time.December + 1, 31, time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := dayInMonth(tt.year, tt.month, tt.day, time.UTC); !got.Equal(tt.want) {
+				t.Errorf("dayInMonth() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOccurrences(t *testing.T) {
+	monthly := RecurrenceMonthly
+	oneTime := RecurrenceOneTime
+	next := time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2026, 4, 15, 0, 0, 0, 0, time.UTC)
+
+	template := &RecurringMovementTemplate{
+		IsActive:          true,
+		RecurrencePattern: &monthly,
+		DayOfMonth:        intPtr(31),
+		NextScheduledDate: &next,
+	}
+	got := template.Occurrences(until)
+	want := []time.Time{
+		next,
+		time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC),
+		time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC),
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Occurrences() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if !got[i].Equal(want[i]) {
+			t.Errorf("Occurrences()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+
+	template.RecurrencePattern = &oneTime
+	if got := template.Occurrences(until); len(got) != 1 {
+		t.Errorf("ONE_TIME Occurrences() = %v, want only the next date", got)
+	}
+
+	template.IsActive = false
+	if got := template.Occurrences(until); got != nil {
+		t.Errorf("inactive Occurrences() = %v, want nil", got)
+	}
+}
@@ -88,7 +88,7 @@ func (h *Handler) HandleCreate(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, "Not authorized", http.StatusForbidden)
 		case ErrInvalidRecurrencePattern, ErrInvalidDayOfMonth,
 			ErrInvalidDayOfYear, ErrAmountRequired, ErrRecurrenceRequired,
-			ErrInvalidParticipants, ErrInvalidPercentageSum:
+			ErrInvalidParticipants, ErrInvalidPercentageSum, ErrTransferNotSupported:
 			http.Error(w, err.Error(), http.StatusBadRequest)
 		default:
 			http.Error(w, "Internal server error", http.StatusInternalServerError)
@@ -338,6 +338,8 @@ func (h *Handler) HandleUpdate(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, "Template not found", http.StatusNotFound)
 		case ErrNotAuthorized:
 			http.Error(w, "Not authorized", http.StatusForbidden)
+		case ErrTransferNotSupported:
+			http.Error(w, err.Error(), http.StatusBadRequest)
 		default:
 			h.logger.Error("failed to update template", "error", err)
 			http.Error(w, "Internal server error", http.StatusInternalServerError)
@@ -390,9 +392,9 @@ func (h *Handler) HandleDelete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// If scope=ALL, delete movements generated from this template first
+	// If scope=ALL, move the movements generated from this template to the trash first
 	if scope == "ALL" {
-		deleted, err := h.repo.DeleteMovementsByTemplateID(r.Context(), id)
+		deleted, err := h.repo.DeleteMovementsByTemplateID(r.Context(), id, user.ID)
 		if err != nil {
 			h.logger.Error("failed to delete movements for template", "error", err, "template_id", id)
 		} else if deleted > 0 {
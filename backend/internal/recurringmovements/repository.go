@@ -9,6 +9,8 @@ import (
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/blanquicet/conti/backend/internal/money"
 )
 
 // repository implements Repository using PostgreSQL
@@ -813,21 +815,13 @@ func calculateNextScheduledDate(from time.Time, pattern *RecurrencePattern, dayO
 		day := *dayOfMonth
 		
 		// Try current month first
-		next := time.Date(year, month, day, 0, 0, 0, 0, from.Location())
+		next := dayInMonth(year, month, day, from.Location())
 		if next.After(from) {
 			return next
 		}
 		
 		// Otherwise next month
-		next = time.Date(year, month+1, day, 0, 0, 0, 0, from.Location())
-		
-		// Handle month overflow (e.g., day 31 in February)
-		if next.Month() != month+1 && next.Month() != 1 { // 1 = January (overflow from December)
-			// Go to last day of target month
-			next = time.Date(year, month+1, 1, 0, 0, 0, 0, from.Location()).AddDate(0, 1, -1)
-		}
-		
-		return next
+		return dayInMonth(year, month+1, day, from.Location())
 		
 	case RecurrenceYearly:
 		// Next year, same day of year
@@ -855,6 +849,17 @@ func calculateNextScheduledDate(from time.Time, pattern *RecurrencePattern, dayO
 	}
 }
 
+// dayInMonth returns the given day of a month, or the month's last day when
+// it is shorter (day 31 in February is the 28th, or the 29th in leap years).
+// time.Date alone would roll the extra days over into the next month.
+func dayInMonth(year int, month time.Month, day int, loc *time.Location) time.Time {
+	last := time.Date(year, month+1, 0, 0, 0, 0, 0, loc)
+	if day > last.Day() {
+		return last
+	}
+	return time.Date(year, month, day, 0, 0, 0, 0, loc)
+}
+
 // GetTemplatesUsedInMonth returns a map of template IDs that have movements in the given month
 // key: template_id, value: true if used this month
 func (r *repository) GetTemplatesUsedInMonth(ctx context.Context, householdID, month string) (map[string]bool, error) {
@@ -864,6 +869,7 @@ func (r *repository) GetTemplatesUsedInMonth(ctx context.Context, householdID, m
 		FROM movements
 		WHERE household_id = $1
 		  AND generated_from_template_id IS NOT NULL
+		  AND deleted_at IS NULL
 		  AND to_char(movement_date, 'YYYY-MM') = $2
 	`
 	
@@ -889,11 +895,13 @@ func (r *repository) GetTemplatesUsedInMonth(ctx context.Context, householdID, m
 	return result, nil
 }
 
-// DeleteMovementsByTemplateID deletes all movements generated from a specific template
-func (r *repository) DeleteMovementsByTemplateID(ctx context.Context, templateID string) (int64, error) {
+// DeleteMovementsByTemplateID moves all movements generated from a specific
+// template to the trash
+func (r *repository) DeleteMovementsByTemplateID(ctx context.Context, templateID, deletedBy string) (int64, error) {
 	result, err := r.pool.Exec(ctx, `
-		DELETE FROM movements WHERE generated_from_template_id = $1
-	`, templateID)
+		UPDATE movements SET deleted_at = NOW(), deleted_by = $2
+		WHERE generated_from_template_id = $1 AND deleted_at IS NULL
+	`, templateID, deletedBy)
 	if err != nil {
 		return 0, err
 	}
@@ -901,10 +909,10 @@ func (r *repository) DeleteMovementsByTemplateID(ctx context.Context, templateID
 }
 
 // UpdateMovementsByTemplateID updates amount and description for all movements generated from a template
-func (r *repository) UpdateMovementsByTemplateID(ctx context.Context, templateID string, amount float64, description string) (int64, error) {
+func (r *repository) UpdateMovementsByTemplateID(ctx context.Context, templateID string, amount money.Amount, description string) (int64, error) {
 	result, err := r.pool.Exec(ctx, `
 		UPDATE movements SET amount = $2, description = $3, updated_at = NOW()
-		WHERE generated_from_template_id = $1
+		WHERE generated_from_template_id = $1 AND deleted_at IS NULL
 	`, templateID, amount, description)
 	if err != nil {
 		return 0, err
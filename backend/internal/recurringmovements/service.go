@@ -8,6 +8,7 @@ import (
 
 	"github.com/blanquicet/conti/backend/internal/budgets"
 	"github.com/blanquicet/conti/backend/internal/households"
+	"github.com/blanquicet/conti/backend/internal/money"
 	"github.com/blanquicet/conti/backend/internal/movements"
 )
 
@@ -407,7 +408,7 @@ func (s *service) Delete(ctx context.Context, userID, id string) error {
 
 // CalculateTemplatesSum calculates the sum of all template amounts for a category
 // This is used by the budgets service to validate manual budgets
-func (s *service) CalculateTemplatesSum(ctx context.Context, userID, categoryID string) (float64, error) {
+func (s *service) CalculateTemplatesSum(ctx context.Context, userID, categoryID string) (money.Amount, error) {
 	// Get household for authorization
 	households, err := s.householdsRepo.ListByUser(ctx, userID)
 	if err != nil {
@@ -428,7 +429,7 @@ func (s *service) CalculateTemplatesSum(ctx context.Context, userID, categoryID
 	}
 	
 	// Calculate sum
-	totalAmount := 0.0
+	totalAmount := money.Amount(0)
 	for _, t := range templates {
 		totalAmount += t.Amount
 	}
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/blanquicet/conti/backend/internal/money"
 	"github.com/blanquicet/conti/backend/internal/movements"
 )
 
@@ -22,6 +23,7 @@ var (
 	ErrInvalidParticipants      = errors.New("participants required for SPLIT templates")
 	ErrInvalidPercentageSum     = errors.New("participant percentages must sum to 100%")
 	ErrInvalidScope             = errors.New("invalid scope (must be THIS, FUTURE, or ALL)")
+	ErrTransferNotSupported     = errors.New("TRANSFER templates are not supported")
 )
 
 // NullableDate represents a date that can be parsed from multiple formats
@@ -102,8 +104,8 @@ type RecurringMovementTemplate struct {
 	CategoryID   *string                 `json:"category_id,omitempty"`
 	
 	// Amount configuration (always required - either exact or estimated)
-	Amount   float64 `json:"amount"`   // Always required (NOT NULL in DB)
-	Currency string  `json:"currency"`
+	Amount   money.Amount `json:"amount"` // Always required (NOT NULL in DB)
+	Currency string       `json:"currency"`
 	
 	// Auto-generation flag
 	AutoGenerate bool `json:"auto_generate"` // If true, auto-create movements
@@ -148,6 +150,25 @@ type RecurringMovementTemplate struct {
 	UsedThisMonth bool `json:"used_this_month,omitempty"`
 }
 
+// Occurrences returns the dates the template is scheduled for, from its next
+// scheduled date up to until (inclusive). Nil when it isn't scheduled.
+func (t *RecurringMovementTemplate) Occurrences(until time.Time) []time.Time {
+	if !t.IsActive || t.NextScheduledDate == nil || t.RecurrencePattern == nil {
+		return nil
+	}
+
+	var dates []time.Time
+	for date := *t.NextScheduledDate; !date.After(until); {
+		dates = append(dates, date)
+		next := calculateNextScheduledDate(date, t.RecurrencePattern, t.DayOfMonth, t.DayOfYear)
+		if !next.After(date) {
+			break // ONE_TIME
+		}
+		date = next
+	}
+	return dates
+}
+
 // TemplateParticipant represents a participant in a SPLIT template
 type TemplateParticipant struct {
 	ID                   string    `json:"id"`
@@ -170,7 +191,7 @@ type CreateTemplateInput struct {
 	CategoryID   *string                 `json:"category_id,omitempty"`
 	
 	// Amount - always required (for budget display)
-	Amount float64 `json:"amount"`
+	Amount money.Amount `json:"amount"`
 	
 	// Auto-generation
 	AutoGenerate *bool `json:"auto_generate,omitempty"` // Defaults to false
@@ -238,6 +259,10 @@ func (i *CreateTemplateInput) Validate() error {
 	if err := i.MovementType.Validate(); err != nil {
 		return err
 	}
+	// Transfers aren't budgeted spending
+	if *i.MovementType == movements.TypeTransfer {
+		return ErrTransferNotSupported
+	}
 	
 	// Check if auto-generate is enabled
 	isAutoGenerate := i.AutoGenerate != nil && *i.AutoGenerate
@@ -380,10 +405,10 @@ func (i *CreateTemplateInput) Validate() error {
 
 // UpdateTemplateInput represents input for updating a template
 type UpdateTemplateInput struct {
-	Name        *string  `json:"name,omitempty"`
-	Description *string  `json:"description,omitempty"`
-	IsActive    *bool    `json:"is_active,omitempty"`
-	Amount      *float64 `json:"amount,omitempty"`
+	Name        *string       `json:"name,omitempty"`
+	Description *string       `json:"description,omitempty"`
+	IsActive    *bool         `json:"is_active,omitempty"`
+	Amount      *money.Amount `json:"amount,omitempty"`
 	
 	// Movement type - can be changed
 	MovementType *movements.MovementType `json:"movement_type,omitempty"`
@@ -427,6 +452,9 @@ func (i *UpdateTemplateInput) Validate() error {
 	if i.Amount != nil && *i.Amount <= 0 {
 		return errors.New("amount must be positive")
 	}
+	if i.MovementType != nil && *i.MovementType == movements.TypeTransfer {
+		return ErrTransferNotSupported
+	}
 	return nil
 }
 
@@ -444,7 +472,7 @@ type PreFillData struct {
 	TemplateID   string                  `json:"template_id"`
 	TemplateName string                  `json:"template_name"`
 	MovementType *movements.MovementType `json:"movement_type,omitempty"`
-	Amount       *float64                `json:"amount,omitempty"` // Pre-filled from template
+	Amount       *money.Amount           `json:"amount,omitempty"` // Pre-filled from template
 	
 	PayerUserID    *string `json:"payer_user_id,omitempty"`
 	PayerContactID *string `json:"payer_contact_id,omitempty"`
@@ -469,8 +497,8 @@ type Repository interface {
 	UpdateGenerationTracking(ctx context.Context, id string, lastGenerated, nextScheduled time.Time) error
 	Delete(ctx context.Context, id string) error
 	GetTemplatesUsedInMonth(ctx context.Context, householdID, month string) (map[string]bool, error)
-	DeleteMovementsByTemplateID(ctx context.Context, templateID string) (int64, error)
-	UpdateMovementsByTemplateID(ctx context.Context, templateID string, amount float64, description string) (int64, error)
+	DeleteMovementsByTemplateID(ctx context.Context, templateID, deletedBy string) (int64, error)
+	UpdateMovementsByTemplateID(ctx context.Context, templateID string, amount money.Amount, description string) (int64, error)
 }
 
 // Service defines the interface for recurring movement template business logic
@@ -486,5 +514,5 @@ type Service interface {
 	
 	// CalculateTemplatesSum returns the sum of all template amounts for a category
 	// Used by budgets service to validate that budget >= templates sum
-	CalculateTemplatesSum(ctx context.Context, userID, categoryID string) (float64, error)
+	CalculateTemplatesSum(ctx context.Context, userID, categoryID string) (money.Amount, error)
 }
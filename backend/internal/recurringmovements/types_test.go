@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/blanquicet/conti/backend/internal/money"
 	"github.com/blanquicet/conti/backend/internal/movements"
 )
 
@@ -89,7 +90,7 @@ func TestNullableDateUnmarshalJSON(t *testing.T) {
 
 // TestCreateTemplateInputValidate tests template input validation
 func TestCreateTemplateInputValidate(t *testing.T) {
-	amount := 100000.0
+	amount := money.New(100000)
 	categoryID := "cat-123"
 	payerUserID := "user-123"
 	participantUserID := "participant-123"
@@ -0,0 +1,192 @@
+package rules
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/blanquicet/conti/backend/internal/auth"
+	"github.com/blanquicet/conti/backend/internal/categories"
+	"github.com/blanquicet/conti/backend/internal/households"
+	"github.com/blanquicet/conti/backend/internal/movements"
+	"github.com/blanquicet/conti/backend/internal/paymentmethods"
+)
+
+// Handler handles HTTP requests for categorization rules
+type Handler struct {
+	service    Service
+	authSvc    *auth.Service
+	cookieName string
+	logger     *slog.Logger
+}
+
+// NewHandler creates a new rules handler
+func NewHandler(service Service, authService *auth.Service, cookieName string, logger *slog.Logger) *Handler {
+	return &Handler{
+		service:    service,
+		authSvc:    authService,
+		cookieName: cookieName,
+		logger:     logger,
+	}
+}
+
+// getUserFromSession extracts user from session cookie
+func (h *Handler) getUserFromSession(r *http.Request) (*auth.User, error) {
+	cookie, err := r.Cookie(h.cookieName)
+	if err != nil {
+		return nil, err
+	}
+	return h.authSvc.GetUserBySession(r.Context(), cookie.Value)
+}
+
+// HandleCreate handles POST /rules
+func (h *Handler) HandleCreate(w http.ResponseWriter, r *http.Request) {
+	user, err := h.getUserFromSession(r)
+	if err != nil {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var input RuleInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	rule, err := h.service.Create(r.Context(), user.ID, &input)
+	if err != nil {
+		h.writeError(w, "failed to create rule", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(rule)
+}
+
+// HandleList handles GET /rules
+func (h *Handler) HandleList(w http.ResponseWriter, r *http.Request) {
+	user, err := h.getUserFromSession(r)
+	if err != nil {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	rules, err := h.service.List(r.Context(), user.ID)
+	if err != nil {
+		h.writeError(w, "failed to list rules", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"rules": rules,
+	})
+}
+
+// HandleUpdate handles PUT /rules/{id} (replaces the whole rule)
+func (h *Handler) HandleUpdate(w http.ResponseWriter, r *http.Request) {
+	user, err := h.getUserFromSession(r)
+	if err != nil {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var input RuleInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	rule, err := h.service.Update(r.Context(), user.ID, r.PathValue("id"), &input)
+	if err != nil {
+		h.writeError(w, "failed to update rule", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rule)
+}
+
+// HandleDelete handles DELETE /rules/{id}
+func (h *Handler) HandleDelete(w http.ResponseWriter, r *http.Request) {
+	user, err := h.getUserFromSession(r)
+	if err != nil {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), user.ID, r.PathValue("id")); err != nil {
+		h.writeError(w, "failed to delete rule", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleTest handles POST /rules/test: the movements of the last 3 months an
+// unsaved rule (same body as POST /rules) would match
+func (h *Handler) HandleTest(w http.ResponseWriter, r *http.Request) {
+	user, err := h.getUserFromSession(r)
+	if err != nil {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var input RuleInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.service.Test(r.Context(), user.ID, &input)
+	if err != nil {
+		h.writeError(w, "failed to test rule", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// HandleTestSaved handles GET /rules/{id}/test
+func (h *Handler) HandleTestSaved(w http.ResponseWriter, r *http.Request) {
+	user, err := h.getUserFromSession(r)
+	if err != nil {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	result, err := h.service.TestSaved(r.Context(), user.ID, r.PathValue("id"))
+	if err != nil {
+		h.writeError(w, "failed to test rule", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// writeError maps service errors to HTTP status codes
+func (h *Handler) writeError(w http.ResponseWriter, msg string, err error) {
+	switch {
+	case errors.Is(err, ErrRuleNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, ErrNotAuthorized):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, ErrNameRequired), errors.Is(err, ErrNameTooLong),
+		errors.Is(err, ErrNoConditions), errors.Is(err, ErrNoActions),
+		errors.Is(err, ErrInvalidMatch), errors.Is(err, ErrInvalidPattern),
+		errors.Is(err, ErrInvalidAmountRange), errors.Is(err, ErrPayerConflict),
+		errors.Is(err, ErrInvalidParticipant), errors.Is(err, ErrDebtPaymentType),
+		errors.Is(err, ErrTransferType),
+		errors.Is(err, movements.ErrInvalidMovementType), errors.Is(err, movements.ErrInvalidPercentageSum),
+		errors.Is(err, movements.ErrEventNotFound), errors.Is(err, categories.ErrCategoryNotFound),
+		errors.Is(err, paymentmethods.ErrPaymentMethodNotFound), errors.Is(err, households.ErrContactNotFound):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		h.logger.Error(msg, "error", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	}
+}
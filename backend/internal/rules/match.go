@@ -0,0 +1,142 @@
+package rules
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/blanquicet/conti/backend/internal/money"
+	"github.com/blanquicet/conti/backend/internal/movements"
+)
+
+// Subject is what rule conditions look at, taken from a new or existing movement
+type Subject struct {
+	Description     string
+	Amount          money.Amount
+	PaymentMethodID *string
+	PayerUserID     *string
+	PayerContactID  *string
+}
+
+// SubjectOfInput returns the subject of a movement being created
+func SubjectOfInput(input *movements.CreateMovementInput) *Subject {
+	return &Subject{
+		Description:     input.Description,
+		Amount:          input.Amount,
+		PaymentMethodID: input.PaymentMethodID,
+		PayerUserID:     input.PayerUserID,
+		PayerContactID:  input.PayerContactID,
+	}
+}
+
+// SubjectOfMovement returns the subject of an existing movement
+func SubjectOfMovement(m *movements.Movement) *Subject {
+	return &Subject{
+		Description:     m.Description,
+		Amount:          m.Amount,
+		PaymentMethodID: m.PaymentMethodID,
+		PayerUserID:     m.PayerUserID,
+		PayerContactID:  m.PayerContactID,
+	}
+}
+
+// accents folds the accented letters of Spanish descriptions
+var accents = strings.NewReplacer("á", "a", "é", "e", "í", "i", "ó", "o", "ú", "u", "ü", "u", "ñ", "n")
+
+// fold normalizes text for comparison: lower case, no accents, single spaces
+func fold(s string) string {
+	return accents.Replace(strings.Join(strings.Fields(strings.ToLower(s)), " "))
+}
+
+// Matches reports whether the subject meets every condition of the rule
+func (r *Rule) Matches(s *Subject) bool {
+	if r.DescriptionPattern != nil && !r.matchesDescription(s.Description) {
+		return false
+	}
+	if r.AmountMin != nil && s.Amount < *r.AmountMin {
+		return false
+	}
+	if r.AmountMax != nil && s.Amount > *r.AmountMax {
+		return false
+	}
+	if r.PaymentMethodID != nil && !sameID(r.PaymentMethodID, s.PaymentMethodID) {
+		return false
+	}
+	if r.PayerUserID != nil && !sameID(r.PayerUserID, s.PayerUserID) {
+		return false
+	}
+	if r.PayerContactID != nil && !sameID(r.PayerContactID, s.PayerContactID) {
+		return false
+	}
+	return true
+}
+
+func (r *Rule) matchesDescription(description string) bool {
+	if r.DescriptionMatch == MatchRegex {
+		if r.pattern == nil {
+			re, err := regexp.Compile("(?i)" + *r.DescriptionPattern)
+			if err != nil {
+				return false // Validated on save
+			}
+			r.pattern = re
+		}
+		return r.pattern.MatchString(description)
+	}
+
+	text, pattern := fold(description), fold(*r.DescriptionPattern)
+	switch r.DescriptionMatch {
+	case MatchEquals:
+		return text == pattern
+	case MatchStartsWith:
+		return strings.HasPrefix(text, pattern)
+	default:
+		return strings.Contains(text, pattern)
+	}
+}
+
+func sameID(want, got *string) bool {
+	return got != nil && *got == *want
+}
+
+// apply runs the rules in order over a new movement. Each rule only fills
+// what is still empty, so what the user chose and earlier rules win. The
+// category is only set for types that have one, and participants for SPLIT.
+// Returns the rules that changed something.
+func apply(rules []*Rule, input *movements.CreateMovementInput) []*Rule {
+	subject := SubjectOfInput(input)
+	var applied []*Rule
+	for _, rule := range rules {
+		if !rule.IsActive || !rule.Matches(subject) {
+			continue
+		}
+
+		changed := false
+		if input.Type == "" && rule.SetType != nil {
+			input.Type = *rule.SetType
+			changed = true
+		}
+		if input.Type != movements.TypeDebtPayment && rule.SetCategoryID != nil &&
+			isEmpty(input.CategoryID) && isEmpty(input.Category) {
+			categoryID := *rule.SetCategoryID
+			input.CategoryID = &categoryID
+			changed = true
+		}
+		if input.Type == movements.TypeSplit && len(input.Participants) == 0 && len(rule.SetParticipants) > 0 {
+			input.Participants = append([]movements.ParticipantInput(nil), rule.SetParticipants...)
+			changed = true
+		}
+		if input.EventID == nil && rule.SetEventID != nil {
+			eventID := *rule.SetEventID
+			input.EventID = &eventID
+			changed = true
+		}
+
+		if changed {
+			applied = append(applied, rule)
+		}
+	}
+	return applied
+}
+
+func isEmpty(s *string) bool {
+	return s == nil || *s == ""
+}
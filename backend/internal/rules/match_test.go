@@ -0,0 +1,207 @@
+package rules
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/blanquicet/conti/backend/internal/money"
+	"github.com/blanquicet/conti/backend/internal/movements"
+)
+
+func strPtr(s string) *string { return &s }
+
+func amountPtr(units int64) *money.Amount {
+	a := money.New(units)
+	return &a
+}
+
+func TestMatches(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    Rule
+		subject Subject
+		want    bool
+	}{
+		{
+			name:    "contains ignores case and accents",
+			rule:    Rule{DescriptionPattern: strPtr("cafe"), DescriptionMatch: MatchContains},
+			subject: Subject{Description: "Juan Valdez CAFÉ"},
+			want:    true,
+		},
+		{
+			name:    "equals needs the whole description",
+			rule:    Rule{DescriptionPattern: strPtr("Éxito"), DescriptionMatch: MatchEquals},
+			subject: Subject{Description: "Exito Laureles"},
+			want:    false,
+		},
+		{
+			name:    "starts with",
+			rule:    Rule{DescriptionPattern: strPtr("rappi"), DescriptionMatch: MatchStartsWith},
+			subject: Subject{Description: "RAPPI  Colombia*DL"},
+			want:    true,
+		},
+		{
+			name:    "regex is case-insensitive",
+			rule:    Rule{DescriptionPattern: strPtr(`^uber\s*(eats)?$`), DescriptionMatch: MatchRegex},
+			subject: Subject{Description: "Uber Eats"},
+			want:    true,
+		},
+		{
+			name:    "amount range is inclusive",
+			rule:    Rule{AmountMin: amountPtr(10000), AmountMax: amountPtr(50000)},
+			subject: Subject{Amount: money.New(50000)},
+			want:    true,
+		},
+		{
+			name:    "amount below range",
+			rule:    Rule{AmountMin: amountPtr(10000)},
+			subject: Subject{Amount: money.New(9999)},
+			want:    false,
+		},
+		{
+			name:    "payer must match",
+			rule:    Rule{PayerUserID: strPtr("ana")},
+			subject: Subject{PayerContactID: strPtr("ana")},
+			want:    false,
+		},
+		{
+			name: "all conditions must match",
+			rule: Rule{
+				DescriptionPattern: strPtr("netflix"),
+				DescriptionMatch:   MatchContains,
+				PaymentMethodID:    strPtr("visa"),
+			},
+			subject: Subject{Description: "NETFLIX.COM", PaymentMethodID: strPtr("master")},
+			want:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rule.Matches(&tt.subject); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApply_FillsOnlyEmptyFields(t *testing.T) {
+	split := movements.TypeSplit
+	rules := []*Rule{
+		{
+			ID:                 "r1",
+			IsActive:           true,
+			DescriptionPattern: strPtr("mercado"),
+			SetCategoryID:      strPtr("cat-mercado"),
+		},
+		{
+			ID:                 "r2",
+			IsActive:           true,
+			DescriptionPattern: strPtr("mercado"),
+			SetType:            &split,
+			SetCategoryID:      strPtr("cat-other"),
+			SetParticipants: []movements.ParticipantInput{
+				{ParticipantUserID: strPtr("ana"), Percentage: 0.5},
+				{ParticipantContactID: strPtr("luis"), Percentage: 0.5},
+			},
+			SetEventID: strPtr("event-1"),
+		},
+		{
+			ID:                 "r3",
+			IsActive:           false,
+			DescriptionPattern: strPtr("mercado"),
+			SetEventID:         strPtr("event-2"),
+		},
+	}
+
+	input := &movements.CreateMovementInput{Description: "Mercado Éxito", Amount: money.New(120000)}
+	applied := apply(rules, input)
+
+	if len(applied) != 2 {
+		t.Fatalf("applied %d rules, want 2", len(applied))
+	}
+	if input.Type != movements.TypeSplit {
+		t.Errorf("Type = %q, want SPLIT", input.Type)
+	}
+	if input.CategoryID == nil || *input.CategoryID != "cat-mercado" {
+		t.Errorf("CategoryID = %v, want the first rule's category", input.CategoryID)
+	}
+	if len(input.Participants) != 2 {
+		t.Errorf("got %d participants, want 2", len(input.Participants))
+	}
+	if input.EventID == nil || *input.EventID != "event-1" {
+		t.Errorf("EventID = %v, want event-1 (inactive rules don't run)", input.EventID)
+	}
+
+	// What the user chose is kept
+	input = &movements.CreateMovementInput{
+		Type:        movements.TypeHousehold,
+		Description: "mercado",
+		Category:    strPtr("Restaurantes"),
+	}
+	apply(rules, input)
+	if input.Type != movements.TypeHousehold || input.CategoryID != nil || len(input.Participants) != 0 {
+		t.Errorf("user fields were overwritten: %+v", input)
+	}
+}
+
+func TestRuleInputValidate(t *testing.T) {
+	debt := movements.TypeDebtPayment
+	transfer := movements.TypeTransfer
+	tests := []struct {
+		name    string
+		input   RuleInput
+		wantErr error
+	}{
+		{
+			name:    "no conditions",
+			input:   RuleInput{Name: "r", SetCategoryID: strPtr("c")},
+			wantErr: ErrNoConditions,
+		},
+		{
+			name:    "no actions",
+			input:   RuleInput{Name: "r", DescriptionPattern: strPtr("x")},
+			wantErr: ErrNoActions,
+		},
+		{
+			name:    "bad regex",
+			input:   RuleInput{Name: "r", DescriptionPattern: strPtr("("), DescriptionMatch: MatchRegex, SetCategoryID: strPtr("c")},
+			wantErr: ErrInvalidPattern,
+		},
+		{
+			name:    "inverted amount range",
+			input:   RuleInput{Name: "r", AmountMin: amountPtr(10), AmountMax: amountPtr(5), SetCategoryID: strPtr("c")},
+			wantErr: ErrInvalidAmountRange,
+		},
+		{
+			name:    "debt payment type",
+			input:   RuleInput{Name: "r", DescriptionPattern: strPtr("x"), SetType: &debt},
+			wantErr: ErrDebtPaymentType,
+		},
+		{
+			name:    "transfer type",
+			input:   RuleInput{Name: "r", DescriptionPattern: strPtr("x"), SetType: &transfer},
+			wantErr: ErrTransferType,
+		},
+		{
+			name: "participants must add up to 100%",
+			input: RuleInput{Name: "r", DescriptionPattern: strPtr("x"), SetParticipants: []movements.ParticipantInput{
+				{ParticipantUserID: strPtr("ana"), Percentage: 0.5},
+			}},
+			wantErr: movements.ErrInvalidPercentageSum,
+		},
+		{
+			name:  "valid",
+			input: RuleInput{Name: "r", DescriptionPattern: strPtr("x"), SetCategoryID: strPtr("c")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.input.Validate()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
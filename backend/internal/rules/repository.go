@@ -0,0 +1,172 @@
+package rules
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// repository implements Repository using PostgreSQL
+type repository struct {
+	pool *pgxpool.Pool
+}
+
+// NewRepository creates a new rules repository
+func NewRepository(pool *pgxpool.Pool) Repository {
+	return &repository{pool: pool}
+}
+
+const ruleColumns = `
+	id, household_id, name, priority, is_active,
+	description_pattern, description_match, amount_min, amount_max,
+	payment_method_id, payer_user_id, payer_contact_id,
+	set_type, set_category_id, set_participants, set_event_id,
+	created_by, created_at, updated_at
+`
+
+// scanRule scans a row selected with ruleColumns
+func scanRule(row pgx.Row) (*Rule, error) {
+	var rule Rule
+	var participants []byte
+	err := row.Scan(
+		&rule.ID,
+		&rule.HouseholdID,
+		&rule.Name,
+		&rule.Priority,
+		&rule.IsActive,
+		&rule.DescriptionPattern,
+		&rule.DescriptionMatch,
+		&rule.AmountMin,
+		&rule.AmountMax,
+		&rule.PaymentMethodID,
+		&rule.PayerUserID,
+		&rule.PayerContactID,
+		&rule.SetType,
+		&rule.SetCategoryID,
+		&participants,
+		&rule.SetEventID,
+		&rule.CreatedBy,
+		&rule.CreatedAt,
+		&rule.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if participants != nil {
+		if err := json.Unmarshal(participants, &rule.SetParticipants); err != nil {
+			return nil, err
+		}
+	}
+	return &rule, nil
+}
+
+// participantsJSON encodes the participants action (NULL when there are none)
+func participantsJSON(input *RuleInput) ([]byte, error) {
+	if len(input.SetParticipants) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(input.SetParticipants)
+}
+
+// Create creates a new rule
+func (r *repository) Create(ctx context.Context, householdID, createdBy string, input *RuleInput) (*Rule, error) {
+	participants, err := participantsJSON(input)
+	if err != nil {
+		return nil, err
+	}
+	values := input.toRule(householdID)
+
+	return scanRule(r.pool.QueryRow(ctx, `
+		INSERT INTO movement_rules (
+			household_id, name, priority, is_active,
+			description_pattern, description_match, amount_min, amount_max,
+			payment_method_id, payer_user_id, payer_contact_id,
+			set_type, set_category_id, set_participants, set_event_id,
+			created_by
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+		RETURNING `+ruleColumns,
+		householdID, values.Name, values.Priority, values.IsActive,
+		values.DescriptionPattern, values.DescriptionMatch, values.AmountMin, values.AmountMax,
+		values.PaymentMethodID, values.PayerUserID, values.PayerContactID,
+		values.SetType, values.SetCategoryID, participants, values.SetEventID,
+		createdBy,
+	))
+}
+
+// GetByID retrieves a rule by ID
+func (r *repository) GetByID(ctx context.Context, id string) (*Rule, error) {
+	rule, err := scanRule(r.pool.QueryRow(ctx, `SELECT `+ruleColumns+` FROM movement_rules WHERE id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrRuleNotFound
+	}
+	return rule, err
+}
+
+// ListByHousehold lists the rules of a household in the order they run
+func (r *repository) ListByHousehold(ctx context.Context, householdID string, activeOnly bool) ([]*Rule, error) {
+	query := `SELECT ` + ruleColumns + ` FROM movement_rules WHERE household_id = $1`
+	if activeOnly {
+		query += " AND is_active"
+	}
+	query += " ORDER BY priority ASC, created_at ASC"
+
+	rows, err := r.pool.Query(ctx, query, householdID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rules := make([]*Rule, 0)
+	for rows.Next() {
+		rule, err := scanRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, rows.Err()
+}
+
+// Update replaces every field of a rule
+func (r *repository) Update(ctx context.Context, id string, input *RuleInput) (*Rule, error) {
+	participants, err := participantsJSON(input)
+	if err != nil {
+		return nil, err
+	}
+	values := input.toRule("")
+
+	rule, err := scanRule(r.pool.QueryRow(ctx, `
+		UPDATE movement_rules SET
+			name = $2, priority = $3, is_active = $4,
+			description_pattern = $5, description_match = $6, amount_min = $7, amount_max = $8,
+			payment_method_id = $9, payer_user_id = $10, payer_contact_id = $11,
+			set_type = $12, set_category_id = $13, set_participants = $14, set_event_id = $15,
+			updated_at = NOW()
+		WHERE id = $1
+		RETURNING `+ruleColumns,
+		id, values.Name, values.Priority, values.IsActive,
+		values.DescriptionPattern, values.DescriptionMatch, values.AmountMin, values.AmountMax,
+		values.PaymentMethodID, values.PayerUserID, values.PayerContactID,
+		values.SetType, values.SetCategoryID, participants, values.SetEventID,
+	))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrRuleNotFound
+	}
+	return rule, err
+}
+
+// Delete deletes a rule
+func (r *repository) Delete(ctx context.Context, id string) error {
+	result, err := r.pool.Exec(ctx, `DELETE FROM movement_rules WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrRuleNotFound
+	}
+	return nil
+}
@@ -0,0 +1,320 @@
+package rules
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/blanquicet/conti/backend/internal/audit"
+	"github.com/blanquicet/conti/backend/internal/categories"
+	"github.com/blanquicet/conti/backend/internal/households"
+	"github.com/blanquicet/conti/backend/internal/movements"
+	"github.com/blanquicet/conti/backend/internal/paymentmethods"
+)
+
+// testWindow is how far back a rule test looks
+const testWindow = 3 // months
+
+// service implements Service interface
+type service struct {
+	repo              Repository
+	householdsRepo    households.HouseholdRepository
+	categoriesRepo    categories.Repository
+	paymentMethodRepo paymentmethods.Repository
+	movementsRepo     movements.Repository
+	auditService      audit.Service
+	logger            *slog.Logger
+}
+
+// NewService creates a new rules service
+func NewService(
+	repo Repository,
+	householdsRepo households.HouseholdRepository,
+	categoriesRepo categories.Repository,
+	paymentMethodRepo paymentmethods.Repository,
+	movementsRepo movements.Repository,
+	auditService audit.Service,
+	logger *slog.Logger,
+) Service {
+	return &service{
+		repo:              repo,
+		householdsRepo:    householdsRepo,
+		categoriesRepo:    categoriesRepo,
+		paymentMethodRepo: paymentMethodRepo,
+		movementsRepo:     movementsRepo,
+		auditService:      auditService,
+		logger:            logger,
+	}
+}
+
+// Create creates a rule for the user's household
+func (s *service) Create(ctx context.Context, userID string, input *RuleInput) (*Rule, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	householdID, err := s.householdsRepo.GetUserHouseholdID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validateReferences(ctx, householdID, input); err != nil {
+		return nil, err
+	}
+
+	rule, err := s.repo.Create(ctx, householdID, userID, input)
+	if err != nil {
+		s.auditService.LogAsync(ctx, &audit.LogInput{
+			UserID:       audit.StringPtr(userID),
+			Action:       audit.ActionRuleCreated,
+			ResourceType: "rule",
+			HouseholdID:  audit.StringPtr(householdID),
+			Success:      false,
+			ErrorMessage: audit.StringPtr(err.Error()),
+		})
+		return nil, err
+	}
+
+	s.auditService.LogAsync(ctx, &audit.LogInput{
+		UserID:       audit.StringPtr(userID),
+		Action:       audit.ActionRuleCreated,
+		ResourceType: "rule",
+		ResourceID:   audit.StringPtr(rule.ID),
+		HouseholdID:  audit.StringPtr(householdID),
+		NewValues:    audit.StructToMap(rule),
+		Success:      true,
+	})
+
+	return rule, nil
+}
+
+// List lists the rules of the user's household in the order they run
+func (s *service) List(ctx context.Context, userID string) ([]*Rule, error) {
+	householdID, err := s.householdsRepo.GetUserHouseholdID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.ListByHousehold(ctx, householdID, false)
+}
+
+// Update replaces a rule of the user's household
+func (s *service) Update(ctx context.Context, userID, id string, input *RuleInput) (*Rule, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	existing, householdID, err := s.getAuthorized(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validateReferences(ctx, householdID, input); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.repo.Update(ctx, id, input)
+	if err != nil {
+		s.auditService.LogAsync(ctx, &audit.LogInput{
+			UserID:       audit.StringPtr(userID),
+			Action:       audit.ActionRuleUpdated,
+			ResourceType: "rule",
+			ResourceID:   audit.StringPtr(id),
+			HouseholdID:  audit.StringPtr(householdID),
+			OldValues:    audit.StructToMap(existing),
+			Success:      false,
+			ErrorMessage: audit.StringPtr(err.Error()),
+		})
+		return nil, err
+	}
+
+	s.auditService.LogAsync(ctx, &audit.LogInput{
+		UserID:       audit.StringPtr(userID),
+		Action:       audit.ActionRuleUpdated,
+		ResourceType: "rule",
+		ResourceID:   audit.StringPtr(id),
+		HouseholdID:  audit.StringPtr(householdID),
+		OldValues:    audit.StructToMap(existing),
+		NewValues:    audit.StructToMap(updated),
+		Success:      true,
+	})
+
+	return updated, nil
+}
+
+// Delete deletes a rule of the user's household
+func (s *service) Delete(ctx context.Context, userID, id string) error {
+	existing, householdID, err := s.getAuthorized(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(ctx, id); err != nil {
+		s.auditService.LogAsync(ctx, &audit.LogInput{
+			UserID:       audit.StringPtr(userID),
+			Action:       audit.ActionRuleDeleted,
+			ResourceType: "rule",
+			ResourceID:   audit.StringPtr(id),
+			HouseholdID:  audit.StringPtr(householdID),
+			OldValues:    audit.StructToMap(existing),
+			Success:      false,
+			ErrorMessage: audit.StringPtr(err.Error()),
+		})
+		return err
+	}
+
+	s.auditService.LogAsync(ctx, &audit.LogInput{
+		UserID:       audit.StringPtr(userID),
+		Action:       audit.ActionRuleDeleted,
+		ResourceType: "rule",
+		ResourceID:   audit.StringPtr(id),
+		HouseholdID:  audit.StringPtr(householdID),
+		OldValues:    audit.StructToMap(existing),
+		Success:      true,
+	})
+
+	return nil
+}
+
+// Test shows which movements of the last 3 months an unsaved rule matches
+func (s *service) Test(ctx context.Context, userID string, input *RuleInput) (*TestResult, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	householdID, err := s.householdsRepo.GetUserHouseholdID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.test(ctx, input.toRule(householdID))
+}
+
+// TestSaved shows which movements of the last 3 months a saved rule matches
+func (s *service) TestSaved(ctx context.Context, userID, id string) (*TestResult, error) {
+	rule, _, err := s.getAuthorized(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.test(ctx, rule)
+}
+
+func (s *service) test(ctx context.Context, rule *Rule) (*TestResult, error) {
+	to := time.Now()
+	from := to.AddDate(0, -testWindow, 0)
+	existing, err := s.movementsRepo.ListByHousehold(ctx, rule.HouseholdID, &movements.ListMovementsFilters{
+		StartDate: &from,
+		EndDate:   &to,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &TestResult{
+		From:      from,
+		To:        to,
+		Scanned:   len(existing),
+		Movements: make([]*movements.Movement, 0),
+	}
+	for _, m := range existing {
+		if rule.Matches(SubjectOfMovement(m)) {
+			result.Movements = append(result.Movements, m)
+		}
+	}
+	result.Matched = len(result.Movements)
+	return result, nil
+}
+
+// Apply fills in a new movement of the household from its active rules
+func (s *service) Apply(ctx context.Context, householdID string, input *movements.CreateMovementInput) error {
+	rules, err := s.repo.ListByHousehold(ctx, householdID, true)
+	if err != nil {
+		return err
+	}
+	hadEvent := input.EventID != nil
+	for _, rule := range apply(rules, input) {
+		s.logger.Debug("movement rule applied", "rule_id", rule.ID, "rule", rule.Name, "household_id", householdID)
+	}
+
+	// A rule's event may have been closed since; the movement goes without it
+	if !hadEvent && input.EventID != nil {
+		_, status, err := s.movementsRepo.GetEventStatus(ctx, *input.EventID)
+		if err != nil && !errors.Is(err, movements.ErrEventNotFound) {
+			return err
+		}
+		if status != "open" {
+			input.EventID = nil
+		}
+	}
+	return nil
+}
+
+// getAuthorized loads a rule and verifies it belongs to the user's household
+func (s *service) getAuthorized(ctx context.Context, userID, id string) (*Rule, string, error) {
+	householdID, err := s.householdsRepo.GetUserHouseholdID(ctx, userID)
+	if err != nil {
+		return nil, "", err
+	}
+	rule, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, "", err
+	}
+	if rule.HouseholdID != householdID {
+		return nil, "", ErrNotAuthorized
+	}
+	return rule, householdID, nil
+}
+
+// validateReferences checks that everything the rule points to belongs to the household
+func (s *service) validateReferences(ctx context.Context, householdID string, input *RuleInput) error {
+	if input.PaymentMethodID != nil {
+		pm, err := s.paymentMethodRepo.GetByID(ctx, *input.PaymentMethodID)
+		if err != nil {
+			return err
+		}
+		if pm.HouseholdID != householdID {
+			return ErrNotAuthorized
+		}
+	}
+
+	if input.SetCategoryID != nil {
+		category, err := s.categoriesRepo.GetByID(ctx, *input.SetCategoryID)
+		if err != nil {
+			return err
+		}
+		if category.HouseholdID != householdID {
+			return ErrNotAuthorized
+		}
+	}
+
+	if input.SetEventID != nil {
+		eventHouseholdID, _, err := s.movementsRepo.GetEventStatus(ctx, *input.SetEventID)
+		if err != nil {
+			return err
+		}
+		if eventHouseholdID != householdID {
+			return ErrNotAuthorized
+		}
+	}
+
+	people := []movements.ParticipantInput{{ParticipantUserID: input.PayerUserID, ParticipantContactID: input.PayerContactID}}
+	people = append(people, input.SetParticipants...)
+	for _, p := range people {
+		switch {
+		case p.ParticipantUserID != nil:
+			isMember, err := s.householdsRepo.IsUserMember(ctx, householdID, *p.ParticipantUserID)
+			if err != nil {
+				return err
+			}
+			if !isMember {
+				return ErrNotAuthorized
+			}
+		case p.ParticipantContactID != nil:
+			contact, err := s.householdsRepo.GetContact(ctx, *p.ParticipantContactID)
+			if err != nil {
+				return err
+			}
+			if contact.HouseholdID != householdID {
+				return ErrNotAuthorized
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,228 @@
+package rules
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"time"
+
+	"github.com/blanquicet/conti/backend/internal/money"
+	"github.com/blanquicet/conti/backend/internal/movements"
+)
+
+// Errors for categorization rules
+var (
+	ErrRuleNotFound       = errors.New("rule not found")
+	ErrNotAuthorized      = errors.New("not authorized")
+	ErrNameRequired       = errors.New("rule name is required")
+	ErrNameTooLong        = errors.New("rule name must be 100 characters or less")
+	ErrNoConditions       = errors.New("rule needs at least one condition")
+	ErrNoActions          = errors.New("rule needs at least one action")
+	ErrInvalidMatch       = errors.New("invalid description_match, use CONTAINS, EQUALS, STARTS_WITH or REGEX")
+	ErrInvalidPattern     = errors.New("invalid description_pattern")
+	ErrInvalidAmountRange = errors.New("amount_min must be less than or equal to amount_max")
+	ErrPayerConflict      = errors.New("cannot specify both payer_user_id and payer_contact_id")
+	ErrInvalidParticipant = errors.New("participant must have either user_id or contact_id and a percentage (no amount) between 0 and 1")
+	ErrDebtPaymentType    = errors.New("rules cannot set type DEBT_PAYMENT")
+	ErrTransferType       = errors.New("rules cannot set type TRANSFER")
+)
+
+// MatchType is how a rule compares its pattern with the description
+type MatchType string
+
+const (
+	MatchContains   MatchType = "CONTAINS"    // Pattern appears anywhere (default)
+	MatchEquals     MatchType = "EQUALS"      // Whole description
+	MatchStartsWith MatchType = "STARTS_WITH" // Description prefix
+	MatchRegex      MatchType = "REGEX"       // Go regular expression, case-insensitive
+)
+
+// Validate checks if the match type is valid
+func (m MatchType) Validate() error {
+	switch m {
+	case MatchContains, MatchEquals, MatchStartsWith, MatchRegex:
+		return nil
+	default:
+		return ErrInvalidMatch
+	}
+}
+
+// Rule fills in the fields a new movement leaves empty when the movement
+// matches all of its conditions. Text matches ignore case and accents.
+type Rule struct {
+	ID          string `json:"id"`
+	HouseholdID string `json:"household_id"`
+	Name        string `json:"name"`
+	Priority    int    `json:"priority"` // Lower runs first
+	IsActive    bool   `json:"is_active"`
+
+	// Conditions
+	DescriptionPattern *string       `json:"description_pattern,omitempty"`
+	DescriptionMatch   MatchType     `json:"description_match"`
+	AmountMin          *money.Amount `json:"amount_min,omitempty"` // Inclusive, in the movement currency
+	AmountMax          *money.Amount `json:"amount_max,omitempty"` // Inclusive
+	PaymentMethodID    *string       `json:"payment_method_id,omitempty"`
+	PayerUserID        *string       `json:"payer_user_id,omitempty"`
+	PayerContactID     *string       `json:"payer_contact_id,omitempty"`
+
+	// Actions
+	SetType         *movements.MovementType      `json:"set_type,omitempty"`
+	SetCategoryID   *string                      `json:"set_category_id,omitempty"`
+	SetParticipants []movements.ParticipantInput `json:"set_participants,omitempty"`
+	SetEventID      *string                      `json:"set_event_id,omitempty"`
+
+	CreatedBy *string   `json:"created_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	pattern *regexp.Regexp // Compiled REGEX pattern, see matchesDescription
+}
+
+// RuleInput is a rule as sent by the client (create, replace and test)
+type RuleInput struct {
+	Name     string `json:"name"`
+	Priority *int   `json:"priority,omitempty"`  // Defaults to 100
+	IsActive *bool  `json:"is_active,omitempty"` // Defaults to true
+
+	DescriptionPattern *string       `json:"description_pattern,omitempty"`
+	DescriptionMatch   MatchType     `json:"description_match,omitempty"` // Defaults to CONTAINS
+	AmountMin          *money.Amount `json:"amount_min,omitempty"`
+	AmountMax          *money.Amount `json:"amount_max,omitempty"`
+	PaymentMethodID    *string       `json:"payment_method_id,omitempty"`
+	PayerUserID        *string       `json:"payer_user_id,omitempty"`
+	PayerContactID     *string       `json:"payer_contact_id,omitempty"`
+
+	SetType         *movements.MovementType      `json:"set_type,omitempty"`
+	SetCategoryID   *string                      `json:"set_category_id,omitempty"`
+	SetParticipants []movements.ParticipantInput `json:"set_participants,omitempty"`
+	SetEventID      *string                      `json:"set_event_id,omitempty"`
+}
+
+// Validate validates the rule input and fills in defaults
+func (i *RuleInput) Validate() error {
+	if i.Name == "" {
+		return ErrNameRequired
+	}
+	if len(i.Name) > 100 {
+		return ErrNameTooLong
+	}
+
+	if i.DescriptionPattern != nil && *i.DescriptionPattern == "" {
+		i.DescriptionPattern = nil
+	}
+	if i.DescriptionMatch == "" {
+		i.DescriptionMatch = MatchContains
+	}
+	if err := i.DescriptionMatch.Validate(); err != nil {
+		return err
+	}
+	if i.DescriptionPattern != nil && i.DescriptionMatch == MatchRegex {
+		if _, err := regexp.Compile(*i.DescriptionPattern); err != nil {
+			return ErrInvalidPattern
+		}
+	}
+	if i.AmountMin != nil && i.AmountMax != nil && *i.AmountMin > *i.AmountMax {
+		return ErrInvalidAmountRange
+	}
+	if i.PayerUserID != nil && i.PayerContactID != nil {
+		return ErrPayerConflict
+	}
+	if i.DescriptionPattern == nil && i.AmountMin == nil && i.AmountMax == nil &&
+		i.PaymentMethodID == nil && i.PayerUserID == nil && i.PayerContactID == nil {
+		return ErrNoConditions
+	}
+
+	if i.SetType != nil {
+		if err := i.SetType.Validate(); err != nil {
+			return err
+		}
+		// A debt payment needs a counterparty, which a rule can't pick
+		if *i.SetType == movements.TypeDebtPayment {
+			return ErrDebtPaymentType
+		}
+		// Nor the accounts of a transfer
+		if *i.SetType == movements.TypeTransfer {
+			return ErrTransferType
+		}
+	}
+	// Participants split by percentage (movement amounts vary)
+	totalPercentage := 0.0
+	for _, p := range i.SetParticipants {
+		hasUser := p.ParticipantUserID != nil && *p.ParticipantUserID != ""
+		hasContact := p.ParticipantContactID != nil && *p.ParticipantContactID != ""
+		if hasUser == hasContact || p.Amount != nil || p.Percentage <= 0 || p.Percentage > 1 {
+			return ErrInvalidParticipant
+		}
+		totalPercentage += p.Percentage
+	}
+	if len(i.SetParticipants) > 0 && (totalPercentage < 0.9999 || totalPercentage > 1.0001) {
+		return movements.ErrInvalidPercentageSum
+	}
+	if i.SetType == nil && i.SetCategoryID == nil && len(i.SetParticipants) == 0 && i.SetEventID == nil {
+		return ErrNoActions
+	}
+	return nil
+}
+
+// toRule builds an unsaved rule from the input (for testing it)
+func (i *RuleInput) toRule(householdID string) *Rule {
+	rule := &Rule{
+		HouseholdID:        householdID,
+		Name:               i.Name,
+		Priority:           100,
+		IsActive:           true,
+		DescriptionPattern: i.DescriptionPattern,
+		DescriptionMatch:   i.DescriptionMatch,
+		AmountMin:          i.AmountMin,
+		AmountMax:          i.AmountMax,
+		PaymentMethodID:    i.PaymentMethodID,
+		PayerUserID:        i.PayerUserID,
+		PayerContactID:     i.PayerContactID,
+		SetType:            i.SetType,
+		SetCategoryID:      i.SetCategoryID,
+		SetParticipants:    i.SetParticipants,
+		SetEventID:         i.SetEventID,
+	}
+	if i.Priority != nil {
+		rule.Priority = *i.Priority
+	}
+	if i.IsActive != nil {
+		rule.IsActive = *i.IsActive
+	}
+	return rule
+}
+
+// TestResult lists the existing movements a rule would have matched
+type TestResult struct {
+	From      time.Time             `json:"from"`
+	To        time.Time             `json:"to"`
+	Scanned   int                   `json:"scanned"`
+	Matched   int                   `json:"matched"`
+	Movements []*movements.Movement `json:"movements"`
+}
+
+// Repository defines the interface for rule data access
+type Repository interface {
+	Create(ctx context.Context, householdID, createdBy string, input *RuleInput) (*Rule, error)
+	GetByID(ctx context.Context, id string) (*Rule, error)
+	// ListByHousehold returns rules in the order they run (priority, then age)
+	ListByHousehold(ctx context.Context, householdID string, activeOnly bool) ([]*Rule, error)
+	Update(ctx context.Context, id string, input *RuleInput) (*Rule, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// Service defines the interface for rule business logic
+type Service interface {
+	Create(ctx context.Context, userID string, input *RuleInput) (*Rule, error)
+	List(ctx context.Context, userID string) ([]*Rule, error)
+	// Update replaces every field of the rule
+	Update(ctx context.Context, userID, id string, input *RuleInput) (*Rule, error)
+	Delete(ctx context.Context, userID, id string) error
+	// Test runs a rule (saved or not) over the last 3 months of movements
+	Test(ctx context.Context, userID string, input *RuleInput) (*TestResult, error)
+	TestSaved(ctx context.Context, userID, id string) (*TestResult, error)
+
+	// Apply fills in a new movement from the household's active rules
+	// (implements movements.RuleApplier)
+	Apply(ctx context.Context, householdID string, input *movements.CreateMovementInput) error
+}
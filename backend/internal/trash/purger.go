@@ -0,0 +1,80 @@
+// Package trash purges deleted movements and income once they have been in
+// the household trash longer than the retention period.
+package trash
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Purgeable is a kind of entry with a trash (implemented by the movements and
+// income services)
+type Purgeable interface {
+	// PurgeTrash permanently deletes the entries moved to the trash before a
+	// date and returns how many there were
+	PurgeTrash(ctx context.Context, before time.Time) (int, error)
+}
+
+// Purger periodically empties the trash of old entries
+type Purger struct {
+	targets   map[string]Purgeable // By name, for logs
+	retention time.Duration
+	logger    *slog.Logger
+	stopChan  chan struct{}
+}
+
+// NewPurger creates a purger that keeps deleted entries for retention
+func NewPurger(targets map[string]Purgeable, retention time.Duration, logger *slog.Logger) *Purger {
+	return &Purger{
+		targets:   targets,
+		retention: retention,
+		logger:    logger,
+		stopChan:  make(chan struct{}),
+	}
+}
+
+// Start begins the purge loop (runs once a day)
+func (p *Purger) Start(ctx context.Context) {
+	ticker := time.NewTicker(24 * time.Hour)
+	defer ticker.Stop()
+
+	p.logger.Info("trash purger started", "retention", p.retention.String())
+
+	// Run immediately on start
+	p.purge(ctx, time.Now())
+
+	for {
+		select {
+		case <-ticker.C:
+			p.purge(ctx, time.Now())
+		case <-p.stopChan:
+			p.logger.Info("trash purger stopped")
+			return
+		case <-ctx.Done():
+			p.logger.Info("trash purger context canceled")
+			return
+		}
+	}
+}
+
+// Stop stops the purger
+func (p *Purger) Stop() {
+	close(p.stopChan)
+}
+
+// purge deletes what was moved to the trash more than the retention period
+// before now. A failing target doesn't stop the others.
+func (p *Purger) purge(ctx context.Context, now time.Time) {
+	before := now.Add(-p.retention)
+	for name, target := range p.targets {
+		count, err := target.PurgeTrash(ctx, before)
+		if err != nil {
+			p.logger.Error("failed to purge trash", "target", name, "error", err)
+			continue
+		}
+		if count > 0 {
+			p.logger.Info("purged trash", "target", name, "count", count, "deleted_before", before.Format(time.RFC3339))
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package trash
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+type fakeTrash struct {
+	before time.Time
+	calls  int
+	err    error
+}
+
+func (f *fakeTrash) PurgeTrash(ctx context.Context, before time.Time) (int, error) {
+	f.calls++
+	f.before = before
+	return 2, f.err
+}
+
+func TestPurge(t *testing.T) {
+	failing := &fakeTrash{err: errors.New("boom")}
+	movements := &fakeTrash{}
+	income := &fakeTrash{}
+	p := NewPurger(map[string]Purgeable{
+		"failing":   failing,
+		"movements": movements,
+		"income":    income,
+	}, 30*24*time.Hour, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	now := time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC)
+	p.purge(context.Background(), now)
+
+	want := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for name, target := range map[string]*fakeTrash{"failing": failing, "movements": movements, "income": income} {
+		if target.calls != 1 {
+			t.Errorf("%s purged %d times, want once", name, target.calls)
+		}
+		if !target.before.Equal(want) {
+			t.Errorf("%s purged before %v, want %v", name, target.before, want)
+		}
+	}
+}
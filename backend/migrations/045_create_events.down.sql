@@ -0,0 +1,7 @@
+-- Rollback: Drop events tables and movements.event_id
+
+DROP INDEX IF EXISTS idx_movements_event;
+ALTER TABLE movements DROP COLUMN IF EXISTS event_id;
+
+DROP TABLE IF EXISTS event_participants;
+DROP TABLE IF EXISTS events;
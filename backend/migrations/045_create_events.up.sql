@@ -0,0 +1,57 @@
+-- Migration: Create events and event_participants tables
+-- Events group movements that belong to a temporary shared context
+-- (trips, dinners, parties) so they can be summarized and closed together.
+
+CREATE TABLE events (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  household_id UUID NOT NULL REFERENCES households(id) ON DELETE CASCADE,
+  name VARCHAR(100) NOT NULL,
+  description TEXT,
+
+  -- Date range covered by the event (inclusive)
+  start_date DATE NOT NULL,
+  end_date DATE NOT NULL,
+
+  -- Lifecycle: open events accept movements, closed events are frozen
+  status VARCHAR(10) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'closed')),
+  closed_at TIMESTAMPTZ,
+
+  -- Metadata
+  created_by UUID NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+  CONSTRAINT events_date_range CHECK (end_date >= start_date)
+);
+
+CREATE INDEX idx_events_household ON events(household_id);
+CREATE INDEX idx_events_household_dates ON events(household_id, start_date DESC);
+
+-- Participants: household members or contacts (exactly one)
+CREATE TABLE event_participants (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  event_id UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+  participant_user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+  participant_contact_id UUID REFERENCES contacts(id) ON DELETE CASCADE,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+  CONSTRAINT event_participant_exactly_one CHECK (
+    (participant_user_id IS NOT NULL AND participant_contact_id IS NULL) OR
+    (participant_user_id IS NULL AND participant_contact_id IS NOT NULL)
+  )
+);
+
+CREATE INDEX idx_event_participants_event ON event_participants(event_id);
+CREATE UNIQUE INDEX idx_event_participants_user
+  ON event_participants(event_id, participant_user_id) WHERE participant_user_id IS NOT NULL;
+CREATE UNIQUE INDEX idx_event_participants_contact
+  ON event_participants(event_id, participant_contact_id) WHERE participant_contact_id IS NOT NULL;
+
+-- Link movements to an event (optional)
+ALTER TABLE movements ADD COLUMN event_id UUID REFERENCES events(id) ON DELETE SET NULL;
+CREATE INDEX idx_movements_event ON movements(event_id) WHERE event_id IS NOT NULL;
+
+COMMENT ON TABLE events IS
+  'Temporary shared contexts (trips, dinners) that group movements for a consolidated summary.';
+COMMENT ON COLUMN movements.event_id IS
+  'Optional event this movement belongs to. Set to NULL when the event is deleted.';
@@ -0,0 +1,3 @@
+-- Rollback: PostgreSQL cannot drop values from an enum type.
+-- The event audit actions are left in place; they are unused once events are removed.
+SELECT 1;
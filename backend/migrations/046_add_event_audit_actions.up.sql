@@ -0,0 +1,6 @@
+-- Add audit actions for events
+
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'EVENT_CREATED';
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'EVENT_UPDATED';
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'EVENT_DELETED';
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'EVENT_CLOSED';
@@ -0,0 +1,11 @@
+-- Rollback: Remove two-party confirmation from movements
+
+DROP INDEX IF EXISTS idx_movements_confirmation_pending;
+ALTER TABLE movements DROP CONSTRAINT IF EXISTS movements_confirmation_only_debt_payment;
+
+ALTER TABLE movements DROP COLUMN IF EXISTS dispute_reason;
+ALTER TABLE movements DROP COLUMN IF EXISTS confirmation_responded_at;
+ALTER TABLE movements DROP COLUMN IF EXISTS confirmation_responded_by;
+ALTER TABLE movements DROP COLUMN IF EXISTS confirmation_status;
+
+DROP TYPE IF EXISTS movement_confirmation_status;
@@ -0,0 +1,22 @@
+-- Add two-party confirmation to debt payments
+-- A DEBT_PAYMENT to a linked contact (a contact with their own account) starts
+-- as PENDING and only clears the debt once the receiver confirms it from their
+-- own household. NULL means no confirmation is needed (members, unregistered contacts).
+
+CREATE TYPE movement_confirmation_status AS ENUM ('PENDING', 'CONFIRMED', 'DISPUTED');
+
+ALTER TABLE movements ADD COLUMN confirmation_status movement_confirmation_status;
+ALTER TABLE movements ADD COLUMN confirmation_responded_by UUID REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE movements ADD COLUMN confirmation_responded_at TIMESTAMPTZ;
+ALTER TABLE movements ADD COLUMN dispute_reason TEXT;
+
+-- Only debt payments go through confirmation
+ALTER TABLE movements ADD CONSTRAINT movements_confirmation_only_debt_payment
+  CHECK (confirmation_status IS NULL OR type = 'DEBT_PAYMENT');
+
+CREATE INDEX idx_movements_confirmation_pending
+  ON movements(counterparty_contact_id)
+  WHERE confirmation_status = 'PENDING';
+
+COMMENT ON COLUMN movements.confirmation_status IS
+  'Receiver confirmation for DEBT_PAYMENT to a linked contact. NULL = not required.';
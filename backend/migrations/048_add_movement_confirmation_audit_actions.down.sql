@@ -0,0 +1,3 @@
+-- Rollback: PostgreSQL cannot drop values from an enum type.
+-- MOVEMENT_CONFIRMED and MOVEMENT_DISPUTED are left in place.
+SELECT 1;
@@ -0,0 +1,4 @@
+-- Add audit actions for debt payment confirmation
+
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'MOVEMENT_CONFIRMED';
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'MOVEMENT_DISPUTED';
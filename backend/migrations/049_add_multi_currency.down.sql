@@ -0,0 +1,7 @@
+-- Rollback: Remove multi-currency support
+
+ALTER TABLE movements DROP COLUMN IF EXISTS base_amount;
+ALTER TABLE movements DROP COLUMN IF EXISTS exchange_rate;
+
+DROP TABLE IF EXISTS fx_rates;
+DROP TYPE IF EXISTS fx_rate_source;
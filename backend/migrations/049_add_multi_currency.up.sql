@@ -0,0 +1,61 @@
+-- Multi-currency movements
+-- Movements keep their original amount and currency. base_amount is the same
+-- movement converted to the household currency at the time it was recorded, and
+-- is what totals, budgets, card summaries and debt balances add up.
+
+CREATE TYPE fx_rate_source AS ENUM ('MANUAL', 'CSV');
+
+-- Exchange rates per household: 1 unit of from_currency = rate units of to_currency
+CREATE TABLE fx_rates (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  household_id UUID NOT NULL REFERENCES households(id) ON DELETE CASCADE,
+  from_currency CHAR(3) NOT NULL,
+  to_currency CHAR(3) NOT NULL,
+  rate NUMERIC(20, 10) NOT NULL CHECK (rate > 0),
+  rate_date DATE NOT NULL,
+  source fx_rate_source NOT NULL DEFAULT 'MANUAL',
+
+  -- Metadata
+  created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+  CONSTRAINT fx_rates_distinct_currencies CHECK (from_currency <> to_currency),
+  CONSTRAINT fx_rates_unique_day UNIQUE (household_id, from_currency, to_currency, rate_date)
+);
+
+CREATE INDEX idx_fx_rates_lookup ON fx_rates(household_id, from_currency, to_currency, rate_date DESC);
+
+-- Movements recorded before in a currency other than their household's can't
+-- be converted here (there are no exchange rates yet), and copying their amount
+-- would add them up as if they were in the household currency. Stop and list
+-- them, so they are fixed by hand before migrating again.
+DO $$
+DECLARE
+    foreign_movements TEXT;
+BEGIN
+    SELECT string_agg(m.id || ' (' || m.amount || ' ' || m.currency || ' in a ' || COALESCE(h.currency, 'COP') || ' household)', ', ' ORDER BY m.movement_date, m.id)
+    INTO foreign_movements
+    FROM movements m
+    JOIN households h ON h.id = m.household_id
+    WHERE m.currency <> COALESCE(h.currency, 'COP');
+
+    IF foreign_movements IS NOT NULL THEN
+        RAISE EXCEPTION 'movements not in their household currency need an exchange rate: %', foreign_movements;
+    END IF;
+END $$;
+
+-- Converted amount on movements (NULL exchange_rate = already in household currency)
+ALTER TABLE movements ADD COLUMN exchange_rate NUMERIC(20, 10) CHECK (exchange_rate IS NULL OR exchange_rate > 0);
+ALTER TABLE movements ADD COLUMN base_amount DECIMAL(15, 2);
+UPDATE movements m SET base_amount = m.amount
+FROM households h
+WHERE h.id = m.household_id AND m.currency = COALESCE(h.currency, 'COP');
+ALTER TABLE movements ALTER COLUMN base_amount SET NOT NULL;
+
+COMMENT ON TABLE fx_rates IS
+  'Exchange rates entered manually or loaded from CSV, used to convert movements to the household currency.';
+COMMENT ON COLUMN movements.exchange_rate IS
+  'Rate used to convert amount (in currency) to the household currency. NULL when no conversion was needed.';
+COMMENT ON COLUMN movements.base_amount IS
+  'Amount in the household currency. Equal to amount when exchange_rate is NULL.';
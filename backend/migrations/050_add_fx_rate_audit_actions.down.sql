@@ -0,0 +1,3 @@
+-- Rollback: PostgreSQL cannot drop values from an enum type.
+-- FX_RATE_CREATED, FX_RATE_DELETED and FX_RATES_IMPORTED are left in place.
+SELECT 1;
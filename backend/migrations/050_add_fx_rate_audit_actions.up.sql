@@ -0,0 +1,5 @@
+-- Add audit actions for exchange rates
+
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'FX_RATE_CREATED';
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'FX_RATE_DELETED';
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'FX_RATES_IMPORTED';
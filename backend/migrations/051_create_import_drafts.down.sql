@@ -0,0 +1,6 @@
+-- Rollback: Remove statement import drafts
+
+DROP TABLE IF EXISTS import_drafts;
+DROP TYPE IF EXISTS import_draft_status;
+DROP TYPE IF EXISTS import_direction;
+DROP TYPE IF EXISTS import_source;
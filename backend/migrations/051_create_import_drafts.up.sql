@@ -0,0 +1,47 @@
+-- Statement import review queue
+-- Transactions read from bank and card statements (OFX/QFX, Bancolombia
+-- extracts) land here as drafts. Each draft belongs to the payment method or
+-- account the statement is for, and only becomes a movement once the user
+-- confirms its type and category.
+
+CREATE TYPE import_source AS ENUM ('OFX', 'BANCOLOMBIA');
+CREATE TYPE import_direction AS ENUM ('DEBIT', 'CREDIT');
+CREATE TYPE import_draft_status AS ENUM ('PENDING', 'CONFIRMED', 'DISCARDED');
+
+CREATE TABLE import_drafts (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  household_id UUID NOT NULL REFERENCES households(id) ON DELETE CASCADE,
+  source import_source NOT NULL,
+
+  -- What the statement is for (exactly one)
+  payment_method_id UUID REFERENCES payment_methods(id) ON DELETE CASCADE,
+  account_id UUID REFERENCES accounts(id) ON DELETE CASCADE,
+
+  -- Transaction as read from the statement
+  transaction_date DATE NOT NULL,
+  description TEXT NOT NULL,
+  amount DECIMAL(15, 2) NOT NULL CHECK (amount > 0),
+  currency CHAR(3) NOT NULL,
+  direction import_direction NOT NULL,
+  external_id TEXT,                -- Bank transaction ID (OFX FITID, document number)
+  fingerprint TEXT NOT NULL,       -- Identifies the transaction across re-imports
+
+  -- Review
+  status import_draft_status NOT NULL DEFAULT 'PENDING',
+  movement_id UUID REFERENCES movements(id) ON DELETE SET NULL,
+
+  -- Metadata
+  created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+  CONSTRAINT import_drafts_one_target CHECK ((payment_method_id IS NULL) <> (account_id IS NULL)),
+  CONSTRAINT import_drafts_unique_fingerprint UNIQUE (household_id, fingerprint)
+);
+
+CREATE INDEX idx_import_drafts_queue ON import_drafts(household_id, status, transaction_date DESC);
+
+COMMENT ON TABLE import_drafts IS
+  'Statement transactions awaiting review. Confirming a draft creates a movement; discarded drafts are kept so re-imports skip them.';
+COMMENT ON COLUMN import_drafts.direction IS
+  'DEBIT: money out of the account or a charge on the card. CREDIT: money in or a payment/refund on the card.';
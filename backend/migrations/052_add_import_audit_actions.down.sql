@@ -0,0 +1,3 @@
+-- Rollback: PostgreSQL cannot drop values from an enum type.
+-- IMPORT_DRAFTS_CREATED, IMPORT_DRAFT_CONFIRMED and IMPORT_DRAFT_DISCARDED are left in place.
+SELECT 1;
@@ -0,0 +1,5 @@
+-- Add audit actions for statement imports
+
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'IMPORT_DRAFTS_CREATED';
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'IMPORT_DRAFT_CONFIRMED';
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'IMPORT_DRAFT_DISCARDED';
@@ -0,0 +1,3 @@
+-- Rollback: PostgreSQL cannot drop values from an enum type.
+-- EMAIL is left in place on import_source.
+SELECT 1;
@@ -0,0 +1,3 @@
+-- Bank notification emails (Bancolombia alerts) feed the same review queue as statements
+
+ALTER TYPE import_source ADD VALUE IF NOT EXISTS 'EMAIL';
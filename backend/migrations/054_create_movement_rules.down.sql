@@ -0,0 +1,4 @@
+-- Rollback: Drop movement rules
+
+DROP TABLE IF EXISTS movement_rules;
+DROP TYPE IF EXISTS rule_description_match;
@@ -0,0 +1,56 @@
+-- Household categorization rules
+-- A rule matches new movements on description text, amount range, payment
+-- method or payer, and fills in what the movement leaves empty: type,
+-- category, participants or event. Rules run by ascending priority on
+-- POST /movements, on imports and on chat drafts.
+
+CREATE TYPE rule_description_match AS ENUM ('CONTAINS', 'EQUALS', 'STARTS_WITH', 'REGEX');
+
+CREATE TABLE movement_rules (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  household_id UUID NOT NULL REFERENCES households(id) ON DELETE CASCADE,
+  name VARCHAR(100) NOT NULL,
+  priority INT NOT NULL DEFAULT 100,  -- Lower runs first
+  is_active BOOLEAN NOT NULL DEFAULT TRUE,
+
+  -- Conditions (all set conditions must match)
+  description_pattern TEXT,
+  description_match rule_description_match NOT NULL DEFAULT 'CONTAINS',
+  amount_min DECIMAL(15, 2),
+  amount_max DECIMAL(15, 2),
+  payment_method_id UUID REFERENCES payment_methods(id) ON DELETE CASCADE,
+  payer_user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+  payer_contact_id UUID REFERENCES contacts(id) ON DELETE CASCADE,
+
+  -- Actions
+  set_type movement_type,
+  set_category_id UUID REFERENCES categories(id) ON DELETE CASCADE,
+  set_participants JSONB,             -- [{participant_user_id|participant_contact_id, percentage}]
+  set_event_id UUID REFERENCES events(id) ON DELETE CASCADE,
+
+  -- Metadata
+  created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+  CONSTRAINT movement_rules_has_condition CHECK (
+    description_pattern IS NOT NULL OR amount_min IS NOT NULL OR amount_max IS NOT NULL OR
+    payment_method_id IS NOT NULL OR payer_user_id IS NOT NULL OR payer_contact_id IS NOT NULL
+  ),
+  CONSTRAINT movement_rules_has_action CHECK (
+    set_type IS NOT NULL OR set_category_id IS NOT NULL OR
+    set_participants IS NOT NULL OR set_event_id IS NOT NULL
+  ),
+  CONSTRAINT movement_rules_amount_range CHECK (
+    amount_min IS NULL OR amount_max IS NULL OR amount_min <= amount_max
+  ),
+  CONSTRAINT movement_rules_one_payer CHECK (
+    payer_user_id IS NULL OR payer_contact_id IS NULL
+  )
+);
+
+CREATE INDEX idx_movement_rules_household ON movement_rules(household_id, priority, created_at)
+  WHERE is_active;
+
+COMMENT ON TABLE movement_rules IS
+  'Household rules that fill in the category, type, participants or event of new movements.';
@@ -0,0 +1,3 @@
+-- Rollback: PostgreSQL cannot drop values from an enum type.
+-- RULE_CREATED, RULE_UPDATED and RULE_DELETED are left in place.
+SELECT 1;
@@ -0,0 +1,5 @@
+-- Add audit actions for categorization rules
+
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'RULE_CREATED';
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'RULE_UPDATED';
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'RULE_DELETED';
@@ -0,0 +1,4 @@
+-- Rollback: Drop movement attachments
+-- Stored files are not removed.
+
+DROP TABLE IF EXISTS movement_attachments;
@@ -0,0 +1,23 @@
+-- Receipts and invoices attached to movements
+-- Rows go with their movement (ON DELETE CASCADE); the files themselves live
+-- in the attachment storage under <household_id>/<movement_id>/ and are
+-- removed by the movements service when the movement is deleted.
+
+CREATE TABLE movement_attachments (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  movement_id UUID NOT NULL REFERENCES movements(id) ON DELETE CASCADE,
+  household_id UUID NOT NULL REFERENCES households(id) ON DELETE CASCADE,
+  file_name VARCHAR(255) NOT NULL,     -- As uploaded, shown on download
+  content_type VARCHAR(100) NOT NULL,  -- Sniffed from the content, not the client header
+  size_bytes BIGINT NOT NULL CHECK (size_bytes > 0),
+  storage_key TEXT NOT NULL UNIQUE,
+
+  -- Metadata
+  uploaded_by UUID REFERENCES users(id) ON DELETE SET NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_movement_attachments_movement ON movement_attachments(movement_id, created_at);
+
+COMMENT ON TABLE movement_attachments IS
+  'Receipt photos and invoice PDFs attached to movements.';
@@ -0,0 +1,3 @@
+-- Rollback: PostgreSQL cannot drop values from an enum type.
+-- ATTACHMENT_UPLOADED and ATTACHMENT_DELETED are left in place.
+SELECT 1;
@@ -0,0 +1,4 @@
+-- Add audit actions for movement attachments
+
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'ATTACHMENT_UPLOADED';
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'ATTACHMENT_DELETED';
@@ -0,0 +1,4 @@
+-- Rollback: Drop tags
+
+DROP TABLE IF EXISTS movement_tags;
+DROP TABLE IF EXISTS tags;
@@ -0,0 +1,22 @@
+-- Free-form household tags on movements ("reembolsable", "Navidad 2026")
+-- Unlike categories, a movement can have many tags. Tag names are unique per
+-- household ignoring case; tags are created the first time a movement uses them.
+
+CREATE TABLE tags (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  household_id UUID NOT NULL REFERENCES households(id) ON DELETE CASCADE,
+  name VARCHAR(50) NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_tags_household_name ON tags(household_id, LOWER(name));
+
+CREATE TABLE movement_tags (
+  movement_id UUID NOT NULL REFERENCES movements(id) ON DELETE CASCADE,
+  tag_id UUID NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+  PRIMARY KEY (movement_id, tag_id)
+);
+
+CREATE INDEX idx_movement_tags_tag ON movement_tags(tag_id);
+
+COMMENT ON TABLE tags IS 'Household tags, many-to-many with movements through movement_tags.';
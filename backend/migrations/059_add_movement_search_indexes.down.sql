@@ -0,0 +1,8 @@
+-- Rollback: Drop movement search indexes
+-- The pg_trgm extension is left installed.
+
+DROP INDEX IF EXISTS idx_movements_receiver_account;
+DROP INDEX IF EXISTS idx_movements_household_created_page;
+DROP INDEX IF EXISTS idx_movements_household_amount_page;
+DROP INDEX IF EXISTS idx_movements_household_date_page;
+DROP INDEX IF EXISTS idx_movements_description_trgm;
@@ -0,0 +1,21 @@
+-- Indexes for movement search and cursor pagination
+-- Description search uses ILIKE '%word%', which a trigram GIN index serves
+-- on multi-year histories. pg_trgm must be allow-listed on managed servers
+-- (azure.extensions on Azure Database for PostgreSQL).
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS idx_movements_description_trgm
+  ON movements USING GIN (description gin_trgm_ops);
+
+-- Keyset pagination: one index per sort order, ending in id as tie-breaker
+CREATE INDEX IF NOT EXISTS idx_movements_household_date_page
+  ON movements(household_id, movement_date DESC, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_movements_household_amount_page
+  ON movements(household_id, base_amount DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_movements_household_created_page
+  ON movements(household_id, created_at DESC, id DESC);
+
+-- Account filter (movements received in an account)
+CREATE INDEX IF NOT EXISTS idx_movements_receiver_account
+  ON movements(receiver_account_id) WHERE receiver_account_id IS NOT NULL;
@@ -0,0 +1,14 @@
+-- Rollback: Remove soft delete from movements and income
+-- Rows still in the trash are deleted for good.
+
+DELETE FROM movements WHERE deleted_at IS NOT NULL;
+DELETE FROM income WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_income_trash;
+DROP INDEX IF EXISTS idx_movements_trash;
+
+ALTER TABLE income DROP COLUMN IF EXISTS deleted_by;
+ALTER TABLE income DROP COLUMN IF EXISTS deleted_at;
+
+ALTER TABLE movements DROP COLUMN IF EXISTS deleted_by;
+ALTER TABLE movements DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,25 @@
+-- Soft delete for movements and income
+-- Deleting a movement or an income entry moves it to the household trash
+-- (deleted_at is set). Participants, tags, attachments and the template link
+-- stay in place so a restore brings the entry back as it was. Entries older
+-- than the retention period are purged for good by a background job.
+
+ALTER TABLE movements ADD COLUMN deleted_at TIMESTAMPTZ;
+ALTER TABLE movements ADD COLUMN deleted_by UUID REFERENCES users(id) ON DELETE SET NULL;
+
+ALTER TABLE income ADD COLUMN deleted_at TIMESTAMPTZ;
+ALTER TABLE income ADD COLUMN deleted_by UUID REFERENCES users(id) ON DELETE SET NULL;
+
+-- Trash listings and the purge job only look at deleted rows
+CREATE INDEX idx_movements_trash
+  ON movements(household_id, deleted_at)
+  WHERE deleted_at IS NOT NULL;
+
+CREATE INDEX idx_income_trash
+  ON income(household_id, deleted_at)
+  WHERE deleted_at IS NOT NULL;
+
+COMMENT ON COLUMN movements.deleted_at IS
+  'When the movement was moved to the trash. NULL = live.';
+COMMENT ON COLUMN income.deleted_at IS
+  'When the income entry was moved to the trash. NULL = live.';
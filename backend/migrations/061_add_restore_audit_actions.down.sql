@@ -0,0 +1,3 @@
+-- Rollback: PostgreSQL cannot drop values from an enum type.
+-- MOVEMENT_RESTORED and INCOME_RESTORED are left in place.
+SELECT 1;
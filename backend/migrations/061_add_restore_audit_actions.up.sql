@@ -0,0 +1,4 @@
+-- Add audit actions for restoring movements and income from the trash
+
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'MOVEMENT_RESTORED';
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'INCOME_RESTORED';
@@ -0,0 +1,2 @@
+-- Add TRANSFER movement type (money moved between the household's own accounts)
+ALTER TYPE movement_type ADD VALUE IF NOT EXISTS 'TRANSFER';
@@ -0,0 +1,11 @@
+-- Drop source_account_id from movements (transfers can't exist without it)
+
+DELETE FROM movements WHERE type = 'TRANSFER';
+
+DROP INDEX IF EXISTS idx_movements_source_account;
+
+ALTER TABLE movements
+DROP CONSTRAINT IF EXISTS movements_transfer_accounts_check;
+
+ALTER TABLE movements 
+DROP COLUMN IF EXISTS source_account_id;
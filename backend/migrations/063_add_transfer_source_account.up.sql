@@ -0,0 +1,25 @@
+-- Add source_account_id to movements for TRANSFER movements.
+-- The money leaves source_account_id and arrives in receiver_account_id, so
+-- both account balances reflect the transfer.
+
+ALTER TABLE movements 
+ADD COLUMN source_account_id UUID REFERENCES accounts(id) ON DELETE RESTRICT;
+
+-- A transfer moves money between two different accounts, without a payment method.
+-- Only transfers have a source account.
+ALTER TABLE movements
+ADD CONSTRAINT movements_transfer_accounts_check CHECK (
+    (type = 'TRANSFER'
+        AND source_account_id IS NOT NULL
+        AND receiver_account_id IS NOT NULL
+        AND source_account_id <> receiver_account_id
+        AND payment_method_id IS NULL)
+    OR (type <> 'TRANSFER' AND source_account_id IS NULL)
+);
+
+CREATE INDEX idx_movements_source_account 
+ON movements(source_account_id) 
+WHERE source_account_id IS NOT NULL;
+
+COMMENT ON COLUMN movements.source_account_id IS 
+'Account the money leaves in a TRANSFER. The destination is receiver_account_id.';
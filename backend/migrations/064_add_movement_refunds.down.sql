@@ -0,0 +1,14 @@
+-- Drop refunds from movements
+
+DELETE FROM movements WHERE refund_of_id IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_movements_refund_of;
+
+ALTER TABLE movements
+DROP COLUMN IF EXISTS signed_base_amount;
+
+ALTER TABLE movements
+DROP CONSTRAINT IF EXISTS movements_refund_type_check;
+
+ALTER TABLE movements 
+DROP COLUMN IF EXISTS refund_of_id;
@@ -0,0 +1,26 @@
+-- Add refunds: a refund is a movement that gives back part or all of an
+-- original HOUSEHOLD or SPLIT movement. It keeps a positive amount (like every
+-- movement) and counts negatively wherever spending is added up.
+
+ALTER TABLE movements 
+ADD COLUMN refund_of_id UUID REFERENCES movements(id);
+
+ALTER TABLE movements
+ADD CONSTRAINT movements_refund_type_check CHECK (
+    refund_of_id IS NULL OR type IN ('HOUSEHOLD', 'SPLIT')
+);
+
+-- base_amount with the sign it has in totals, budgets and balances
+ALTER TABLE movements
+ADD COLUMN signed_base_amount DECIMAL(15, 2) GENERATED ALWAYS AS (
+    CASE WHEN refund_of_id IS NULL THEN base_amount ELSE -base_amount END
+) STORED;
+
+CREATE INDEX idx_movements_refund_of 
+ON movements(refund_of_id) 
+WHERE refund_of_id IS NOT NULL;
+
+COMMENT ON COLUMN movements.refund_of_id IS 
+'Original movement this movement refunds. Refunds copy the type, category, payment method and participants of the original.';
+COMMENT ON COLUMN movements.signed_base_amount IS
+'base_amount, negative for refunds. Use it to add up spending.';
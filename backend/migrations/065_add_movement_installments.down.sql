@@ -0,0 +1,11 @@
+-- Drop installment purchases from movements
+
+DROP INDEX IF EXISTS idx_movements_installments;
+
+ALTER TABLE movements
+DROP CONSTRAINT IF EXISTS movements_installments_check;
+
+ALTER TABLE movements
+DROP COLUMN IF EXISTS installments_first_cycle,
+DROP COLUMN IF EXISTS installment_rate,
+DROP COLUMN IF EXISTS installments;
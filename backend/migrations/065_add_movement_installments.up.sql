@@ -0,0 +1,25 @@
+-- Add installment purchases (compras a cuotas) on credit cards.
+-- A purchase deferred over N installments is charged one installment per
+-- billing cycle, starting in the cycle of installments_first_cycle.
+
+ALTER TABLE movements
+ADD COLUMN installments INT CHECK (installments BETWEEN 1 AND 48),
+ADD COLUMN installment_rate DECIMAL(7, 6) CHECK (installment_rate >= 0 AND installment_rate < 1),
+ADD COLUMN installments_first_cycle DATE;
+
+-- Rate and first cycle only make sense with installments
+ALTER TABLE movements
+ADD CONSTRAINT movements_installments_check CHECK (
+    installments IS NOT NULL OR (installment_rate IS NULL AND installments_first_cycle IS NULL)
+);
+
+CREATE INDEX idx_movements_installments 
+ON movements(payment_method_id, installments_first_cycle) 
+WHERE installments > 1 AND deleted_at IS NULL;
+
+COMMENT ON COLUMN movements.installments IS 
+'Number of monthly installments a credit card purchase is deferred over (NULL or 1 = single payment)';
+COMMENT ON COLUMN movements.installment_rate IS 
+'Monthly interest rate of the installments (0.0189 = 1.89% per month), charged on the outstanding capital';
+COMMENT ON COLUMN movements.installments_first_cycle IS 
+'A date in the billing cycle of the first installment';
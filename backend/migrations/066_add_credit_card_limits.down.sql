@@ -0,0 +1,9 @@
+-- Rollback: Remove credit limit, payment due day and minimum payment from payment_methods
+
+ALTER TABLE payment_methods
+DROP CONSTRAINT IF EXISTS payment_methods_credit_fields_check;
+
+ALTER TABLE payment_methods
+DROP COLUMN IF EXISTS minimum_payment_percentage,
+DROP COLUMN IF EXISTS payment_due_day,
+DROP COLUMN IF EXISTS credit_limit;
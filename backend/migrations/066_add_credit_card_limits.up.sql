@@ -0,0 +1,30 @@
+-- Migration: Add credit limit, payment due day and minimum payment to payment_methods
+
+-- Credit limit of the card, in the household currency
+ALTER TABLE payment_methods
+ADD COLUMN credit_limit DECIMAL(15, 2) CHECK (credit_limit > 0);
+
+-- Day of the month the statement must be paid by (the first such day after
+-- the cut-off). 29-31 use the last day of shorter months.
+ALTER TABLE payment_methods
+ADD COLUMN payment_due_day INTEGER CHECK (payment_due_day >= 1 AND payment_due_day <= 31);
+
+-- Share of the statement balance the bank asks for as minimum payment (0.05 = 5%)
+ALTER TABLE payment_methods
+ADD COLUMN minimum_payment_percentage DECIMAL(5, 4)
+  CHECK (minimum_payment_percentage > 0 AND minimum_payment_percentage <= 1);
+
+ALTER TABLE payment_methods
+ADD CONSTRAINT payment_methods_credit_fields_check CHECK (
+  type = 'credit_card'
+  OR (credit_limit IS NULL AND payment_due_day IS NULL AND minimum_payment_percentage IS NULL)
+);
+
+COMMENT ON COLUMN payment_methods.credit_limit IS 
+  'Credit card limit in the household currency. NULL = unknown. Only applicable for credit_card type.';
+
+COMMENT ON COLUMN payment_methods.payment_due_day IS 
+  'Credit card payment due day, the first one after the cut-off. Only applicable for credit_card type.';
+
+COMMENT ON COLUMN payment_methods.minimum_payment_percentage IS 
+  'Credit card minimum payment as a fraction of the statement balance. Only applicable for credit_card type.';
@@ -0,0 +1,4 @@
+-- Rollback: Drop card statements
+
+DROP TABLE IF EXISTS card_statement_items;
+DROP TABLE IF EXISTS card_statements;
@@ -0,0 +1,61 @@
+-- Migration: Create card_statements and card_statement_items tables
+-- A billing cycle closes into a statement: a snapshot of what it billed, so
+-- editing an old movement no longer changes a closed cycle
+
+CREATE TABLE card_statements (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  household_id UUID NOT NULL REFERENCES households(id) ON DELETE CASCADE,
+  credit_card_id UUID NOT NULL REFERENCES payment_methods(id) ON DELETE CASCADE,
+  
+  -- Billing cycle (period_end is exclusive, the day after the cut-off)
+  period_start DATE NOT NULL,
+  period_end DATE NOT NULL,
+  
+  -- Balances in the household currency:
+  -- closing = opening + charges + interest - payments
+  opening_balance DECIMAL(15, 2) NOT NULL,
+  charges DECIMAL(15, 2) NOT NULL,
+  payments DECIMAL(15, 2) NOT NULL,
+  interest DECIMAL(15, 2) NOT NULL DEFAULT 0,
+  closing_balance DECIMAL(15, 2) NOT NULL,
+  due_date DATE,
+  minimum_payment DECIMAL(15, 2),
+  
+  -- Reconciliation with the bank's statement
+  bank_total DECIMAL(15, 2),
+  reconciled_at TIMESTAMPTZ,
+  
+  -- Metadata (closed_by is NULL when the cycle was closed automatically)
+  closed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  
+  CONSTRAINT card_statements_period_check CHECK (period_end > period_start),
+  CONSTRAINT card_statements_card_period_unique UNIQUE (credit_card_id, period_start)
+);
+
+CREATE INDEX idx_card_statements_household ON card_statements(household_id);
+
+-- What the statement billed: charges (one row per installment of purchases
+-- in installments) and payments
+CREATE TABLE card_statement_items (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  statement_id UUID NOT NULL REFERENCES card_statements(id) ON DELETE CASCADE,
+  kind VARCHAR(10) NOT NULL CHECK (kind IN ('CHARGE', 'PAYMENT')),
+  
+  -- What the item was (kept when it is later deleted)
+  movement_id UUID REFERENCES movements(id) ON DELETE SET NULL,
+  payment_id UUID REFERENCES credit_card_payments(id) ON DELETE SET NULL,
+  
+  description VARCHAR(255) NOT NULL,
+  item_date DATE NOT NULL,
+  amount DECIMAL(15, 2) NOT NULL,
+  interest DECIMAL(15, 2) NOT NULL DEFAULT 0,
+  installment_number INTEGER,
+  installment_count INTEGER
+);
+
+CREATE INDEX idx_card_statement_items_statement ON card_statement_items(statement_id);
+
+COMMENT ON TABLE card_statements IS 
+  'Closed credit card billing cycles, with the bank statement total they were reconciled against.';
@@ -0,0 +1,3 @@
+-- Rollback: PostgreSQL cannot drop values from an enum type.
+-- CARD_STATEMENT_CLOSED, CARD_STATEMENT_RECONCILED and CARD_STATEMENT_DELETED are left in place.
+SELECT 1;
@@ -0,0 +1,5 @@
+-- Add audit actions for closing, reconciling and reopening card statements
+
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'CARD_STATEMENT_CLOSED';
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'CARD_STATEMENT_RECONCILED';
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'CARD_STATEMENT_DELETED';
@@ -0,0 +1,23 @@
+-- Rollback: Restore credit_card_payments.source_account_id
+-- Payments with several sources keep the one they drew the most from
+
+ALTER TABLE credit_card_payments
+ADD COLUMN source_account_id UUID REFERENCES accounts(id) ON DELETE RESTRICT;
+
+UPDATE credit_card_payments ccp
+SET source_account_id = (
+  SELECT s.account_id FROM credit_card_payment_sources s
+  WHERE s.payment_id = ccp.id
+  ORDER BY s.amount DESC
+  LIMIT 1
+);
+
+ALTER TABLE credit_card_payments
+ALTER COLUMN source_account_id SET NOT NULL;
+
+CREATE INDEX idx_cc_payments_source ON credit_card_payments(source_account_id);
+
+DROP TABLE IF EXISTS credit_card_payment_sources;
+
+COMMENT ON TABLE credit_card_payments IS 
+  'Payments made to credit cards to reduce the balance. Source must be a savings account.';
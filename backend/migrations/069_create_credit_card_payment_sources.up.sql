@@ -0,0 +1,30 @@
+-- Migration: Create credit_card_payment_sources
+-- A credit card payment can draw from several accounts: each source is the
+-- part of the payment taken from one account. Sources replace
+-- credit_card_payments.source_account_id.
+
+CREATE TABLE credit_card_payment_sources (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  payment_id UUID NOT NULL REFERENCES credit_card_payments(id) ON DELETE CASCADE,
+  account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE RESTRICT,
+  amount DECIMAL(15, 2) NOT NULL CHECK (amount > 0),
+  
+  CONSTRAINT credit_card_payment_sources_unique UNIQUE (payment_id, account_id)
+);
+
+CREATE INDEX idx_cc_payment_sources_account ON credit_card_payment_sources(account_id);
+
+-- Existing payments have a single source for their whole amount
+INSERT INTO credit_card_payment_sources (payment_id, account_id, amount)
+SELECT id, source_account_id, amount FROM credit_card_payments;
+
+DROP INDEX IF EXISTS idx_cc_payments_source;
+
+ALTER TABLE credit_card_payments
+DROP COLUMN source_account_id;
+
+COMMENT ON TABLE credit_card_payments IS 
+  'Payments made to credit cards to reduce the balance. The accounts they draw from are in credit_card_payment_sources.';
+
+COMMENT ON TABLE credit_card_payment_sources IS 
+  'Accounts a credit card payment draws from. The amounts add up to the payment amount (enforced in application).';
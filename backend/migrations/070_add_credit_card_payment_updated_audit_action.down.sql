@@ -0,0 +1,3 @@
+-- Rollback: PostgreSQL cannot drop values from an enum type.
+-- CREDIT_CARD_PAYMENT_UPDATED is left in place.
+SELECT 1;
@@ -0,0 +1,3 @@
+-- Add audit action for editing credit card payments
+
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'CREDIT_CARD_PAYMENT_UPDATED';
@@ -0,0 +1,3 @@
+-- Rollback: Drop account checkpoints (their adjustments leave the balances)
+
+DROP TABLE IF EXISTS account_checkpoints;
@@ -0,0 +1,31 @@
+-- Migration: Create account_checkpoints table
+-- A checkpoint records the real balance of an account on a date (what the
+-- bank app says), to compare it with the balance derived from its movements.
+-- Closing the gap adds an adjustment to the account balance on that date.
+
+CREATE TABLE account_checkpoints (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  household_id UUID NOT NULL REFERENCES households(id) ON DELETE CASCADE,
+  account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+  
+  checkpoint_date DATE NOT NULL,
+  reported_balance DECIMAL(15, 2) NOT NULL,
+  
+  -- Added to the derived balance from checkpoint_date on (0 = not adjusted)
+  adjustment DECIMAL(15, 2) NOT NULL DEFAULT 0,
+  adjusted_at TIMESTAMPTZ,
+  
+  notes TEXT,
+  
+  -- Metadata
+  created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  
+  CONSTRAINT account_checkpoints_account_date_unique UNIQUE (account_id, checkpoint_date)
+);
+
+CREATE INDEX idx_account_checkpoints_household ON account_checkpoints(household_id);
+
+COMMENT ON TABLE account_checkpoints IS 
+  'Real account balances on a date, reconciled against the balance derived from movements.';
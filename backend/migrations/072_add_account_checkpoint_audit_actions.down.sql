@@ -0,0 +1,3 @@
+-- Rollback: PostgreSQL cannot drop values from an enum type.
+-- ACCOUNT_CHECKPOINT_CREATED, ACCOUNT_CHECKPOINT_DELETED and ACCOUNT_BALANCE_ADJUSTED are left in place.
+SELECT 1;
@@ -0,0 +1,5 @@
+-- Add audit actions for account balance checkpoints and adjustments
+
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'ACCOUNT_CHECKPOINT_CREATED';
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'ACCOUNT_CHECKPOINT_DELETED';
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'ACCOUNT_BALANCE_ADJUSTED';
@@ -0,0 +1,3 @@
+-- Rollback: Drop net worth snapshots
+
+DROP TABLE IF EXISTS net_worth_snapshots;
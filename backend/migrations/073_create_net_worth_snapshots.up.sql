@@ -0,0 +1,30 @@
+-- Migration: Create net_worth_snapshots table
+-- One snapshot per household and month, refreshed until the month ends, so
+-- the net worth trend can be charted
+
+CREATE TABLE net_worth_snapshots (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  household_id UUID NOT NULL REFERENCES households(id) ON DELETE CASCADE,
+  
+  -- First day of the month
+  month DATE NOT NULL,
+  
+  -- In the household currency:
+  -- net_worth = account_balances + owed_by_contacts - card_debt - owed_to_contacts
+  account_balances DECIMAL(15, 2) NOT NULL,
+  owed_by_contacts DECIMAL(15, 2) NOT NULL,
+  card_debt DECIMAL(15, 2) NOT NULL,
+  owed_to_contacts DECIMAL(15, 2) NOT NULL,
+  net_worth DECIMAL(15, 2) NOT NULL,
+  
+  -- Same breakdown per member: [{member_id, member_name, account_balances, ...}]
+  members JSONB NOT NULL DEFAULT '[]',
+  
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  
+  CONSTRAINT net_worth_snapshots_household_month_unique UNIQUE (household_id, month)
+);
+
+COMMENT ON TABLE net_worth_snapshots IS 
+  'Monthly net worth of each household, the last one taken in the month.';